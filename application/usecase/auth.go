@@ -0,0 +1,288 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/takumi616/go-restapi/domain"
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+type AuthUsecase struct {
+	gateway         UserGateway
+	hasher          PasswordHasher
+	tokens          AccessTokenCodec
+	refreshTokenTTL time.Duration
+	now             func() time.Time
+
+	// Set in OIDC mode, where users sign in with an identity provider and
+	// the tokens it issues are the only ones accepted besides personal
+	// access tokens
+	identities IdentityTokenVerifier
+
+	// Checked against when nobody has the email given on login, so that
+	// a wrong email takes as long to refuse as a wrong password
+	dummyHashOnce sync.Once
+	dummyHash     string
+}
+
+func NewAuthUsecase(gateway UserGateway, hasher PasswordHasher, tokens AccessTokenCodec, refreshTokenTTL time.Duration) *AuthUsecase {
+	return &AuthUsecase{
+		gateway:         gateway,
+		hasher:          hasher,
+		tokens:          tokens,
+		refreshTokenTTL: refreshTokenTTL,
+		now:             time.Now,
+	}
+}
+
+// NewOIDCAuthUsecase authenticates users by the tokens of an identity
+// provider, recording each user on their first request. Signing up, in and
+// out with a password is refused
+func NewOIDCAuthUsecase(gateway UserGateway, identities IdentityTokenVerifier) *AuthUsecase {
+	return &AuthUsecase{
+		gateway:    gateway,
+		identities: identities,
+		now:        time.Now,
+	}
+}
+
+// Register creates a user with the password hashed, in the default role
+func (u *AuthUsecase) Register(ctx context.Context, email string, password string) (*domain.User, error) {
+	if u.identities != nil {
+		return nil, customError.ErrPasswordAuthOff
+	}
+
+	hash, err := u.hasher.Hash(password)
+	if err != nil {
+		return nil, customError.ErrRegisterUser
+	}
+
+	user, err := u.gateway.AddUser(ctx, &domain.User{
+		Email:        domain.NormalizeEmail(email),
+		PasswordHash: hash,
+		Role:         domain.DefaultRole,
+	})
+	if err != nil {
+		var fieldErr *customError.FieldError
+		if errors.As(err, &fieldErr) && errors.Is(fieldErr.Err, customError.ErrDuplicate) {
+			return nil, &customError.FieldError{Field: fieldErr.Field, Err: customError.ErrEmailTaken}
+		}
+		return nil, customError.ErrRegisterUser
+	}
+
+	return user, nil
+}
+
+// Login starts a session for the user with the email, if the password is theirs
+func (u *AuthUsecase) Login(ctx context.Context, email string, password string) (*domain.Session, error) {
+	if u.identities != nil {
+		return nil, customError.ErrPasswordAuthOff
+	}
+
+	user, err := u.gateway.GetUserByEmail(ctx, domain.NormalizeEmail(email))
+	if err != nil {
+		if errors.Is(err, customError.ErrNotFound) {
+			u.hasher.Matches(u.getDummyHash(), password)
+			return nil, customError.ErrInvalidCredentials
+		}
+		return nil, customError.ErrLogin
+	}
+
+	if !u.hasher.Matches(user.PasswordHash, password) {
+		return nil, customError.ErrInvalidCredentials
+	}
+
+	refreshToken, stored, err := u.newRefreshToken(user.Id)
+	if err != nil {
+		return nil, customError.ErrLogin
+	}
+
+	if err := u.gateway.AddRefreshToken(ctx, stored); err != nil {
+		return nil, customError.ErrLogin
+	}
+
+	return u.newSession(user, refreshToken, stored.ExpiresAt, customError.ErrLogin)
+}
+
+// Refresh trades a refresh token for a new session. The token is used up,
+// and using it again revokes every token that has replaced it since
+func (u *AuthUsecase) Refresh(ctx context.Context, refreshToken string) (*domain.Session, error) {
+	if u.identities != nil {
+		return nil, customError.ErrPasswordAuthOff
+	}
+
+	// The new token goes to the owner of the one it replaces
+	next, stored, err := u.newRefreshToken("")
+	if err != nil {
+		return nil, customError.ErrRefreshSession
+	}
+
+	userId, err := u.gateway.RotateRefreshToken(ctx, hashToken(refreshToken), stored)
+	if err != nil {
+		if errors.Is(err, customError.ErrNotFound) || errors.Is(err, customError.ErrExpired) || errors.Is(err, customError.ErrRevoked) {
+			return nil, customError.ErrInvalidRefresh
+		}
+		return nil, customError.ErrRefreshSession
+	}
+
+	user, err := u.gateway.GetUserById(ctx, userId)
+	if err != nil {
+		if errors.Is(err, customError.ErrNotFound) {
+			return nil, customError.ErrInvalidRefresh
+		}
+		return nil, customError.ErrRefreshSession
+	}
+
+	return u.newSession(user, next, stored.ExpiresAt, customError.ErrRefreshSession)
+}
+
+// Logout revokes a refresh token along with every token of its family.
+// An unknown token is nothing to revoke
+func (u *AuthUsecase) Logout(ctx context.Context, refreshToken string) error {
+	if u.identities != nil {
+		return customError.ErrPasswordAuthOff
+	}
+
+	if err := u.gateway.RevokeRefreshTokens(ctx, hashToken(refreshToken)); err != nil {
+		return customError.ErrLogout
+	}
+	return nil
+}
+
+// Authenticate finds who a bearer token was issued to: the user an access
+// token names, or the user a personal access token belongs to, limited to
+// the scopes of the token. In OIDC mode access tokens are those of the
+// identity provider, and name users who are recorded here as they come
+func (u *AuthUsecase) Authenticate(ctx context.Context, token string) (*domain.Actor, error) {
+	if strings.HasPrefix(token, domain.PersonalAccessTokenPrefix) {
+		return u.authenticatePersonalAccessToken(ctx, token)
+	}
+
+	if u.identities != nil {
+		identity, err := u.identities.Verify(ctx, token)
+		if err != nil {
+			return nil, customError.ErrUnauthenticated
+		}
+
+		user, err := u.saveIdentityUser(ctx, identity)
+		if err != nil {
+			return nil, err
+		}
+		return &domain.Actor{UserId: user.Id, Role: user.Role}, nil
+	}
+
+	id, err := u.tokens.Verify(token)
+	if err != nil {
+		return nil, customError.ErrUnauthenticated
+	}
+
+	user, err := u.getAuthenticatedUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.Actor{UserId: user.Id, Role: user.Role}, nil
+}
+
+func (u *AuthUsecase) authenticatePersonalAccessToken(ctx context.Context, token string) (*domain.Actor, error) {
+	stored, err := u.gateway.UsePersonalAccessToken(ctx, hashToken(token))
+	if err != nil {
+		if errors.Is(err, customError.ErrNotFound) {
+			return nil, customError.ErrUnauthenticated
+		}
+		return nil, customError.ErrAuthenticate
+	}
+
+	user, err := u.getAuthenticatedUser(ctx, stored.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.Actor{UserId: user.Id, Role: user.Role, Scopes: stored.Scopes}, nil
+}
+
+// Record the user of an identity the first time they make a request, and
+// their role again whenever the provider changes it, so that they can have
+// personal access tokens like anyone who signed up
+func (u *AuthUsecase) saveIdentityUser(ctx context.Context, identity *domain.Identity) (*domain.User, error) {
+	user, err := u.gateway.GetUserById(ctx, identity.UserId())
+	if err == nil && user.Role == identity.Role() {
+		return user, nil
+	}
+	if err != nil && !errors.Is(err, customError.ErrNotFound) {
+		return nil, customError.ErrAuthenticate
+	}
+
+	user, err = u.gateway.SaveUser(ctx, &domain.User{Id: identity.UserId(), Role: identity.Role()})
+	if err != nil {
+		return nil, customError.ErrAuthenticate
+	}
+
+	return user, nil
+}
+
+// The user a token was issued to, whose role may have changed since
+func (u *AuthUsecase) getAuthenticatedUser(ctx context.Context, id domain.UserID) (*domain.User, error) {
+	user, err := u.gateway.GetUserById(ctx, id)
+	if err != nil {
+		if errors.Is(err, customError.ErrNotFound) {
+			// The user was removed after the token was issued
+			return nil, customError.ErrUnauthenticated
+		}
+		return nil, customError.ErrAuthenticate
+	}
+
+	return user, nil
+}
+
+func (u *AuthUsecase) newSession(user *domain.User, refreshToken string, refreshExpiresAt time.Time, fallback error) (*domain.Session, error) {
+	accessToken, accessExpiresAt, err := u.tokens.Issue(user)
+	if err != nil {
+		return nil, fallback
+	}
+
+	return &domain.Session{
+		User:                  user,
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  accessExpiresAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: refreshExpiresAt,
+	}, nil
+}
+
+// A new random refresh token for the client, and what is stored of it
+func (u *AuthUsecase) newRefreshToken(userId domain.UserID) (string, *domain.RefreshToken, error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return "", nil, err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(random)
+	return token, &domain.RefreshToken{
+		UserId:    userId,
+		Hash:      hashToken(token),
+		ExpiresAt: u.now().Add(u.refreshTokenTTL).UTC(),
+	}, nil
+}
+
+func (u *AuthUsecase) getDummyHash() string {
+	u.dummyHashOnce.Do(func() {
+		u.dummyHash, _ = u.hasher.Hash("not the password of anyone")
+	})
+	return u.dummyHash
+}
+
+// Refresh and personal access tokens are random enough that a plain SHA-256
+// keeps them safe at rest
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,22 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/takumi616/go-restapi/domain"
+)
+
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Matches(hash string, password string) bool
+}
+
+type AccessTokenCodec interface {
+	Issue(user *domain.User) (string, time.Time, error)
+	Verify(token string) (domain.UserID, error)
+}
+
+type IdentityTokenVerifier interface {
+	Verify(ctx context.Context, token string) (*domain.Identity, error)
+}
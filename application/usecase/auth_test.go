@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/takumi616/go-restapi/domain"
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+// Users and personal access tokens kept in memory, as the database would
+type memoryUserGateway struct {
+	UserGateway
+	users  map[domain.UserID]*domain.User
+	tokens map[string]*domain.PersonalAccessToken
+}
+
+func newMemoryUserGateway() *memoryUserGateway {
+	return &memoryUserGateway{
+		users:  map[domain.UserID]*domain.User{},
+		tokens: map[string]*domain.PersonalAccessToken{},
+	}
+}
+
+func (g *memoryUserGateway) SaveUser(ctx context.Context, user *domain.User) (*domain.User, error) {
+	g.users[user.Id] = user
+	return user, nil
+}
+
+func (g *memoryUserGateway) GetUserById(ctx context.Context, id domain.UserID) (*domain.User, error) {
+	user, ok := g.users[id]
+	if !ok {
+		return nil, customError.ErrNotFound
+	}
+	return user, nil
+}
+
+func (g *memoryUserGateway) AddPersonalAccessToken(ctx context.Context, token *domain.PersonalAccessToken) (*domain.PersonalAccessToken, error) {
+	// Tokens refer to a user, who has to be recorded
+	if _, ok := g.users[token.UserId]; !ok {
+		return nil, customError.ErrNotFound
+	}
+	g.tokens[token.Hash] = token
+	return token, nil
+}
+
+func (g *memoryUserGateway) UsePersonalAccessToken(ctx context.Context, hash string) (*domain.PersonalAccessToken, error) {
+	token, ok := g.tokens[hash]
+	if !ok {
+		return nil, customError.ErrNotFound
+	}
+	return token, nil
+}
+
+// Accepts the token "idp-token" only, as issued to one identity
+type stubIdentityVerifier struct {
+	identity *domain.Identity
+}
+
+func (v *stubIdentityVerifier) Verify(ctx context.Context, token string) (*domain.Identity, error) {
+	if token != "idp-token" {
+		return nil, customError.ErrUnauthenticated
+	}
+	return v.identity, nil
+}
+
+func TestOIDCAuthenticate(t *testing.T) {
+	identity := &domain.Identity{Issuer: "https://sso.example.com", Subject: "alice", Roles: []string{"viewer"}}
+	gateway := newMemoryUserGateway()
+	sut := NewOIDCAuthUsecase(gateway, &stubIdentityVerifier{identity: identity})
+
+	// The first request records the user
+	actor, err := sut.Authenticate(context.Background(), "idp-token")
+	require.NoError(t, err)
+	assert.Equal(t, &domain.Actor{UserId: identity.UserId(), Role: domain.RoleViewer}, actor)
+	assert.Equal(t, domain.RoleViewer, gateway.users[identity.UserId()].Role)
+
+	// A role changed by the provider is recorded too
+	identity.Roles = []string{"admin"}
+	actor, err = sut.Authenticate(context.Background(), "idp-token")
+	require.NoError(t, err)
+	assert.Equal(t, domain.RoleAdmin, actor.Role)
+	assert.Equal(t, domain.RoleAdmin, gateway.users[identity.UserId()].Role)
+
+	_, err = sut.Authenticate(context.Background(), "forged-token")
+	assert.ErrorIs(t, err, customError.ErrUnauthenticated)
+}
+
+func TestOIDCPersonalAccessToken(t *testing.T) {
+	identity := &domain.Identity{Issuer: "https://sso.example.com", Subject: "alice", Roles: []string{"editor"}}
+	sut := NewOIDCAuthUsecase(newMemoryUserGateway(), &stubIdentityVerifier{identity: identity})
+
+	actor, err := sut.Authenticate(context.Background(), "idp-token")
+	require.NoError(t, err)
+
+	stored, token, err := sut.CreatePersonalAccessToken(
+		domain.ContextWithActor(context.Background(), actor), "ci", []domain.TokenScope{domain.ScopeTasksRead}, 24*time.Hour,
+	)
+	require.NoError(t, err)
+	assert.Equal(t, identity.UserId(), stored.UserId)
+
+	actor, err = sut.Authenticate(context.Background(), token)
+	require.NoError(t, err)
+	assert.Equal(t, &domain.Actor{
+		UserId: identity.UserId(),
+		Role:   domain.RoleEditor,
+		Scopes: []domain.TokenScope{domain.ScopeTasksRead},
+	}, actor)
+}
+
+func TestOIDCPasswordAuthOff(t *testing.T) {
+	sut := NewOIDCAuthUsecase(newMemoryUserGateway(), &stubIdentityVerifier{})
+
+	_, err := sut.Register(context.Background(), "alice@example.com", "correct horse")
+	assert.ErrorIs(t, err, customError.ErrPasswordAuthOff)
+
+	_, err = sut.Login(context.Background(), "alice@example.com", "correct horse")
+	assert.ErrorIs(t, err, customError.ErrPasswordAuthOff)
+}
@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/takumi616/go-restapi/domain"
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+type LabelUsecase struct {
+	gateway LabelGateway
+	policy  TaskPolicy
+}
+
+func NewLabelUsecase(gateway LabelGateway, policy TaskPolicy) *LabelUsecase {
+	return &LabelUsecase{
+		gateway: gateway,
+		policy:  policy,
+	}
+}
+
+// AddLabel adds a label owned by whoever makes the request
+func (u *LabelUsecase) AddLabel(ctx context.Context, label *domain.Label) (*domain.Label, error) {
+	actor, err := authorize(ctx, u.policy, LabelActionAdd)
+	if err != nil {
+		return nil, err
+	}
+	label.OwnerId = actor.UserId
+
+	label, err = u.gateway.AddLabel(ctx, label)
+	if err != nil {
+		if fieldErr := toLabelFieldError(err); fieldErr != nil {
+			return nil, fieldErr
+		}
+		return nil, customError.ErrAddLabel
+	}
+
+	return label, nil
+}
+
+func (u *LabelUsecase) GetLabels(ctx context.Context) ([]*domain.Label, error) {
+	if _, err := authorize(ctx, u.policy, LabelActionRead); err != nil {
+		return nil, err
+	}
+
+	labels, err := u.gateway.GetLabels(ctx)
+	if err != nil {
+		return nil, customError.ErrGetLabels
+	}
+
+	return labels, nil
+}
+
+func (u *LabelUsecase) GetLabelById(ctx context.Context, id domain.LabelID) (*domain.Label, error) {
+	if _, err := authorize(ctx, u.policy, LabelActionRead); err != nil {
+		return nil, err
+	}
+
+	label, err := u.gateway.GetLabelById(ctx, id)
+	if err != nil {
+		if errors.Is(err, customError.ErrNotFound) {
+			return nil, customError.ErrLabelNotFound
+		} else {
+			return nil, customError.ErrGetLabelById
+		}
+	}
+
+	return label, nil
+}
+
+func (u *LabelUsecase) UpdateLabel(ctx context.Context, id domain.LabelID, patch *domain.LabelPatch) (*domain.Label, error) {
+	if _, err := authorize(ctx, u.policy, LabelActionUpdate); err != nil {
+		return nil, err
+	}
+
+	label, err := u.gateway.UpdateLabel(ctx, id, patch)
+	if err != nil {
+		if errors.Is(err, customError.ErrNotFound) {
+			return nil, customError.ErrLabelNotFound
+		} else if fieldErr := toLabelFieldError(err); fieldErr != nil {
+			return nil, fieldErr
+		} else {
+			return nil, customError.ErrUpdateLabel
+		}
+	}
+
+	return label, nil
+}
+
+func (u *LabelUsecase) DeleteLabel(ctx context.Context, id domain.LabelID) (*domain.Label, error) {
+	if _, err := authorize(ctx, u.policy, LabelActionDelete); err != nil {
+		return nil, err
+	}
+
+	label, err := u.gateway.DeleteLabel(ctx, id)
+	if err != nil {
+		if errors.Is(err, customError.ErrNotFound) {
+			return nil, customError.ErrLabelNotFound
+		} else {
+			return nil, customError.ErrDeleteLabel
+		}
+	}
+
+	return label, nil
+}
+
+// Translate a constraint violation on a label column into its label-level error,
+// keeping the field it names. nil is returned for any other error
+func toLabelFieldError(err error) error {
+	var fieldErr *customError.FieldError
+	if !errors.As(err, &fieldErr) {
+		return nil
+	}
+
+	if errors.Is(fieldErr.Err, customError.ErrDuplicate) {
+		return &customError.FieldError{Field: fieldErr.Field, Err: customError.ErrLabelConflict}
+	} else if errors.Is(fieldErr.Err, customError.ErrValueTooLong) {
+		return &customError.FieldError{Field: fieldErr.Field, Err: customError.ErrLabelValueTooLong}
+	}
+	return nil
+}
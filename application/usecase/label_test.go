@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/takumi616/go-restapi/domain"
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+// A gateway that only counts the calls reaching it
+type stubLabelGateway struct {
+	calls int
+}
+
+func (g *stubLabelGateway) AddLabel(ctx context.Context, label *domain.Label) (*domain.Label, error) {
+	g.calls++
+	return label, nil
+}
+
+func (g *stubLabelGateway) GetLabels(ctx context.Context) ([]*domain.Label, error) {
+	g.calls++
+	return []*domain.Label{}, nil
+}
+
+func (g *stubLabelGateway) GetLabelById(ctx context.Context, id domain.LabelID) (*domain.Label, error) {
+	g.calls++
+	return &domain.Label{Id: id}, nil
+}
+
+func (g *stubLabelGateway) UpdateLabel(ctx context.Context, id domain.LabelID, patch *domain.LabelPatch) (*domain.Label, error) {
+	g.calls++
+	return &domain.Label{Id: id}, nil
+}
+
+func (g *stubLabelGateway) DeleteLabel(ctx context.Context, id domain.LabelID) (*domain.Label, error) {
+	g.calls++
+	return &domain.Label{Id: id}, nil
+}
+
+func TestLabelUsecaseAuthorize(t *testing.T) {
+	const id = domain.LabelID("c6a1f3e2-7d4b-4e8a-b1f9-3a2d5e6c7b80")
+	name := "backend"
+
+	calls := map[string]func(u *LabelUsecase, ctx context.Context) error{
+		"Add": func(u *LabelUsecase, ctx context.Context) error {
+			_, err := u.AddLabel(ctx, &domain.Label{Name: name})
+			return err
+		},
+		"Get": func(u *LabelUsecase, ctx context.Context) error {
+			_, err := u.GetLabels(ctx)
+			return err
+		},
+		"Update": func(u *LabelUsecase, ctx context.Context) error {
+			_, err := u.UpdateLabel(ctx, id, &domain.LabelPatch{Name: &name})
+			return err
+		},
+		"Delete": func(u *LabelUsecase, ctx context.Context) error {
+			_, err := u.DeleteLabel(ctx, id)
+			return err
+		},
+	}
+
+	testTable := map[string]struct {
+		actor *domain.Actor
+		call  string
+		err   error
+	}{
+		"ViewerGets":    {actor: &domain.Actor{Role: domain.RoleViewer}, call: "Get"},
+		"ViewerAdds":    {actor: &domain.Actor{Role: domain.RoleViewer}, call: "Add", err: customError.ErrForbidden},
+		"ViewerUpdates": {actor: &domain.Actor{Role: domain.RoleViewer}, call: "Update", err: customError.ErrForbidden},
+		"ViewerDeletes": {actor: &domain.Actor{Role: domain.RoleViewer}, call: "Delete", err: customError.ErrForbidden},
+		"EditorAdds":    {actor: &domain.Actor{Role: domain.RoleEditor}, call: "Add"},
+		"EditorUpdates": {actor: &domain.Actor{Role: domain.RoleEditor}, call: "Update"},
+		"EditorDeletes": {actor: &domain.Actor{Role: domain.RoleEditor}, call: "Delete", err: customError.ErrForbidden},
+		"AdminDeletes":  {actor: &domain.Actor{Role: domain.RoleAdmin}, call: "Delete"},
+		"NoActor":       {call: "Get", err: customError.ErrUnauthenticated},
+	}
+
+	for n, tt := range testTable {
+		t.Run(n, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.actor != nil {
+				tt.actor.UserId = "7c9e6679-7425-40de-944b-e07fc1f90ae7"
+				ctx = domain.ContextWithActor(ctx, tt.actor)
+			}
+
+			gateway := &stubLabelGateway{}
+			err := calls[tt.call](NewLabelUsecase(gateway, NewRolePolicy()), ctx)
+
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				assert.Zero(t, gateway.calls)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, 1, gateway.calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/takumi616/go-restapi/domain"
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+// CreatePersonalAccessToken mints a token for the actor with the scopes,
+// valid for expiresIn. The token is returned along with what is stored of
+// it, and cannot be recovered afterwards
+func (u *AuthUsecase) CreatePersonalAccessToken(
+	ctx context.Context, name string, scopes []domain.TokenScope, expiresIn time.Duration,
+) (*domain.PersonalAccessToken, string, error) {
+	actor, err := tokenManager(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return nil, "", customError.ErrCreateToken
+	}
+	token := domain.PersonalAccessTokenPrefix + base64.RawURLEncoding.EncodeToString(random)
+
+	stored, err := u.gateway.AddPersonalAccessToken(ctx, &domain.PersonalAccessToken{
+		UserId:    actor.UserId,
+		Name:      name,
+		Hash:      hashToken(token),
+		Scopes:    scopes,
+		ExpiresAt: u.now().Add(expiresIn).UTC(),
+	})
+	if err != nil {
+		if errors.Is(err, customError.ErrNotFound) {
+			// A caller the gateway vouches for without having signed up here,
+			// whom the token could never authenticate
+			return nil, "", fmt.Errorf("%w: only users who signed up can create tokens", customError.ErrForbidden)
+		}
+		return nil, "", customError.ErrCreateToken
+	}
+
+	return stored, token, nil
+}
+
+// GetPersonalAccessTokens lists the tokens of the actor that are not revoked
+func (u *AuthUsecase) GetPersonalAccessTokens(ctx context.Context) ([]*domain.PersonalAccessToken, error) {
+	actor, err := tokenManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := u.gateway.GetPersonalAccessTokens(ctx, actor.UserId)
+	if err != nil {
+		return nil, customError.ErrGetTokens
+	}
+
+	return tokens, nil
+}
+
+// RevokePersonalAccessToken revokes a token of the actor for good
+func (u *AuthUsecase) RevokePersonalAccessToken(ctx context.Context, id domain.PersonalAccessTokenID) error {
+	actor, err := tokenManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := u.gateway.RevokePersonalAccessToken(ctx, actor.UserId, id); err != nil {
+		if errors.Is(err, customError.ErrNotFound) {
+			return customError.ErrTokenNotFound
+		}
+		return customError.ErrRevokeToken
+	}
+
+	return nil
+}
+
+// Personal access tokens are managed by their user in person. A request made
+// with one of them cannot mint others or revoke them
+func tokenManager(ctx context.Context) (*domain.Actor, error) {
+	actor, ok := domain.ActorFromContext(ctx)
+	if !ok {
+		return nil, customError.ErrUnauthenticated
+	}
+
+	if actor.Scopes != nil {
+		return nil, fmt.Errorf("%w: personal access tokens cannot manage tokens", customError.ErrInsufficientScope)
+	}
+
+	return actor, nil
+}
@@ -3,43 +3,112 @@ package usecase
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/takumi616/go-restapi/domain"
 	customError "github.com/takumi616/go-restapi/shared/error"
 )
 
 type TaskUsecase struct {
-	gateway TaskGateway
+	gateway  TaskGateway
+	workflow *domain.TaskWorkflow
+	policy   TaskPolicy
 }
 
-func NewTaskUsecase(gateway TaskGateway) *TaskUsecase {
+func NewTaskUsecase(gateway TaskGateway, workflow *domain.TaskWorkflow, policy TaskPolicy) *TaskUsecase {
 	return &TaskUsecase{
-		gateway: gateway,
+		gateway:  gateway,
+		workflow: workflow,
+		policy:   policy,
 	}
 }
 
+func (u *TaskUsecase) authorize(ctx context.Context, action TaskAction) (*domain.Actor, error) {
+	return authorize(ctx, u.policy, action)
+}
+
+// AddTask adds a task owned by whoever makes the request
 func (u *TaskUsecase) AddTask(ctx context.Context, task *domain.Task) (*domain.Task, error) {
-	// Set default status
-	task.Status = false
+	actor, err := u.authorize(ctx, TaskActionAdd)
+	if err != nil {
+		return nil, err
+	}
+	prepareNewTask(task, actor)
 
-	task, err := u.gateway.AddTask(ctx, task)
+	task, err = u.gateway.AddTask(ctx, task)
 	if err != nil {
+		if fieldErr := toTaskFieldError(err); fieldErr != nil {
+			return nil, fieldErr
+		}
 		return nil, customError.ErrAddTask
 	}
 
 	return task, nil
 }
 
-func (u *TaskUsecase) GetTaskList(ctx context.Context) ([]*domain.Task, error) {
-	taskList, err := u.gateway.GetTaskList(ctx)
+// Every task starts at the beginning of the workflow, owned by the actor,
+// and at the default priority unless given one
+func prepareNewTask(task *domain.Task, actor *domain.Actor) {
+	task.OwnerId = actor.UserId
+	task.State = domain.TaskStateTodo
+	if task.Priority == "" {
+		task.Priority = domain.DefaultTaskPriority
+	}
+}
+
+func (u *TaskUsecase) GetTaskList(ctx context.Context, query *domain.TaskListQuery) (*domain.TaskList, error) {
+	if _, err := u.authorize(ctx, TaskActionRead); err != nil {
+		return nil, err
+	}
+
+	// Listing the subtasks of a task that does not exist is an error, not an empty list
+	if query.ParentId != nil {
+		if _, err := u.GetTaskById(ctx, *query.ParentId); err != nil {
+			return nil, err
+		}
+	}
+
+	if query.After != nil {
+		return u.getTaskListAfter(ctx, query)
+	}
+
+	taskList, err := u.gateway.GetTaskList(ctx, query)
+	if err != nil {
+		return nil, customError.ErrGetTaskList
+	}
+
+	// Hand out a cursor as well so that clients can switch to keyset pagination
+	if n := len(taskList.Tasks); n > 0 && query.Offset+n < taskList.Total {
+		taskList.NextCursor = domain.NewTaskCursor(query, taskList.Tasks[n-1])
+	}
+
+	return taskList, nil
+}
+
+func (u *TaskUsecase) getTaskListAfter(ctx context.Context, query *domain.TaskListQuery) (*domain.TaskList, error) {
+	// Fetch one extra row to find out whether another page follows
+	lookahead := *query
+	lookahead.Limit++
+
+	tasks, err := u.gateway.GetTaskListAfter(ctx, &lookahead)
 	if err != nil {
 		return nil, customError.ErrGetTaskList
 	}
 
+	taskList := &domain.TaskList{Tasks: tasks}
+	if len(tasks) > query.Limit {
+		taskList.Tasks = tasks[:query.Limit]
+		taskList.NextCursor = domain.NewTaskCursor(query, taskList.Tasks[query.Limit-1])
+	}
+
 	return taskList, nil
 }
 
-func (u *TaskUsecase) GetTaskById(ctx context.Context, id string) (*domain.Task, error) {
+func (u *TaskUsecase) GetTaskById(ctx context.Context, id domain.TaskID) (*domain.Task, error) {
+	if _, err := u.authorize(ctx, TaskActionRead); err != nil {
+		return nil, err
+	}
+
 	task, err := u.gateway.GetTaskById(ctx, id)
 	if err != nil {
 		if errors.Is(err, customError.ErrNotFound) {
@@ -52,11 +121,42 @@ func (u *TaskUsecase) GetTaskById(ctx context.Context, id string) (*domain.Task,
 	return task, nil
 }
 
-func (u *TaskUsecase) UpdateTask(ctx context.Context, id string, task *domain.Task) (*domain.Task, error) {
-	task, err := u.gateway.UpdateTask(ctx, id, task)
+// GetTaskTree gets a task with its subtasks, their subtasks and so on
+func (u *TaskUsecase) GetTaskTree(ctx context.Context, id domain.TaskID) (*domain.Task, error) {
+	if _, err := u.authorize(ctx, TaskActionRead); err != nil {
+		return nil, err
+	}
+
+	task, err := u.gateway.GetTaskTree(ctx, id)
 	if err != nil {
 		if errors.Is(err, customError.ErrNotFound) {
 			return nil, customError.ErrTaskNotFound
+		} else {
+			return nil, customError.ErrGetSubtasks
+		}
+	}
+
+	return task, nil
+}
+
+func (u *TaskUsecase) UpdateTask(ctx context.Context, id domain.TaskID, patch *domain.TaskPatch) (*domain.Task, error) {
+	if _, err := u.authorize(ctx, TaskActionUpdate); err != nil {
+		return nil, err
+	}
+
+	if err := u.checkTransition(ctx, id, patch); err != nil {
+		return nil, err
+	}
+
+	task, err := u.gateway.UpdateTask(ctx, id, patch)
+	if err != nil {
+		// Field errors come first, as a missing parent is not a missing task
+		if fieldErr := toTaskFieldError(err); fieldErr != nil {
+			return nil, fieldErr
+		} else if errors.Is(err, customError.ErrNotFound) {
+			return nil, customError.ErrTaskNotFound
+		} else if errors.Is(err, customError.ErrVersionMismatch) {
+			return nil, customError.ErrTaskVersionMismatch
 		} else {
 			return nil, customError.ErrUpdateTask
 		}
@@ -65,11 +165,81 @@ func (u *TaskUsecase) UpdateTask(ctx context.Context, id string, task *domain.Ta
 	return task, nil
 }
 
-func (u *TaskUsecase) DeleteTask(ctx context.Context, id string) (*domain.Task, error) {
-	task, err := u.gateway.DeleteTask(ctx, id)
+// Make sure a patch that changes the state follows the workflow, and that it
+// does not complete a task still waiting on others unless forced to. The patch
+// is pinned to the version the state was checked at, so that it is not
+// written if the task has moved elsewhere in the meantime
+func (u *TaskUsecase) checkTransition(ctx context.Context, id domain.TaskID, patch *domain.TaskPatch) error {
+	if patch.State == nil {
+		return nil
+	}
+
+	current, err := u.GetTaskById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if patch.Version != 0 && patch.Version != current.Version {
+		return customError.ErrTaskVersionMismatch
+	}
+
+	if err := u.workflow.Check(current.State, *patch.State); err != nil {
+		return err
+	}
+
+	// A task that is done already stays so, even if a blocker reopened since
+	movingToDone := current.State != domain.TaskStateDone && *patch.State == domain.TaskStateDone
+	if movingToDone && !patch.Force && current.Blocked() {
+		blockers := current.OpenBlockers()
+		ids := make([]string, len(blockers))
+		for i, blocker := range blockers {
+			ids[i] = blocker.String()
+		}
+		return &customError.BlockedError{Blockers: ids}
+	}
+
+	patch.Version = current.Version
+	return nil
+}
+
+func (u *TaskUsecase) SetTaskLabels(ctx context.Context, id domain.TaskID, labelIds []domain.LabelID, version int) (*domain.Task, error) {
+	if _, err := u.authorize(ctx, TaskActionUpdate); err != nil {
+		return nil, err
+	}
+
+	task, err := u.gateway.SetTaskLabels(ctx, id, labelIds, version)
+	if err != nil {
+		var fieldErr *customError.FieldError
+		if errors.Is(err, customError.ErrVersionMismatch) {
+			return nil, customError.ErrTaskVersionMismatch
+		} else if errors.As(err, &fieldErr) && errors.Is(fieldErr.Err, customError.ErrNotFound) {
+			// A label that does not exist, as opposed to the task itself
+			return nil, &customError.FieldError{Field: fieldErr.Field, Err: customError.ErrTaskLabelNotFound}
+		} else if errors.Is(err, customError.ErrNotFound) {
+			return nil, customError.ErrTaskNotFound
+		} else {
+			return nil, customError.ErrSetTaskLabels
+		}
+	}
+
+	return task, nil
+}
+
+// DeleteTask moves a task to the trash. A task with subtasks is only
+// deleted if mode says what becomes of them
+func (u *TaskUsecase) DeleteTask(ctx context.Context, id domain.TaskID, version int, mode domain.SubtaskMode) (*domain.Task, error) {
+	if _, err := u.authorize(ctx, TaskActionDelete); err != nil {
+		return nil, err
+	}
+
+	task, err := u.gateway.DeleteTask(ctx, id, version, mode)
 	if err != nil {
 		if errors.Is(err, customError.ErrNotFound) {
 			return nil, customError.ErrTaskNotFound
+		} else if errors.Is(err, customError.ErrVersionMismatch) {
+			return nil, customError.ErrTaskVersionMismatch
+		} else if errors.Is(err, customError.ErrHasChildren) {
+			return nil, customError.ErrTaskHasSubtasks
 		} else {
 			return nil, customError.ErrDeleteTask
 		}
@@ -77,3 +247,95 @@ func (u *TaskUsecase) DeleteTask(ctx context.Context, id string) (*domain.Task,
 
 	return task, nil
 }
+
+func (u *TaskUsecase) GetTrash(ctx context.Context) ([]*domain.Task, error) {
+	if _, err := u.authorize(ctx, TaskActionRead); err != nil {
+		return nil, err
+	}
+
+	taskList, err := u.gateway.GetTrash(ctx)
+	if err != nil {
+		return nil, customError.ErrGetTrash
+	}
+
+	return taskList, nil
+}
+
+func (u *TaskUsecase) RestoreTask(ctx context.Context, id domain.TaskID) (*domain.Task, error) {
+	if _, err := u.authorize(ctx, TaskActionRestore); err != nil {
+		return nil, err
+	}
+
+	task, err := u.gateway.RestoreTask(ctx, id)
+	if err != nil {
+		if errors.Is(err, customError.ErrNotFound) {
+			return nil, customError.ErrTaskNotInTrash
+		} else if fieldErr := toTaskFieldError(err); fieldErr != nil {
+			return nil, fieldErr
+		} else {
+			return nil, customError.ErrRestoreTask
+		}
+	}
+
+	return task, nil
+}
+
+func (u *TaskUsecase) PurgeTask(ctx context.Context, id domain.TaskID, version int, mode domain.SubtaskMode) (*domain.Task, error) {
+	if _, err := u.authorize(ctx, TaskActionPurge); err != nil {
+		return nil, err
+	}
+
+	task, err := u.gateway.PurgeTask(ctx, id, version, mode)
+	if err != nil {
+		if errors.Is(err, customError.ErrNotFound) {
+			return nil, customError.ErrTaskNotFound
+		} else if errors.Is(err, customError.ErrVersionMismatch) {
+			return nil, customError.ErrTaskVersionMismatch
+		} else if errors.Is(err, customError.ErrHasChildren) {
+			return nil, customError.ErrTaskHasSubtasks
+		} else {
+			return nil, customError.ErrPurgeTask
+		}
+	}
+
+	return task, nil
+}
+
+// Permanently remove tasks that have stayed in the trash longer than retention.
+// It runs in the background on behalf of no one, so the policy is not asked
+func (u *TaskUsecase) PurgeExpiredTrash(ctx context.Context, retention time.Duration) (int64, error) {
+	purged, err := u.gateway.PurgeTrash(ctx, time.Now().Add(-retention))
+	if err != nil {
+		return 0, customError.ErrPurgeTrash
+	}
+
+	return purged, nil
+}
+
+// Translate a constraint violation or misplacement in the hierarchy on a task
+// column into its task-level error, keeping the field it names.
+// nil is returned for any other error
+func toTaskFieldError(err error) error {
+	var fieldErr *customError.FieldError
+	if !errors.As(err, &fieldErr) {
+		return nil
+	}
+
+	if errors.Is(fieldErr.Err, customError.ErrDuplicate) {
+		return &customError.FieldError{Field: fieldErr.Field, Err: customError.ErrTaskConflict}
+	} else if errors.Is(fieldErr.Err, customError.ErrNotNull) {
+		return &customError.FieldError{Field: fieldErr.Field, Err: customError.ErrTaskValueMissing}
+	} else if errors.Is(fieldErr.Err, customError.ErrValueTooLong) {
+		return &customError.FieldError{Field: fieldErr.Field, Err: customError.ErrTaskValueTooLong}
+	} else if errors.Is(fieldErr.Err, customError.ErrNotFound) && fieldErr.Field == "depends_on" {
+		return &customError.FieldError{Field: fieldErr.Field, Err: customError.ErrTaskBlockerNotFound}
+	} else if errors.Is(fieldErr.Err, customError.ErrNotFound) {
+		// Otherwise parent_id is the only column of tasks that refers to another row
+		return &customError.FieldError{Field: fieldErr.Field, Err: customError.ErrTaskParentNotFound}
+	} else if errors.Is(fieldErr.Err, customError.ErrCycle) {
+		return &customError.FieldError{Field: fieldErr.Field, Err: customError.ErrTaskParentCycle}
+	} else if errors.Is(fieldErr.Err, customError.ErrTooDeep) {
+		return &customError.FieldError{Field: fieldErr.Field, Err: customError.ErrTaskTooDeep}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/takumi616/go-restapi/domain"
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+func (u *TaskUsecase) AddTasks(ctx context.Context, tasks []*domain.Task, atomic bool) ([]*domain.TaskBatchResult, error) {
+	actor, err := u.authorize(ctx, TaskActionBatchAdd)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, task := range tasks {
+		prepareNewTask(task, actor)
+	}
+
+	results, err := u.gateway.AddTasks(ctx, tasks, atomic)
+	if err != nil {
+		return nil, customError.ErrAddTasks
+	}
+
+	return toTaskBatchResults(results, customError.ErrAddTask), nil
+}
+
+// State changes are checked against the workflow before the batch is written.
+// Items that fail the check are not written, and abort an atomic batch
+func (u *TaskUsecase) UpdateTasks(ctx context.Context, updates []*domain.TaskBatchUpdate, atomic bool) ([]*domain.TaskBatchResult, error) {
+	if _, err := u.authorize(ctx, TaskActionBatchUpdate); err != nil {
+		return nil, err
+	}
+
+	results := make([]*domain.TaskBatchResult, len(updates))
+
+	var checked []*domain.TaskBatchUpdate
+	var positions []int
+	for i, update := range updates {
+		if err := u.checkTransition(ctx, update.Id, update.Patch); err != nil {
+			if atomic {
+				return abortedTaskBatch(len(updates), i, err), nil
+			}
+			results[i] = &domain.TaskBatchResult{Err: err}
+			continue
+		}
+
+		checked = append(checked, update)
+		positions = append(positions, i)
+	}
+
+	if len(checked) == 0 {
+		return results, nil
+	}
+
+	written, err := u.gateway.UpdateTasks(ctx, checked, atomic)
+	if err != nil {
+		return nil, customError.ErrUpdateTasks
+	}
+
+	for i, result := range toTaskBatchResults(written, customError.ErrUpdateTask) {
+		results[positions[i]] = result
+	}
+
+	return results, nil
+}
+
+func (u *TaskUsecase) DeleteTasks(ctx context.Context, deletes []*domain.TaskBatchDelete, atomic bool) ([]*domain.TaskBatchResult, error) {
+	if _, err := u.authorize(ctx, TaskActionBatchDelete); err != nil {
+		return nil, err
+	}
+
+	results, err := u.gateway.DeleteTasks(ctx, deletes, atomic)
+	if err != nil {
+		return nil, customError.ErrDeleteTasks
+	}
+
+	return toTaskBatchResults(results, customError.ErrDeleteTask), nil
+}
+
+// Results of an atomic batch given up on because the item at failed did not pass
+func abortedTaskBatch(size, failed int, err error) []*domain.TaskBatchResult {
+	results := make([]*domain.TaskBatchResult, size)
+	for i := range results {
+		results[i] = &domain.TaskBatchResult{Err: customError.ErrBatchAborted}
+	}
+	results[failed].Err = err
+
+	return results
+}
+
+// Translate per-item repository errors the same way the single-task usecases do
+func toTaskBatchResults(results []*domain.TaskBatchResult, fallback error) []*domain.TaskBatchResult {
+	for _, result := range results {
+		if result.Err == nil {
+			continue
+		}
+
+		if fieldErr := toTaskFieldError(result.Err); fieldErr != nil {
+			result.Err = fieldErr
+		} else if errors.Is(result.Err, customError.ErrNotFound) {
+			result.Err = customError.ErrTaskNotFound
+		} else if errors.Is(result.Err, customError.ErrVersionMismatch) {
+			result.Err = customError.ErrTaskVersionMismatch
+		} else if errors.Is(result.Err, customError.ErrHasChildren) {
+			result.Err = customError.ErrTaskHasSubtasks
+		} else if !errors.Is(result.Err, customError.ErrBatchAborted) {
+			result.Err = fallback
+		}
+	}
+
+	return results
+}
@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/takumi616/go-restapi/domain"
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+func (u *TaskUsecase) GetChecklist(ctx context.Context, taskId domain.TaskID) (*domain.Checklist, error) {
+	if _, err := u.authorize(ctx, TaskActionRead); err != nil {
+		return nil, err
+	}
+
+	checklist, err := u.gateway.GetChecklist(ctx, taskId)
+	if err != nil {
+		return nil, toChecklistError(err, customError.ErrGetChecklist)
+	}
+
+	return checklist, nil
+}
+
+// AddChecklistItem adds an item at position, or at the end without one
+func (u *TaskUsecase) AddChecklistItem(ctx context.Context, taskId domain.TaskID, text string, position *int, version int) (*domain.Checklist, error) {
+	if _, err := u.authorize(ctx, TaskActionUpdate); err != nil {
+		return nil, err
+	}
+
+	checklist, err := u.gateway.AddChecklistItem(ctx, taskId, text, position, version)
+	if err != nil {
+		return nil, toChecklistError(err, customError.ErrAddChecklistItem)
+	}
+
+	return checklist, nil
+}
+
+func (u *TaskUsecase) UpdateChecklistItem(ctx context.Context, taskId domain.TaskID, itemId domain.ChecklistItemID, patch *domain.ChecklistItemPatch, version int) (*domain.Checklist, error) {
+	if _, err := u.authorize(ctx, TaskActionUpdate); err != nil {
+		return nil, err
+	}
+
+	checklist, err := u.gateway.UpdateChecklistItem(ctx, taskId, itemId, patch, version)
+	if err != nil {
+		return nil, toChecklistError(err, customError.ErrUpdateChecklistItem)
+	}
+
+	return checklist, nil
+}
+
+func (u *TaskUsecase) RemoveChecklistItem(ctx context.Context, taskId domain.TaskID, itemId domain.ChecklistItemID, version int) (*domain.Checklist, error) {
+	if _, err := u.authorize(ctx, TaskActionUpdate); err != nil {
+		return nil, err
+	}
+
+	checklist, err := u.gateway.RemoveChecklistItem(ctx, taskId, itemId, version)
+	if err != nil {
+		return nil, toChecklistError(err, customError.ErrRemoveChecklistItem)
+	}
+
+	return checklist, nil
+}
+
+// ReorderChecklist puts the items of a checklist in the order of itemIds
+func (u *TaskUsecase) ReorderChecklist(ctx context.Context, taskId domain.TaskID, itemIds []domain.ChecklistItemID, version int) (*domain.Checklist, error) {
+	if _, err := u.authorize(ctx, TaskActionUpdate); err != nil {
+		return nil, err
+	}
+
+	checklist, err := u.gateway.ReorderChecklist(ctx, taskId, itemIds, version)
+	if err != nil {
+		return nil, toChecklistError(err, customError.ErrReorderChecklist)
+	}
+
+	return checklist, nil
+}
+
+// Map what the repository reports for a checklist write onto the errors clients
+// see, falling back to fallback for anything unexpected
+func toChecklistError(err error, fallback error) error {
+	if errors.Is(err, customError.ErrNotFound) {
+		return customError.ErrTaskNotFound
+	} else if errors.Is(err, customError.ErrVersionMismatch) {
+		return customError.ErrTaskVersionMismatch
+	} else if errors.Is(err, customError.ErrItemNotFound) {
+		return customError.ErrChecklistItemNotFound
+	} else if errors.Is(err, customError.ErrTooMany) {
+		return customError.ErrChecklistFull
+	} else if errors.Is(err, customError.ErrOrderMismatch) {
+		return &customError.FieldError{Field: "items", Err: customError.ErrChecklistOrderMismatch}
+	} else {
+		return fallback
+	}
+}
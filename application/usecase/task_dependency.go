@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/takumi616/go-restapi/domain"
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+// AddTaskDependency makes a task wait on the blocker. A dependency that
+// would have the task wait on itself, directly or through other tasks,
+// is refused
+func (u *TaskUsecase) AddTaskDependency(ctx context.Context, id domain.TaskID, blockerId domain.TaskID) (*domain.Task, error) {
+	if _, err := u.authorize(ctx, TaskActionUpdate); err != nil {
+		return nil, err
+	}
+
+	if _, err := u.GetTaskById(ctx, id); err != nil {
+		return nil, err
+	}
+
+	if _, err := u.gateway.GetTaskById(ctx, blockerId); err != nil {
+		if errors.Is(err, customError.ErrNotFound) {
+			return nil, &customError.FieldError{Field: "blocker_id", Err: customError.ErrTaskBlockerNotFound}
+		}
+		return nil, customError.ErrAddTaskDependency
+	}
+
+	dependencies, err := u.gateway.GetTaskDependencies(ctx)
+	if err != nil {
+		return nil, customError.ErrAddTaskDependency
+	}
+
+	// The new edge closes a loop if the task already blocks the blocker
+	if domain.NewTaskDependencyGraph(dependencies).Reaches(id, blockerId) {
+		return nil, &customError.FieldError{Field: "blocker_id", Err: customError.ErrTaskDependencyCycle}
+	}
+
+	task, err := u.gateway.AddTaskDependency(ctx, &domain.TaskDependency{BlockerId: blockerId, BlockedId: id})
+	if err != nil {
+		var fieldErr *customError.FieldError
+		if errors.As(err, &fieldErr) && errors.Is(fieldErr.Err, customError.ErrCycle) {
+			return nil, &customError.FieldError{Field: fieldErr.Field, Err: customError.ErrTaskDependencyCycle}
+		} else if errors.As(err, &fieldErr) && errors.Is(fieldErr.Err, customError.ErrNotFound) {
+			// The blocker was purged since it was looked up
+			return nil, &customError.FieldError{Field: fieldErr.Field, Err: customError.ErrTaskBlockerNotFound}
+		} else {
+			return nil, customError.ErrAddTaskDependency
+		}
+	}
+
+	return task, nil
+}
+
+// RemoveTaskDependency stops a task from waiting on the blocker
+func (u *TaskUsecase) RemoveTaskDependency(ctx context.Context, id domain.TaskID, blockerId domain.TaskID) (*domain.Task, error) {
+	if _, err := u.authorize(ctx, TaskActionUpdate); err != nil {
+		return nil, err
+	}
+
+	if _, err := u.GetTaskById(ctx, id); err != nil {
+		return nil, err
+	}
+
+	task, err := u.gateway.RemoveTaskDependency(ctx, &domain.TaskDependency{BlockerId: blockerId, BlockedId: id})
+	if err != nil {
+		if errors.Is(err, customError.ErrNotFound) {
+			return nil, customError.ErrTaskDependencyNotFound
+		} else {
+			return nil, customError.ErrRemoveTaskDependency
+		}
+	}
+
+	return task, nil
+}
+
+// GetTaskSchedule plans the open tasks around the dependencies between them
+func (u *TaskUsecase) GetTaskSchedule(ctx context.Context) (*domain.TaskSchedule, error) {
+	if _, err := u.authorize(ctx, TaskActionRead); err != nil {
+		return nil, err
+	}
+
+	tasks, err := u.gateway.GetOpenTasks(ctx)
+	if err != nil {
+		return nil, customError.ErrGetTaskSchedule
+	}
+
+	dependencies, err := u.gateway.GetTaskDependencies(ctx)
+	if err != nil {
+		return nil, customError.ErrGetTaskSchedule
+	}
+
+	// Dependencies cannot loop as they are added, so this is not expected
+	schedule, err := domain.NewTaskSchedule(tasks, dependencies)
+	if err != nil {
+		return nil, customError.ErrGetTaskSchedule
+	}
+
+	return schedule, nil
+}
@@ -2,14 +2,34 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	"github.com/takumi616/go-restapi/domain"
 )
 
 type TaskGateway interface {
 	AddTask(ctx context.Context, task *domain.Task) (*domain.Task, error)
-	GetTaskList(ctx context.Context) ([]*domain.Task, error)
-	GetTaskById(ctx context.Context, id string) (*domain.Task, error)
-	UpdateTask(ctx context.Context, id string, task *domain.Task) (*domain.Task, error)
-	DeleteTask(ctx context.Context, id string) (*domain.Task, error)
+	GetTaskList(ctx context.Context, query *domain.TaskListQuery) (*domain.TaskList, error)
+	GetTaskListAfter(ctx context.Context, query *domain.TaskListQuery) ([]*domain.Task, error)
+	GetTaskById(ctx context.Context, id domain.TaskID) (*domain.Task, error)
+	GetTaskTree(ctx context.Context, id domain.TaskID) (*domain.Task, error)
+	UpdateTask(ctx context.Context, id domain.TaskID, patch *domain.TaskPatch) (*domain.Task, error)
+	SetTaskLabels(ctx context.Context, id domain.TaskID, labelIds []domain.LabelID, version int) (*domain.Task, error)
+	GetOpenTasks(ctx context.Context) ([]*domain.Task, error)
+	GetTaskDependencies(ctx context.Context) ([]*domain.TaskDependency, error)
+	AddTaskDependency(ctx context.Context, dependency *domain.TaskDependency) (*domain.Task, error)
+	RemoveTaskDependency(ctx context.Context, dependency *domain.TaskDependency) (*domain.Task, error)
+	GetChecklist(ctx context.Context, taskId domain.TaskID) (*domain.Checklist, error)
+	AddChecklistItem(ctx context.Context, taskId domain.TaskID, text string, position *int, version int) (*domain.Checklist, error)
+	UpdateChecklistItem(ctx context.Context, taskId domain.TaskID, itemId domain.ChecklistItemID, patch *domain.ChecklistItemPatch, version int) (*domain.Checklist, error)
+	RemoveChecklistItem(ctx context.Context, taskId domain.TaskID, itemId domain.ChecklistItemID, version int) (*domain.Checklist, error)
+	ReorderChecklist(ctx context.Context, taskId domain.TaskID, itemIds []domain.ChecklistItemID, version int) (*domain.Checklist, error)
+	DeleteTask(ctx context.Context, id domain.TaskID, version int, mode domain.SubtaskMode) (*domain.Task, error)
+	GetTrash(ctx context.Context) ([]*domain.Task, error)
+	RestoreTask(ctx context.Context, id domain.TaskID) (*domain.Task, error)
+	PurgeTask(ctx context.Context, id domain.TaskID, version int, mode domain.SubtaskMode) (*domain.Task, error)
+	PurgeTrash(ctx context.Context, before time.Time) (int64, error)
+	AddTasks(ctx context.Context, tasks []*domain.Task, atomic bool) ([]*domain.TaskBatchResult, error)
+	UpdateTasks(ctx context.Context, updates []*domain.TaskBatchUpdate, atomic bool) ([]*domain.TaskBatchResult, error)
+	DeleteTasks(ctx context.Context, deletes []*domain.TaskBatchDelete, atomic bool) ([]*domain.TaskBatchResult, error)
 }
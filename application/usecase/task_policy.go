@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/takumi616/go-restapi/domain"
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+// The least role that may perform each action by default
+var defaultTaskActionRoles = map[TaskAction]domain.Role{
+	TaskActionRead:        domain.RoleViewer,
+	TaskActionAdd:         domain.RoleEditor,
+	TaskActionUpdate:      domain.RoleEditor,
+	TaskActionDelete:      domain.RoleEditor,
+	TaskActionRestore:     domain.RoleEditor,
+	TaskActionBatchAdd:    domain.RoleEditor,
+	TaskActionBatchUpdate: domain.RoleEditor,
+	TaskActionPurge:       domain.RoleAdmin,
+	TaskActionBatchDelete: domain.RoleAdmin,
+	LabelActionRead:       domain.RoleViewer,
+	LabelActionAdd:        domain.RoleEditor,
+	LabelActionUpdate:     domain.RoleEditor,
+	LabelActionDelete:     domain.RoleAdmin,
+}
+
+// RolePolicy lets an actor perform an action if their role is at least the
+// one it is given for. Actions it does not know are allowed to no one
+type RolePolicy struct {
+	actionRoles map[TaskAction]domain.Role
+}
+
+// NewRolePolicy gives the policy of defaultTaskActionRoles: viewers read,
+// editors also write, and only admins purge, delete in bulk or delete labels
+func NewRolePolicy() *RolePolicy {
+	return &RolePolicy{
+		actionRoles: defaultTaskActionRoles,
+	}
+}
+
+func (p *RolePolicy) Allows(actor *domain.Actor, action TaskAction) bool {
+	least, ok := p.actionRoles[action]
+	return ok && actor.Role.AtLeast(least)
+}
+
+// Find who makes the request and make sure the policy lets them perform
+// action. Denials are logged, since they may be someone probing for more
+// than they were given
+func authorize(ctx context.Context, policy TaskPolicy, action TaskAction) (*domain.Actor, error) {
+	actor, ok := domain.ActorFromContext(ctx)
+	if !ok {
+		return nil, customError.ErrUnauthenticated
+	}
+
+	if !policy.Allows(actor, action) {
+		slog.WarnContext(
+			ctx, "action denied",
+			slog.String("user_id", actor.UserId.String()),
+			slog.String("role", actor.Role.String()),
+			slog.String("action", string(action)),
+		)
+		return nil, fmt.Errorf("%w: role '%s' may not %s", customError.ErrForbidden, actor.Role, action)
+	}
+
+	return actor, nil
+}
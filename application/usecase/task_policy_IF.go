@@ -0,0 +1,33 @@
+package usecase
+
+import "github.com/takumi616/go-restapi/domain"
+
+// Something an actor may be allowed to do with tasks or the labels they
+// are given
+type TaskAction string
+
+const (
+	// Read tasks, subtasks, checklists, the schedule and the trash
+	TaskActionRead TaskAction = "read_tasks"
+	TaskActionAdd  TaskAction = "add_task"
+	// Change a task, its labels, dependencies or checklist
+	TaskActionUpdate      TaskAction = "update_task"
+	TaskActionDelete      TaskAction = "delete_task"
+	TaskActionRestore     TaskAction = "restore_task"
+	TaskActionPurge       TaskAction = "purge_task"
+	TaskActionBatchAdd    TaskAction = "batch_add_tasks"
+	TaskActionBatchUpdate TaskAction = "batch_update_tasks"
+	TaskActionBatchDelete TaskAction = "batch_delete_tasks"
+	LabelActionRead       TaskAction = "read_labels"
+	LabelActionAdd        TaskAction = "add_label"
+	// Rename a label, which every task it is on shows
+	LabelActionUpdate TaskAction = "update_label"
+	// Delete a label, which takes it off every task it is on
+	LabelActionDelete TaskAction = "delete_label"
+)
+
+// Decides what actors may do with tasks and labels. TaskUsecase and
+// LabelUsecase ask it before anything else
+type TaskPolicy interface {
+	Allows(actor *domain.Actor, action TaskAction) bool
+}
@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/takumi616/go-restapi/domain"
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+// A gateway holding one task, which updates only change the version of
+type stubTaskGateway struct {
+	TaskGateway
+	task    *domain.Task
+	updated bool
+}
+
+func (g *stubTaskGateway) GetTaskById(ctx context.Context, id domain.TaskID) (*domain.Task, error) {
+	return g.task, nil
+}
+
+func (g *stubTaskGateway) UpdateTask(ctx context.Context, id domain.TaskID, patch *domain.TaskPatch) (*domain.Task, error) {
+	g.updated = true
+	updated := *g.task
+	updated.Version++
+	return &updated, nil
+}
+
+func TestUpdateTaskBlocked(t *testing.T) {
+	const id = domain.TaskID("6a30b9b0-18bf-47b4-bd23-d72726864def")
+	reopened := []*domain.TaskBlocker{{Id: "3e440171-0921-4c88-a7ec-13f4cdab0d69", State: domain.TaskStateInProgress}}
+	title := "Renamed Title"
+	done := domain.TaskStateDone
+
+	testTable := map[string]struct {
+		current *domain.Task
+		patch   *domain.TaskPatch
+		err     bool
+	}{
+		"CompleteBlocked": {
+			current: &domain.Task{Id: id, State: domain.TaskStateInProgress, BlockedBy: reopened, Version: 1},
+			patch:   &domain.TaskPatch{State: &done},
+			err:     true,
+		},
+		"CompleteBlockedForced": {
+			current: &domain.Task{Id: id, State: domain.TaskStateInProgress, BlockedBy: reopened, Version: 1},
+			patch:   &domain.TaskPatch{State: &done, Force: true},
+		},
+		// Whole-document patches always carry the state, which is unchanged
+		"RenameDoneWithBlockerReopened": {
+			current: &domain.Task{Id: id, State: domain.TaskStateDone, BlockedBy: reopened, Version: 1},
+			patch:   &domain.TaskPatch{Title: &title, State: &done},
+		},
+	}
+
+	for n, tt := range testTable {
+		t.Run(n, func(t *testing.T) {
+			ctx := domain.ContextWithActor(context.Background(), &domain.Actor{
+				UserId: "7c9e6679-7425-40de-944b-e07fc1f90ae7", Role: domain.RoleEditor,
+			})
+			gateway := &stubTaskGateway{task: tt.current}
+			sut := NewTaskUsecase(gateway, domain.DefaultTaskWorkflow(), NewRolePolicy())
+
+			task, err := sut.UpdateTask(ctx, id, tt.patch)
+
+			if tt.err {
+				var blockedErr *customError.BlockedError
+				assert.ErrorAs(t, err, &blockedErr)
+				assert.Equal(t, []string{"3e440171-0921-4c88-a7ec-13f4cdab0d69"}, blockedErr.Blockers)
+				assert.False(t, gateway.updated)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, 2, task.Version)
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/takumi616/go-restapi/domain"
+)
+
+type UserGateway interface {
+	AddUser(ctx context.Context, user *domain.User) (*domain.User, error)
+	SaveUser(ctx context.Context, user *domain.User) (*domain.User, error)
+	GetUserByEmail(ctx context.Context, email string) (*domain.User, error)
+	GetUserById(ctx context.Context, id domain.UserID) (*domain.User, error)
+	AddRefreshToken(ctx context.Context, token *domain.RefreshToken) error
+	RotateRefreshToken(ctx context.Context, hash string, next *domain.RefreshToken) (domain.UserID, error)
+	RevokeRefreshTokens(ctx context.Context, hash string) error
+	AddPersonalAccessToken(ctx context.Context, token *domain.PersonalAccessToken) (*domain.PersonalAccessToken, error)
+	GetPersonalAccessTokens(ctx context.Context, userId domain.UserID) ([]*domain.PersonalAccessToken, error)
+	UsePersonalAccessToken(ctx context.Context, hash string) (*domain.PersonalAccessToken, error)
+	RevokePersonalAccessToken(ctx context.Context, userId domain.UserID, id domain.PersonalAccessTokenID) error
+}
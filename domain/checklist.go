@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+// Most items the checklist of a task may hold
+const MaxChecklistItems = 50
+
+// Identifier of a checklist item, a UUID in its canonical lowercase form
+type ChecklistItemID string
+
+// ParseChecklistItemID accepts a UUID written as 8-4-4-4-12 hex digits in either case
+func ParseChecklistItemID(s string) (ChecklistItemID, error) {
+	if !isUUID(s) {
+		return "", fmt.Errorf("%w: '%s' is not a UUID", customError.InvalidChecklistItemId, s)
+	}
+	return ChecklistItemID(strings.ToLower(s)), nil
+}
+
+func (id ChecklistItemID) String() string {
+	return string(id)
+}
+
+// A step of a task too small to be a subtask of its own
+type ChecklistItem struct {
+	Id   ChecklistItemID
+	Text string
+	Done bool
+	// Where the item is shown, counting from 0
+	Position  int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Only non-nil fields are changed
+type ChecklistItemPatch struct {
+	Text *string
+	Done *bool
+}
+
+// The checklist of a task with its items in order. Any change to the
+// checklist counts as a change to the task, so Version is the task's
+type Checklist struct {
+	TaskId  TaskID
+	Items   []*ChecklistItem
+	Version int
+}
+
+// Done items out of all the items of a checklist
+type ChecklistProgress struct {
+	Completed int
+	Total     int
+}
+
+func (c *Checklist) Progress() ChecklistProgress {
+	progress := ChecklistProgress{Total: len(c.Items)}
+	for _, item := range c.Items {
+		if item.Done {
+			progress.Completed++
+		}
+	}
+	return progress
+}
@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+func TestParseChecklistItemID(t *testing.T) {
+	id, err := ParseChecklistItemID("D1E2F3A4-B5C6-4D7E-8F90-A1B2C3D4E5F6")
+	assert.NoError(t, err)
+	assert.Equal(t, ChecklistItemID("d1e2f3a4-b5c6-4d7e-8f90-a1b2c3d4e5f6"), id)
+
+	_, err = ParseChecklistItemID("first")
+	assert.ErrorIs(t, err, customError.InvalidChecklistItemId)
+}
+
+func TestChecklistProgress(t *testing.T) {
+	checklist := &Checklist{Items: []*ChecklistItem{{Done: true}, {Done: false}, {Done: true}}}
+	assert.Equal(t, ChecklistProgress{Completed: 2, Total: 3}, checklist.Progress())
+
+	assert.Equal(t, ChecklistProgress{}, (&Checklist{}).Progress())
+}
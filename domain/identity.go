@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"crypto/sha1"
+	"fmt"
+)
+
+// Namespace the ids of users known from an identity provider are derived in,
+// the one RFC 9562 reserves for names that are URLs
+var identityNamespace = [16]byte{
+	0x6b, 0xa7, 0xb8, 0x11, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8,
+}
+
+// A user as an identity provider vouches for them in a verified token
+type Identity struct {
+	Issuer  string
+	Subject string
+	// Names of the roles the provider grants, which need not all be
+	// roles here
+	Roles []string
+}
+
+// UserId gives the same id to the same subject of the same issuer every
+// time, a name-based UUID, so that users need not sign up here first
+func (i *Identity) UserId() UserID {
+	hash := sha1.New()
+	hash.Write(identityNamespace[:])
+	hash.Write([]byte(i.Issuer + "#" + i.Subject))
+	uuid := hash.Sum(nil)[:16]
+
+	// Version 5, RFC 9562 variant
+	uuid[6] = uuid[6]&0x0f | 0x50
+	uuid[8] = uuid[8]&0x3f | 0x80
+
+	return UserID(fmt.Sprintf("%x-%x-%x-%x-%x", uuid[0:4], uuid[4:6], uuid[6:8], uuid[8:10], uuid[10:16]))
+}
+
+// Role gives the most capable of the roles granted that is a role here, or
+// the default role when none is
+func (i *Identity) Role() Role {
+	var best Role
+	for _, name := range i.Roles {
+		role, err := ParseRole(name)
+		if err != nil {
+			continue
+		}
+		if best == "" || role.AtLeast(best) {
+			best = role
+		}
+	}
+
+	if best == "" {
+		return DefaultRole
+	}
+	return best
+}
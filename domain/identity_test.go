@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdentityUserId(t *testing.T) {
+	alice := &Identity{Issuer: "https://sso.example.com", Subject: "alice"}
+
+	userId := alice.UserId()
+	assert.Equal(t, UserID("105c07a2-65f4-5703-8187-c1d0f0a3d659"), userId)
+	assert.True(t, isUUID(userId.String()))
+
+	// The same subject of another issuer is someone else
+	other := &Identity{Issuer: "https://other.example.com", Subject: "alice"}
+	assert.Equal(t, UserID("e0b0ba08-6057-5d00-8592-094318d7e3b9"), other.UserId())
+}
+
+func TestIdentityRole(t *testing.T) {
+	testTable := map[string]struct {
+		roles    []string
+		expected Role
+	}{
+		"None":        {roles: nil, expected: DefaultRole},
+		"Viewer":      {roles: []string{"viewer"}, expected: RoleViewer},
+		"Highest":     {roles: []string{"admin", "viewer", "editor"}, expected: RoleAdmin},
+		"SkipUnknown": {roles: []string{"offline_access", "viewer"}, expected: RoleViewer},
+		"AllUnknown":  {roles: []string{"offline_access", "Admin"}, expected: DefaultRole},
+	}
+
+	for n, tt := range testTable {
+		t.Run(n, func(t *testing.T) {
+			identity := &Identity{Issuer: "https://sso.example.com", Subject: "alice", Roles: tt.roles}
+			assert.Equal(t, tt.expected, identity.Role())
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+// Identifier of a label, a UUID in its canonical lowercase form
+type LabelID string
+
+// ParseLabelID accepts a UUID written as 8-4-4-4-12 hex digits in either case
+func ParseLabelID(s string) (LabelID, error) {
+	if !isUUID(s) {
+		return "", fmt.Errorf("%w: '%s' is not a UUID", customError.InvalidLabelId, s)
+	}
+	return LabelID(strings.ToLower(s)), nil
+}
+
+func (id LabelID) String() string {
+	return string(id)
+}
+
+// Label groups tasks, such as "backend" or "bug". Every user has labels of
+// their own, whose names are unique among them
+type Label struct {
+	Id        LabelID
+	OwnerId   UserID
+	Name      string
+	Color     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Only non-nil fields are changed
+type LabelPatch struct {
+	Name  *string
+	Color *string
+}
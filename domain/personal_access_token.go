@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+// Identifier of a personal access token, a UUID in its canonical lowercase form
+type PersonalAccessTokenID string
+
+// ParsePersonalAccessTokenID accepts a UUID written as 8-4-4-4-12 hex digits in either case
+func ParsePersonalAccessTokenID(s string) (PersonalAccessTokenID, error) {
+	if !isUUID(s) {
+		return "", fmt.Errorf("%w: '%s' is not a UUID", customError.InvalidTokenId, s)
+	}
+	return PersonalAccessTokenID(strings.ToLower(s)), nil
+}
+
+func (id PersonalAccessTokenID) String() string {
+	return string(id)
+}
+
+// What a personal access token may be used for
+type TokenScope string
+
+const (
+	ScopeTasksRead TokenScope = "tasks:read"
+	// Also grants tasks:read, since writes answer with what they wrote
+	ScopeTasksWrite TokenScope = "tasks:write"
+)
+
+// Personal access tokens all start with this, which tells them apart from
+// access tokens at a glance and makes them easy to spot when leaked
+const PersonalAccessTokenPrefix = "pat_"
+
+// A long-lived credential a user mints for scripts and bots. Only a hash of
+// the token is kept, as it is shown once when minted and never again
+type PersonalAccessToken struct {
+	Id         PersonalAccessTokenID
+	UserId     UserID
+	Name       string
+	Hash       string
+	Scopes     []TokenScope
+	ExpiresAt  time.Time
+	LastUsedAt *time.Time
+	CreatedAt  time.Time
+}
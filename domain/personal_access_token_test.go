@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActorHasScope(t *testing.T) {
+	testTable := map[string]struct {
+		scopes   []TokenScope
+		scope    TokenScope
+		expected bool
+	}{
+		"Session":         {scopes: nil, scope: ScopeTasksWrite, expected: true},
+		"Granted":         {scopes: []TokenScope{ScopeTasksRead}, scope: ScopeTasksRead, expected: true},
+		"WriteGrantsRead": {scopes: []TokenScope{ScopeTasksWrite}, scope: ScopeTasksRead, expected: true},
+		"ReadOnly":        {scopes: []TokenScope{ScopeTasksRead}, scope: ScopeTasksWrite, expected: false},
+		"NoScopes":        {scopes: []TokenScope{}, scope: ScopeTasksRead, expected: false},
+	}
+
+	for n, tt := range testTable {
+		t.Run(n, func(t *testing.T) {
+			actor := &Actor{UserId: "7c9e6679-7425-40de-944b-e07fc1f90ae7", Role: RoleEditor, Scopes: tt.scopes}
+			assert.Equal(t, tt.expected, actor.HasScope(tt.scope))
+		})
+	}
+}
@@ -1,8 +1,51 @@
 package domain
 
+import "time"
+
 type Task struct {
-	Id          string
+	Id TaskID
+	// The user the task belongs to, stamped when it is added. Tasks are
+	// only ever read back by their owner, so it is not filled in on reads
+	OwnerId     UserID
 	Title       string
 	Description string
-	Status      bool
+	State       TaskState
+	Priority    TaskPriority
+	DueAt       *time.Time
+	// Expected effort in hours, if estimated
+	EstimateHours *int
+	ParentId      *TaskID
+	Labels        []*Label
+	// Tasks this one waits on. Only their ids are read when adding a task
+	BlockedBy []*TaskBlocker
+	Progress  TaskProgress
+	// Items of the task's checklist, and how many of them are done
+	ChecklistProgress ChecklistProgress
+	// Filled in only when the task is read along with its subtree
+	Subtasks    []*Task
+	Version     int
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	CompletedAt *time.Time
+	DeletedAt   *time.Time
+}
+
+// Partial update of a task, where nil fields are left unchanged
+type TaskPatch struct {
+	Title       *string
+	Description *string
+	State       *TaskState
+	Priority    *TaskPriority
+	DueAt       *time.Time
+	// Removes the due date, in which case DueAt is ignored
+	ClearDueAt    bool
+	EstimateHours *int
+	// Removes the estimate, in which case EstimateHours is ignored
+	ClearEstimate bool
+	ParentId      *TaskID
+	// Makes the task top-level, in which case ParentId is ignored
+	ClearParent bool
+	// Completes the task even while tasks blocking it are open
+	Force   bool
+	Version int
 }
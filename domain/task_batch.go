@@ -0,0 +1,22 @@
+package domain
+
+// Largest number of tasks a single batch request may carry
+const MaxTaskBatchSize = 100
+
+type TaskBatchUpdate struct {
+	Id    TaskID
+	Patch *TaskPatch
+}
+
+type TaskBatchDelete struct {
+	Id       TaskID
+	Version  int
+	Subtasks SubtaskMode
+}
+
+// Outcome of one item of a batch write, kept in request order.
+// Err is set instead of Task when the item was not written
+type TaskBatchResult struct {
+	Task *Task
+	Err  error
+}
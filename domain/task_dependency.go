@@ -0,0 +1,72 @@
+package domain
+
+// TaskDependency says that the blocker has to be finished before the
+// blocked task can be
+type TaskDependency struct {
+	BlockerId TaskID
+	BlockedId TaskID
+}
+
+// A task that another task waits on, as listed on the waiting task
+type TaskBlocker struct {
+	Id    TaskID
+	Title string
+	State TaskState
+}
+
+// Open reports whether the blocker still holds up the tasks waiting on it.
+// An archived task is as finished as a done one
+func (b *TaskBlocker) Open() bool {
+	return b.State != TaskStateDone && b.State != TaskStateArchived
+}
+
+// Blocked reports whether any task this one waits on is still open
+func (t *Task) Blocked() bool {
+	return len(t.OpenBlockers()) > 0
+}
+
+// OpenBlockers lists the ids of the tasks this one still waits on
+func (t *Task) OpenBlockers() []TaskID {
+	var open []TaskID
+	for _, blocker := range t.BlockedBy {
+		if blocker.Open() {
+			open = append(open, blocker.Id)
+		}
+	}
+	return open
+}
+
+// TaskDependencyGraph follows dependencies from blockers to the tasks they block
+type TaskDependencyGraph struct {
+	blocks map[TaskID][]TaskID
+}
+
+func NewTaskDependencyGraph(dependencies []*TaskDependency) *TaskDependencyGraph {
+	graph := &TaskDependencyGraph{blocks: map[TaskID][]TaskID{}}
+	for _, dependency := range dependencies {
+		graph.blocks[dependency.BlockerId] = append(graph.blocks[dependency.BlockerId], dependency.BlockedId)
+	}
+	return graph
+}
+
+// Reaches reports whether from blocks to, directly or through other tasks.
+// A task reaches itself
+func (g *TaskDependencyGraph) Reaches(from, to TaskID) bool {
+	visited := map[TaskID]bool{from: true}
+	queue := []TaskID{from}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current == to {
+			return true
+		}
+
+		for _, next := range g.blocks[current] {
+			if !visited[next] {
+				visited[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return false
+}
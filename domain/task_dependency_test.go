@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaskDependencyGraphReaches(t *testing.T) {
+	a := TaskID("6a30b9b0-18bf-47b4-bd23-d72726864def")
+	b := TaskID("3e440171-0921-4c88-a7ec-13f4cdab0d69")
+	c := TaskID("f299e7ed-a22a-4494-b59e-21bb91fdae3b")
+	d := TaskID("4d758d63-5c4f-4bef-9a80-d5837c324a07")
+
+	// a blocks b, b blocks c, and d blocks c
+	graph := NewTaskDependencyGraph([]*TaskDependency{
+		{BlockerId: a, BlockedId: b},
+		{BlockerId: b, BlockedId: c},
+		{BlockerId: d, BlockedId: c},
+	})
+
+	assert.True(t, graph.Reaches(a, c))
+	assert.True(t, graph.Reaches(b, c))
+	assert.True(t, graph.Reaches(d, d))
+	assert.False(t, graph.Reaches(c, a))
+	assert.False(t, graph.Reaches(a, d))
+}
+
+func TestTaskBlocked(t *testing.T) {
+	done := &TaskBlocker{Id: "6a30b9b0-18bf-47b4-bd23-d72726864def", State: TaskStateDone}
+	archived := &TaskBlocker{Id: "3e440171-0921-4c88-a7ec-13f4cdab0d69", State: TaskStateArchived}
+	inReview := &TaskBlocker{Id: "f299e7ed-a22a-4494-b59e-21bb91fdae3b", State: TaskStateInReview}
+
+	finished := &Task{BlockedBy: []*TaskBlocker{done, archived}}
+	assert.False(t, finished.Blocked())
+	assert.Empty(t, finished.OpenBlockers())
+
+	waiting := &Task{BlockedBy: []*TaskBlocker{done, inReview}}
+	assert.True(t, waiting.Blocked())
+	assert.Equal(t, []TaskID{inReview.Id}, waiting.OpenBlockers())
+
+	assert.False(t, (&Task{}).Blocked())
+}
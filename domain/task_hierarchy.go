@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"fmt"
+
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+// Most levels a task hierarchy may have, counting the top-level task
+const MaxTaskDepth = 5
+
+// Rollup of the direct subtasks of a task. Subtasks in the trash are not counted
+type TaskProgress struct {
+	Completed int
+	Total     int
+}
+
+// What becomes of the subtasks of a task that is deleted
+type SubtaskMode string
+
+const (
+	// Refuse to delete a task that still has subtasks
+	SubtaskModeNone SubtaskMode = ""
+	// Delete the whole subtree along with the task
+	SubtaskModeCascade SubtaskMode = "cascade"
+	// Move the subtasks up to the parent of the deleted task
+	SubtaskModeReparent SubtaskMode = "reparent"
+)
+
+func ParseSubtaskMode(s string) (SubtaskMode, error) {
+	switch mode := SubtaskMode(s); mode {
+	case SubtaskModeNone, SubtaskModeCascade, SubtaskModeReparent:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("%w: '%s'", customError.ErrUnknownSubtaskMode, s)
+	}
+}
+
+// NewTaskTree hangs every task under its parent and returns the one with
+// the root id, or nil if it is missing. Tasks keep the order they are given in
+func NewTaskTree(root TaskID, tasks []*Task) *Task {
+	byId := make(map[TaskID]*Task, len(tasks))
+	for _, task := range tasks {
+		byId[task.Id] = task
+	}
+
+	for _, task := range tasks {
+		if task.Id == root || task.ParentId == nil {
+			continue
+		}
+		if parent, ok := byId[*task.ParentId]; ok {
+			parent.Subtasks = append(parent.Subtasks, task)
+		}
+	}
+
+	return byId[root]
+}
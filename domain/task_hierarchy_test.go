@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+func TestNewTaskTree(t *testing.T) {
+	root := TaskID("6a30b9b0-18bf-47b4-bd23-d72726864def")
+	child := TaskID("3e440171-0921-4c88-a7ec-13f4cdab0d69")
+	grandchild := TaskID("f299e7ed-a22a-4494-b59e-21bb91fdae3b")
+	sibling := TaskID("4d758d63-5c4f-4bef-9a80-d5837c324a07")
+	outside := TaskID("0b9b4d6e-5c1f-4b0e-9a43-2f6f0c3d8e71")
+
+	tasks := []*Task{
+		{Id: root, ParentId: &outside},
+		{Id: child, ParentId: &root},
+		{Id: grandchild, ParentId: &child},
+		{Id: sibling, ParentId: &root},
+	}
+
+	tree := NewTaskTree(root, tasks)
+
+	assert.Equal(t, root, tree.Id)
+	assert.Len(t, tree.Subtasks, 2)
+	assert.Equal(t, child, tree.Subtasks[0].Id)
+	assert.Equal(t, sibling, tree.Subtasks[1].Id)
+	assert.Len(t, tree.Subtasks[0].Subtasks, 1)
+	assert.Equal(t, grandchild, tree.Subtasks[0].Subtasks[0].Id)
+	assert.Empty(t, tree.Subtasks[1].Subtasks)
+
+	assert.Nil(t, NewTaskTree(outside, tasks))
+}
+
+func TestParseSubtaskMode(t *testing.T) {
+	for _, s := range []string{"", "cascade", "reparent"} {
+		mode, err := ParseSubtaskMode(s)
+		assert.NoError(t, err)
+		assert.Equal(t, SubtaskMode(s), mode)
+	}
+
+	_, err := ParseSubtaskMode("orphan")
+	assert.ErrorIs(t, err, customError.ErrUnknownSubtaskMode)
+}
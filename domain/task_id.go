@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+// Identifier of a task, a UUID in its canonical lowercase form
+type TaskID string
+
+// ParseTaskID accepts a UUID written as 8-4-4-4-12 hex digits in either case
+func ParseTaskID(s string) (TaskID, error) {
+	if !isUUID(s) {
+		return "", fmt.Errorf("%w: '%s' is not a UUID", customError.InvalidTaskId, s)
+	}
+	return TaskID(strings.ToLower(s)), nil
+}
+
+func (id TaskID) String() string {
+	return string(id)
+}
+
+func isUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+
+	for i := 0; i < len(s); i++ {
+		switch i {
+		case 8, 13, 18, 23:
+			if s[i] != '-' {
+				return false
+			}
+		default:
+			if !isHexDigit(s[i]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func isHexDigit(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+// How urgent a task is, from P0 (most urgent) to P4. The names sort in
+// order of urgency, so they can be compared as they are
+type TaskPriority string
+
+const (
+	TaskPriorityP0 TaskPriority = "P0"
+	TaskPriorityP1 TaskPriority = "P1"
+	TaskPriorityP2 TaskPriority = "P2"
+	TaskPriorityP3 TaskPriority = "P3"
+	TaskPriorityP4 TaskPriority = "P4"
+)
+
+// Priority of tasks created without one
+const DefaultTaskPriority = TaskPriorityP2
+
+var TaskPriorities = []TaskPriority{
+	TaskPriorityP0, TaskPriorityP1, TaskPriorityP2, TaskPriorityP3, TaskPriorityP4,
+}
+
+func ParseTaskPriority(s string) (TaskPriority, error) {
+	priority := TaskPriority(s)
+	if !slices.Contains(TaskPriorities, priority) {
+		return "", fmt.Errorf("%w: '%s'", customError.ErrUnknownTaskPriority, s)
+	}
+	return priority, nil
+}
+
+func (p TaskPriority) String() string {
+	return string(p)
+}
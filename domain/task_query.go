@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"strconv"
+	"time"
+)
+
+type TaskSortField string
+
+const (
+	TaskSortById        TaskSortField = "id"
+	TaskSortByTitle     TaskSortField = "title"
+	TaskSortByStatus    TaskSortField = "status"
+	TaskSortByState     TaskSortField = "state"
+	TaskSortByPriority  TaskSortField = "priority"
+	TaskSortByDueAt     TaskSortField = "due_at"
+	TaskSortByCreatedAt TaskSortField = "created_at"
+	TaskSortByUpdatedAt TaskSortField = "updated_at"
+)
+
+// How tasks are matched against several labels
+type TaskLabelMode string
+
+const (
+	// Tasks with at least one of the labels
+	TaskLabelModeAny TaskLabelMode = "any"
+	// Tasks with every one of the labels
+	TaskLabelModeAll TaskLabelMode = "all"
+)
+
+type TaskListQuery struct {
+	Limit     int
+	Offset    int
+	Status    *bool
+	State     *TaskState
+	Title     string
+	DueBefore *time.Time
+	DueAfter  *time.Time
+	// Only tasks that are, or with false are not, past due and unfinished
+	Overdue *bool
+	// Names of the labels tasks are filtered by
+	Labels    []string
+	LabelMode TaskLabelMode
+	// Only the direct subtasks of this task
+	ParentId  *TaskID
+	SortField TaskSortField
+	SortDesc  bool
+	After     *TaskCursor
+}
+
+type TaskList struct {
+	Tasks        []*Task
+	Total        int
+	LastModified time.Time
+	NextCursor   *TaskCursor
+}
+
+// Position in a sorted task list, identified by the sort key
+// and id of the last task seen
+type TaskCursor struct {
+	SortField TaskSortField
+	SortDesc  bool
+	SortValue string
+	Id        string
+}
+
+func NewTaskCursor(query *TaskListQuery, last *Task) *TaskCursor {
+	return &TaskCursor{
+		SortField: query.SortField,
+		SortDesc:  query.SortDesc,
+		SortValue: query.SortField.ValueOf(last),
+		Id:        last.Id.String(),
+	}
+}
+
+func (f TaskSortField) ValueOf(task *Task) string {
+	switch f {
+	case TaskSortByTitle:
+		return task.Title
+	case TaskSortByStatus:
+		return strconv.FormatBool(task.State.Done())
+	case TaskSortByState:
+		return task.State.String()
+	case TaskSortByPriority:
+		return task.Priority.String()
+	case TaskSortByDueAt:
+		// Tasks without a due date sort after every dated one
+		if task.DueAt == nil {
+			return "infinity"
+		}
+		return task.DueAt.Format(time.RFC3339Nano)
+	case TaskSortByCreatedAt:
+		return task.CreatedAt.Format(time.RFC3339Nano)
+	case TaskSortByUpdatedAt:
+		return task.UpdatedAt.Format(time.RFC3339Nano)
+	default:
+		return task.Id.String()
+	}
+}
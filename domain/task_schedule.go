@@ -0,0 +1,178 @@
+package domain
+
+import (
+	"sort"
+
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+// Longest a single task may be estimated to take, in hours
+const MaxTaskEstimateHours = 1000
+
+// TaskSchedule plans tasks around their dependencies, as if each task
+// started as soon as every task it waits on had finished. Times are in
+// hours from the start of the plan, and a task without an estimate takes
+// no time
+type TaskSchedule struct {
+	// Every task comes after the tasks it waits on
+	Items []*TaskScheduleItem
+	// The chain of tasks that decides how long the plan takes, in order
+	CriticalPath []TaskID
+	Duration     int
+}
+
+type TaskScheduleItem struct {
+	Task *Task
+	// Tasks of the plan this one waits on
+	DependsOn      []TaskID
+	EarliestStart  int
+	EarliestFinish int
+	// As late as the task can start and finish without delaying the plan
+	LatestStart  int
+	LatestFinish int
+}
+
+// Slack is how long the task can slip without delaying the plan
+func (i *TaskScheduleItem) Slack() int {
+	return i.LatestStart - i.EarliestStart
+}
+
+// Critical reports whether any delay to the task delays the plan
+func (i *TaskScheduleItem) Critical() bool {
+	return i.Slack() == 0
+}
+
+// NewTaskSchedule plans tasks in the order they are given, except that
+// tasks are moved after the tasks they wait on. Dependencies on tasks that
+// are not given are left out, and customError.ErrCycle is returned if the
+// dependencies loop
+func NewTaskSchedule(tasks []*Task, dependencies []*TaskDependency) (*TaskSchedule, error) {
+	index := make(map[TaskID]int, len(tasks))
+	for i, task := range tasks {
+		index[task.Id] = i
+	}
+
+	predecessors := make([][]int, len(tasks))
+	successors := make([][]int, len(tasks))
+	for _, dependency := range dependencies {
+		blocker, ok := index[dependency.BlockerId]
+		if !ok {
+			continue
+		}
+		blocked, ok := index[dependency.BlockedId]
+		if !ok {
+			continue
+		}
+		predecessors[blocked] = append(predecessors[blocked], blocker)
+		successors[blocker] = append(successors[blocker], blocked)
+	}
+
+	order, err := topologicalOrder(predecessors, successors)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*TaskScheduleItem, len(tasks))
+	schedule := &TaskSchedule{}
+
+	// Forward pass: a task starts once the last of its blockers finishes
+	for _, i := range order {
+		item := &TaskScheduleItem{Task: tasks[i]}
+		sort.Ints(predecessors[i])
+		for _, p := range predecessors[i] {
+			item.DependsOn = append(item.DependsOn, tasks[p].Id)
+			item.EarliestStart = max(item.EarliestStart, items[p].EarliestFinish)
+		}
+		item.EarliestFinish = item.EarliestStart + tasks[i].estimate()
+
+		items[i] = item
+		schedule.Items = append(schedule.Items, item)
+		schedule.Duration = max(schedule.Duration, item.EarliestFinish)
+	}
+
+	// Backward pass: a task must finish before the first of the tasks
+	// waiting on it has to start
+	for k := len(order) - 1; k >= 0; k-- {
+		i := order[k]
+		item := items[i]
+		item.LatestFinish = schedule.Duration
+		for _, s := range successors[i] {
+			item.LatestFinish = min(item.LatestFinish, items[s].LatestStart)
+		}
+		item.LatestStart = item.LatestFinish - tasks[i].estimate()
+	}
+
+	schedule.CriticalPath = criticalPath(schedule, items, predecessors, order)
+	return schedule, nil
+}
+
+// Order the tasks so that each comes after its predecessors. Of the tasks
+// ready at any point, the one given first goes first
+func topologicalOrder(predecessors, successors [][]int) ([]int, error) {
+	waiting := make([]int, len(predecessors))
+	var ready []int
+	for i := range predecessors {
+		waiting[i] = len(predecessors[i])
+		if waiting[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	order := make([]int, 0, len(predecessors))
+	for len(ready) > 0 {
+		i := ready[0]
+		ready = ready[1:]
+		order = append(order, i)
+
+		for _, s := range successors[i] {
+			waiting[s]--
+			if waiting[s] == 0 {
+				at := sort.SearchInts(ready, s)
+				ready = append(ready[:at], append([]int{s}, ready[at:]...)...)
+			}
+		}
+	}
+
+	if len(order) < len(predecessors) {
+		return nil, customError.ErrCycle
+	}
+	return order, nil
+}
+
+// Walk back from the task that finishes last through the blockers that
+// hold up each task until the start of the plan
+func criticalPath(schedule *TaskSchedule, items []*TaskScheduleItem, predecessors [][]int, order []int) []TaskID {
+	last := -1
+	for _, i := range order {
+		if items[i].EarliestFinish == schedule.Duration {
+			last = i
+		}
+	}
+	if last < 0 {
+		return nil
+	}
+
+	path := []TaskID{items[last].Task.Id}
+	for current := last; ; {
+		next := -1
+		for _, p := range predecessors[current] {
+			if items[p].EarliestFinish == items[current].EarliestStart && items[p].Critical() {
+				next = p
+			}
+		}
+		if next < 0 {
+			break
+		}
+		path = append([]TaskID{items[next].Task.Id}, path...)
+		current = next
+	}
+	return path
+}
+
+// Hours the task is expected to take
+func (t *Task) estimate() int {
+	if t.EstimateHours == nil {
+		return 0
+	}
+	return *t.EstimateHours
+}
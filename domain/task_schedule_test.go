@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+func hours(h int) *int {
+	return &h
+}
+
+func TestNewTaskSchedule(t *testing.T) {
+	design := &Task{Id: "6a30b9b0-18bf-47b4-bd23-d72726864def", EstimateHours: hours(3)}
+	backend := &Task{Id: "3e440171-0921-4c88-a7ec-13f4cdab0d69", EstimateHours: hours(5)}
+	frontend := &Task{Id: "f299e7ed-a22a-4494-b59e-21bb91fdae3b", EstimateHours: hours(2)}
+	release := &Task{Id: "4d758d63-5c4f-4bef-9a80-d5837c324a07", EstimateHours: hours(1)}
+	docs := &Task{Id: "0b9b4d6e-5c1f-4b0e-9a43-2f6f0c3d8e71"}
+
+	// Given in an order that has to be rearranged: design comes before
+	// backend and frontend, which both come before the release
+	schedule, err := NewTaskSchedule(
+		[]*Task{release, frontend, backend, design, docs},
+		[]*TaskDependency{
+			{BlockerId: design.Id, BlockedId: backend.Id},
+			{BlockerId: design.Id, BlockedId: frontend.Id},
+			{BlockerId: backend.Id, BlockedId: release.Id},
+			{BlockerId: frontend.Id, BlockedId: release.Id},
+			// Waits on a task outside the plan, which is left out
+			{BlockerId: "c6a1f3e2-7d4b-4e8a-b1f9-3a2d5e6c7b80", BlockedId: docs.Id},
+		},
+	)
+	require.NoError(t, err)
+
+	var order []TaskID
+	for _, item := range schedule.Items {
+		order = append(order, item.Task.Id)
+	}
+	assert.Equal(t, []TaskID{design.Id, frontend.Id, backend.Id, release.Id, docs.Id}, order)
+
+	assert.Equal(t, 9, schedule.Duration)
+	assert.Equal(t, []TaskID{design.Id, backend.Id, release.Id}, schedule.CriticalPath)
+
+	byId := map[TaskID]*TaskScheduleItem{}
+	for _, item := range schedule.Items {
+		byId[item.Task.Id] = item
+	}
+
+	assert.Equal(t, []TaskID{frontend.Id, backend.Id}, byId[release.Id].DependsOn)
+	assert.Equal(t, 8, byId[release.Id].EarliestStart)
+	assert.Equal(t, 9, byId[release.Id].EarliestFinish)
+
+	// The frontend can slip until the backend is done
+	assert.Equal(t, 3, byId[frontend.Id].EarliestStart)
+	assert.Equal(t, 6, byId[frontend.Id].LatestStart)
+	assert.Equal(t, 3, byId[frontend.Id].Slack())
+	assert.False(t, byId[frontend.Id].Critical())
+
+	assert.True(t, byId[backend.Id].Critical())
+
+	// Unestimated and unconnected, so it can happen any time
+	assert.Empty(t, byId[docs.Id].DependsOn)
+	assert.Equal(t, 0, byId[docs.Id].EarliestFinish)
+	assert.Equal(t, 9, byId[docs.Id].Slack())
+}
+
+func TestNewTaskScheduleEmpty(t *testing.T) {
+	schedule, err := NewTaskSchedule(nil, nil)
+	require.NoError(t, err)
+
+	assert.Empty(t, schedule.Items)
+	assert.Empty(t, schedule.CriticalPath)
+	assert.Equal(t, 0, schedule.Duration)
+}
+
+func TestNewTaskScheduleCycle(t *testing.T) {
+	a := &Task{Id: "6a30b9b0-18bf-47b4-bd23-d72726864def"}
+	b := &Task{Id: "3e440171-0921-4c88-a7ec-13f4cdab0d69"}
+
+	_, err := NewTaskSchedule([]*Task{a, b}, []*TaskDependency{
+		{BlockerId: a.Id, BlockedId: b.Id},
+		{BlockerId: b.Id, BlockedId: a.Id},
+	})
+	assert.ErrorIs(t, err, customError.ErrCycle)
+}
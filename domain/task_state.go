@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+// Where a task is in its workflow
+type TaskState string
+
+const (
+	TaskStateTodo       TaskState = "todo"
+	TaskStateInProgress TaskState = "in_progress"
+	TaskStateBlocked    TaskState = "blocked"
+	TaskStateInReview   TaskState = "in_review"
+	TaskStateDone       TaskState = "done"
+	TaskStateArchived   TaskState = "archived"
+)
+
+// Every state a task can be in, in workflow order
+var TaskStates = []TaskState{
+	TaskStateTodo, TaskStateInProgress, TaskStateBlocked, TaskStateInReview, TaskStateDone, TaskStateArchived,
+}
+
+func ParseTaskState(s string) (TaskState, error) {
+	state := TaskState(s)
+	if !slices.Contains(TaskStates, state) {
+		return "", fmt.Errorf("%w: '%s'", customError.ErrUnknownTaskState, s)
+	}
+	return state, nil
+}
+
+func (s TaskState) String() string {
+	return string(s)
+}
+
+// Done reports whether the task is finished, which is all the
+// boolean status of earlier API versions could express
+func (s TaskState) Done() bool {
+	return s == TaskStateDone
+}
+
+// TaskWorkflow lists the states a task may move to from each state.
+// Staying in the same state is always allowed
+type TaskWorkflow struct {
+	transitions map[TaskState][]TaskState
+}
+
+// NewTaskWorkflow builds a workflow from the allowed moves per state.
+// States without an entry are final
+func NewTaskWorkflow(transitions map[TaskState][]TaskState) (*TaskWorkflow, error) {
+	workflow := &TaskWorkflow{transitions: map[TaskState][]TaskState{}}
+	for from, nexts := range transitions {
+		if _, err := ParseTaskState(from.String()); err != nil {
+			return nil, err
+		}
+
+		for _, next := range nexts {
+			if _, err := ParseTaskState(next.String()); err != nil {
+				return nil, err
+			}
+			if next != from && !slices.Contains(workflow.transitions[from], next) {
+				workflow.transitions[from] = append(workflow.transitions[from], next)
+			}
+		}
+	}
+
+	// Keep the moves in workflow order so that they are listed predictably
+	for _, nexts := range workflow.transitions {
+		slices.SortFunc(nexts, func(a, b TaskState) int {
+			return slices.Index(TaskStates, a) - slices.Index(TaskStates, b)
+		})
+	}
+
+	return workflow, nil
+}
+
+// The workflow used unless another one is configured
+func DefaultTaskWorkflow() *TaskWorkflow {
+	workflow, _ := NewTaskWorkflow(map[TaskState][]TaskState{
+		TaskStateTodo:       {TaskStateInProgress, TaskStateBlocked, TaskStateDone, TaskStateArchived},
+		TaskStateInProgress: {TaskStateTodo, TaskStateBlocked, TaskStateInReview, TaskStateDone},
+		TaskStateBlocked:    {TaskStateTodo, TaskStateInProgress},
+		TaskStateInReview:   {TaskStateInProgress, TaskStateDone},
+		TaskStateDone:       {TaskStateTodo, TaskStateArchived},
+		TaskStateArchived:   {TaskStateTodo},
+	})
+	return workflow
+}
+
+// Next lists the states a task in from may move to
+func (w *TaskWorkflow) Next(from TaskState) []TaskState {
+	return slices.Clone(w.transitions[from])
+}
+
+// Check returns a TransitionError if a task may not move from one state to the other
+func (w *TaskWorkflow) Check(from, to TaskState) error {
+	if from == to || slices.Contains(w.transitions[from], to) {
+		return nil
+	}
+
+	allowed := make([]string, 0, len(w.transitions[from]))
+	for _, next := range w.transitions[from] {
+		allowed = append(allowed, next.String())
+	}
+
+	return &customError.TransitionError{From: from.String(), To: to.String(), Allowed: allowed}
+}
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+func TestTaskWorkflowCheck(t *testing.T) {
+	workflow := DefaultTaskWorkflow()
+
+	testTable := map[string]struct {
+		from, to TaskState
+		allowed  []string
+	}{
+		"Allowed":   {from: TaskStateTodo, to: TaskStateInProgress},
+		"Reopen":    {from: TaskStateDone, to: TaskStateTodo},
+		"Unchanged": {from: TaskStateBlocked, to: TaskStateBlocked},
+		"NotAllowed": {
+			from: TaskStateBlocked, to: TaskStateDone,
+			allowed: []string{"todo", "in_progress"},
+		},
+	}
+
+	for n, tt := range testTable {
+		t.Run(n, func(t *testing.T) {
+			err := workflow.Check(tt.from, tt.to)
+			if tt.allowed == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			var transitionErr *customError.TransitionError
+			assert.True(t, errors.As(err, &transitionErr))
+			assert.ErrorIs(t, err, customError.ErrTaskTransitionNotAllowed)
+			assert.Equal(t, tt.allowed, transitionErr.Allowed)
+		})
+	}
+}
+
+func TestNewTaskWorkflow(t *testing.T) {
+	workflow, err := NewTaskWorkflow(map[TaskState][]TaskState{
+		TaskStateTodo: {TaskStateDone, TaskStateTodo, TaskStateInProgress, TaskStateDone},
+		TaskStateDone: {},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []TaskState{TaskStateInProgress, TaskStateDone}, workflow.Next(TaskStateTodo))
+	assert.Empty(t, workflow.Next(TaskStateDone))
+	assert.Empty(t, workflow.Next(TaskStateBlocked))
+}
+
+func TestNewTaskWorkflowUnknownState(t *testing.T) {
+	workflow, err := NewTaskWorkflow(map[TaskState][]TaskState{
+		TaskStateTodo: {"finished"},
+	})
+
+	assert.Nil(t, workflow)
+	assert.ErrorIs(t, err, customError.ErrUnknownTaskState)
+	assert.EqualError(t, err, "task state is unknown: 'finished'")
+}
@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+// Identifier of a user, a UUID in its canonical lowercase form
+type UserID string
+
+// ParseUserID accepts a UUID written as 8-4-4-4-12 hex digits in either case
+func ParseUserID(s string) (UserID, error) {
+	if !isUUID(s) {
+		return "", fmt.Errorf("%w: '%s' is not a UUID", customError.InvalidUserId, s)
+	}
+	return UserID(strings.ToLower(s)), nil
+}
+
+func (id UserID) String() string {
+	return string(id)
+}
+
+// What a user may do with tasks. Every role may do everything the ones
+// before it may
+type Role string
+
+const (
+	// Reads tasks only
+	RoleViewer Role = "viewer"
+	// Also writes tasks, which is what users sign up as
+	RoleEditor Role = "editor"
+	// Also removes tasks for good and deletes them in bulk
+	RoleAdmin Role = "admin"
+)
+
+const DefaultRole = RoleEditor
+
+var roles = []Role{RoleViewer, RoleEditor, RoleAdmin}
+
+// ParseRole accepts the name of a role in lowercase
+func ParseRole(s string) (Role, error) {
+	for _, role := range roles {
+		if string(role) == s {
+			return role, nil
+		}
+	}
+	return "", fmt.Errorf("%w: '%s' is not a role", customError.InvalidRole, s)
+}
+
+// AtLeast reports whether r may do everything other may. Neither is
+// anything if it is not a role
+func (r Role) AtLeast(other Role) bool {
+	return slices.Contains(roles, other) && slices.Index(roles, r) >= slices.Index(roles, other)
+}
+
+func (r Role) String() string {
+	return string(r)
+}
+
+// Someone who can sign in. Emails are kept in lowercase. Users of an
+// identity provider have neither email nor password here
+type User struct {
+	Id           UserID
+	Email        string
+	PasswordHash string
+	Role         Role
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// NormalizeEmail gives the form emails are stored and looked up in, so that
+// one address cannot be registered twice by changing its case
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// A refresh token as stored, which is only ever its hash. Tokens replacing
+// each other share a family in storage, so that all of them can be revoked
+// at once
+type RefreshToken struct {
+	UserId    UserID
+	Hash      string
+	ExpiresAt time.Time
+}
+
+// What a client receives on signing in or refreshing its session
+type Session struct {
+	User                  *User
+	AccessToken           string
+	AccessTokenExpiresAt  time.Time
+	RefreshToken          string
+	RefreshTokenExpiresAt time.Time
+}
+
+// Who a request is made by, either a user signed in with an access token or
+// a personal access token, or a caller the gateway in front of the API
+// vouches for
+type Actor struct {
+	UserId UserID
+	Role   Role
+	// Scopes of the personal access token the request was made with.
+	// Nil for anyone else, who is limited by their role alone
+	Scopes []TokenScope
+}
+
+// HasScope reports whether the actor may make requests that need scope
+func (a *Actor) HasScope(scope TokenScope) bool {
+	if a.Scopes == nil || slices.Contains(a.Scopes, scope) {
+		return true
+	}
+	return scope == ScopeTasksRead && slices.Contains(a.Scopes, ScopeTasksWrite)
+}
+
+type actorContextKey struct{}
+
+// ContextWithActor records who a request is made by
+func ContextWithActor(ctx context.Context, actor *Actor) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, actor)
+}
+
+// ActorFromContext reads who a request is made by, if anyone
+func ActorFromContext(ctx context.Context) (*Actor, bool) {
+	actor, ok := ctx.Value(actorContextKey{}).(*Actor)
+	return actor, ok
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+func TestParseRole(t *testing.T) {
+	testTable := map[string]struct {
+		input    string
+		expected Role
+		err      error
+	}{
+		"Viewer":  {input: "viewer", expected: RoleViewer},
+		"Admin":   {input: "admin", expected: RoleAdmin},
+		"Unknown": {input: "owner", err: customError.InvalidRole},
+		"Case":    {input: "Editor", err: customError.InvalidRole},
+		"Empty":   {input: "", err: customError.InvalidRole},
+	}
+
+	for n, tt := range testTable {
+		t.Run(n, func(t *testing.T) {
+			role, err := ParseRole(tt.input)
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.expected, role)
+		})
+	}
+}
+
+func TestRoleAtLeast(t *testing.T) {
+	testTable := map[string]struct {
+		role, other Role
+		expected    bool
+	}{
+		"Same":         {role: RoleEditor, other: RoleEditor, expected: true},
+		"Higher":       {role: RoleAdmin, other: RoleViewer, expected: true},
+		"Lower":        {role: RoleViewer, other: RoleEditor, expected: false},
+		"UnknownRole":  {role: "owner", other: RoleViewer, expected: false},
+		"UnknownOther": {role: RoleAdmin, other: "owner", expected: false},
+	}
+
+	for n, tt := range testTable {
+		t.Run(n, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.role.AtLeast(tt.other))
+		})
+	}
+}
@@ -0,0 +1,290 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/takumi616/go-restapi/shared/config"
+)
+
+var ErrUnknownKey = errors.New("signing key is unknown")
+
+const (
+	// Least time between two loads of the key set, so that tokens naming
+	// keys nobody has cannot have it loaded on every request
+	jwksMinRefreshInterval = time.Minute
+	// Longest a load of the key set may take
+	jwksLoadTimeout = 10 * time.Second
+	// Largest key set document read
+	maxJWKSSize = 1 << 20
+	// Shortest RSA modulus accepted, in bits
+	minRSAKeySize = 2048
+)
+
+type jsonWebKey struct {
+	KeyType string `json:"kty"`
+	KeyId   string `json:"kid"`
+	Use     string `json:"use"`
+	// RSA
+	Modulus  string `json:"n"`
+	Exponent string `json:"e"`
+	// EC
+	Curve string `json:"crv"`
+	X     string `json:"x"`
+	Y     string `json:"y"`
+}
+
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+// JWKSet keeps the public keys an identity provider signs tokens with,
+// loaded from a JSON Web Key Set file or URL. Keys are loaded again once
+// the cache expires, or sooner when a token names a key not seen yet,
+// which is how a rotation is picked up
+type JWKSet struct {
+	load func(ctx context.Context) ([]byte, error)
+	ttl  time.Duration
+	now  func() time.Time
+
+	mu       sync.Mutex
+	keys     map[string]crypto.PublicKey
+	loadedAt time.Time
+	triedAt  time.Time
+	// Why the last load failed, if it did
+	loadErr error
+	// Closed once the load under way, if any, is done
+	loading chan struct{}
+}
+
+func NewJWKSet(oidcConf *config.OIDCConfig) *JWKSet {
+	set := &JWKSet{ttl: oidcConf.JWKSCacheTTL, now: time.Now}
+
+	if oidcConf.JWKSFile != "" {
+		path := oidcConf.JWKSFile
+		set.load = func(ctx context.Context) ([]byte, error) {
+			return os.ReadFile(path)
+		}
+	} else {
+		client := &http.Client{}
+		url := oidcConf.JWKSURL
+		set.load = func(ctx context.Context) ([]byte, error) {
+			return fetchJWKS(ctx, client, url)
+		}
+	}
+
+	return set
+}
+
+// Key finds the key with the id. Keys past the cache TTL are still used
+// while they are loaded again in the background. Only a key not seen yet
+// has the caller wait for a load, which every caller waiting shares
+func (s *JWKSet) Key(ctx context.Context, id string) (crypto.PublicKey, error) {
+	key, ok, fresh := s.lookup(id)
+	if ok {
+		if !fresh {
+			s.startLoad()
+		}
+		return key, nil
+	}
+
+	// The provider may have rotated to a key published since the last load
+	if done := s.startLoad(); done != nil {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if key, ok := s.keys[id]; ok {
+		return key, nil
+	}
+	if s.keys == nil && s.loadErr != nil {
+		return nil, s.loadErr
+	}
+	return nil, ErrUnknownKey
+}
+
+func (s *JWKSet) lookup(id string) (key crypto.PublicKey, ok bool, fresh bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, ok = s.keys[id]
+	return key, ok, s.now().Before(s.loadedAt.Add(s.ttl))
+}
+
+// Start loading the key set unless a load is under way already, which is
+// joined instead, or the last one was tried too recently, when nil is
+// returned. The load does not belong to any request, so a client going
+// away does not cut it short
+func (s *JWKSet) startLoad() <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.loading != nil {
+		return s.loading
+	}
+
+	now := s.now()
+	if !s.triedAt.IsZero() && now.Before(s.triedAt.Add(jwksMinRefreshInterval)) {
+		return nil
+	}
+	s.triedAt = now
+
+	done := make(chan struct{})
+	s.loading = done
+	go s.runLoad(done)
+	return done
+}
+
+// Load the key set. Keys loaded before stay in use when loading fails
+func (s *JWKSet) runLoad(done chan struct{}) {
+	defer close(done)
+
+	ctx, cancel := context.WithTimeout(context.Background(), jwksLoadTimeout)
+	defer cancel()
+
+	keys, err := s.loadKeys(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.loading = nil
+	s.loadErr = err
+	if err == nil {
+		s.keys = keys
+		s.loadedAt = s.now()
+	}
+}
+
+func (s *JWKSet) loadKeys(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	document, err := s.load(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to load the key set", "err", err)
+		return nil, err
+	}
+
+	keys, err := parseJWKS(document)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to parse the key set", "err", err)
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func fetchJWKS(ctx context.Context, client *http.Client, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("key set responded with status %d", res.StatusCode)
+	}
+
+	return io.ReadAll(io.LimitReader(res.Body, maxJWKSSize))
+}
+
+// Read the signing keys of a key set by their ids. Keys of a type or curve
+// not supported, or for encryption only, are left out
+func parseJWKS(document []byte) (map[string]crypto.PublicKey, error) {
+	var set jsonWebKeySet
+	if err := json.Unmarshal(document, &set); err != nil {
+		return nil, err
+	}
+
+	keys := map[string]crypto.PublicKey{}
+	for _, jwk := range set.Keys {
+		if jwk.KeyId == "" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+
+		var key crypto.PublicKey
+		var err error
+		switch {
+		case jwk.KeyType == "RSA":
+			key, err = parseRSAKey(jwk)
+		case jwk.KeyType == "EC" && jwk.Curve == "P-256":
+			key, err = parseP256Key(jwk)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("key '%s': %w", jwk.KeyId, err)
+		}
+
+		keys[jwk.KeyId] = key
+	}
+
+	return keys, nil
+}
+
+func parseRSAKey(jwk jsonWebKey) (*rsa.PublicKey, error) {
+	modulus, err := base64.RawURLEncoding.DecodeString(jwk.Modulus)
+	if err != nil {
+		return nil, err
+	}
+	exponent, err := base64.RawURLEncoding.DecodeString(jwk.Exponent)
+	if err != nil {
+		return nil, err
+	}
+
+	n := new(big.Int).SetBytes(modulus)
+	e := new(big.Int).SetBytes(exponent)
+	if n.BitLen() < minRSAKeySize {
+		return nil, fmt.Errorf("modulus is shorter than %d bits", minRSAKeySize)
+	}
+	if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+		return nil, errors.New("exponent is out of range")
+	}
+
+	return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+}
+
+func parseP256Key(jwk jsonWebKey) (*ecdsa.PublicKey, error) {
+	x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+	if err != nil {
+		return nil, err
+	}
+	y, err := base64.RawURLEncoding.DecodeString(jwk.Y)
+	if err != nil {
+		return nil, err
+	}
+	if len(x) != 32 || len(y) != 32 {
+		return nil, errors.New("coordinates are not 32 bytes long")
+	}
+
+	point := append(append([]byte{0x04}, x...), y...)
+	if _, err := ecdh.P256().NewPublicKey(point); err != nil {
+		return nil, errors.New("point is not on the curve")
+	}
+
+	return &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+}
@@ -0,0 +1,239 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/takumi616/go-restapi/shared/config"
+)
+
+// A key set served over HTTP, whose document can be swapped as a provider
+// rotating its keys would
+type testJWKSServer struct {
+	*httptest.Server
+	document atomic.Value
+	requests atomic.Int32
+	// Holds responses back until closed, when set
+	delay atomic.Value
+}
+
+func newTestJWKSServer(t *testing.T, document []byte) *testJWKSServer {
+	server := &testJWKSServer{}
+	server.document.Store(document)
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.requests.Add(1)
+		if release, ok := server.delay.Load().(chan struct{}); ok {
+			<-release
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(server.document.Load().([]byte))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestJWKSet(oidcConf *config.OIDCConfig, now *time.Time) *JWKSet {
+	set := NewJWKSet(oidcConf)
+	set.now = func() time.Time { return *now }
+	return set
+}
+
+// Wait for the load under way, if any
+func waitForLoad(set *JWKSet) {
+	set.mu.Lock()
+	done := set.loading
+	set.mu.Unlock()
+
+	if done != nil {
+		<-done
+	}
+}
+
+func TestJWKSetFile(t *testing.T) {
+	rsaSigner := newRSASigner(t, "rsa-1")
+	ecSigner := newECSigner(t, "ec-1")
+	now := testIssuedAt
+	set := newTestJWKSet(&config.OIDCConfig{JWKSFile: writeTestJWKS(t, rsaSigner, ecSigner), JWKSCacheTTL: time.Hour}, &now)
+
+	key, err := set.Key(context.Background(), "rsa-1")
+	assert.NoError(t, err)
+	assert.Equal(t, rsaSigner.key.Public(), key)
+
+	key, err = set.Key(context.Background(), "ec-1")
+	assert.NoError(t, err)
+	assert.Equal(t, ecSigner.key.Public(), key)
+}
+
+func TestJWKSetRotation(t *testing.T) {
+	oldSigner := newECSigner(t, "2025-01")
+	newSigner := newECSigner(t, "2025-02")
+	server := newTestJWKSServer(t, testJWKS(t, oldSigner))
+	now := testIssuedAt
+	set := newTestJWKSet(&config.OIDCConfig{JWKSURL: server.URL, JWKSCacheTTL: time.Hour}, &now)
+
+	_, err := set.Key(context.Background(), "2025-01")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), server.requests.Load())
+
+	// Cached keys are used without loading them again
+	_, err = set.Key(context.Background(), "2025-01")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), server.requests.Load())
+
+	// The provider publishes a new key and signs with it. Tokens naming it
+	// have the key set loaded again, once the last load is far enough back
+	server.document.Store(testJWKS(t, newSigner, oldSigner))
+
+	_, err = set.Key(context.Background(), "2025-02")
+	assert.ErrorIs(t, err, ErrUnknownKey)
+	assert.Equal(t, int32(1), server.requests.Load())
+
+	now = now.Add(jwksMinRefreshInterval)
+	_, err = set.Key(context.Background(), "2025-02")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), server.requests.Load())
+
+	// An unknown key does not have the set loaded on every request
+	_, err = set.Key(context.Background(), "2024-12")
+	assert.ErrorIs(t, err, ErrUnknownKey)
+	assert.Equal(t, int32(2), server.requests.Load())
+}
+
+func TestJWKSetCacheExpiry(t *testing.T) {
+	signer := newECSigner(t, "2025-01")
+	server := newTestJWKSServer(t, testJWKS(t, signer))
+	now := testIssuedAt
+	set := newTestJWKSet(&config.OIDCConfig{JWKSURL: server.URL, JWKSCacheTTL: 10 * time.Minute}, &now)
+
+	_, err := set.Key(context.Background(), "2025-01")
+	require.NoError(t, err)
+
+	// The key is withdrawn, which is only noticed once the cache expires
+	server.document.Store(testJWKS(t))
+
+	now = now.Add(9 * time.Minute)
+	_, err = set.Key(context.Background(), "2025-01")
+	assert.NoError(t, err)
+
+	// Expired keys are still used while they are loaded again
+	now = now.Add(time.Minute)
+	_, err = set.Key(context.Background(), "2025-01")
+	assert.NoError(t, err)
+	waitForLoad(set)
+
+	_, err = set.Key(context.Background(), "2025-01")
+	assert.ErrorIs(t, err, ErrUnknownKey)
+	assert.Equal(t, int32(2), server.requests.Load())
+}
+
+func TestJWKSetSlowLoad(t *testing.T) {
+	oldSigner := newECSigner(t, "2025-01")
+	newSigner := newECSigner(t, "2025-02")
+	server := newTestJWKSServer(t, testJWKS(t, oldSigner))
+	now := testIssuedAt
+	set := newTestJWKSet(&config.OIDCConfig{JWKSURL: server.URL, JWKSCacheTTL: 10 * time.Minute}, &now)
+
+	_, err := set.Key(context.Background(), "2025-01")
+	require.NoError(t, err)
+
+	// The provider answers slowly from now on
+	release := make(chan struct{})
+	server.delay.Store(release)
+	server.document.Store(testJWKS(t, newSigner, oldSigner))
+	now = now.Add(time.Hour)
+
+	// Keys in the cache are used without waiting for the load
+	_, err = set.Key(context.Background(), "2025-01")
+	assert.NoError(t, err)
+
+	// A caller giving up on a key not seen yet does not cut the load short
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = set.Key(ctx, "2025-02")
+	assert.ErrorIs(t, err, context.Canceled)
+
+	close(release)
+	waitForLoad(set)
+
+	_, err = set.Key(context.Background(), "2025-02")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), server.requests.Load())
+}
+
+func TestJWKSetLoadFailure(t *testing.T) {
+	signer := newECSigner(t, "2025-01")
+	path := writeTestJWKS(t, signer)
+	now := testIssuedAt
+	set := newTestJWKSet(&config.OIDCConfig{JWKSFile: path, JWKSCacheTTL: 10 * time.Minute}, &now)
+
+	_, err := set.Key(context.Background(), "2025-01")
+	require.NoError(t, err)
+
+	// Keys loaded before stay in use while the key set cannot be loaded
+	require.NoError(t, os.Remove(path))
+	now = now.Add(time.Hour)
+	_, err = set.Key(context.Background(), "2025-01")
+	assert.NoError(t, err)
+
+	// Nothing is usable when nothing has ever loaded
+	empty := newTestJWKSet(&config.OIDCConfig{JWKSFile: path, JWKSCacheTTL: 10 * time.Minute}, &now)
+	_, err = empty.Key(context.Background(), "2025-01")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestParseJWKS(t *testing.T) {
+	testTable := map[string]struct {
+		document string
+		expected []string
+		err      string
+	}{
+		"SkipUnsupported": {
+			document: `{"keys":[
+				{"kty":"oct","kid":"hmac","k":"c2VjcmV0"},
+				{"kty":"EC","kid":"p384","crv":"P-384","x":"AA","y":"AA"},
+				{"kty":"RSA","kid":"enc","use":"enc","n":"AA","e":"AQAB"},
+				{"kty":"RSA","n":"AA","e":"AQAB"}
+			]}`,
+			expected: []string{},
+		},
+		"ShortRSAKey": {
+			document: `{"keys":[{"kty":"RSA","kid":"short","n":"AQAB","e":"AQAB"}]}`,
+			err:      "key 'short': modulus is shorter than 2048 bits",
+		},
+		"PointNotOnCurve": {
+			document: `{"keys":[{"kty":"EC","kid":"bad","crv":"P-256",` +
+				`"x":"AQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA","y":"AQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"}]}`,
+			err: "key 'bad': point is not on the curve",
+		},
+		"NotJSON": {
+			document: `<html>`,
+			err:      "invalid character '<' looking for beginning of value",
+		},
+	}
+
+	for n, tt := range testTable {
+		t.Run(n, func(t *testing.T) {
+			keys, err := parseJWKS([]byte(tt.document))
+
+			if tt.err != "" {
+				assert.Nil(t, keys)
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			ids := []string{}
+			for id := range keys {
+				ids = append(ids, id)
+			}
+			assert.ElementsMatch(t, tt.expected, ids)
+		})
+	}
+}
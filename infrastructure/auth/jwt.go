@@ -0,0 +1,138 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/takumi616/go-restapi/domain"
+	"github.com/takumi616/go-restapi/shared/config"
+)
+
+var (
+	ErrInvalidToken = errors.New("access token is invalid")
+	ErrTokenExpired = errors.New("access token has expired")
+)
+
+// Only HMAC-SHA256 is issued or accepted, so a token cannot pick a weaker
+// algorithm, or none at all, for itself
+const jwtAlgorithm = "HS256"
+
+type jwtHeader struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ"`
+	KeyId     string `json:"kid"`
+}
+
+type jwtClaims struct {
+	Issuer    string `json:"iss"`
+	Subject   string `json:"sub"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// JWTCodec issues access tokens as JSON Web Tokens naming the user as their
+// subject, and checks the ones clients present
+type JWTCodec struct {
+	keys   []config.SigningKey
+	issuer string
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+func NewJWTCodec(authConf *config.AuthConfig) *JWTCodec {
+	return &JWTCodec{
+		keys:   authConf.SigningKeys,
+		issuer: authConf.Issuer,
+		ttl:    authConf.AccessTokenTTL,
+		now:    time.Now,
+	}
+}
+
+// Issue signs a token for user with the newest key
+func (c *JWTCodec) Issue(user *domain.User) (string, time.Time, error) {
+	key := c.keys[0]
+	issuedAt := c.now()
+	expiresAt := issuedAt.Add(c.ttl)
+
+	header, err := json.Marshal(jwtHeader{Algorithm: jwtAlgorithm, Type: "JWT", KeyId: key.Id})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	claims, err := json.Marshal(jwtClaims{
+		Issuer:    c.issuer,
+		Subject:   user.Id.String(),
+		IssuedAt:  issuedAt.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	signature := base64.RawURLEncoding.EncodeToString(sign(key.Secret, signingInput))
+	return signingInput + "." + signature, expiresAt, nil
+}
+
+// Verify checks the signature, issuer and expiry of token and
+// reads the user it was issued to
+func (c *JWTCodec) Verify(token string) (domain.UserID, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", ErrInvalidToken
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil || header.Algorithm != jwtAlgorithm {
+		return "", ErrInvalidToken
+	}
+
+	key, ok := c.key(header.KeyId)
+	if !ok {
+		return "", ErrInvalidToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(signature, sign(key.Secret, parts[0]+"."+parts[1])) {
+		return "", ErrInvalidToken
+	}
+
+	var claims jwtClaims
+	if err := decodeSegment(parts[1], &claims); err != nil || claims.Issuer != c.issuer || claims.Subject == "" {
+		return "", ErrInvalidToken
+	}
+
+	if !c.now().Before(time.Unix(claims.ExpiresAt, 0)) {
+		return "", ErrTokenExpired
+	}
+
+	return domain.UserID(claims.Subject), nil
+}
+
+func (c *JWTCodec) key(id string) (config.SigningKey, bool) {
+	for _, key := range c.keys {
+		if key.Id == id {
+			return key, true
+		}
+	}
+	return config.SigningKey{}, false
+}
+
+func decodeSegment(segment string, v any) error {
+	bytes, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(bytes, v)
+}
+
+func sign(secret string, signingInput string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(signingInput))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,108 @@
+package auth
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/takumi616/go-restapi/domain"
+	"github.com/takumi616/go-restapi/shared/config"
+)
+
+var (
+	testIssuedAt = time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	testUser     = &domain.User{Id: "7c9e6679-7425-40de-944b-e07fc1f90ae7", Email: "alice@example.com"}
+	testKey      = config.SigningKey{Id: "2025-02", Secret: "0123456789abcdef0123456789abcdef"}
+	testOldKey   = config.SigningKey{Id: "2025-01", Secret: "fedcba9876543210fedcba9876543210"}
+)
+
+func newTestCodec(keys ...config.SigningKey) *JWTCodec {
+	codec := NewJWTCodec(&config.AuthConfig{SigningKeys: keys, Issuer: "go-restapi", AccessTokenTTL: 15 * time.Minute})
+	codec.now = func() time.Time { return testIssuedAt }
+	return codec
+}
+
+func TestJWTCodecRoundTrip(t *testing.T) {
+	codec := newTestCodec(testKey, testOldKey)
+
+	token, expiresAt, err := codec.Issue(testUser)
+	require.NoError(t, err)
+	assert.Equal(t, testIssuedAt.Add(15*time.Minute), expiresAt)
+
+	header, _ := base64.RawURLEncoding.DecodeString(strings.Split(token, ".")[0])
+	assert.JSONEq(t, `{"alg":"HS256","typ":"JWT","kid":"2025-02"}`, string(header))
+
+	userId, err := codec.Verify(token)
+	assert.NoError(t, err)
+	assert.Equal(t, testUser.Id, userId)
+}
+
+func TestJWTCodecVerifyAfterKeyRotation(t *testing.T) {
+	token, _, err := newTestCodec(testOldKey).Issue(testUser)
+	require.NoError(t, err)
+
+	// Signed with the key that was current before, which is still listed
+	userId, err := newTestCodec(testKey, testOldKey).Verify(token)
+	assert.NoError(t, err)
+	assert.Equal(t, testUser.Id, userId)
+
+	// Once the old key is dropped its tokens stop working
+	_, err = newTestCodec(testKey).Verify(token)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestJWTCodecVerifyExpired(t *testing.T) {
+	codec := newTestCodec(testKey)
+	token, _, err := codec.Issue(testUser)
+	require.NoError(t, err)
+
+	codec.now = func() time.Time { return testIssuedAt.Add(15 * time.Minute) }
+
+	_, err = codec.Verify(token)
+	assert.ErrorIs(t, err, ErrTokenExpired)
+}
+
+func TestJWTCodecVerifyInvalid(t *testing.T) {
+	codec := newTestCodec(testKey)
+	token, _, err := codec.Issue(testUser)
+	require.NoError(t, err)
+	parts := strings.Split(token, ".")
+
+	otherIssuer := newTestCodec(testKey)
+	otherIssuer.issuer = "someone-else"
+	foreign, _, err := otherIssuer.Issue(testUser)
+	require.NoError(t, err)
+
+	unsigned := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT","kid":"2025-02"}`))
+	forgedClaims := base64.RawURLEncoding.EncodeToString(
+		[]byte(`{"iss":"go-restapi","sub":"0b9b4d6e-5c1f-4b0e-9a43-2f6f0c3d8e71","iat":1735787045,"exp":4102444800}`),
+	)
+
+	testTable := map[string]string{
+		"Malformed":       "not-a-token",
+		"TamperedClaims":  parts[0] + "." + forgedClaims + "." + parts[2],
+		"BadSignature":    parts[0] + "." + parts[1] + ".c2lnbmF0dXJl",
+		"AlgorithmNone":   unsigned + "." + parts[1] + ".",
+		"ForeignIssuer":   foreign,
+		"UnknownKey":      mustIssue(t, newTestCodec(config.SigningKey{Id: "other", Secret: testKey.Secret})),
+		"EmptySignature":  parts[0] + "." + parts[1] + ".",
+		"ExtraSegment":    token + ".extra",
+		"UndecodableBody": parts[0] + ".!!!." + parts[2],
+	}
+
+	for n, token := range testTable {
+		t.Run(n, func(t *testing.T) {
+			_, err := codec.Verify(token)
+			assert.ErrorIs(t, err, ErrInvalidToken)
+		})
+	}
+}
+
+func mustIssue(t *testing.T, codec *JWTCodec) string {
+	token, _, err := codec.Issue(testUser)
+	require.NoError(t, err)
+	return token
+}
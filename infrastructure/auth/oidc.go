@@ -0,0 +1,163 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/takumi616/go-restapi/domain"
+	"github.com/takumi616/go-restapi/shared/config"
+)
+
+// How far the clocks of the identity provider and the API may drift apart
+// before a token is refused as expired or not yet valid
+const clockSkew = time.Minute
+
+type oidcClaims struct {
+	Issuer    string          `json:"iss"`
+	Subject   string          `json:"sub"`
+	Audience  json.RawMessage `json:"aud"`
+	ExpiresAt *float64        `json:"exp"`
+	NotBefore *float64        `json:"nbf"`
+}
+
+// OIDCVerifier checks ID and access tokens an identity provider signs with
+// RS256 or ES256, and reads who they were issued to
+type OIDCVerifier struct {
+	keys       *JWKSet
+	issuer     string
+	audience   string
+	rolesClaim string
+	now        func() time.Time
+}
+
+func NewOIDCVerifier(oidcConf *config.OIDCConfig, keys *JWKSet) *OIDCVerifier {
+	return &OIDCVerifier{
+		keys:       keys,
+		issuer:     oidcConf.Issuer,
+		audience:   oidcConf.Audience,
+		rolesClaim: oidcConf.RolesClaim,
+		now:        time.Now,
+	}
+}
+
+// Verify checks the signature, issuer, audience and expiry of token and
+// reads the identity it was issued to
+func (v *OIDCVerifier) Verify(ctx context.Context, token string) (*domain.Identity, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidToken
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil || header.KeyId == "" {
+		return nil, ErrInvalidToken
+	}
+
+	// The algorithm is checked before any key is looked up, so that a token
+	// naming none, or HS256 with a public key as secret, goes no further
+	if header.Algorithm != "RS256" && header.Algorithm != "ES256" {
+		return nil, ErrInvalidToken
+	}
+
+	key, err := v.keys.Key(ctx, header.KeyId)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !verifySignature(header.Algorithm, key, parts[0]+"."+parts[1], signature) {
+		return nil, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	var claims oidcClaims
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Issuer != v.issuer || claims.Subject == "" {
+		return nil, ErrInvalidToken
+	}
+
+	audience, err := stringOrList(claims.Audience)
+	if err != nil || !slices.Contains(audience, v.audience) {
+		return nil, ErrInvalidToken
+	}
+
+	now := v.now()
+	if claims.ExpiresAt == nil {
+		return nil, ErrInvalidToken
+	}
+	if !now.Add(-clockSkew).Before(numericDate(*claims.ExpiresAt)) {
+		return nil, ErrTokenExpired
+	}
+	if claims.NotBefore != nil && now.Add(clockSkew).Before(numericDate(*claims.NotBefore)) {
+		return nil, ErrInvalidToken
+	}
+
+	// The roles claim is named in the settings, so it is read on its own
+	var custom map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &custom); err != nil {
+		return nil, ErrInvalidToken
+	}
+	roles, err := stringOrList(custom[v.rolesClaim])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	return &domain.Identity{Issuer: claims.Issuer, Subject: claims.Subject, Roles: roles}, nil
+}
+
+// Only keys of the type the algorithm is for are used, whatever the key set
+// says about them
+func verifySignature(algorithm string, key crypto.PublicKey, signingInput string, signature []byte) bool {
+	digest := sha256.Sum256([]byte(signingInput))
+
+	switch algorithm {
+	case "RS256":
+		rsaKey, ok := key.(*rsa.PublicKey)
+		return ok && rsa.VerifyPKCS1v15(rsaKey, crypto.SHA256, digest[:], signature) == nil
+	case "ES256":
+		ecKey, ok := key.(*ecdsa.PublicKey)
+		if !ok || len(signature) != 64 {
+			return false
+		}
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		return ecdsa.Verify(ecKey, digest[:], r, s)
+	}
+	return false
+}
+
+// Read a claim that may hold one string or a list of them. A missing or
+// null claim is an empty list
+func stringOrList(raw json.RawMessage) ([]string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+
+	var one string
+	if err := json.Unmarshal(raw, &one); err == nil {
+		return []string{one}, nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// Seconds since the epoch, which may have a fraction
+func numericDate(seconds float64) time.Time {
+	return time.Unix(0, int64(seconds*float64(time.Second)))
+}
@@ -0,0 +1,342 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/takumi616/go-restapi/domain"
+	"github.com/takumi616/go-restapi/shared/config"
+)
+
+func newTestVerifier(t *testing.T, rolesClaim string, signers ...*testSigner) *OIDCVerifier {
+	oidcConf := &config.OIDCConfig{
+		Issuer:       "https://sso.example.com",
+		Audience:     "go-restapi",
+		JWKSFile:     writeTestJWKS(t, signers...),
+		JWKSCacheTTL: time.Hour,
+		RolesClaim:   rolesClaim,
+	}
+	now := testIssuedAt
+	verifier := NewOIDCVerifier(oidcConf, newTestJWKSet(oidcConf, &now))
+	verifier.now = func() time.Time { return testIssuedAt }
+	return verifier
+}
+
+func testClaims(overrides map[string]any) map[string]any {
+	claims := map[string]any{
+		"iss": "https://sso.example.com",
+		"sub": "alice",
+		"aud": "go-restapi",
+		"iat": testIssuedAt.Unix(),
+		"exp": testIssuedAt.Add(5 * time.Minute).Unix(),
+	}
+	for key, value := range overrides {
+		if value == nil {
+			delete(claims, key)
+		} else {
+			claims[key] = value
+		}
+	}
+	return claims
+}
+
+func TestOIDCVerifierVerify(t *testing.T) {
+	rsaSigner := newRSASigner(t, "rsa-1")
+	ecSigner := newECSigner(t, "ec-1")
+	// Signs with a key the provider never published
+	rogueSigner := newECSigner(t, "ec-1")
+	verifier := newTestVerifier(t, "roles", rsaSigner, ecSigner)
+
+	testTable := map[string]struct {
+		token    func(t *testing.T) string
+		expected *domain.Identity
+		err      error
+	}{
+		"RS256": {
+			token: func(t *testing.T) string {
+				return rsaSigner.sign(t, nil, testClaims(map[string]any{"roles": []string{"admin"}}))
+			},
+			expected: &domain.Identity{Issuer: "https://sso.example.com", Subject: "alice", Roles: []string{"admin"}},
+		},
+		"ES256": {
+			token: func(t *testing.T) string {
+				return ecSigner.sign(t, nil, testClaims(map[string]any{"roles": "viewer"}))
+			},
+			expected: &domain.Identity{Issuer: "https://sso.example.com", Subject: "alice", Roles: []string{"viewer"}},
+		},
+		"AudienceList": {
+			token: func(t *testing.T) string {
+				return ecSigner.sign(t, nil, testClaims(map[string]any{"aud": []string{"account", "go-restapi"}}))
+			},
+			expected: &domain.Identity{Issuer: "https://sso.example.com", Subject: "alice"},
+		},
+		"WithinClockSkew": {
+			token: func(t *testing.T) string {
+				return ecSigner.sign(t, nil, testClaims(map[string]any{
+					"exp": testIssuedAt.Add(-30 * time.Second).Unix(),
+					"nbf": testIssuedAt.Add(30 * time.Second).Unix(),
+				}))
+			},
+			expected: &domain.Identity{Issuer: "https://sso.example.com", Subject: "alice"},
+		},
+		"WrongIssuer": {
+			token: func(t *testing.T) string {
+				return ecSigner.sign(t, nil, testClaims(map[string]any{"iss": "https://evil.example.com"}))
+			},
+			err: ErrInvalidToken,
+		},
+		"WrongAudience": {
+			token: func(t *testing.T) string {
+				return ecSigner.sign(t, nil, testClaims(map[string]any{"aud": []string{"account"}}))
+			},
+			err: ErrInvalidToken,
+		},
+		"NoAudience": {
+			token: func(t *testing.T) string {
+				return ecSigner.sign(t, nil, testClaims(map[string]any{"aud": nil}))
+			},
+			err: ErrInvalidToken,
+		},
+		"Expired": {
+			token: func(t *testing.T) string {
+				return ecSigner.sign(t, nil, testClaims(map[string]any{"exp": testIssuedAt.Add(-2 * time.Minute).Unix()}))
+			},
+			err: ErrTokenExpired,
+		},
+		"NoExpiry": {
+			token: func(t *testing.T) string {
+				return ecSigner.sign(t, nil, testClaims(map[string]any{"exp": nil}))
+			},
+			err: ErrInvalidToken,
+		},
+		"NotYetValid": {
+			token: func(t *testing.T) string {
+				return ecSigner.sign(t, nil, testClaims(map[string]any{"nbf": testIssuedAt.Add(2 * time.Minute).Unix()}))
+			},
+			err: ErrInvalidToken,
+		},
+		"NoSubject": {
+			token: func(t *testing.T) string {
+				return ecSigner.sign(t, nil, testClaims(map[string]any{"sub": ""}))
+			},
+			err: ErrInvalidToken,
+		},
+		"BadSignature": {
+			token: func(t *testing.T) string {
+				return rogueSigner.sign(t, nil, testClaims(nil))
+			},
+			err: ErrInvalidToken,
+		},
+		"UnknownKey": {
+			token: func(t *testing.T) string {
+				return ecSigner.sign(t, map[string]any{"alg": "ES256", "kid": "ec-2"}, testClaims(nil))
+			},
+			err: ErrInvalidToken,
+		},
+		"NoKeyId": {
+			token: func(t *testing.T) string {
+				return ecSigner.sign(t, map[string]any{"alg": "ES256"}, testClaims(nil))
+			},
+			err: ErrInvalidToken,
+		},
+		"KeyOfOtherAlgorithm": {
+			token: func(t *testing.T) string {
+				return ecSigner.sign(t, map[string]any{"alg": "RS256", "kid": "ec-1"}, testClaims(nil))
+			},
+			err: ErrInvalidToken,
+		},
+		"AlgorithmNone": {
+			token: func(t *testing.T) string {
+				token := ecSigner.sign(t, map[string]any{"alg": "none", "kid": "ec-1"}, testClaims(nil))
+				return token[:strings.LastIndex(token, ".")+1]
+			},
+			err: ErrInvalidToken,
+		},
+		"AlgorithmHS256": {
+			token: func(t *testing.T) string {
+				return ecSigner.sign(t, map[string]any{"alg": "HS256", "kid": "ec-1"}, testClaims(nil))
+			},
+			err: ErrInvalidToken,
+		},
+		"InvalidRolesClaim": {
+			token: func(t *testing.T) string {
+				return ecSigner.sign(t, nil, testClaims(map[string]any{"roles": map[string]bool{"admin": true}}))
+			},
+			err: ErrInvalidToken,
+		},
+		"Malformed": {
+			token: func(t *testing.T) string {
+				return "not-a-token"
+			},
+			err: ErrInvalidToken,
+		},
+	}
+
+	for n, tt := range testTable {
+		t.Run(n, func(t *testing.T) {
+			identity, err := verifier.Verify(context.Background(), tt.token(t))
+
+			if tt.err != nil {
+				assert.Nil(t, identity)
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, identity)
+		})
+	}
+}
+
+func TestOIDCVerifierRolesClaim(t *testing.T) {
+	signer := newECSigner(t, "ec-1")
+	verifier := newTestVerifier(t, "groups", signer)
+
+	token := signer.sign(t, nil, testClaims(map[string]any{"roles": "viewer", "groups": []string{"staff", "admin"}}))
+	identity, err := verifier.Verify(context.Background(), token)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"staff", "admin"}, identity.Roles)
+	assert.Equal(t, domain.RoleAdmin, identity.Role())
+	assert.Equal(t, domain.UserID("105c07a2-65f4-5703-8187-c1d0f0a3d659"), identity.UserId())
+}
+
+func TestOIDCVerifierKeyRotation(t *testing.T) {
+	oldSigner := newRSASigner(t, "2025-01")
+	newSigner := newECSigner(t, "2025-02")
+	server := newTestJWKSServer(t, testJWKS(t, oldSigner))
+
+	oidcConf := &config.OIDCConfig{
+		Issuer:       "https://sso.example.com",
+		Audience:     "go-restapi",
+		JWKSURL:      server.URL,
+		JWKSCacheTTL: time.Hour,
+		RolesClaim:   "roles",
+	}
+	now := testIssuedAt
+	verifier := NewOIDCVerifier(oidcConf, newTestJWKSet(oidcConf, &now))
+	verifier.now = func() time.Time { return now }
+
+	_, err := verifier.Verify(context.Background(), oldSigner.sign(t, nil, testClaims(nil)))
+	require.NoError(t, err)
+
+	// Well within the cache TTL, a token signed with a newly published key
+	// has the key set loaded again
+	now = now.Add(2 * time.Minute)
+	server.document.Store(testJWKS(t, newSigner, oldSigner))
+
+	identity, err := verifier.Verify(context.Background(), newSigner.sign(t, nil, testClaims(map[string]any{
+		"exp": now.Add(5 * time.Minute).Unix(),
+	})))
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", identity.Subject)
+	assert.Equal(t, int32(2), server.requests.Load())
+}
+
+// A key pair of the identity provider the tests stand in for
+type testSigner struct {
+	id  string
+	key crypto.Signer
+}
+
+func newRSASigner(t *testing.T, id string) *testSigner {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return &testSigner{id: id, key: key}
+}
+
+func newECSigner(t *testing.T, id string) *testSigner {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return &testSigner{id: id, key: key}
+}
+
+func (s *testSigner) algorithm() string {
+	if _, ok := s.key.(*rsa.PrivateKey); ok {
+		return "RS256"
+	}
+	return "ES256"
+}
+
+func (s *testSigner) jwk() map[string]string {
+	switch key := s.key.(type) {
+	case *rsa.PrivateKey:
+		return map[string]string{
+			"kty": "RSA",
+			"kid": s.id,
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}
+	case *ecdsa.PrivateKey:
+		return map[string]string{
+			"kty": "EC",
+			"kid": s.id,
+			"crv": "P-256",
+			"x":   base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, 32))),
+			"y":   base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, 32))),
+		}
+	}
+	return nil
+}
+
+// Sign claims with the header given, which defaults to the algorithm and id
+// of the key
+func (s *testSigner) sign(t *testing.T, header map[string]any, claims map[string]any) string {
+	if header == nil {
+		header = map[string]any{"alg": s.algorithm(), "typ": "JWT", "kid": s.id}
+	}
+
+	headerJSON, err := json.Marshal(header)
+	require.NoError(t, err)
+	claimsJSON, err := json.Marshal(claims)
+	require.NoError(t, err)
+
+	signingInput := base64.RawURLEncoding.EncodeToString(headerJSON) + "." + base64.RawURLEncoding.EncodeToString(claimsJSON)
+	digest := sha256.Sum256([]byte(signingInput))
+
+	var signature []byte
+	switch key := s.key.(type) {
+	case *rsa.PrivateKey:
+		signature, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+		require.NoError(t, err)
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+		require.NoError(t, err)
+		signature = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func testJWKS(t *testing.T, signers ...*testSigner) []byte {
+	keys := []map[string]string{}
+	for _, signer := range signers {
+		keys = append(keys, signer.jwk())
+	}
+
+	document, err := json.Marshal(map[string]any{"keys": keys})
+	require.NoError(t, err)
+	return document
+}
+
+func writeTestJWKS(t *testing.T, signers ...*testSigner) string {
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, testJWKS(t, signers...), 0o600))
+	return path
+}
@@ -0,0 +1,26 @@
+package auth
+
+import "golang.org/x/crypto/bcrypt"
+
+// BcryptHasher hashes passwords with bcrypt, which salts each hash
+// and stays slow enough to resist guessing
+type BcryptHasher struct {
+	cost int
+}
+
+func NewBcryptHasher() *BcryptHasher {
+	return &BcryptHasher{cost: bcrypt.DefaultCost}
+}
+
+func (h *BcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// Matches reports whether password is the one hash was made from
+func (h *BcryptHasher) Matches(hash string, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
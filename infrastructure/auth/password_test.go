@@ -0,0 +1,26 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestBcryptHasher(t *testing.T) {
+	hasher := &BcryptHasher{cost: bcrypt.MinCost}
+
+	hash, err := hasher.Hash("correct horse battery staple")
+	require.NoError(t, err)
+	assert.NotEqual(t, "correct horse battery staple", hash)
+
+	assert.True(t, hasher.Matches(hash, "correct horse battery staple"))
+	assert.False(t, hasher.Matches(hash, "Correct horse battery staple"))
+	assert.False(t, hasher.Matches("not a hash", "correct horse battery staple"))
+
+	// Each hash is salted on its own
+	again, err := hasher.Hash("correct horse battery staple")
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, again)
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+
+	"github.com/takumi616/go-restapi/domain"
+	"github.com/takumi616/go-restapi/infrastructure/db/repository/model"
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+// Columns read into model.LabelResult, in the order scanLabel expects
+const labelColumns = "id, name, color, created_at, updated_at"
+
+func scanLabel(row rowScanner, result *model.LabelResult) error {
+	return row.Scan(&result.Id, &result.Name, &result.Color, &result.CreatedAt, &result.UpdatedAt)
+}
+
+// Tasks show their labels, so a change to a label is a change to every
+// task carrying it. Prepended to a write on a label of the owner in $2, it
+// moves their versions and update times on in the same statement. Labels
+// only go on tasks of their owner, so no one else's tasks are touched
+const touchLabeledTasks = `WITH touched AS (
+	UPDATE tasks SET version=version+1, updated_at=now()
+	WHERE id IN (SELECT task_id FROM task_labels WHERE label_id=$1) AND owner_id=$2
+)
+`
+
+type LabelRepository struct {
+	Db *sql.DB
+}
+
+func NewLabelRepository(db *sql.DB) *LabelRepository {
+	return &LabelRepository{
+		Db: db,
+	}
+}
+
+func (r *LabelRepository) Insert(ctx context.Context, label *domain.Label) (*domain.Label, error) {
+	param := model.ToInsertLabelParam(label)
+
+	var result model.LabelResult
+	err := scanLabel(r.Db.QueryRowContext(
+		ctx,
+		"INSERT INTO labels(owner_id, name, color) VALUES($1, $2, $3) RETURNING "+labelColumns,
+		param.OwnerId, param.Name, param.Color,
+	), &result)
+
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		if constraintErr := labelConstraintError(err); constraintErr != nil {
+			return nil, constraintErr
+		}
+		return nil, customError.ErrInternalServerError
+	}
+
+	return model.ToLabelDomain(&result), nil
+}
+
+func (r *LabelRepository) SelectAll(ctx context.Context) ([]*domain.Label, error) {
+	rows, err := r.Db.QueryContext(
+		ctx, "SELECT "+labelColumns+" FROM labels WHERE owner_id = $1 ORDER BY name ASC", taskOwner(ctx),
+	)
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		return nil, customError.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	labels := []*domain.Label{}
+	for rows.Next() {
+		var result model.LabelResult
+		if err := scanLabel(rows, &result); err != nil {
+			slog.ErrorContext(ctx, err.Error())
+			return nil, customError.ErrInternalServerError
+		}
+		labels = append(labels, model.ToLabelDomain(&result))
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		return nil, customError.ErrInternalServerError
+	}
+
+	return labels, nil
+}
+
+func (r *LabelRepository) SelectById(ctx context.Context, id domain.LabelID) (*domain.Label, error) {
+	var result model.LabelResult
+	err := scanLabel(r.Db.QueryRowContext(
+		ctx, "SELECT "+labelColumns+" FROM labels WHERE id = $1 AND owner_id = $2", id, taskOwner(ctx),
+	), &result)
+
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customError.ErrNotFound
+		}
+		return nil, customError.ErrInternalServerError
+	}
+
+	return model.ToLabelDomain(&result), nil
+}
+
+func (r *LabelRepository) Update(ctx context.Context, id domain.LabelID, patch *domain.LabelPatch) (*domain.Label, error) {
+	param := model.ToUpdateLabelParam(patch)
+
+	var result model.LabelResult
+	err := scanLabel(r.Db.QueryRowContext(
+		ctx,
+		touchLabeledTasks+`UPDATE labels SET name=COALESCE($3, name), color=COALESCE($4, color), updated_at=now()
+		WHERE id=$1 AND owner_id=$2
+		RETURNING `+labelColumns,
+		id, taskOwner(ctx), param.Name, param.Color,
+	), &result)
+
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customError.ErrNotFound
+		}
+		if constraintErr := labelConstraintError(err); constraintErr != nil {
+			return nil, constraintErr
+		}
+		return nil, customError.ErrInternalServerError
+	}
+
+	return model.ToLabelDomain(&result), nil
+}
+
+// Delete removes a label, and with it the label from every task
+func (r *LabelRepository) Delete(ctx context.Context, id domain.LabelID) (*domain.Label, error) {
+	var deletedId string
+	err := r.Db.QueryRowContext(
+		ctx,
+		touchLabeledTasks+"DELETE FROM labels WHERE id=$1 AND owner_id=$2 RETURNING id",
+		id, taskOwner(ctx),
+	).Scan(&deletedId)
+
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customError.ErrNotFound
+		}
+		return nil, customError.ErrInternalServerError
+	}
+
+	return &domain.Label{Id: domain.LabelID(deletedId)}, nil
+}
@@ -0,0 +1,308 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/takumi616/go-restapi/domain"
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+const testTouchLabeledTasks = `WITH touched AS (
+	UPDATE tasks SET version=version+1, updated_at=now()
+	WHERE id IN (SELECT task_id FROM task_labels WHERE label_id=$1) AND owner_id=$2
+)
+`
+
+func testLabelRow(id, name, color string) *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "name", "color", "created_at", "updated_at"}).
+		AddRow(id, name, color, testTime, testTime)
+}
+
+func TestInsertLabel(t *testing.T) {
+	type expected struct {
+		label *domain.Label
+		err   error
+	}
+
+	insertQuery := regexp.QuoteMeta("INSERT INTO labels(owner_id, name, color) VALUES($1, $2, $3) RETURNING id, name, color, created_at, updated_at")
+
+	testTable := map[string]struct {
+		input     *domain.Label
+		mockSetup func(sqlmock.Sqlmock)
+		expected  expected
+	}{
+		"Ok": {
+			input: &domain.Label{OwnerId: testOwnerId, Name: "bug", Color: "#ff0000"},
+			mockSetup: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(insertQuery).
+					WithArgs(testOwnerId, "bug", "#ff0000").
+					WillReturnRows(testLabelRow("c6a1f3e2-7d4b-4e8a-b1f9-3a2d5e6c7b80", "bug", "#ff0000"))
+			},
+			expected: expected{
+				label: &domain.Label{
+					Id:        "c6a1f3e2-7d4b-4e8a-b1f9-3a2d5e6c7b80",
+					Name:      "bug",
+					Color:     "#ff0000",
+					CreatedAt: testTime,
+					UpdatedAt: testTime,
+				},
+				err: nil,
+			},
+		},
+		"DuplicateName": {
+			input: &domain.Label{OwnerId: testOwnerId, Name: "bug"},
+			mockSetup: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(insertQuery).
+					WithArgs(testOwnerId, "bug", "").
+					WillReturnError(&pq.Error{
+						Code:       "23505",
+						Message:    "duplicate key value violates unique constraint \"labels_name_key\"",
+						Constraint: "labels_name_key",
+					})
+			},
+			expected: expected{
+				label: nil,
+				err:   &customError.FieldError{Field: "name", Err: customError.ErrDuplicate},
+			},
+		},
+		"InternalServerErr": {
+			input: &domain.Label{OwnerId: testOwnerId, Name: "bug"},
+			mockSetup: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(insertQuery).
+					WithArgs(testOwnerId, "bug", "").
+					WillReturnError(errors.New("pq: connection reset by peer"))
+			},
+			expected: expected{
+				label: nil,
+				err:   customError.ErrInternalServerError,
+			},
+		},
+	}
+
+	for n, tt := range testTable {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+			require.NoError(t, err)
+			defer db.Close()
+
+			tt.mockSetup(mock)
+
+			repo := &LabelRepository{Db: db}
+			result, err := repo.Insert(testOwnerCtx, tt.input)
+
+			if tt.expected.err != nil {
+				assert.Nil(t, result)
+				assert.EqualError(t, err, tt.expected.err.Error())
+			} else {
+				assert.Equal(t, tt.expected.label, result)
+				assert.Nil(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSelectAllLabels(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer db.Close()
+
+	rows := testLabelRow("0b9b4d6e-5c1f-4b0e-9a43-2f6f0c3d8e71", "backend", "#0000ff").
+		AddRow("c6a1f3e2-7d4b-4e8a-b1f9-3a2d5e6c7b80", "bug", "#ff0000", testTime, testTime)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, color, created_at, updated_at FROM labels WHERE owner_id = $1 ORDER BY name ASC")).
+		WithArgs(testOwnerId).
+		WillReturnRows(rows)
+
+	repo := &LabelRepository{Db: db}
+	result, err := repo.SelectAll(testOwnerCtx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*domain.Label{
+		{Id: "0b9b4d6e-5c1f-4b0e-9a43-2f6f0c3d8e71", Name: "backend", Color: "#0000ff", CreatedAt: testTime, UpdatedAt: testTime},
+		{Id: "c6a1f3e2-7d4b-4e8a-b1f9-3a2d5e6c7b80", Name: "bug", Color: "#ff0000", CreatedAt: testTime, UpdatedAt: testTime},
+	}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSelectLabelById(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, color, created_at, updated_at FROM labels WHERE id = $1 AND owner_id = $2")).
+		WithArgs("c6a1f3e2-7d4b-4e8a-b1f9-3a2d5e6c7b80", testOwnerId).
+		WillReturnError(sql.ErrNoRows)
+
+	repo := &LabelRepository{Db: db}
+	result, err := repo.SelectById(testOwnerCtx, "c6a1f3e2-7d4b-4e8a-b1f9-3a2d5e6c7b80")
+
+	assert.Nil(t, result)
+	assert.EqualError(t, err, customError.ErrNotFound.Error())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateLabel(t *testing.T) {
+	type expected struct {
+		label *domain.Label
+		err   error
+	}
+
+	updateQuery := regexp.QuoteMeta(
+		testTouchLabeledTasks + `UPDATE labels SET name=COALESCE($3, name), color=COALESCE($4, color), updated_at=now()
+		WHERE id=$1 AND owner_id=$2
+		RETURNING id, name, color, created_at, updated_at`,
+	)
+
+	testTable := map[string]struct {
+		id        domain.LabelID
+		patch     *domain.LabelPatch
+		mockSetup func(sqlmock.Sqlmock, domain.LabelID)
+		expected  expected
+	}{
+		"Ok": {
+			id:    "c6a1f3e2-7d4b-4e8a-b1f9-3a2d5e6c7b80",
+			patch: &domain.LabelPatch{Color: ptr("#00ff00")},
+			mockSetup: func(m sqlmock.Sqlmock, id domain.LabelID) {
+				m.ExpectQuery(updateQuery).
+					WithArgs(id, testOwnerId, nil, "#00ff00").
+					WillReturnRows(testLabelRow(id.String(), "bug", "#00ff00"))
+			},
+			expected: expected{
+				label: &domain.Label{
+					Id:        "c6a1f3e2-7d4b-4e8a-b1f9-3a2d5e6c7b80",
+					Name:      "bug",
+					Color:     "#00ff00",
+					CreatedAt: testTime,
+					UpdatedAt: testTime,
+				},
+				err: nil,
+			},
+		},
+		"NameTooLong": {
+			id:    "c6a1f3e2-7d4b-4e8a-b1f9-3a2d5e6c7b80",
+			patch: &domain.LabelPatch{Name: ptr("Label Name Longer Than Thirty Characters")},
+			mockSetup: func(m sqlmock.Sqlmock, id domain.LabelID) {
+				m.ExpectQuery(updateQuery).
+					WithArgs(id, testOwnerId, "Label Name Longer Than Thirty Characters", nil).
+					WillReturnError(&pq.Error{
+						Code:    "22001",
+						Message: "value too long for type character varying(30)",
+					})
+			},
+			expected: expected{
+				label: nil,
+				err:   &customError.FieldError{Field: "name", Err: customError.ErrValueTooLong},
+			},
+		},
+		"NotFound": {
+			id:    "3e440171-0921-4c88-a7ec-13f4cdab0d69",
+			patch: &domain.LabelPatch{Name: ptr("bug")},
+			mockSetup: func(m sqlmock.Sqlmock, id domain.LabelID) {
+				m.ExpectQuery(updateQuery).
+					WithArgs(id, testOwnerId, "bug", nil).
+					WillReturnError(sql.ErrNoRows)
+			},
+			expected: expected{
+				label: nil,
+				err:   customError.ErrNotFound,
+			},
+		},
+	}
+
+	for n, tt := range testTable {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+			require.NoError(t, err)
+			defer db.Close()
+
+			tt.mockSetup(mock, tt.id)
+
+			repo := &LabelRepository{Db: db}
+			result, err := repo.Update(testOwnerCtx, tt.id, tt.patch)
+
+			if tt.expected.err != nil {
+				assert.Nil(t, result)
+				assert.EqualError(t, err, tt.expected.err.Error())
+			} else {
+				assert.Equal(t, tt.expected.label, result)
+				assert.Nil(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDeleteLabel(t *testing.T) {
+	type expected struct {
+		label *domain.Label
+		err   error
+	}
+
+	deleteQuery := regexp.QuoteMeta(testTouchLabeledTasks + "DELETE FROM labels WHERE id=$1 AND owner_id=$2 RETURNING id")
+
+	testTable := map[string]struct {
+		id        domain.LabelID
+		mockSetup func(sqlmock.Sqlmock, domain.LabelID)
+		expected  expected
+	}{
+		"Ok": {
+			id: "c6a1f3e2-7d4b-4e8a-b1f9-3a2d5e6c7b80",
+			mockSetup: func(m sqlmock.Sqlmock, id domain.LabelID) {
+				m.ExpectQuery(deleteQuery).
+					WithArgs(id, testOwnerId).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(id.String()))
+			},
+			expected: expected{
+				label: &domain.Label{Id: "c6a1f3e2-7d4b-4e8a-b1f9-3a2d5e6c7b80"},
+				err:   nil,
+			},
+		},
+		"NotFound": {
+			id: "3e440171-0921-4c88-a7ec-13f4cdab0d69",
+			mockSetup: func(m sqlmock.Sqlmock, id domain.LabelID) {
+				m.ExpectQuery(deleteQuery).
+					WithArgs(id, testOwnerId).
+					WillReturnError(sql.ErrNoRows)
+			},
+			expected: expected{
+				label: nil,
+				err:   customError.ErrNotFound,
+			},
+		},
+	}
+
+	for n, tt := range testTable {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+			require.NoError(t, err)
+			defer db.Close()
+
+			tt.mockSetup(mock, tt.id)
+
+			repo := &LabelRepository{Db: db}
+			result, err := repo.Delete(testOwnerCtx, tt.id)
+
+			if tt.expected.err != nil {
+				assert.Nil(t, result)
+				assert.EqualError(t, err, tt.expected.err.Error())
+			} else {
+				assert.Equal(t, tt.expected.label, result)
+				assert.Nil(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
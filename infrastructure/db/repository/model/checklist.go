@@ -0,0 +1,45 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/takumi616/go-restapi/domain"
+)
+
+// Fields left NULL keep their current column value
+type UpdateChecklistItemParam struct {
+	Text sql.NullString
+	Done sql.NullBool
+}
+
+func ToUpdateChecklistItemParam(patch *domain.ChecklistItemPatch) *UpdateChecklistItemParam {
+	param := &UpdateChecklistItemParam{}
+	if patch.Text != nil {
+		param.Text = sql.NullString{String: *patch.Text, Valid: true}
+	}
+	if patch.Done != nil {
+		param.Done = sql.NullBool{Bool: *patch.Done, Valid: true}
+	}
+	return param
+}
+
+type ChecklistItemResult struct {
+	Id        string
+	Text      string
+	Done      bool
+	Position  int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func ToChecklistItemDomain(result *ChecklistItemResult) *domain.ChecklistItem {
+	return &domain.ChecklistItem{
+		Id:        domain.ChecklistItemID(result.Id),
+		Text:      result.Text,
+		Done:      result.Done,
+		Position:  result.Position,
+		CreatedAt: result.CreatedAt,
+		UpdatedAt: result.UpdatedAt,
+	}
+}
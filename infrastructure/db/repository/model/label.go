@@ -0,0 +1,90 @@
+package model
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/takumi616/go-restapi/domain"
+)
+
+type InsertLabelParam struct {
+	OwnerId string
+	Name    string
+	Color   string
+}
+
+func ToInsertLabelParam(label *domain.Label) *InsertLabelParam {
+	return &InsertLabelParam{label.OwnerId.String(), label.Name, label.Color}
+}
+
+// Fields left NULL keep their current column value
+type UpdateLabelParam struct {
+	Name  sql.NullString
+	Color sql.NullString
+}
+
+func ToUpdateLabelParam(patch *domain.LabelPatch) *UpdateLabelParam {
+	param := &UpdateLabelParam{}
+	if patch.Name != nil {
+		param.Name = sql.NullString{String: *patch.Name, Valid: true}
+	}
+	if patch.Color != nil {
+		param.Color = sql.NullString{String: *patch.Color, Valid: true}
+	}
+	return param
+}
+
+type LabelResult struct {
+	Id        string
+	Name      string
+	Color     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func ToLabelDomain(result *LabelResult) *domain.Label {
+	return &domain.Label{
+		Id:        domain.LabelID(result.Id),
+		Name:      result.Name,
+		Color:     result.Color,
+		CreatedAt: result.CreatedAt,
+		UpdatedAt: result.UpdatedAt,
+	}
+}
+
+// Labels of a task, read from the JSON array they are aggregated into
+type TaskLabelResults []*TaskLabelResult
+
+type TaskLabelResult struct {
+	Id    string `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+func (t *TaskLabelResults) Scan(src any) error {
+	switch src := src.(type) {
+	case []byte:
+		return json.Unmarshal(src, t)
+	case string:
+		return json.Unmarshal([]byte(src), t)
+	case nil:
+		*t = nil
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into task labels", src)
+	}
+}
+
+func toTaskLabels(results TaskLabelResults) []*domain.Label {
+	var labels []*domain.Label
+	for _, result := range results {
+		labels = append(labels, &domain.Label{
+			Id:    domain.LabelID(result.Id),
+			Name:  result.Name,
+			Color: result.Color,
+		})
+	}
+	return labels
+}
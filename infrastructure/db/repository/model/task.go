@@ -1,38 +1,158 @@
 package model
 
-import "github.com/takumi616/go-restapi/domain"
+import (
+	"database/sql"
+	"time"
+
+	"github.com/takumi616/go-restapi/domain"
+)
 
 type InsertTaskParam struct {
-	Title       string
-	Description string
-	Status      bool
+	Title         string
+	Description   string
+	State         string
+	Priority      string
+	DueAt         sql.NullTime
+	EstimateHours sql.NullInt64
+	ParentId      sql.NullString
+	OwnerId       sql.NullString
 }
 
 func ToInsertTaskParam(task *domain.Task) *InsertTaskParam {
-	return &InsertTaskParam{task.Title, task.Description, task.Status}
+	return &InsertTaskParam{
+		task.Title, task.Description, task.State.String(), task.Priority.String(),
+		ptrToNullTime(task.DueAt), ptrToNullInt(task.EstimateHours), ptrToNullTaskID(task.ParentId),
+		sql.NullString{String: task.OwnerId.String(), Valid: task.OwnerId != ""},
+	}
 }
 
+// Fields left NULL keep their current column value
 type UpdateTaskParam struct {
-	Description string
-	Status      bool
+	Title         sql.NullString
+	Description   sql.NullString
+	State         sql.NullString
+	Priority      sql.NullString
+	DueAt         sql.NullTime
+	ClearDueAt    bool
+	EstimateHours sql.NullInt64
+	ClearEstimate bool
+	ParentId      sql.NullString
+	ClearParent   bool
+	Version       int
 }
 
-func ToUpdateTaskParam(task *domain.Task) *UpdateTaskParam {
-	return &UpdateTaskParam{task.Description, task.Status}
+func ToUpdateTaskParam(patch *domain.TaskPatch) *UpdateTaskParam {
+	param := &UpdateTaskParam{Version: patch.Version}
+	if patch.Title != nil {
+		param.Title = sql.NullString{String: *patch.Title, Valid: true}
+	}
+	if patch.Description != nil {
+		param.Description = sql.NullString{String: *patch.Description, Valid: true}
+	}
+	if patch.State != nil {
+		param.State = sql.NullString{String: patch.State.String(), Valid: true}
+	}
+	if patch.Priority != nil {
+		param.Priority = sql.NullString{String: patch.Priority.String(), Valid: true}
+	}
+	param.DueAt = ptrToNullTime(patch.DueAt)
+	param.ClearDueAt = patch.ClearDueAt
+	param.EstimateHours = ptrToNullInt(patch.EstimateHours)
+	param.ClearEstimate = patch.ClearEstimate
+	param.ParentId = ptrToNullTaskID(patch.ParentId)
+	param.ClearParent = patch.ClearParent
+	return param
 }
 
 type TaskResult struct {
-	Id          string
-	Title       string
-	Description string
-	Status      bool
+	Id            string
+	Title         string
+	Description   string
+	State         string
+	Priority      string
+	DueAt         sql.NullTime
+	EstimateHours sql.NullInt64
+	ParentId      sql.NullString
+	Version       int
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	CompletedAt   sql.NullTime
+	DeletedAt     sql.NullTime
+	Labels        TaskLabelResults
+	BlockedBy     TaskBlockerResults
+	// Counts of the live direct subtasks, and of those that are done
+	Subtasks          int
+	CompletedSubtasks int
+	// Counts of the checklist items, and of those that are done
+	ChecklistItems          int
+	CompletedChecklistItems int
 }
 
 func ToDomain(result *TaskResult) *domain.Task {
 	return &domain.Task{
-		Id:          result.Id,
-		Title:       result.Title,
-		Description: result.Description,
-		Status:      result.Status,
+		Id:            domain.TaskID(result.Id),
+		Title:         result.Title,
+		Description:   result.Description,
+		State:         domain.TaskState(result.State),
+		Priority:      domain.TaskPriority(result.Priority),
+		DueAt:         nullTimeToPtr(result.DueAt),
+		EstimateHours: nullIntToPtr(result.EstimateHours),
+		ParentId:      nullStringToTaskID(result.ParentId),
+		Labels:        toTaskLabels(result.Labels),
+		BlockedBy:     toTaskBlockers(result.BlockedBy),
+		Progress:      domain.TaskProgress{Completed: result.CompletedSubtasks, Total: result.Subtasks},
+		ChecklistProgress: domain.ChecklistProgress{
+			Completed: result.CompletedChecklistItems, Total: result.ChecklistItems,
+		},
+		Version:     result.Version,
+		CreatedAt:   result.CreatedAt,
+		UpdatedAt:   result.UpdatedAt,
+		CompletedAt: nullTimeToPtr(result.CompletedAt),
+		DeletedAt:   nullTimeToPtr(result.DeletedAt),
+	}
+}
+
+func nullTimeToPtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+// Times are stored in UTC whatever offset they were given with
+func ptrToNullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: t.UTC(), Valid: true}
+}
+
+func nullIntToPtr(i sql.NullInt64) *int {
+	if !i.Valid {
+		return nil
+	}
+	n := int(i.Int64)
+	return &n
+}
+
+func ptrToNullInt(i *int) sql.NullInt64 {
+	if i == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: int64(*i), Valid: true}
+}
+
+func nullStringToTaskID(s sql.NullString) *domain.TaskID {
+	if !s.Valid {
+		return nil
+	}
+	id := domain.TaskID(s.String)
+	return &id
+}
+
+func ptrToNullTaskID(id *domain.TaskID) sql.NullString {
+	if id == nil {
+		return sql.NullString{}
 	}
+	return sql.NullString{String: id.String(), Valid: true}
 }
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/takumi616/go-restapi/domain"
+)
+
+// The blockers of a task, read as the JSON array aggregated for each task
+type TaskBlockerResults []*TaskBlockerResult
+
+type TaskBlockerResult struct {
+	Id    string `json:"id"`
+	Title string `json:"title"`
+	State string `json:"state"`
+}
+
+func (t *TaskBlockerResults) Scan(src any) error {
+	switch src := src.(type) {
+	case []byte:
+		return json.Unmarshal(src, t)
+	case string:
+		return json.Unmarshal([]byte(src), t)
+	case nil:
+		*t = nil
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into task blockers", src)
+	}
+}
+
+func toTaskBlockers(results TaskBlockerResults) []*domain.TaskBlocker {
+	var blockers []*domain.TaskBlocker
+	for _, result := range results {
+		blockers = append(blockers, &domain.TaskBlocker{
+			Id:    domain.TaskID(result.Id),
+			Title: result.Title,
+			State: domain.TaskState(result.State),
+		})
+	}
+	return blockers
+}
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/takumi616/go-restapi/domain"
+)
+
+// Email and password hash are NULL for users of an identity provider
+type UserResult struct {
+	Id           string
+	Email        sql.NullString
+	PasswordHash sql.NullString
+	Role         string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func ToUserDomain(result *UserResult) *domain.User {
+	return &domain.User{
+		Id:           domain.UserID(result.Id),
+		Email:        result.Email.String,
+		PasswordHash: result.PasswordHash.String,
+		Role:         domain.Role(result.Role),
+		CreatedAt:    result.CreatedAt,
+		UpdatedAt:    result.UpdatedAt,
+	}
+}
+
+type PersonalAccessTokenResult struct {
+	Id         string
+	UserId     string
+	Name       string
+	Scopes     []string
+	ExpiresAt  time.Time
+	LastUsedAt sql.NullTime
+	CreatedAt  time.Time
+}
+
+func ToPersonalAccessTokenDomain(result *PersonalAccessTokenResult) *domain.PersonalAccessToken {
+	scopes := make([]domain.TokenScope, len(result.Scopes))
+	for i, scope := range result.Scopes {
+		scopes[i] = domain.TokenScope(scope)
+	}
+
+	return &domain.PersonalAccessToken{
+		Id:         domain.PersonalAccessTokenID(result.Id),
+		UserId:     domain.UserID(result.UserId),
+		Name:       result.Name,
+		Scopes:     scopes,
+		ExpiresAt:  result.ExpiresAt,
+		LastUsedAt: nullTimeToPtr(result.LastUsedAt),
+		CreatedAt:  result.CreatedAt,
+	}
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+
+	"github.com/lib/pq"
+	"github.com/takumi616/go-restapi/domain"
+	"github.com/takumi616/go-restapi/infrastructure/db/repository/model"
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+// Columns read into model.PersonalAccessTokenResult, in the order
+// scanPersonalAccessToken expects
+const personalAccessTokenColumns = "id, user_id, name, scopes, expires_at, last_used_at, created_at"
+
+func scanPersonalAccessToken(row rowScanner, result *model.PersonalAccessTokenResult) error {
+	return row.Scan(
+		&result.Id, &result.UserId, &result.Name, pq.Array(&result.Scopes),
+		&result.ExpiresAt, &result.LastUsedAt, &result.CreatedAt,
+	)
+}
+
+// InsertPersonalAccessToken stores a new token. ErrNotFound is returned if
+// its user does not exist
+func (r *UserRepository) InsertPersonalAccessToken(
+	ctx context.Context, token *domain.PersonalAccessToken,
+) (*domain.PersonalAccessToken, error) {
+	scopes := make([]string, len(token.Scopes))
+	for i, scope := range token.Scopes {
+		scopes[i] = string(scope)
+	}
+
+	var result model.PersonalAccessTokenResult
+	err := scanPersonalAccessToken(r.Db.QueryRowContext(
+		ctx,
+		`INSERT INTO personal_access_tokens(user_id, name, token_hash, scopes, expires_at)
+		VALUES($1, $2, $3, $4, $5) RETURNING `+personalAccessTokenColumns,
+		token.UserId, token.Name, token.Hash, pq.Array(scopes), token.ExpiresAt,
+	), &result)
+
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" { // foreign_key_violation
+			return nil, customError.ErrNotFound
+		}
+		return nil, customError.ErrInternalServerError
+	}
+
+	return model.ToPersonalAccessTokenDomain(&result), nil
+}
+
+// SelectPersonalAccessTokens reads the tokens of a user that have not been
+// revoked, newest first. Expired ones are included until revoked
+func (r *UserRepository) SelectPersonalAccessTokens(
+	ctx context.Context, userId domain.UserID,
+) ([]*domain.PersonalAccessToken, error) {
+	rows, err := r.Db.QueryContext(
+		ctx,
+		`SELECT `+personalAccessTokenColumns+` FROM personal_access_tokens
+		WHERE user_id = $1 AND revoked_at IS NULL ORDER BY created_at DESC, id`,
+		userId,
+	)
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		return nil, customError.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	tokens := []*domain.PersonalAccessToken{}
+	for rows.Next() {
+		var result model.PersonalAccessTokenResult
+		if err := scanPersonalAccessToken(rows, &result); err != nil {
+			slog.ErrorContext(ctx, err.Error())
+			return nil, customError.ErrInternalServerError
+		}
+		tokens = append(tokens, model.ToPersonalAccessTokenDomain(&result))
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		return nil, customError.ErrInternalServerError
+	}
+
+	return tokens, nil
+}
+
+// UsePersonalAccessToken finds the live token with the hash and records that
+// it was used just now. Revoked and expired tokens are not found
+func (r *UserRepository) UsePersonalAccessToken(ctx context.Context, hash string) (*domain.PersonalAccessToken, error) {
+	var result model.PersonalAccessTokenResult
+	err := scanPersonalAccessToken(r.Db.QueryRowContext(
+		ctx,
+		`UPDATE personal_access_tokens SET last_used_at=now()
+		WHERE token_hash=$1 AND revoked_at IS NULL AND expires_at > now()
+		RETURNING `+personalAccessTokenColumns,
+		hash,
+	), &result)
+
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customError.ErrNotFound
+		}
+		return nil, customError.ErrInternalServerError
+	}
+
+	return model.ToPersonalAccessTokenDomain(&result), nil
+}
+
+// RevokePersonalAccessToken revokes a token of the user. A token of someone
+// else is as much not found as one that does not exist
+func (r *UserRepository) RevokePersonalAccessToken(
+	ctx context.Context, userId domain.UserID, id domain.PersonalAccessTokenID,
+) error {
+	result, err := r.Db.ExecContext(
+		ctx,
+		"UPDATE personal_access_tokens SET revoked_at=now() WHERE id=$1 AND user_id=$2 AND revoked_at IS NULL",
+		id, userId,
+	)
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		return customError.ErrInternalServerError
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		return customError.ErrInternalServerError
+	}
+	if affected == 0 {
+		return customError.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/takumi616/go-restapi/domain"
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+var personalAccessTokenColumnNames = []string{"id", "user_id", "name", "scopes", "expires_at", "last_used_at", "created_at"}
+
+var testTokenExpiresAt = time.Date(2025, 4, 2, 3, 4, 5, 0, time.UTC)
+
+const testTokenHash = "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"
+
+func TestInsertPersonalAccessToken(t *testing.T) {
+	type expected struct {
+		token *domain.PersonalAccessToken
+		err   error
+	}
+
+	insertQuery := regexp.QuoteMeta(
+		`INSERT INTO personal_access_tokens(user_id, name, token_hash, scopes, expires_at)
+		VALUES($1, $2, $3, $4, $5) RETURNING id, user_id, name, scopes, expires_at, last_used_at, created_at`,
+	)
+	input := &domain.PersonalAccessToken{
+		UserId:    "7c9e6679-7425-40de-944b-e07fc1f90ae7",
+		Name:      "ci",
+		Hash:      testTokenHash,
+		Scopes:    []domain.TokenScope{domain.ScopeTasksRead, domain.ScopeTasksWrite},
+		ExpiresAt: testTokenExpiresAt,
+	}
+
+	testTable := map[string]struct {
+		mockSetup func(sqlmock.Sqlmock)
+		expected  expected
+	}{
+		"Ok": {
+			mockSetup: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(insertQuery).
+					WithArgs(input.UserId, "ci", testTokenHash, pq.Array([]string{"tasks:read", "tasks:write"}), testTokenExpiresAt).
+					WillReturnRows(sqlmock.NewRows(personalAccessTokenColumnNames).AddRow(
+						"0b7d9c2e-5f1a-4e3b-9c8d-7a6b5c4d3e2f", "7c9e6679-7425-40de-944b-e07fc1f90ae7", "ci",
+						"{tasks:read,tasks:write}", testTokenExpiresAt, nil, testTime,
+					))
+			},
+			expected: expected{
+				token: &domain.PersonalAccessToken{
+					Id:        "0b7d9c2e-5f1a-4e3b-9c8d-7a6b5c4d3e2f",
+					UserId:    "7c9e6679-7425-40de-944b-e07fc1f90ae7",
+					Name:      "ci",
+					Scopes:    []domain.TokenScope{domain.ScopeTasksRead, domain.ScopeTasksWrite},
+					ExpiresAt: testTokenExpiresAt,
+					CreatedAt: testTime,
+				},
+			},
+		},
+		"UserNotFound": {
+			mockSetup: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(insertQuery).
+					WillReturnError(&pq.Error{Code: "23503", Constraint: "personal_access_tokens_user_id_fkey"})
+			},
+			expected: expected{
+				err: customError.ErrNotFound,
+			},
+		},
+		"InternalServerError": {
+			mockSetup: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(insertQuery).
+					WillReturnError(errors.New("pq: connection reset by peer"))
+			},
+			expected: expected{
+				err: customError.ErrInternalServerError,
+			},
+		},
+	}
+
+	for n, tt := range testTable {
+		t.Run(n, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+			require.NoError(t, err)
+			defer db.Close()
+
+			tt.mockSetup(mock)
+
+			repo := &UserRepository{Db: db}
+			result, err := repo.InsertPersonalAccessToken(context.Background(), input)
+
+			assert.ErrorIs(t, err, tt.expected.err)
+			assert.Equal(t, tt.expected.token, result)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSelectPersonalAccessTokens(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer db.Close()
+
+	lastUsedAt := time.Date(2025, 2, 3, 4, 5, 6, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT id, user_id, name, scopes, expires_at, last_used_at, created_at FROM personal_access_tokens
+		WHERE user_id = $1 AND revoked_at IS NULL ORDER BY created_at DESC, id`,
+	)).
+		WithArgs("7c9e6679-7425-40de-944b-e07fc1f90ae7").
+		WillReturnRows(sqlmock.NewRows(personalAccessTokenColumnNames).
+			AddRow(
+				"5d4c3b2a-1f0e-4d9c-8b7a-6f5e4d3c2b1a", "7c9e6679-7425-40de-944b-e07fc1f90ae7", "nightly report",
+				"{tasks:read}", testTokenExpiresAt, lastUsedAt, testTime,
+			))
+
+	repo := &UserRepository{Db: db}
+	result, err := repo.SelectPersonalAccessTokens(context.Background(), "7c9e6679-7425-40de-944b-e07fc1f90ae7")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*domain.PersonalAccessToken{{
+		Id:         "5d4c3b2a-1f0e-4d9c-8b7a-6f5e4d3c2b1a",
+		UserId:     "7c9e6679-7425-40de-944b-e07fc1f90ae7",
+		Name:       "nightly report",
+		Scopes:     []domain.TokenScope{domain.ScopeTasksRead},
+		ExpiresAt:  testTokenExpiresAt,
+		LastUsedAt: &lastUsedAt,
+		CreatedAt:  testTime,
+	}}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUsePersonalAccessToken(t *testing.T) {
+	useQuery := regexp.QuoteMeta(
+		`UPDATE personal_access_tokens SET last_used_at=now()
+		WHERE token_hash=$1 AND revoked_at IS NULL AND expires_at > now()
+		RETURNING id, user_id, name, scopes, expires_at, last_used_at, created_at`,
+	)
+
+	t.Run("Ok", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(useQuery).
+			WithArgs(testTokenHash).
+			WillReturnRows(sqlmock.NewRows(personalAccessTokenColumnNames).AddRow(
+				"0b7d9c2e-5f1a-4e3b-9c8d-7a6b5c4d3e2f", "7c9e6679-7425-40de-944b-e07fc1f90ae7", "ci",
+				"{tasks:write}", testTokenExpiresAt, testTime, testTime,
+			))
+
+		repo := &UserRepository{Db: db}
+		result, err := repo.UsePersonalAccessToken(context.Background(), testTokenHash)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.UserID("7c9e6679-7425-40de-944b-e07fc1f90ae7"), result.UserId)
+		assert.Equal(t, []domain.TokenScope{domain.ScopeTasksWrite}, result.Scopes)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(useQuery).
+			WithArgs(testTokenHash).
+			WillReturnError(sql.ErrNoRows)
+
+		repo := &UserRepository{Db: db}
+		result, err := repo.UsePersonalAccessToken(context.Background(), testTokenHash)
+
+		assert.ErrorIs(t, err, customError.ErrNotFound)
+		assert.Nil(t, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRevokePersonalAccessToken(t *testing.T) {
+	revokeQuery := regexp.QuoteMeta(
+		"UPDATE personal_access_tokens SET revoked_at=now() WHERE id=$1 AND user_id=$2 AND revoked_at IS NULL",
+	)
+
+	testTable := map[string]struct {
+		affected int64
+		expected error
+	}{
+		"Ok":       {affected: 1, expected: nil},
+		"NotFound": {affected: 0, expected: customError.ErrNotFound},
+	}
+
+	for n, tt := range testTable {
+		t.Run(n, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+			require.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectExec(revokeQuery).
+				WithArgs("0b7d9c2e-5f1a-4e3b-9c8d-7a6b5c4d3e2f", "7c9e6679-7425-40de-944b-e07fc1f90ae7").
+				WillReturnResult(sqlmock.NewResult(0, tt.affected))
+
+			repo := &UserRepository{Db: db}
+			err = repo.RevokePersonalAccessToken(
+				context.Background(), "7c9e6679-7425-40de-944b-e07fc1f90ae7", "0b7d9c2e-5f1a-4e3b-9c8d-7a6b5c4d3e2f",
+			)
+
+			assert.ErrorIs(t, err, tt.expected)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
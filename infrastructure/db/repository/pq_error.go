@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/lib/pq"
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+// Column guarded by each unique constraint or index on tasks
+var taskUniqueColumns = map[string]string{
+	"tasks_title_key": "title",
+}
+
+// Field that refers to another row, for each foreign key of tasks and
+// their join tables
+var taskForeignKeyFields = map[string]string{
+	"tasks_parent_id_fkey":              "parent_id",
+	"task_labels_label_id_fkey":         "labels",
+	"task_dependencies_blocker_id_fkey": "blocker_id",
+}
+
+// Turn a constraint violation reported by Postgres into a typed error naming
+// the offending column. nil is returned for any other error
+func taskConstraintError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return nil
+	}
+
+	switch pqErr.Code {
+	case "23505": // unique_violation
+		return &customError.FieldError{Field: taskUniqueColumns[pqErr.Constraint], Err: customError.ErrDuplicate}
+	case "23502": // not_null_violation
+		return &customError.FieldError{Field: pqErr.Column, Err: customError.ErrNotNull}
+	case "23503": // foreign_key_violation
+		return &customError.FieldError{Field: taskForeignKeyFields[pqErr.Constraint], Err: customError.ErrNotFound}
+	case "22001": // string_data_right_truncation
+		// Postgres does not say which column overflowed, but title is
+		// the only length-limited column of tasks
+		return &customError.FieldError{Field: "title", Err: customError.ErrValueTooLong}
+	default:
+		return nil
+	}
+}
+
+// Column guarded by each unique constraint or index on labels
+var labelUniqueColumns = map[string]string{
+	"labels_name_key": "name",
+}
+
+// Like taskConstraintError, for constraints on labels
+func labelConstraintError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return nil
+	}
+
+	switch pqErr.Code {
+	case "23505": // unique_violation
+		return &customError.FieldError{Field: labelUniqueColumns[pqErr.Constraint], Err: customError.ErrDuplicate}
+	case "22001": // string_data_right_truncation
+		// The color has a fixed length checked before it is written,
+		// so the name is the column that overflowed
+		return &customError.FieldError{Field: "name", Err: customError.ErrValueTooLong}
+	default:
+		return nil
+	}
+}
+
+// Column guarded by each unique constraint or index on users
+var userUniqueColumns = map[string]string{
+	"users_email_key": "email",
+}
+
+// Like taskConstraintError, for constraints on users
+func userConstraintError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return nil
+	}
+
+	switch pqErr.Code {
+	case "23505": // unique_violation
+		return &customError.FieldError{Field: userUniqueColumns[pqErr.Constraint], Err: customError.ErrDuplicate}
+	default:
+		return nil
+	}
+}
@@ -4,13 +4,70 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"log/slog"
+	"slices"
+	"time"
 
+	"github.com/lib/pq"
 	"github.com/takumi616/go-restapi/domain"
 	"github.com/takumi616/go-restapi/infrastructure/db/repository/model"
 	customError "github.com/takumi616/go-restapi/shared/error"
 )
 
+// Columns read into model.TaskResult, in the order scanTask expects
+const taskColumns = "id, title, description, state, priority, due_at, estimate_hours, parent_id, version, created_at, updated_at, completed_at, deleted_at, " +
+	taskLabelsColumn + ", " + taskBlockersColumn + ", " + taskProgressColumns + ", " + taskChecklistColumns
+
+// The labels of each task aggregated into a JSON array, so that
+// listing tasks takes one query however many labels they have
+const taskLabelsColumn = `COALESCE((SELECT json_agg(json_build_object('id', l.id, 'name', l.name, 'color', l.color) ORDER BY l.name)
+	FROM task_labels tl JOIN labels l ON l.id = tl.label_id WHERE tl.task_id = tasks.id), '[]')`
+
+// The live tasks each task waits on, aggregated the same way as its labels
+const taskBlockersColumn = `COALESCE((SELECT json_agg(json_build_object('id', b.id, 'title', b.title, 'state', b.state) ORDER BY b.title)
+	FROM task_dependencies d JOIN tasks b ON b.id = d.blocker_id WHERE d.blocked_id = tasks.id AND b.deleted_at IS NULL), '[]')`
+
+// How many live subtasks each task has, and how many of them are done
+const taskProgressColumns = `(SELECT COUNT(*) FROM tasks c WHERE c.parent_id = tasks.id AND c.deleted_at IS NULL),
+	(SELECT COUNT(*) FROM tasks c WHERE c.parent_id = tasks.id AND c.deleted_at IS NULL AND c.state = 'done')`
+
+// How many items the checklist of each task has, and how many of them are done
+const taskChecklistColumns = `(SELECT COUNT(*) FROM task_checklist_items i WHERE i.task_id = tasks.id),
+	(SELECT COUNT(*) FROM task_checklist_items i WHERE i.task_id = tasks.id AND i.done)`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTask(row rowScanner, result *model.TaskResult) error {
+	return row.Scan(
+		&result.Id, &result.Title, &result.Description, &result.State, &result.Priority, &result.DueAt, &result.EstimateHours, &result.ParentId,
+		&result.Version, &result.CreatedAt, &result.UpdatedAt, &result.CompletedAt, &result.DeletedAt, &result.Labels,
+		&result.BlockedBy, &result.Subtasks, &result.CompletedSubtasks,
+		&result.ChecklistItems, &result.CompletedChecklistItems,
+	)
+}
+
+// The user whose tasks a statement may reach, as recorded in the request
+// context. Without one it is NULL, which matches no task at all and cannot
+// own a new one
+func taskOwner(ctx context.Context) sql.NullString {
+	actor, ok := domain.ActorFromContext(ctx)
+	if !ok {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: actor.UserId.String(), Valid: true}
+}
+
+// Implemented by both *sql.DB and *sql.Tx, so that single-task writes
+// can run on their own or as one item of a batch
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
 type TaskRepository struct {
 	Db *sql.DB
 }
@@ -21,38 +78,115 @@ func NewTaskRepository(db *sql.DB) *TaskRepository {
 	}
 }
 
+// Insert adds a task, placed under its parent if it has one and waiting
+// on the tasks it depends on
 func (r *TaskRepository) Insert(ctx context.Context, task *domain.Task) (*domain.Task, error) {
+	if task.ParentId == nil && len(task.BlockedBy) == 0 {
+		return insertTask(ctx, r.Db, task)
+	}
+
+	return r.withTx(ctx, func(tx *sql.Tx) (*domain.Task, error) {
+		return insertTask(ctx, tx, task)
+	})
+}
+
+func insertTask(ctx context.Context, q queryer, task *domain.Task) (*domain.Task, error) {
+	if task.ParentId != nil {
+		if err := placeTask(ctx, q, "", *task.ParentId); err != nil {
+			return nil, err
+		}
+	}
+
 	param := model.ToInsertTaskParam(task)
 
 	var result model.TaskResult
-	err := r.Db.QueryRowContext(
+	err := scanTask(q.QueryRowContext(
 		ctx,
-		`INSERT INTO tasks(title, description, status)
-		VALUES($1, $2, $3)
-		RETURNING id, title, description, status`,
-		param.Title, param.Description, param.Status,
-	).Scan(&result.Id, &result.Title, &result.Description, &result.Status)
+		`INSERT INTO tasks(title, description, state, priority, due_at, estimate_hours, parent_id, owner_id)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING `+taskColumns,
+		param.Title, param.Description, param.State, param.Priority, param.DueAt, param.EstimateHours, param.ParentId, param.OwnerId,
+	), &result)
 
 	if err != nil {
 		slog.ErrorContext(ctx, err.Error())
+		if constraintErr := taskConstraintError(err); constraintErr != nil {
+			return nil, constraintErr
+		}
 		return nil, customError.ErrInternalServerError
 	}
 
+	if len(task.BlockedBy) > 0 {
+		id := domain.TaskID(result.Id)
+		if err := insertBlockers(ctx, q, id, task.BlockedBy); err != nil {
+			return nil, err
+		}
+		// Read again to list the blockers just added
+		return selectTaskById(ctx, q, id)
+	}
+
 	return model.ToDomain(&result), nil
 }
 
-func (r *TaskRepository) SelectAll(ctx context.Context) ([]*domain.Task, error) {
-	rows, err := r.Db.QueryContext(ctx, "SELECT id, title, description, status FROM tasks")
+func (r *TaskRepository) SelectAll(ctx context.Context, query *domain.TaskListQuery) (*domain.TaskList, error) {
+	where, args := buildTaskListCondition(taskOwner(ctx), query)
+
+	// The count and latest update time double as the list's cache validator
+	var total int
+	var lastModified sql.NullTime
+	err := r.Db.QueryRowContext(
+		ctx, "SELECT COUNT(*), MAX(updated_at) FROM tasks"+where, args...,
+	).Scan(&total, &lastModified)
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		return nil, customError.ErrInternalServerError
+	}
+
+	args = append(args, query.Limit, query.Offset)
+	taskList, err := r.selectTasks(
+		ctx,
+		fmt.Sprintf(
+			"SELECT %s FROM tasks%s ORDER BY %s LIMIT $%d OFFSET $%d",
+			taskColumns, where, buildTaskListOrder(query), len(args)-1, len(args),
+		),
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.TaskList{
+		Tasks: taskList, Total: total, LastModified: lastModified.Time,
+	}, nil
+}
+
+func (r *TaskRepository) SelectAfter(ctx context.Context, query *domain.TaskListQuery) ([]*domain.Task, error) {
+	where, args := buildTaskListCondition(taskOwner(ctx), query)
+	where, args = appendTaskKeysetCondition(where, args, query)
+
+	args = append(args, query.Limit)
+	return r.selectTasks(
+		ctx,
+		fmt.Sprintf(
+			"SELECT %s FROM tasks%s ORDER BY %s LIMIT $%d",
+			taskColumns, where, buildTaskListOrder(query), len(args),
+		),
+		args...,
+	)
+}
+
+func (r *TaskRepository) selectTasks(ctx context.Context, query string, args ...any) ([]*domain.Task, error) {
+	rows, err := r.Db.QueryContext(ctx, query, args...)
 	if err != nil {
 		slog.ErrorContext(ctx, err.Error())
 		return nil, customError.ErrInternalServerError
 	}
 	defer rows.Close()
 
-	var taskList []*domain.Task
+	taskList := []*domain.Task{}
 	for rows.Next() {
 		var taskResult model.TaskResult
-		if err := rows.Scan(&taskResult.Id, &taskResult.Title, &taskResult.Description, &taskResult.Status); err != nil {
+		if err := scanTask(rows, &taskResult); err != nil {
 			slog.ErrorContext(ctx, err.Error())
 			return nil, customError.ErrInternalServerError
 		}
@@ -64,18 +198,14 @@ func (r *TaskRepository) SelectAll(ctx context.Context) ([]*domain.Task, error)
 		return nil, customError.ErrInternalServerError
 	}
 
-	if len(taskList) == 0 {
-		return []*domain.Task{}, nil
-	}
-
 	return taskList, nil
 }
 
-func (r *TaskRepository) SelectById(ctx context.Context, id string) (*domain.Task, error) {
+func (r *TaskRepository) SelectById(ctx context.Context, id domain.TaskID) (*domain.Task, error) {
 	var taskRes model.TaskResult
-	err := r.Db.QueryRowContext(
-		ctx, "SELECT id, title, description, status FROM tasks WHERE id = $1", id,
-	).Scan(&taskRes.Id, &taskRes.Title, &taskRes.Description, &taskRes.Status)
+	err := scanTask(r.Db.QueryRowContext(
+		ctx, "SELECT "+taskColumns+" FROM tasks WHERE id = $1 AND deleted_at IS NULL AND owner_id = $2", id, taskOwner(ctx),
+	), &taskRes)
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -90,21 +220,54 @@ func (r *TaskRepository) SelectById(ctx context.Context, id string) (*domain.Tas
 	return model.ToDomain(&taskRes), nil
 }
 
-func (r *TaskRepository) Update(ctx context.Context, id string, task *domain.Task) (*domain.Task, error) {
-	param := model.ToUpdateTaskParam(task)
+// Update applies the change only while the stored version still equals
+// the expected one, so that the check and the write happen atomically.
+// An expected version of 0 updates unconditionally.
+// completed_at keeps the time a task was first done, survives archiving
+// and is cleared when the task is reopened
+func (r *TaskRepository) Update(ctx context.Context, id domain.TaskID, patch *domain.TaskPatch) (*domain.Task, error) {
+	if patch.ParentId == nil || patch.ClearParent {
+		return updateTask(ctx, r.Db, id, patch)
+	}
+
+	return r.withTx(ctx, func(tx *sql.Tx) (*domain.Task, error) {
+		return updateTask(ctx, tx, id, patch)
+	})
+}
+
+func updateTask(ctx context.Context, q queryer, id domain.TaskID, patch *domain.TaskPatch) (*domain.Task, error) {
+	if patch.ParentId != nil && !patch.ClearParent {
+		if err := placeTask(ctx, q, id, *patch.ParentId); err != nil {
+			return nil, err
+		}
+	}
+
+	param := model.ToUpdateTaskParam(patch)
 
 	var result model.TaskResult
-	err := r.Db.QueryRowContext(
+	err := scanTask(q.QueryRowContext(
 		ctx,
-		`UPDATE tasks SET description=$1, status=$2 WHERE id=$3
-		RETURNING id, title, description, status`,
-		param.Description, param.Status, id,
-	).Scan(&result.Id, &result.Title, &result.Description, &result.Status)
+		`UPDATE tasks SET title=COALESCE($1, title), description=COALESCE($2, description),
+		state=COALESCE($3, state), priority=COALESCE($6, priority),
+		due_at=CASE WHEN $8 THEN NULL ELSE COALESCE($7, due_at) END,
+		estimate_hours=CASE WHEN $12 THEN NULL ELSE COALESCE($11, estimate_hours) END,
+		parent_id=CASE WHEN $10 THEN NULL ELSE COALESCE($9, parent_id) END, version=version+1, updated_at=now(),
+		completed_at=CASE COALESCE($3, state) WHEN 'done' THEN COALESCE(completed_at, now()) WHEN 'archived' THEN completed_at END
+		WHERE id=$4 AND deleted_at IS NULL AND ($5 = 0 OR version=$5) AND owner_id=$13
+		RETURNING `+taskColumns,
+		param.Title, param.Description, param.State, id, param.Version, param.Priority, param.DueAt, param.ClearDueAt,
+		param.ParentId, param.ClearParent, param.EstimateHours, param.ClearEstimate, taskOwner(ctx),
+	), &result)
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			slog.ErrorContext(ctx, err.Error())
-			return nil, customError.ErrNotFound
+			return nil, missingOrStale(ctx, q, id, param.Version, false)
+		}
+
+		if constraintErr := taskConstraintError(err); constraintErr != nil {
+			slog.ErrorContext(ctx, err.Error())
+			return nil, constraintErr
 		}
 
 		slog.ErrorContext(ctx, err.Error())
@@ -114,24 +277,265 @@ func (r *TaskRepository) Update(ctx context.Context, id string, task *domain.Tas
 	return model.ToDomain(&result), nil
 }
 
-func (r *TaskRepository) Delete(ctx context.Context, id string) (*domain.Task, error) {
+// SetLabels replaces every label of a task. The task counts as modified,
+// so its version and update time move on as with any other change
+func (r *TaskRepository) SetLabels(ctx context.Context, id domain.TaskID, labelIds []domain.LabelID, version int) (*domain.Task, error) {
+	return r.withTx(ctx, func(tx *sql.Tx) (*domain.Task, error) {
+		var touchedId string
+		err := tx.QueryRowContext(
+			ctx,
+			`UPDATE tasks SET version=version+1, updated_at=now()
+			WHERE id=$1 AND deleted_at IS NULL AND ($2 = 0 OR version=$2) AND owner_id=$3
+			RETURNING id`,
+			id, version, taskOwner(ctx),
+		).Scan(&touchedId)
+
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				slog.ErrorContext(ctx, err.Error())
+				return nil, missingOrStale(ctx, tx, id, version, false)
+			}
+
+			slog.ErrorContext(ctx, err.Error())
+			return nil, customError.ErrInternalServerError
+		}
+
+		if _, err := tx.ExecContext(ctx, "DELETE FROM task_labels WHERE task_id=$1", id); err != nil {
+			slog.ErrorContext(ctx, err.Error())
+			return nil, customError.ErrInternalServerError
+		}
+
+		ids := []string{}
+		for _, labelId := range labelIds {
+			if !slices.Contains(ids, labelId.String()) {
+				ids = append(ids, labelId.String())
+			}
+		}
+
+		// Only labels of the owner of the task can go on it. Any other is
+		// as good as missing
+		res, err := tx.ExecContext(
+			ctx,
+			"INSERT INTO task_labels(task_id, label_id) SELECT $1, id FROM labels WHERE id = ANY($2::uuid[]) AND owner_id = $3",
+			id, pq.Array(ids), taskOwner(ctx),
+		)
+		if err != nil {
+			slog.ErrorContext(ctx, err.Error())
+			return nil, customError.ErrInternalServerError
+		}
+
+		inserted, err := res.RowsAffected()
+		if err != nil {
+			slog.ErrorContext(ctx, err.Error())
+			return nil, customError.ErrInternalServerError
+		}
+		if inserted != int64(len(ids)) {
+			return nil, &customError.FieldError{Field: "labels", Err: customError.ErrNotFound}
+		}
+
+		return selectTaskById(ctx, tx, id)
+	})
+}
+
+// Read a task as written so far by the enclosing transaction
+func selectTaskById(ctx context.Context, q queryer, id domain.TaskID) (*domain.Task, error) {
+	var result model.TaskResult
+	err := scanTask(q.QueryRowContext(
+		ctx, "SELECT "+taskColumns+" FROM tasks WHERE id = $1", id,
+	), &result)
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		return nil, customError.ErrInternalServerError
+	}
+
+	return model.ToDomain(&result), nil
+}
+
+// Run a write that takes several statements in a transaction of its own.
+// Nothing is committed unless the write succeeds
+func (r *TaskRepository) withTx(ctx context.Context, write func(tx *sql.Tx) (*domain.Task, error)) (*domain.Task, error) {
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		return nil, customError.ErrInternalServerError
+	}
+	defer tx.Rollback()
+
+	task, err := write(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		return nil, customError.ErrInternalServerError
+	}
+
+	return task, nil
+}
+
+// Delete moves a task to the trash, from where it can still be restored.
+// Its subtasks are dealt with as mode says
+func (r *TaskRepository) Delete(ctx context.Context, id domain.TaskID, version int, mode domain.SubtaskMode) (*domain.Task, error) {
+	return r.withTx(ctx, func(tx *sql.Tx) (*domain.Task, error) {
+		return deleteTask(ctx, tx, id, version, mode)
+	})
+}
+
+func deleteTask(ctx context.Context, q queryer, id domain.TaskID, version int, mode domain.SubtaskMode) (*domain.Task, error) {
+	if _, err := q.ExecContext(ctx, lockTaskHierarchy); err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		return nil, customError.ErrInternalServerError
+	}
+
 	var deletedId string
-	err := r.Db.QueryRowContext(
-		ctx, "DELETE FROM tasks WHERE id=$1 RETURNING id", id,
+	err := q.QueryRowContext(
+		ctx,
+		`UPDATE tasks SET deleted_at=now()
+		WHERE id=$1 AND deleted_at IS NULL AND ($2 = 0 OR version=$2) AND owner_id=$3
+		RETURNING id`,
+		id, version, taskOwner(ctx),
 	).Scan(&deletedId)
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			slog.ErrorContext(ctx, err.Error())
-			return nil, customError.ErrNotFound
+			return nil, missingOrStale(ctx, q, id, version, false)
 		}
 
 		slog.ErrorContext(ctx, err.Error())
 		return nil, customError.ErrInternalServerError
 	}
 
+	if err := trashSubtasks(ctx, q, id, mode); err != nil {
+		return nil, err
+	}
+
 	task := &domain.Task{}
-	task.Id = deletedId
+	task.Id = domain.TaskID(deletedId)
 
 	return task, nil
 }
+
+func (r *TaskRepository) SelectTrash(ctx context.Context) ([]*domain.Task, error) {
+	return r.selectTasks(
+		ctx,
+		"SELECT "+taskColumns+" FROM tasks WHERE deleted_at IS NOT NULL AND owner_id = $1 ORDER BY deleted_at DESC, id DESC",
+		taskOwner(ctx),
+	)
+}
+
+// Restore takes a task out of the trash. A task whose parent is still in
+// the trash comes back at the top level
+func (r *TaskRepository) Restore(ctx context.Context, id domain.TaskID) (*domain.Task, error) {
+	var result model.TaskResult
+	err := scanTask(r.Db.QueryRowContext(
+		ctx,
+		`UPDATE tasks SET deleted_at=NULL, version=version+1, updated_at=now(),
+		parent_id=(SELECT p.id FROM tasks p WHERE p.id = tasks.parent_id AND p.deleted_at IS NULL)
+		WHERE id=$1 AND deleted_at IS NOT NULL AND owner_id=$2
+		RETURNING `+taskColumns,
+		id, taskOwner(ctx),
+	), &result)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			slog.ErrorContext(ctx, err.Error())
+			return nil, customError.ErrNotFound
+		}
+
+		// A task with the same title may have been created since this one was trashed
+		if constraintErr := taskConstraintError(err); constraintErr != nil {
+			slog.ErrorContext(ctx, err.Error())
+			return nil, constraintErr
+		}
+
+		slog.ErrorContext(ctx, err.Error())
+		return nil, customError.ErrInternalServerError
+	}
+
+	return model.ToDomain(&result), nil
+}
+
+// Purge removes a task for good, whether or not it is in the trash.
+// Its subtasks are dealt with as mode says
+func (r *TaskRepository) Purge(ctx context.Context, id domain.TaskID, version int, mode domain.SubtaskMode) (*domain.Task, error) {
+	return r.withTx(ctx, func(tx *sql.Tx) (*domain.Task, error) {
+		if _, err := tx.ExecContext(ctx, lockTaskHierarchy); err != nil {
+			slog.ErrorContext(ctx, err.Error())
+			return nil, customError.ErrInternalServerError
+		}
+
+		var parentId sql.NullString
+		err := tx.QueryRowContext(
+			ctx, "SELECT parent_id FROM tasks WHERE id=$1 AND ($2 = 0 OR version=$2) AND owner_id=$3 FOR UPDATE",
+			id, version, taskOwner(ctx),
+		).Scan(&parentId)
+
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				slog.ErrorContext(ctx, err.Error())
+				return nil, missingOrStale(ctx, tx, id, version, true)
+			}
+
+			slog.ErrorContext(ctx, err.Error())
+			return nil, customError.ErrInternalServerError
+		}
+
+		if err := purgeSubtasks(ctx, tx, id, parentId, mode); err != nil {
+			return nil, err
+		}
+
+		if _, err := tx.ExecContext(ctx, "DELETE FROM tasks WHERE id=$1", id); err != nil {
+			slog.ErrorContext(ctx, err.Error())
+			return nil, customError.ErrInternalServerError
+		}
+
+		return &domain.Task{Id: id}, nil
+	})
+}
+
+// PurgeDeletedBefore empties the trash of every owner at once
+func (r *TaskRepository) PurgeDeletedBefore(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.Db.ExecContext(
+		ctx, "DELETE FROM tasks WHERE deleted_at < $1", before,
+	)
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		return 0, customError.ErrInternalServerError
+	}
+
+	purged, err := result.RowsAffected()
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		return 0, customError.ErrInternalServerError
+	}
+
+	return purged, nil
+}
+
+// Tell apart why a conditional write matched no row.
+// Trashed tasks only count as existing for writes that can reach the trash,
+// and the tasks of other owners never do, so that they look just as missing
+func missingOrStale(ctx context.Context, q queryer, id domain.TaskID, version int, withTrash bool) error {
+	if version == 0 {
+		return customError.ErrNotFound
+	}
+
+	query := "SELECT EXISTS(SELECT 1 FROM tasks WHERE id = $1 AND deleted_at IS NULL AND owner_id = $2)"
+	if withTrash {
+		query = "SELECT EXISTS(SELECT 1 FROM tasks WHERE id = $1 AND owner_id = $2)"
+	}
+
+	var exists bool
+	err := q.QueryRowContext(ctx, query, id, taskOwner(ctx)).Scan(&exists)
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		return customError.ErrInternalServerError
+	}
+
+	if exists {
+		return customError.ErrVersionMismatch
+	}
+	return customError.ErrNotFound
+}
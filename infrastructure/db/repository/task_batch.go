@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+
+	"github.com/takumi616/go-restapi/domain"
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+func (r *TaskRepository) InsertBatch(ctx context.Context, tasks []*domain.Task, atomic bool) ([]*domain.TaskBatchResult, error) {
+	return r.runBatch(ctx, len(tasks), atomic, func(tx *sql.Tx, i int) (*domain.Task, error) {
+		return insertTask(ctx, tx, tasks[i])
+	})
+}
+
+func (r *TaskRepository) UpdateBatch(ctx context.Context, updates []*domain.TaskBatchUpdate, atomic bool) ([]*domain.TaskBatchResult, error) {
+	return r.runBatch(ctx, len(updates), atomic, func(tx *sql.Tx, i int) (*domain.Task, error) {
+		return updateTask(ctx, tx, updates[i].Id, updates[i].Patch)
+	})
+}
+
+func (r *TaskRepository) DeleteBatch(ctx context.Context, deletes []*domain.TaskBatchDelete, atomic bool) ([]*domain.TaskBatchResult, error) {
+	return r.runBatch(ctx, len(deletes), atomic, func(tx *sql.Tx, i int) (*domain.Task, error) {
+		return deleteTask(ctx, tx, deletes[i].Id, deletes[i].Version, deletes[i].Subtasks)
+	})
+}
+
+// Run n writes in one transaction. In atomic mode the first failing item
+// rolls back the whole batch and every other item is reported as aborted.
+// Otherwise each item runs under its own savepoint, so a failure only
+// undoes that item and the rest are still committed
+func (r *TaskRepository) runBatch(ctx context.Context, n int, atomic bool, write func(tx *sql.Tx, i int) (*domain.Task, error)) ([]*domain.TaskBatchResult, error) {
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		return nil, customError.ErrInternalServerError
+	}
+	defer tx.Rollback()
+
+	results := make([]*domain.TaskBatchResult, n)
+	for i := 0; i < n; i++ {
+		if !atomic {
+			if _, err := tx.ExecContext(ctx, "SAVEPOINT batch_item"); err != nil {
+				slog.ErrorContext(ctx, err.Error())
+				return nil, customError.ErrInternalServerError
+			}
+		}
+
+		task, err := write(tx, i)
+		if err != nil {
+			if atomic {
+				return abortBatch(n, i, err), nil
+			}
+
+			results[i] = &domain.TaskBatchResult{Err: err}
+			if _, err := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT batch_item"); err != nil {
+				slog.ErrorContext(ctx, err.Error())
+				return nil, customError.ErrInternalServerError
+			}
+			continue
+		}
+
+		results[i] = &domain.TaskBatchResult{Task: task}
+		if !atomic {
+			if _, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT batch_item"); err != nil {
+				slog.ErrorContext(ctx, err.Error())
+				return nil, customError.ErrInternalServerError
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		return nil, customError.ErrInternalServerError
+	}
+
+	return results, nil
+}
+
+func abortBatch(n, failed int, err error) []*domain.TaskBatchResult {
+	results := make([]*domain.TaskBatchResult, n)
+	for i := range results {
+		results[i] = &domain.TaskBatchResult{Err: customError.ErrBatchAborted}
+	}
+	results[failed].Err = err
+	return results
+}
@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/takumi616/go-restapi/domain"
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+const (
+	testInsertQuery = `INSERT INTO tasks(title, description, state, priority, due_at, estimate_hours, parent_id, owner_id)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, title, description, state, priority, due_at, estimate_hours, parent_id, version, created_at, updated_at, completed_at, deleted_at, ` + testComputedColumns
+	testDeleteQuery = `UPDATE tasks SET deleted_at=now()
+		WHERE id=$1 AND deleted_at IS NULL AND ($2 = 0 OR version=$2) AND owner_id=$3
+		RETURNING id`
+	testLockHierarchy     = "SELECT pg_advisory_xact_lock(hashtext('tasks.parent_id'))"
+	testLiveSubtasksQuery = "SELECT EXISTS(SELECT 1 FROM tasks WHERE parent_id = $1 AND deleted_at IS NULL)"
+)
+
+func testTaskRow(id, title string) *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "title", "description", "state", "priority", "due_at", "estimate_hours", "parent_id", "version", "created_at", "updated_at", "completed_at", "deleted_at", "labels", "blocked_by", "subtasks", "completed_subtasks", "checklist_items", "completed_checklist_items"}).
+		AddRow(id, title, "Test Description", "todo", "P2", nil, nil, nil, 1, testTime, testTime, nil, nil, "[]", "[]", 0, 0, 0, 0)
+}
+
+func testTask(id, title string) *domain.Task {
+	return &domain.Task{
+		Id:          domain.TaskID(id),
+		Title:       title,
+		Description: "Test Description",
+		State:       domain.TaskStateTodo,
+		Priority:    domain.TaskPriorityP2,
+		Version:     1,
+		CreatedAt:   testTime,
+		UpdatedAt:   testTime,
+	}
+}
+
+func TestInsertBatch(t *testing.T) {
+	input := []*domain.Task{
+		{OwnerId: testOwnerId, Title: "First Title", Description: "Test Description", State: domain.TaskStateTodo, Priority: domain.TaskPriorityP2},
+		{OwnerId: testOwnerId, Title: "Second Title", Description: "Test Description", State: domain.TaskStateTodo, Priority: domain.TaskPriorityP2},
+	}
+	insertErr := errors.New("pq: connection reset")
+
+	testTable := map[string]struct {
+		atomic    bool
+		mockSetup func(sqlmock.Sqlmock)
+		expected  []*domain.TaskBatchResult
+		err       error
+	}{
+		"AtomicOk": {
+			atomic: true,
+			mockSetup: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(regexp.QuoteMeta(testInsertQuery)).
+					WithArgs("First Title", "Test Description", "todo", "P2", nil, nil, nil, testOwnerId).
+					WillReturnRows(testTaskRow("6a30b9b0-18bf-47b4-bd23-d72726864def", "First Title"))
+				m.ExpectQuery(regexp.QuoteMeta(testInsertQuery)).
+					WithArgs("Second Title", "Test Description", "todo", "P2", nil, nil, nil, testOwnerId).
+					WillReturnRows(testTaskRow("3e440171-0921-4c88-a7ec-13f4cdab0d69", "Second Title"))
+				m.ExpectCommit()
+			},
+			expected: []*domain.TaskBatchResult{
+				{Task: testTask("6a30b9b0-18bf-47b4-bd23-d72726864def", "First Title")},
+				{Task: testTask("3e440171-0921-4c88-a7ec-13f4cdab0d69", "Second Title")},
+			},
+		},
+		"AtomicItemFails": {
+			atomic: true,
+			mockSetup: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(regexp.QuoteMeta(testInsertQuery)).
+					WithArgs("First Title", "Test Description", "todo", "P2", nil, nil, nil, testOwnerId).
+					WillReturnRows(testTaskRow("6a30b9b0-18bf-47b4-bd23-d72726864def", "First Title"))
+				m.ExpectQuery(regexp.QuoteMeta(testInsertQuery)).
+					WithArgs("Second Title", "Test Description", "todo", "P2", nil, nil, nil, testOwnerId).
+					WillReturnError(insertErr)
+				m.ExpectRollback()
+			},
+			expected: []*domain.TaskBatchResult{
+				{Err: customError.ErrBatchAborted},
+				{Err: customError.ErrInternalServerError},
+			},
+		},
+		"BestEffortItemFails": {
+			atomic: false,
+			mockSetup: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectExec(regexp.QuoteMeta("SAVEPOINT batch_item")).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				m.ExpectQuery(regexp.QuoteMeta(testInsertQuery)).
+					WithArgs("First Title", "Test Description", "todo", "P2", nil, nil, nil, testOwnerId).
+					WillReturnError(insertErr)
+				m.ExpectExec(regexp.QuoteMeta("ROLLBACK TO SAVEPOINT batch_item")).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				m.ExpectExec(regexp.QuoteMeta("SAVEPOINT batch_item")).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				m.ExpectQuery(regexp.QuoteMeta(testInsertQuery)).
+					WithArgs("Second Title", "Test Description", "todo", "P2", nil, nil, nil, testOwnerId).
+					WillReturnRows(testTaskRow("3e440171-0921-4c88-a7ec-13f4cdab0d69", "Second Title"))
+				m.ExpectExec(regexp.QuoteMeta("RELEASE SAVEPOINT batch_item")).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				m.ExpectCommit()
+			},
+			expected: []*domain.TaskBatchResult{
+				{Err: customError.ErrInternalServerError},
+				{Task: testTask("3e440171-0921-4c88-a7ec-13f4cdab0d69", "Second Title")},
+			},
+		},
+		"BeginFails": {
+			atomic: true,
+			mockSetup: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin().WillReturnError(sql.ErrConnDone)
+			},
+			err: customError.ErrInternalServerError,
+		},
+	}
+
+	for n, tt := range testTable {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+			require.NoError(t, err)
+			defer db.Close()
+
+			tt.mockSetup(mock)
+
+			repo := &TaskRepository{Db: db}
+			results, err := repo.InsertBatch(testOwnerCtx, input, tt.atomic)
+
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.expected, results)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUpdateBatch(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer db.Close()
+
+	updates := []*domain.TaskBatchUpdate{
+		{Id: "6a30b9b0-18bf-47b4-bd23-d72726864def", Patch: &domain.TaskPatch{Title: ptr("Renamed Title"), Version: 1}},
+		{Id: "3e440171-0921-4c88-a7ec-13f4cdab0d69", Patch: &domain.TaskPatch{Title: ptr("Other Title"), Version: 4}},
+	}
+	updateQuery := regexp.QuoteMeta(
+		`UPDATE tasks SET title=COALESCE($1, title), description=COALESCE($2, description),
+		state=COALESCE($3, state), priority=COALESCE($6, priority),
+		due_at=CASE WHEN $8 THEN NULL ELSE COALESCE($7, due_at) END,
+		estimate_hours=CASE WHEN $12 THEN NULL ELSE COALESCE($11, estimate_hours) END,
+		parent_id=CASE WHEN $10 THEN NULL ELSE COALESCE($9, parent_id) END, version=version+1, updated_at=now(),
+		completed_at=CASE COALESCE($3, state) WHEN 'done' THEN COALESCE(completed_at, now()) WHEN 'archived' THEN completed_at END
+		WHERE id=$4 AND deleted_at IS NULL AND ($5 = 0 OR version=$5) AND owner_id=$13
+		RETURNING id, title, description, state, priority, due_at, estimate_hours, parent_id, version, created_at, updated_at, completed_at, deleted_at, ` + testComputedColumns,
+	)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("SAVEPOINT batch_item")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(updateQuery).
+		WithArgs("Renamed Title", nil, nil, "6a30b9b0-18bf-47b4-bd23-d72726864def", 1, nil, nil, false, nil, false, nil, false, testOwnerId).
+		WillReturnRows(testTaskRow("6a30b9b0-18bf-47b4-bd23-d72726864def", "Renamed Title"))
+	mock.ExpectExec(regexp.QuoteMeta("RELEASE SAVEPOINT batch_item")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("SAVEPOINT batch_item")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(updateQuery).
+		WithArgs("Other Title", nil, nil, "3e440171-0921-4c88-a7ec-13f4cdab0d69", 4, nil, nil, false, nil, false, nil, false, testOwnerId).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(regexp.QuoteMeta(
+		"SELECT EXISTS(SELECT 1 FROM tasks WHERE id = $1 AND deleted_at IS NULL AND owner_id = $2)",
+	)).
+		WithArgs("3e440171-0921-4c88-a7ec-13f4cdab0d69", testOwnerId).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectExec(regexp.QuoteMeta("ROLLBACK TO SAVEPOINT batch_item")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	repo := &TaskRepository{Db: db}
+	results, err := repo.UpdateBatch(testOwnerCtx, updates, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*domain.TaskBatchResult{
+		{Task: testTask("6a30b9b0-18bf-47b4-bd23-d72726864def", "Renamed Title")},
+		{Err: customError.ErrVersionMismatch},
+	}, results)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteBatch(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer db.Close()
+
+	deletes := []*domain.TaskBatchDelete{
+		{Id: "6a30b9b0-18bf-47b4-bd23-d72726864def"},
+		{Id: "3e440171-0921-4c88-a7ec-13f4cdab0d69", Version: 2, Subtasks: domain.SubtaskModeReparent},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(testLockHierarchy)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(testDeleteQuery)).
+		WithArgs("6a30b9b0-18bf-47b4-bd23-d72726864def", 0, testOwnerId).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("6a30b9b0-18bf-47b4-bd23-d72726864def"))
+	mock.ExpectQuery(regexp.QuoteMeta(testLiveSubtasksQuery)).
+		WithArgs("6a30b9b0-18bf-47b4-bd23-d72726864def").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectExec(regexp.QuoteMeta(testLockHierarchy)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(testDeleteQuery)).
+		WithArgs("3e440171-0921-4c88-a7ec-13f4cdab0d69", 2, testOwnerId).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("3e440171-0921-4c88-a7ec-13f4cdab0d69"))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE tasks SET parent_id=(SELECT parent_id FROM tasks WHERE id=$1)")).
+		WithArgs("3e440171-0921-4c88-a7ec-13f4cdab0d69").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	repo := &TaskRepository{Db: db}
+	results, err := repo.DeleteBatch(testOwnerCtx, deletes, true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*domain.TaskBatchResult{
+		{Task: &domain.Task{Id: "6a30b9b0-18bf-47b4-bd23-d72726864def"}},
+		{Task: &domain.Task{Id: "3e440171-0921-4c88-a7ec-13f4cdab0d69"}},
+	}, results)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
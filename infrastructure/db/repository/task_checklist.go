@@ -0,0 +1,271 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+
+	"github.com/lib/pq"
+	"github.com/takumi616/go-restapi/domain"
+	"github.com/takumi616/go-restapi/infrastructure/db/repository/model"
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+// Columns read into model.ChecklistItemResult, in the order scanChecklistItem expects
+const checklistItemColumns = "id, text, done, position, created_at, updated_at"
+
+func scanChecklistItem(row rowScanner, result *model.ChecklistItemResult) error {
+	return row.Scan(&result.Id, &result.Text, &result.Done, &result.Position, &result.CreatedAt, &result.UpdatedAt)
+}
+
+// SelectChecklist reads the checklist of a live task
+func (r *TaskRepository) SelectChecklist(ctx context.Context, taskId domain.TaskID) (*domain.Checklist, error) {
+	var version int
+	err := r.Db.QueryRowContext(
+		ctx, "SELECT version FROM tasks WHERE id = $1 AND deleted_at IS NULL AND owner_id = $2", taskId, taskOwner(ctx),
+	).Scan(&version)
+
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customError.ErrNotFound
+		}
+		return nil, customError.ErrInternalServerError
+	}
+
+	items, err := selectChecklistItems(ctx, r.Db, taskId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.Checklist{TaskId: taskId, Items: items, Version: version}, nil
+}
+
+// InsertChecklistItem adds an item at position, or at the end when position
+// is nil or past it. The items from there on move down by one
+func (r *TaskRepository) InsertChecklistItem(
+	ctx context.Context, taskId domain.TaskID, text string, position *int, version int,
+) (*domain.Checklist, error) {
+	return r.withChecklist(ctx, taskId, version, func(tx *sql.Tx) error {
+		var count int
+		err := tx.QueryRowContext(
+			ctx, "SELECT COUNT(*) FROM task_checklist_items WHERE task_id = $1", taskId,
+		).Scan(&count)
+		if err != nil {
+			slog.ErrorContext(ctx, err.Error())
+			return customError.ErrInternalServerError
+		}
+
+		if count >= domain.MaxChecklistItems {
+			return customError.ErrTooMany
+		}
+
+		at := count
+		if position != nil && *position < count {
+			at = *position
+		}
+
+		_, err = tx.ExecContext(
+			ctx,
+			"UPDATE task_checklist_items SET position=position+1 WHERE task_id=$1 AND position >= $2",
+			taskId, at,
+		)
+		if err != nil {
+			slog.ErrorContext(ctx, err.Error())
+			return customError.ErrInternalServerError
+		}
+
+		_, err = tx.ExecContext(
+			ctx,
+			"INSERT INTO task_checklist_items(task_id, text, position) VALUES($1, $2, $3)",
+			taskId, text, at,
+		)
+		if err != nil {
+			slog.ErrorContext(ctx, err.Error())
+			return customError.ErrInternalServerError
+		}
+		return nil
+	})
+}
+
+// UpdateChecklistItem changes the text of an item or ticks it on or off
+func (r *TaskRepository) UpdateChecklistItem(
+	ctx context.Context, taskId domain.TaskID, itemId domain.ChecklistItemID, patch *domain.ChecklistItemPatch, version int,
+) (*domain.Checklist, error) {
+	param := model.ToUpdateChecklistItemParam(patch)
+
+	return r.withChecklist(ctx, taskId, version, func(tx *sql.Tx) error {
+		var updatedId string
+		err := tx.QueryRowContext(
+			ctx,
+			`UPDATE task_checklist_items SET text=COALESCE($3, text), done=COALESCE($4, done), updated_at=now()
+			WHERE task_id=$1 AND id=$2
+			RETURNING id`,
+			taskId, itemId, param.Text, param.Done,
+		).Scan(&updatedId)
+
+		if err != nil {
+			slog.ErrorContext(ctx, err.Error())
+			if errors.Is(err, sql.ErrNoRows) {
+				return customError.ErrItemNotFound
+			}
+			return customError.ErrInternalServerError
+		}
+		return nil
+	})
+}
+
+// DeleteChecklistItem removes an item and closes the gap it leaves
+func (r *TaskRepository) DeleteChecklistItem(
+	ctx context.Context, taskId domain.TaskID, itemId domain.ChecklistItemID, version int,
+) (*domain.Checklist, error) {
+	return r.withChecklist(ctx, taskId, version, func(tx *sql.Tx) error {
+		var position int
+		err := tx.QueryRowContext(
+			ctx,
+			"DELETE FROM task_checklist_items WHERE task_id=$1 AND id=$2 RETURNING position",
+			taskId, itemId,
+		).Scan(&position)
+
+		if err != nil {
+			slog.ErrorContext(ctx, err.Error())
+			if errors.Is(err, sql.ErrNoRows) {
+				return customError.ErrItemNotFound
+			}
+			return customError.ErrInternalServerError
+		}
+
+		_, err = tx.ExecContext(
+			ctx,
+			"UPDATE task_checklist_items SET position=position-1 WHERE task_id=$1 AND position > $2",
+			taskId, position,
+		)
+		if err != nil {
+			slog.ErrorContext(ctx, err.Error())
+			return customError.ErrInternalServerError
+		}
+		return nil
+	})
+}
+
+// ReorderChecklist puts the items in the order of itemIds, which has to list
+// every item of the checklist exactly once
+func (r *TaskRepository) ReorderChecklist(
+	ctx context.Context, taskId domain.TaskID, itemIds []domain.ChecklistItemID, version int,
+) (*domain.Checklist, error) {
+	return r.withChecklist(ctx, taskId, version, func(tx *sql.Tx) error {
+		items, err := selectChecklistItems(ctx, tx, taskId)
+		if err != nil {
+			return err
+		}
+
+		current := map[domain.ChecklistItemID]bool{}
+		for _, item := range items {
+			current[item.Id] = true
+		}
+
+		ids := make([]string, len(itemIds))
+		for i, itemId := range itemIds {
+			if !current[itemId] {
+				return customError.ErrOrderMismatch
+			}
+			delete(current, itemId)
+			ids[i] = itemId.String()
+		}
+		if len(current) > 0 {
+			return customError.ErrOrderMismatch
+		}
+
+		_, err = tx.ExecContext(
+			ctx,
+			`UPDATE task_checklist_items i SET position=o.ordinality-1
+			FROM unnest($2::uuid[]) WITH ORDINALITY AS o(id, ordinality)
+			WHERE i.task_id=$1 AND i.id=o.id`,
+			taskId, pq.Array(ids),
+		)
+		if err != nil {
+			slog.ErrorContext(ctx, err.Error())
+			return customError.ErrInternalServerError
+		}
+		return nil
+	})
+}
+
+// Run a write on the checklist of a task in a transaction of its own, and
+// read the checklist back as it left it. The write counts as a change to the
+// task, whose row is updated first. Its lock makes concurrent writes on the
+// same checklist wait for each other, so positions never collide
+func (r *TaskRepository) withChecklist(
+	ctx context.Context, taskId domain.TaskID, version int, write func(tx *sql.Tx) error,
+) (*domain.Checklist, error) {
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		return nil, customError.ErrInternalServerError
+	}
+	defer tx.Rollback()
+
+	var touchedVersion int
+	err = tx.QueryRowContext(
+		ctx,
+		`UPDATE tasks SET version=version+1, updated_at=now()
+		WHERE id=$1 AND deleted_at IS NULL AND ($2 = 0 OR version=$2) AND owner_id=$3
+		RETURNING version`,
+		taskId, version, taskOwner(ctx),
+	).Scan(&touchedVersion)
+
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, missingOrStale(ctx, tx, taskId, version, false)
+		}
+		return nil, customError.ErrInternalServerError
+	}
+
+	if err := write(tx); err != nil {
+		return nil, err
+	}
+
+	items, err := selectChecklistItems(ctx, tx, taskId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		return nil, customError.ErrInternalServerError
+	}
+
+	return &domain.Checklist{TaskId: taskId, Items: items, Version: touchedVersion}, nil
+}
+
+func selectChecklistItems(ctx context.Context, q queryer, taskId domain.TaskID) ([]*domain.ChecklistItem, error) {
+	rows, err := q.QueryContext(
+		ctx,
+		"SELECT "+checklistItemColumns+" FROM task_checklist_items WHERE task_id = $1 ORDER BY position",
+		taskId,
+	)
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		return nil, customError.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	items := []*domain.ChecklistItem{}
+	for rows.Next() {
+		var result model.ChecklistItemResult
+		if err := scanChecklistItem(rows, &result); err != nil {
+			slog.ErrorContext(ctx, err.Error())
+			return nil, customError.ErrInternalServerError
+		}
+		items = append(items, model.ToChecklistItemDomain(&result))
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		return nil, customError.ErrInternalServerError
+	}
+
+	return items, nil
+}
@@ -0,0 +1,618 @@
+package repository
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/takumi616/go-restapi/domain"
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+var (
+	checklistTouchQuery = regexp.QuoteMeta(
+		`UPDATE tasks SET version=version+1, updated_at=now()
+		WHERE id=$1 AND deleted_at IS NULL AND ($2 = 0 OR version=$2) AND owner_id=$3
+		RETURNING version`,
+	)
+	checklistItemsQuery = regexp.QuoteMeta(
+		"SELECT id, text, done, position, created_at, updated_at FROM task_checklist_items WHERE task_id = $1 ORDER BY position",
+	)
+	checklistItemColumnNames = []string{"id", "text", "done", "position", "created_at", "updated_at"}
+)
+
+func TestSelectChecklist(t *testing.T) {
+	type expected struct {
+		checklist *domain.Checklist
+		err       error
+	}
+
+	testTable := map[string]struct {
+		id        domain.TaskID
+		mockSetup func(sqlmock.Sqlmock, domain.TaskID)
+		expected  expected
+	}{
+		"Ok": {
+			id: "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			mockSetup: func(m sqlmock.Sqlmock, id domain.TaskID) {
+				m.ExpectQuery(regexp.QuoteMeta("SELECT version FROM tasks WHERE id = $1 AND deleted_at IS NULL AND owner_id = $2")).
+					WithArgs(id, testOwnerId).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(4))
+				m.ExpectQuery(checklistItemsQuery).
+					WithArgs(id).
+					WillReturnRows(sqlmock.NewRows(checklistItemColumnNames).
+						AddRow("d1e2f3a4-b5c6-4d7e-8f90-a1b2c3d4e5f6", "Write tests", true, 0, testTime, testTime).
+						AddRow("0f1e2d3c-4b5a-4968-8776-a5b4c3d2e1f0", "Update docs", false, 1, testTime, testTime))
+			},
+			expected: expected{
+				checklist: &domain.Checklist{
+					TaskId: "6a30b9b0-18bf-47b4-bd23-d72726864def",
+					Items: []*domain.ChecklistItem{
+						{Id: "d1e2f3a4-b5c6-4d7e-8f90-a1b2c3d4e5f6", Text: "Write tests", Done: true, Position: 0, CreatedAt: testTime, UpdatedAt: testTime},
+						{Id: "0f1e2d3c-4b5a-4968-8776-a5b4c3d2e1f0", Text: "Update docs", Done: false, Position: 1, CreatedAt: testTime, UpdatedAt: testTime},
+					},
+					Version: 4,
+				},
+				err: nil,
+			},
+		},
+		"NotFound": {
+			id: "3e440171-0921-4c88-a7ec-13f4cdab0d69",
+			mockSetup: func(m sqlmock.Sqlmock, id domain.TaskID) {
+				m.ExpectQuery(regexp.QuoteMeta("SELECT version FROM tasks WHERE id = $1 AND deleted_at IS NULL AND owner_id = $2")).
+					WithArgs(id, testOwnerId).
+					WillReturnError(sql.ErrNoRows)
+			},
+			expected: expected{
+				checklist: nil,
+				err:       customError.ErrNotFound,
+			},
+		},
+	}
+
+	for n, tt := range testTable {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+			require.NoError(t, err)
+			defer db.Close()
+
+			tt.mockSetup(mock, tt.id)
+
+			repo := &TaskRepository{Db: db}
+			result, err := repo.SelectChecklist(testOwnerCtx, tt.id)
+
+			if tt.expected.err != nil {
+				assert.Nil(t, result)
+				assert.EqualError(t, err, tt.expected.err.Error())
+			} else {
+				assert.Equal(t, tt.expected.checklist, result)
+				assert.Nil(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestInsertChecklistItem(t *testing.T) {
+	type expected struct {
+		checklist *domain.Checklist
+		err       error
+	}
+
+	countQuery := regexp.QuoteMeta("SELECT COUNT(*) FROM task_checklist_items WHERE task_id = $1")
+	shiftQuery := regexp.QuoteMeta("UPDATE task_checklist_items SET position=position+1 WHERE task_id=$1 AND position >= $2")
+	insertQuery := regexp.QuoteMeta("INSERT INTO task_checklist_items(task_id, text, position) VALUES($1, $2, $3)")
+
+	first := 0
+	past := 5
+
+	testTable := map[string]struct {
+		id        domain.TaskID
+		text      string
+		position  *int
+		version   int
+		mockSetup func(sqlmock.Sqlmock, domain.TaskID, int)
+		expected  expected
+	}{
+		"Append": {
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			text:    "Update docs",
+			version: 1,
+			mockSetup: func(m sqlmock.Sqlmock, id domain.TaskID, version int) {
+				m.ExpectBegin()
+				m.ExpectQuery(checklistTouchQuery).
+					WithArgs(id, version, testOwnerId).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(2))
+				m.ExpectQuery(countQuery).
+					WithArgs(id).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				m.ExpectExec(shiftQuery).
+					WithArgs(id, 1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				m.ExpectExec(insertQuery).
+					WithArgs(id, "Update docs", 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				m.ExpectQuery(checklistItemsQuery).
+					WithArgs(id).
+					WillReturnRows(sqlmock.NewRows(checklistItemColumnNames).
+						AddRow("d1e2f3a4-b5c6-4d7e-8f90-a1b2c3d4e5f6", "Write tests", false, 0, testTime, testTime).
+						AddRow("0f1e2d3c-4b5a-4968-8776-a5b4c3d2e1f0", "Update docs", false, 1, testTime, testTime))
+				m.ExpectCommit()
+			},
+			expected: expected{
+				checklist: &domain.Checklist{
+					TaskId: "6a30b9b0-18bf-47b4-bd23-d72726864def",
+					Items: []*domain.ChecklistItem{
+						{Id: "d1e2f3a4-b5c6-4d7e-8f90-a1b2c3d4e5f6", Text: "Write tests", Position: 0, CreatedAt: testTime, UpdatedAt: testTime},
+						{Id: "0f1e2d3c-4b5a-4968-8776-a5b4c3d2e1f0", Text: "Update docs", Position: 1, CreatedAt: testTime, UpdatedAt: testTime},
+					},
+					Version: 2,
+				},
+				err: nil,
+			},
+		},
+		"AtPosition": {
+			id:       "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			text:     "Update docs",
+			position: &first,
+			mockSetup: func(m sqlmock.Sqlmock, id domain.TaskID, version int) {
+				m.ExpectBegin()
+				m.ExpectQuery(checklistTouchQuery).
+					WithArgs(id, version, testOwnerId).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(2))
+				m.ExpectQuery(countQuery).
+					WithArgs(id).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				m.ExpectExec(shiftQuery).
+					WithArgs(id, 0).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				m.ExpectExec(insertQuery).
+					WithArgs(id, "Update docs", 0).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				m.ExpectQuery(checklistItemsQuery).
+					WithArgs(id).
+					WillReturnRows(sqlmock.NewRows(checklistItemColumnNames).
+						AddRow("0f1e2d3c-4b5a-4968-8776-a5b4c3d2e1f0", "Update docs", false, 0, testTime, testTime).
+						AddRow("d1e2f3a4-b5c6-4d7e-8f90-a1b2c3d4e5f6", "Write tests", false, 1, testTime, testTime))
+				m.ExpectCommit()
+			},
+			expected: expected{
+				checklist: &domain.Checklist{
+					TaskId: "6a30b9b0-18bf-47b4-bd23-d72726864def",
+					Items: []*domain.ChecklistItem{
+						{Id: "0f1e2d3c-4b5a-4968-8776-a5b4c3d2e1f0", Text: "Update docs", Position: 0, CreatedAt: testTime, UpdatedAt: testTime},
+						{Id: "d1e2f3a4-b5c6-4d7e-8f90-a1b2c3d4e5f6", Text: "Write tests", Position: 1, CreatedAt: testTime, UpdatedAt: testTime},
+					},
+					Version: 2,
+				},
+				err: nil,
+			},
+		},
+		"PastTheEnd": {
+			id:       "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			text:     "Write tests",
+			position: &past,
+			mockSetup: func(m sqlmock.Sqlmock, id domain.TaskID, version int) {
+				m.ExpectBegin()
+				m.ExpectQuery(checklistTouchQuery).
+					WithArgs(id, version, testOwnerId).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(2))
+				m.ExpectQuery(countQuery).
+					WithArgs(id).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				m.ExpectExec(shiftQuery).
+					WithArgs(id, 0).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				m.ExpectExec(insertQuery).
+					WithArgs(id, "Write tests", 0).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				m.ExpectQuery(checklistItemsQuery).
+					WithArgs(id).
+					WillReturnRows(sqlmock.NewRows(checklistItemColumnNames).
+						AddRow("d1e2f3a4-b5c6-4d7e-8f90-a1b2c3d4e5f6", "Write tests", false, 0, testTime, testTime))
+				m.ExpectCommit()
+			},
+			expected: expected{
+				checklist: &domain.Checklist{
+					TaskId: "6a30b9b0-18bf-47b4-bd23-d72726864def",
+					Items: []*domain.ChecklistItem{
+						{Id: "d1e2f3a4-b5c6-4d7e-8f90-a1b2c3d4e5f6", Text: "Write tests", Position: 0, CreatedAt: testTime, UpdatedAt: testTime},
+					},
+					Version: 2,
+				},
+				err: nil,
+			},
+		},
+		"Full": {
+			id:   "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			text: "One too many",
+			mockSetup: func(m sqlmock.Sqlmock, id domain.TaskID, version int) {
+				m.ExpectBegin()
+				m.ExpectQuery(checklistTouchQuery).
+					WithArgs(id, version, testOwnerId).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(2))
+				m.ExpectQuery(countQuery).
+					WithArgs(id).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(domain.MaxChecklistItems))
+				m.ExpectRollback()
+			},
+			expected: expected{
+				checklist: nil,
+				err:       customError.ErrTooMany,
+			},
+		},
+		"VersionMismatch": {
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			text:    "Write tests",
+			version: 3,
+			mockSetup: func(m sqlmock.Sqlmock, id domain.TaskID, version int) {
+				m.ExpectBegin()
+				m.ExpectQuery(checklistTouchQuery).
+					WithArgs(id, version, testOwnerId).
+					WillReturnError(sql.ErrNoRows)
+				m.ExpectQuery(regexp.QuoteMeta(
+					"SELECT EXISTS(SELECT 1 FROM tasks WHERE id = $1 AND deleted_at IS NULL AND owner_id = $2)",
+				)).
+					WithArgs(id, testOwnerId).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				m.ExpectRollback()
+			},
+			expected: expected{
+				checklist: nil,
+				err:       customError.ErrVersionMismatch,
+			},
+		},
+		"NotFound": {
+			id:   "3e440171-0921-4c88-a7ec-13f4cdab0d69",
+			text: "Write tests",
+			mockSetup: func(m sqlmock.Sqlmock, id domain.TaskID, version int) {
+				m.ExpectBegin()
+				m.ExpectQuery(checklistTouchQuery).
+					WithArgs(id, version, testOwnerId).
+					WillReturnError(sql.ErrNoRows)
+				m.ExpectRollback()
+			},
+			expected: expected{
+				checklist: nil,
+				err:       customError.ErrNotFound,
+			},
+		},
+	}
+
+	for n, tt := range testTable {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+			require.NoError(t, err)
+			defer db.Close()
+
+			tt.mockSetup(mock, tt.id, tt.version)
+
+			repo := &TaskRepository{Db: db}
+			result, err := repo.InsertChecklistItem(testOwnerCtx, tt.id, tt.text, tt.position, tt.version)
+
+			if tt.expected.err != nil {
+				assert.Nil(t, result)
+				assert.EqualError(t, err, tt.expected.err.Error())
+			} else {
+				assert.Equal(t, tt.expected.checklist, result)
+				assert.Nil(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUpdateChecklistItem(t *testing.T) {
+	type expected struct {
+		checklist *domain.Checklist
+		err       error
+	}
+
+	updateQuery := regexp.QuoteMeta(
+		`UPDATE task_checklist_items SET text=COALESCE($3, text), done=COALESCE($4, done), updated_at=now()
+		WHERE task_id=$1 AND id=$2
+		RETURNING id`,
+	)
+
+	done := true
+
+	testTable := map[string]struct {
+		id        domain.TaskID
+		itemId    domain.ChecklistItemID
+		patch     *domain.ChecklistItemPatch
+		mockSetup func(sqlmock.Sqlmock, domain.TaskID, domain.ChecklistItemID)
+		expected  expected
+	}{
+		"Ok": {
+			id:     "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			itemId: "d1e2f3a4-b5c6-4d7e-8f90-a1b2c3d4e5f6",
+			patch:  &domain.ChecklistItemPatch{Done: &done},
+			mockSetup: func(m sqlmock.Sqlmock, id domain.TaskID, itemId domain.ChecklistItemID) {
+				m.ExpectBegin()
+				m.ExpectQuery(checklistTouchQuery).
+					WithArgs(id, 0, testOwnerId).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(5))
+				m.ExpectQuery(updateQuery).
+					WithArgs(id, itemId, nil, true).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(itemId.String()))
+				m.ExpectQuery(checklistItemsQuery).
+					WithArgs(id).
+					WillReturnRows(sqlmock.NewRows(checklistItemColumnNames).
+						AddRow(itemId.String(), "Write tests", true, 0, testTime, testTime))
+				m.ExpectCommit()
+			},
+			expected: expected{
+				checklist: &domain.Checklist{
+					TaskId: "6a30b9b0-18bf-47b4-bd23-d72726864def",
+					Items: []*domain.ChecklistItem{
+						{Id: "d1e2f3a4-b5c6-4d7e-8f90-a1b2c3d4e5f6", Text: "Write tests", Done: true, Position: 0, CreatedAt: testTime, UpdatedAt: testTime},
+					},
+					Version: 5,
+				},
+				err: nil,
+			},
+		},
+		"ItemNotFound": {
+			id:     "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			itemId: "0f1e2d3c-4b5a-4968-8776-a5b4c3d2e1f0",
+			patch:  &domain.ChecklistItemPatch{Done: &done},
+			mockSetup: func(m sqlmock.Sqlmock, id domain.TaskID, itemId domain.ChecklistItemID) {
+				m.ExpectBegin()
+				m.ExpectQuery(checklistTouchQuery).
+					WithArgs(id, 0, testOwnerId).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(5))
+				m.ExpectQuery(updateQuery).
+					WithArgs(id, itemId, nil, true).
+					WillReturnError(sql.ErrNoRows)
+				m.ExpectRollback()
+			},
+			expected: expected{
+				checklist: nil,
+				err:       customError.ErrItemNotFound,
+			},
+		},
+	}
+
+	for n, tt := range testTable {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+			require.NoError(t, err)
+			defer db.Close()
+
+			tt.mockSetup(mock, tt.id, tt.itemId)
+
+			repo := &TaskRepository{Db: db}
+			result, err := repo.UpdateChecklistItem(testOwnerCtx, tt.id, tt.itemId, tt.patch, 0)
+
+			if tt.expected.err != nil {
+				assert.Nil(t, result)
+				assert.EqualError(t, err, tt.expected.err.Error())
+			} else {
+				assert.Equal(t, tt.expected.checklist, result)
+				assert.Nil(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDeleteChecklistItem(t *testing.T) {
+	type expected struct {
+		checklist *domain.Checklist
+		err       error
+	}
+
+	deleteQuery := regexp.QuoteMeta("DELETE FROM task_checklist_items WHERE task_id=$1 AND id=$2 RETURNING position")
+	closeGapQuery := regexp.QuoteMeta("UPDATE task_checklist_items SET position=position-1 WHERE task_id=$1 AND position > $2")
+
+	testTable := map[string]struct {
+		id        domain.TaskID
+		itemId    domain.ChecklistItemID
+		mockSetup func(sqlmock.Sqlmock, domain.TaskID, domain.ChecklistItemID)
+		expected  expected
+	}{
+		"Ok": {
+			id:     "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			itemId: "d1e2f3a4-b5c6-4d7e-8f90-a1b2c3d4e5f6",
+			mockSetup: func(m sqlmock.Sqlmock, id domain.TaskID, itemId domain.ChecklistItemID) {
+				m.ExpectBegin()
+				m.ExpectQuery(checklistTouchQuery).
+					WithArgs(id, 0, testOwnerId).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(3))
+				m.ExpectQuery(deleteQuery).
+					WithArgs(id, itemId).
+					WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow(0))
+				m.ExpectExec(closeGapQuery).
+					WithArgs(id, 0).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				m.ExpectQuery(checklistItemsQuery).
+					WithArgs(id).
+					WillReturnRows(sqlmock.NewRows(checklistItemColumnNames).
+						AddRow("0f1e2d3c-4b5a-4968-8776-a5b4c3d2e1f0", "Update docs", false, 0, testTime, testTime))
+				m.ExpectCommit()
+			},
+			expected: expected{
+				checklist: &domain.Checklist{
+					TaskId: "6a30b9b0-18bf-47b4-bd23-d72726864def",
+					Items: []*domain.ChecklistItem{
+						{Id: "0f1e2d3c-4b5a-4968-8776-a5b4c3d2e1f0", Text: "Update docs", Position: 0, CreatedAt: testTime, UpdatedAt: testTime},
+					},
+					Version: 3,
+				},
+				err: nil,
+			},
+		},
+		"ItemNotFound": {
+			id:     "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			itemId: "d1e2f3a4-b5c6-4d7e-8f90-a1b2c3d4e5f6",
+			mockSetup: func(m sqlmock.Sqlmock, id domain.TaskID, itemId domain.ChecklistItemID) {
+				m.ExpectBegin()
+				m.ExpectQuery(checklistTouchQuery).
+					WithArgs(id, 0, testOwnerId).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(3))
+				m.ExpectQuery(deleteQuery).
+					WithArgs(id, itemId).
+					WillReturnError(sql.ErrNoRows)
+				m.ExpectRollback()
+			},
+			expected: expected{
+				checklist: nil,
+				err:       customError.ErrItemNotFound,
+			},
+		},
+	}
+
+	for n, tt := range testTable {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+			require.NoError(t, err)
+			defer db.Close()
+
+			tt.mockSetup(mock, tt.id, tt.itemId)
+
+			repo := &TaskRepository{Db: db}
+			result, err := repo.DeleteChecklistItem(testOwnerCtx, tt.id, tt.itemId, 0)
+
+			if tt.expected.err != nil {
+				assert.Nil(t, result)
+				assert.EqualError(t, err, tt.expected.err.Error())
+			} else {
+				assert.Equal(t, tt.expected.checklist, result)
+				assert.Nil(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestReorderChecklist(t *testing.T) {
+	type expected struct {
+		checklist *domain.Checklist
+		err       error
+	}
+
+	reorderQuery := regexp.QuoteMeta(
+		`UPDATE task_checklist_items i SET position=o.ordinality-1
+		FROM unnest($2::uuid[]) WITH ORDINALITY AS o(id, ordinality)
+		WHERE i.task_id=$1 AND i.id=o.id`,
+	)
+
+	current := func() *sqlmock.Rows {
+		return sqlmock.NewRows(checklistItemColumnNames).
+			AddRow("d1e2f3a4-b5c6-4d7e-8f90-a1b2c3d4e5f6", "Write tests", false, 0, testTime, testTime).
+			AddRow("0f1e2d3c-4b5a-4968-8776-a5b4c3d2e1f0", "Update docs", false, 1, testTime, testTime)
+	}
+
+	testTable := map[string]struct {
+		id        domain.TaskID
+		itemIds   []domain.ChecklistItemID
+		mockSetup func(sqlmock.Sqlmock, domain.TaskID)
+		expected  expected
+	}{
+		"Ok": {
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			itemIds: []domain.ChecklistItemID{"0f1e2d3c-4b5a-4968-8776-a5b4c3d2e1f0", "d1e2f3a4-b5c6-4d7e-8f90-a1b2c3d4e5f6"},
+			mockSetup: func(m sqlmock.Sqlmock, id domain.TaskID) {
+				m.ExpectBegin()
+				m.ExpectQuery(checklistTouchQuery).
+					WithArgs(id, 0, testOwnerId).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(7))
+				m.ExpectQuery(checklistItemsQuery).
+					WithArgs(id).
+					WillReturnRows(current())
+				m.ExpectExec(reorderQuery).
+					WithArgs(id, pq.Array([]string{"0f1e2d3c-4b5a-4968-8776-a5b4c3d2e1f0", "d1e2f3a4-b5c6-4d7e-8f90-a1b2c3d4e5f6"})).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				m.ExpectQuery(checklistItemsQuery).
+					WithArgs(id).
+					WillReturnRows(sqlmock.NewRows(checklistItemColumnNames).
+						AddRow("0f1e2d3c-4b5a-4968-8776-a5b4c3d2e1f0", "Update docs", false, 0, testTime, testTime).
+						AddRow("d1e2f3a4-b5c6-4d7e-8f90-a1b2c3d4e5f6", "Write tests", false, 1, testTime, testTime))
+				m.ExpectCommit()
+			},
+			expected: expected{
+				checklist: &domain.Checklist{
+					TaskId: "6a30b9b0-18bf-47b4-bd23-d72726864def",
+					Items: []*domain.ChecklistItem{
+						{Id: "0f1e2d3c-4b5a-4968-8776-a5b4c3d2e1f0", Text: "Update docs", Position: 0, CreatedAt: testTime, UpdatedAt: testTime},
+						{Id: "d1e2f3a4-b5c6-4d7e-8f90-a1b2c3d4e5f6", Text: "Write tests", Position: 1, CreatedAt: testTime, UpdatedAt: testTime},
+					},
+					Version: 7,
+				},
+				err: nil,
+			},
+		},
+		"MissingItem": {
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			itemIds: []domain.ChecklistItemID{"0f1e2d3c-4b5a-4968-8776-a5b4c3d2e1f0"},
+			mockSetup: func(m sqlmock.Sqlmock, id domain.TaskID) {
+				m.ExpectBegin()
+				m.ExpectQuery(checklistTouchQuery).
+					WithArgs(id, 0, testOwnerId).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(7))
+				m.ExpectQuery(checklistItemsQuery).
+					WithArgs(id).
+					WillReturnRows(current())
+				m.ExpectRollback()
+			},
+			expected: expected{
+				checklist: nil,
+				err:       customError.ErrOrderMismatch,
+			},
+		},
+		"UnknownItem": {
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			itemIds: []domain.ChecklistItemID{"0f1e2d3c-4b5a-4968-8776-a5b4c3d2e1f0", "4d758d63-5c4f-4bef-9a80-d5837c324a07"},
+			mockSetup: func(m sqlmock.Sqlmock, id domain.TaskID) {
+				m.ExpectBegin()
+				m.ExpectQuery(checklistTouchQuery).
+					WithArgs(id, 0, testOwnerId).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(7))
+				m.ExpectQuery(checklistItemsQuery).
+					WithArgs(id).
+					WillReturnRows(current())
+				m.ExpectRollback()
+			},
+			expected: expected{
+				checklist: nil,
+				err:       customError.ErrOrderMismatch,
+			},
+		},
+	}
+
+	for n, tt := range testTable {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+			require.NoError(t, err)
+			defer db.Close()
+
+			tt.mockSetup(mock, tt.id)
+
+			repo := &TaskRepository{Db: db}
+			result, err := repo.ReorderChecklist(testOwnerCtx, tt.id, tt.itemIds, 0)
+
+			if tt.expected.err != nil {
+				assert.Nil(t, result)
+				assert.EqualError(t, err, tt.expected.err.Error())
+			} else {
+				assert.Equal(t, tt.expected.checklist, result)
+				assert.Nil(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
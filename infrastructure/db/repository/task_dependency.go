@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+
+	"github.com/lib/pq"
+	"github.com/takumi616/go-restapi/domain"
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+// Dependency writes hold this lock until their transaction ends, for the
+// same reason as lockTaskHierarchy: two edges added at once could each
+// pass the cycle check and together close a loop
+const lockTaskDependencies = "SELECT pg_advisory_xact_lock(hashtext('task_dependencies'))"
+
+// Whether task $1 already blocks task $2, directly or through other tasks
+const taskDependencyReachQuery = `WITH RECURSIVE downstream AS (
+	SELECT blocked_id FROM task_dependencies WHERE blocker_id = $1
+	UNION
+	SELECT d.blocked_id FROM task_dependencies d JOIN downstream s ON d.blocker_id = s.blocked_id
+)
+SELECT EXISTS(SELECT 1 FROM downstream WHERE blocked_id = $2)`
+
+// SelectDependencies reads every dependency between the caller's tasks,
+// trashed tasks included, since restoring a task brings its dependencies
+// back with it. Both ends of a dependency always have the same owner
+func (r *TaskRepository) SelectDependencies(ctx context.Context) ([]*domain.TaskDependency, error) {
+	rows, err := r.Db.QueryContext(
+		ctx,
+		"SELECT d.blocker_id, d.blocked_id FROM task_dependencies d JOIN tasks t ON t.id = d.blocked_id WHERE t.owner_id = $1",
+		taskOwner(ctx),
+	)
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		return nil, customError.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	var dependencies []*domain.TaskDependency
+	for rows.Next() {
+		var dependency domain.TaskDependency
+		if err := rows.Scan(&dependency.BlockerId, &dependency.BlockedId); err != nil {
+			slog.ErrorContext(ctx, err.Error())
+			return nil, customError.ErrInternalServerError
+		}
+		dependencies = append(dependencies, &dependency)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		return nil, customError.ErrInternalServerError
+	}
+
+	return dependencies, nil
+}
+
+// InsertDependency makes a task wait on another one and returns the waiting
+// task. Adding a dependency that is already there changes nothing
+func (r *TaskRepository) InsertDependency(ctx context.Context, dependency *domain.TaskDependency) (*domain.Task, error) {
+	return r.withTx(ctx, func(tx *sql.Tx) (*domain.Task, error) {
+		if _, err := tx.ExecContext(ctx, lockTaskDependencies); err != nil {
+			slog.ErrorContext(ctx, err.Error())
+			return nil, customError.ErrInternalServerError
+		}
+
+		// Checked again under the lock, in case a dependency was added
+		// since the caller looked
+		var loops bool
+		err := tx.QueryRowContext(
+			ctx, taskDependencyReachQuery, dependency.BlockedId, dependency.BlockerId,
+		).Scan(&loops)
+		if err != nil {
+			slog.ErrorContext(ctx, err.Error())
+			return nil, customError.ErrInternalServerError
+		}
+
+		if loops {
+			return nil, &customError.FieldError{Field: "blocker_id", Err: customError.ErrCycle}
+		}
+
+		_, err = tx.ExecContext(
+			ctx,
+			"INSERT INTO task_dependencies(blocker_id, blocked_id) VALUES($1, $2) ON CONFLICT DO NOTHING",
+			dependency.BlockerId, dependency.BlockedId,
+		)
+		if err != nil {
+			slog.ErrorContext(ctx, err.Error())
+			if constraintErr := taskConstraintError(err); constraintErr != nil {
+				return nil, constraintErr
+			}
+			return nil, customError.ErrInternalServerError
+		}
+
+		return selectTaskById(ctx, tx, dependency.BlockedId)
+	})
+}
+
+// DeleteDependency stops a task from waiting on another one and returns the
+// task that was waiting
+func (r *TaskRepository) DeleteDependency(ctx context.Context, dependency *domain.TaskDependency) (*domain.Task, error) {
+	return r.withTx(ctx, func(tx *sql.Tx) (*domain.Task, error) {
+		var blockedId string
+		err := tx.QueryRowContext(
+			ctx,
+			"DELETE FROM task_dependencies WHERE blocker_id=$1 AND blocked_id=$2 RETURNING blocked_id",
+			dependency.BlockerId, dependency.BlockedId,
+		).Scan(&blockedId)
+
+		if err != nil {
+			slog.ErrorContext(ctx, err.Error())
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, customError.ErrNotFound
+			}
+			return nil, customError.ErrInternalServerError
+		}
+
+		return selectTaskById(ctx, tx, dependency.BlockedId)
+	})
+}
+
+// Make a task that has just been added wait on the given tasks, all of which
+// must be live and have the same owner. A new task blocks nothing yet, so no
+// cycle can be formed
+func insertBlockers(ctx context.Context, q queryer, id domain.TaskID, blockers []*domain.TaskBlocker) error {
+	ids := make([]string, len(blockers))
+	for i, blocker := range blockers {
+		ids[i] = blocker.Id.String()
+	}
+
+	res, err := q.ExecContext(
+		ctx,
+		`INSERT INTO task_dependencies(blocker_id, blocked_id)
+		SELECT id, $2 FROM tasks WHERE id = ANY($1::uuid[]) AND deleted_at IS NULL AND owner_id = $3`,
+		pq.Array(ids), id, taskOwner(ctx),
+	)
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		return customError.ErrInternalServerError
+	}
+
+	inserted, err := res.RowsAffected()
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		return customError.ErrInternalServerError
+	}
+
+	if int(inserted) < len(ids) {
+		return &customError.FieldError{Field: "depends_on", Err: customError.ErrNotFound}
+	}
+	return nil
+}
+
+// SelectOpen reads the live tasks that are neither done nor archived,
+// oldest first
+func (r *TaskRepository) SelectOpen(ctx context.Context) ([]*domain.Task, error) {
+	return r.selectTasks(
+		ctx,
+		"SELECT "+taskColumns+" FROM tasks WHERE deleted_at IS NULL AND state NOT IN ('done', 'archived') AND owner_id = $1 ORDER BY created_at, id",
+		taskOwner(ctx),
+	)
+}
@@ -0,0 +1,317 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/takumi616/go-restapi/domain"
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+const testLockDependencies = "SELECT pg_advisory_xact_lock(hashtext('task_dependencies'))"
+
+const testReachQuery = `WITH RECURSIVE downstream AS (
+	SELECT blocked_id FROM task_dependencies WHERE blocker_id = $1
+	UNION
+	SELECT d.blocked_id FROM task_dependencies d JOIN downstream s ON d.blocker_id = s.blocked_id
+)
+SELECT EXISTS(SELECT 1 FROM downstream WHERE blocked_id = $2)`
+
+func TestSelectDependencies(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(
+		"SELECT d.blocker_id, d.blocked_id FROM task_dependencies d JOIN tasks t ON t.id = d.blocked_id WHERE t.owner_id = $1",
+	)).
+		WithArgs(testOwnerId).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"blocker_id", "blocked_id"}).
+				AddRow("f299e7ed-a22a-4494-b59e-21bb91fdae3b", "6a30b9b0-18bf-47b4-bd23-d72726864def"),
+		)
+
+	repo := &TaskRepository{Db: db}
+	result, err := repo.SelectDependencies(testOwnerCtx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*domain.TaskDependency{
+		{BlockerId: "f299e7ed-a22a-4494-b59e-21bb91fdae3b", BlockedId: "6a30b9b0-18bf-47b4-bd23-d72726864def"},
+	}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertDependency(t *testing.T) {
+	type expected struct {
+		task *domain.Task
+		err  error
+	}
+
+	blockerId := domain.TaskID("f299e7ed-a22a-4494-b59e-21bb91fdae3b")
+	blockedId := domain.TaskID("6a30b9b0-18bf-47b4-bd23-d72726864def")
+	insertQuery := regexp.QuoteMeta("INSERT INTO task_dependencies(blocker_id, blocked_id) VALUES($1, $2) ON CONFLICT DO NOTHING")
+
+	testTable := map[string]struct {
+		mockSetup func(sqlmock.Sqlmock)
+		expected  expected
+	}{
+		"Ok": {
+			mockSetup: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectExec(regexp.QuoteMeta(testLockDependencies)).WillReturnResult(sqlmock.NewResult(0, 0))
+				m.ExpectQuery(regexp.QuoteMeta(testReachQuery)).
+					WithArgs(blockedId, blockerId).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+				m.ExpectExec(insertQuery).
+					WithArgs(blockerId, blockedId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "state", "priority", "due_at", "estimate_hours", "parent_id", "version", "created_at", "updated_at", "completed_at", "deleted_at", "labels", "blocked_by", "subtasks", "completed_subtasks", "checklist_items", "completed_checklist_items"}).
+					AddRow(blockedId.String(), "Test Title", "", "todo", "P2", nil, nil, nil, 2, testTime, testTime, nil, nil, "[]",
+						`[{"id": "f299e7ed-a22a-4494-b59e-21bb91fdae3b", "title": "Test Blocker", "state": "in_progress"}]`, 0, 0, 0, 0)
+				m.ExpectQuery(regexp.QuoteMeta(
+					"SELECT id, title, description, state, priority, due_at, estimate_hours, parent_id, version, created_at, updated_at, completed_at, deleted_at, " + testComputedColumns + " FROM tasks WHERE id = $1",
+				)).
+					WithArgs(blockedId).
+					WillReturnRows(rows)
+				m.ExpectCommit()
+			},
+			expected: expected{
+				task: &domain.Task{
+					Id:       blockedId,
+					Title:    "Test Title",
+					State:    domain.TaskStateTodo,
+					Priority: domain.TaskPriorityP2,
+					BlockedBy: []*domain.TaskBlocker{
+						{Id: blockerId, Title: "Test Blocker", State: domain.TaskStateInProgress},
+					},
+					Version:   2,
+					CreatedAt: testTime,
+					UpdatedAt: testTime,
+				},
+				err: nil,
+			},
+		},
+		"Cycle": {
+			mockSetup: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectExec(regexp.QuoteMeta(testLockDependencies)).WillReturnResult(sqlmock.NewResult(0, 0))
+				m.ExpectQuery(regexp.QuoteMeta(testReachQuery)).
+					WithArgs(blockedId, blockerId).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				m.ExpectRollback()
+			},
+			expected: expected{
+				task: nil,
+				err:  &customError.FieldError{Field: "blocker_id", Err: customError.ErrCycle},
+			},
+		},
+		"BlockerNotFound": {
+			mockSetup: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectExec(regexp.QuoteMeta(testLockDependencies)).WillReturnResult(sqlmock.NewResult(0, 0))
+				m.ExpectQuery(regexp.QuoteMeta(testReachQuery)).
+					WithArgs(blockedId, blockerId).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+				m.ExpectExec(insertQuery).
+					WithArgs(blockerId, blockedId).
+					WillReturnError(&pq.Error{
+						Code:       "23503",
+						Message:    "insert or update on table \"task_dependencies\" violates foreign key constraint \"task_dependencies_blocker_id_fkey\"",
+						Constraint: "task_dependencies_blocker_id_fkey",
+					})
+				m.ExpectRollback()
+			},
+			expected: expected{
+				task: nil,
+				err:  &customError.FieldError{Field: "blocker_id", Err: customError.ErrNotFound},
+			},
+		},
+	}
+
+	for n, tt := range testTable {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+			require.NoError(t, err)
+			defer db.Close()
+
+			tt.mockSetup(mock)
+
+			repo := &TaskRepository{Db: db}
+			result, err := repo.InsertDependency(testOwnerCtx, &domain.TaskDependency{BlockerId: blockerId, BlockedId: blockedId})
+
+			if tt.expected.err != nil {
+				assert.Nil(t, result)
+				assert.EqualError(t, err, tt.expected.err.Error())
+			} else {
+				assert.Equal(t, tt.expected.task, result)
+				assert.Nil(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDeleteDependency(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer db.Close()
+
+	blockerId := domain.TaskID("f299e7ed-a22a-4494-b59e-21bb91fdae3b")
+	blockedId := domain.TaskID("6a30b9b0-18bf-47b4-bd23-d72726864def")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("DELETE FROM task_dependencies WHERE blocker_id=$1 AND blocked_id=$2 RETURNING blocked_id")).
+		WithArgs(blockerId, blockedId).
+		WillReturnRows(sqlmock.NewRows([]string{"blocked_id"}))
+	mock.ExpectRollback()
+
+	repo := &TaskRepository{Db: db}
+	result, err := repo.DeleteDependency(testOwnerCtx, &domain.TaskDependency{BlockerId: blockerId, BlockedId: blockedId})
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, customError.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertTaskWithBlockers(t *testing.T) {
+	type expected struct {
+		task *domain.Task
+		err  error
+	}
+
+	blockerId := domain.TaskID("f299e7ed-a22a-4494-b59e-21bb91fdae3b")
+	newId := domain.TaskID("6a30b9b0-18bf-47b4-bd23-d72726864def")
+	input := &domain.Task{
+		OwnerId:       testOwnerId,
+		Title:         "Test Title",
+		State:         domain.TaskStateTodo,
+		Priority:      domain.TaskPriorityP2,
+		EstimateHours: ptr(3),
+		BlockedBy:     []*domain.TaskBlocker{{Id: blockerId}},
+	}
+	blockersQuery := regexp.QuoteMeta(
+		`INSERT INTO task_dependencies(blocker_id, blocked_id)
+		SELECT id, $2 FROM tasks WHERE id = ANY($1::uuid[]) AND deleted_at IS NULL AND owner_id = $3`,
+	)
+	columns := []string{"id", "title", "description", "state", "priority", "due_at", "estimate_hours", "parent_id", "version", "created_at", "updated_at", "completed_at", "deleted_at", "labels", "blocked_by", "subtasks", "completed_subtasks", "checklist_items", "completed_checklist_items"}
+
+	testTable := map[string]struct {
+		mockSetup func(sqlmock.Sqlmock)
+		expected  expected
+	}{
+		"Ok": {
+			mockSetup: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(regexp.QuoteMeta(testInsertQuery)).
+					WithArgs("Test Title", "", "todo", "P2", nil, 3, nil, testOwnerId).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(newId.String(), "Test Title", "", "todo", "P2", nil, 3, nil, 1, testTime, testTime, nil, nil, "[]", "[]", 0, 0, 0, 0))
+				m.ExpectExec(blockersQuery).
+					WithArgs(pq.Array([]string{blockerId.String()}), newId, testOwnerId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				m.ExpectQuery(regexp.QuoteMeta(
+					"SELECT id, title, description, state, priority, due_at, estimate_hours, parent_id, version, created_at, updated_at, completed_at, deleted_at, " + testComputedColumns + " FROM tasks WHERE id = $1",
+				)).
+					WithArgs(newId).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(newId.String(), "Test Title", "", "todo", "P2", nil, 3, nil, 2, testTime, testTime, nil, nil, "[]",
+							`[{"id": "f299e7ed-a22a-4494-b59e-21bb91fdae3b", "title": "Test Blocker", "state": "todo"}]`, 0, 0, 0, 0))
+				m.ExpectCommit()
+			},
+			expected: expected{
+				task: &domain.Task{
+					Id:            newId,
+					Title:         "Test Title",
+					State:         domain.TaskStateTodo,
+					Priority:      domain.TaskPriorityP2,
+					EstimateHours: ptr(3),
+					BlockedBy: []*domain.TaskBlocker{
+						{Id: blockerId, Title: "Test Blocker", State: domain.TaskStateTodo},
+					},
+					Version:   2,
+					CreatedAt: testTime,
+					UpdatedAt: testTime,
+				},
+				err: nil,
+			},
+		},
+		"BlockerNotFound": {
+			mockSetup: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(regexp.QuoteMeta(testInsertQuery)).
+					WithArgs("Test Title", "", "todo", "P2", nil, 3, nil, testOwnerId).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(newId.String(), "Test Title", "", "todo", "P2", nil, 3, nil, 1, testTime, testTime, nil, nil, "[]", "[]", 0, 0, 0, 0))
+				// The blocker is in the trash, so nothing is inserted
+				m.ExpectExec(blockersQuery).
+					WithArgs(pq.Array([]string{blockerId.String()}), newId, testOwnerId).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				m.ExpectRollback()
+			},
+			expected: expected{
+				task: nil,
+				err:  &customError.FieldError{Field: "depends_on", Err: customError.ErrNotFound},
+			},
+		},
+	}
+
+	for n, tt := range testTable {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+			require.NoError(t, err)
+			defer db.Close()
+
+			tt.mockSetup(mock)
+
+			repo := &TaskRepository{Db: db}
+			result, err := repo.Insert(testOwnerCtx, input)
+
+			if tt.expected.err != nil {
+				assert.Nil(t, result)
+				assert.EqualError(t, err, tt.expected.err.Error())
+			} else {
+				assert.Equal(t, tt.expected.task, result)
+				assert.Nil(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSelectOpen(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(
+		"SELECT id, title, description, state, priority, due_at, estimate_hours, parent_id, version, created_at, updated_at, completed_at, deleted_at, " + testComputedColumns +
+			" FROM tasks WHERE deleted_at IS NULL AND state NOT IN ('done', 'archived') AND owner_id = $1 ORDER BY created_at, id",
+	)).
+		WithArgs(testOwnerId).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "title", "description", "state", "priority", "due_at", "estimate_hours", "parent_id", "version", "created_at", "updated_at", "completed_at", "deleted_at", "labels", "blocked_by", "subtasks", "completed_subtasks", "checklist_items", "completed_checklist_items"}).
+				AddRow("6a30b9b0-18bf-47b4-bd23-d72726864def", "Test Title", "", "in_progress", "P2", nil, 5, nil, 1, testTime, testTime, nil, nil, "[]", "[]", 0, 0, 0, 0),
+		)
+
+	repo := &TaskRepository{Db: db}
+	result, err := repo.SelectOpen(testOwnerCtx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*domain.Task{
+		{
+			Id: "6a30b9b0-18bf-47b4-bd23-d72726864def", Title: "Test Title",
+			State: domain.TaskStateInProgress, Priority: domain.TaskPriorityP2, EstimateHours: ptr(5),
+			Version: 1, CreatedAt: testTime, UpdatedAt: testTime,
+		},
+	}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+
+	"github.com/takumi616/go-restapi/domain"
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+// Changes to the hierarchy hold this lock until their transaction ends.
+// Otherwise two concurrent moves could each pass the cycle check and
+// together form a loop, or a subtask could be added to a task being deleted
+const lockTaskHierarchy = "SELECT pg_advisory_xact_lock(hashtext('tasks.parent_id'))"
+
+// How many levels the new parent $1 is deep, how many of them are the task
+// $2 being placed under it, and how many levels the task's own subtree has.
+// A task that does not exist yet has a subtree of one level. A parent not
+// owned by $3 has no levels, as if it did not exist
+const taskPlacementQuery = `WITH RECURSIVE ancestors AS (
+	SELECT id, parent_id FROM tasks WHERE id = $1 AND deleted_at IS NULL AND owner_id = $3
+	UNION
+	SELECT t.id, t.parent_id FROM tasks t JOIN ancestors a ON t.id = a.parent_id
+), subtree AS (
+	SELECT id, 1 AS height FROM tasks WHERE id = $2
+	UNION ALL
+	SELECT t.id, s.height + 1 FROM tasks t JOIN subtree s ON t.parent_id = s.id
+)
+SELECT (SELECT COUNT(*) FROM ancestors), (SELECT COUNT(*) FROM ancestors WHERE id = $2),
+	(SELECT COALESCE(MAX(height), 1) FROM subtree)`
+
+// The live subtree below task $1, not including the task itself
+const taskSubtreeQuery = `WITH RECURSIVE subtree AS (
+	SELECT id FROM tasks WHERE parent_id = $1 AND deleted_at IS NULL
+	UNION ALL
+	SELECT t.id FROM tasks t JOIN subtree s ON t.parent_id = s.id WHERE t.deleted_at IS NULL
+)
+`
+
+// Make sure a task can be placed under parentId, keeping the hierarchy free of
+// cycles and within domain.MaxTaskDepth levels. id is empty for a new task.
+// Must run in the transaction that places the task
+func placeTask(ctx context.Context, q queryer, id domain.TaskID, parentId domain.TaskID) error {
+	if _, err := q.ExecContext(ctx, lockTaskHierarchy); err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		return customError.ErrInternalServerError
+	}
+
+	taskId := sql.NullString{String: id.String(), Valid: id != ""}
+
+	var depth, loops, height int
+	err := q.QueryRowContext(ctx, taskPlacementQuery, parentId, taskId, taskOwner(ctx)).Scan(&depth, &loops, &height)
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		return customError.ErrInternalServerError
+	}
+
+	if depth == 0 {
+		return &customError.FieldError{Field: "parent_id", Err: customError.ErrNotFound}
+	}
+	if loops > 0 {
+		return &customError.FieldError{Field: "parent_id", Err: customError.ErrCycle}
+	}
+	if depth+height > domain.MaxTaskDepth {
+		return &customError.FieldError{Field: "parent_id", Err: customError.ErrTooDeep}
+	}
+	return nil
+}
+
+// SelectTree reads a task along with every live task below it
+func (r *TaskRepository) SelectTree(ctx context.Context, id domain.TaskID) (*domain.Task, error) {
+	tasks, err := r.selectTasks(
+		ctx,
+		`WITH RECURSIVE tree AS (
+			SELECT id FROM tasks WHERE id = $1 AND deleted_at IS NULL AND owner_id = $2
+			UNION ALL
+			SELECT t.id FROM tasks t JOIN tree ON t.parent_id = tree.id WHERE t.deleted_at IS NULL
+		)
+		SELECT `+taskColumns+` FROM tasks WHERE id IN (SELECT id FROM tree) ORDER BY created_at, id`,
+		id, taskOwner(ctx),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	root := domain.NewTaskTree(id, tasks)
+	if root == nil {
+		return nil, customError.ErrNotFound
+	}
+
+	return root, nil
+}
+
+// Deal with the live subtasks of a task that has just been moved to the trash
+func trashSubtasks(ctx context.Context, q queryer, id domain.TaskID, mode domain.SubtaskMode) error {
+	var err error
+	switch mode {
+	case domain.SubtaskModeCascade:
+		// The subtree is trashed at the same time as the task
+		_, err = q.ExecContext(
+			ctx,
+			taskSubtreeQuery+"UPDATE tasks SET deleted_at=now() WHERE id IN (SELECT id FROM subtree)",
+			id,
+		)
+	case domain.SubtaskModeReparent:
+		_, err = q.ExecContext(
+			ctx,
+			`UPDATE tasks SET parent_id=(SELECT parent_id FROM tasks WHERE id=$1), version=version+1, updated_at=now()
+			WHERE parent_id=$1 AND deleted_at IS NULL`,
+			id,
+		)
+	default:
+		return refuseWithSubtasks(ctx, q, "SELECT EXISTS(SELECT 1 FROM tasks WHERE parent_id = $1 AND deleted_at IS NULL)", id)
+	}
+
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		return customError.ErrInternalServerError
+	}
+	return nil
+}
+
+// Deal with the subtasks of a task about to be purged. They may be in the
+// trash already, after being deleted along with the task
+func purgeSubtasks(ctx context.Context, q queryer, id domain.TaskID, parentId sql.NullString, mode domain.SubtaskMode) error {
+	switch mode {
+	case domain.SubtaskModeCascade:
+		// The foreign key takes the subtree along
+		return nil
+	case domain.SubtaskModeReparent:
+		_, err := q.ExecContext(
+			ctx, "UPDATE tasks SET parent_id=$2, version=version+1, updated_at=now() WHERE parent_id=$1", id, parentId,
+		)
+		if err != nil {
+			slog.ErrorContext(ctx, err.Error())
+			return customError.ErrInternalServerError
+		}
+		return nil
+	default:
+		return refuseWithSubtasks(ctx, q, "SELECT EXISTS(SELECT 1 FROM tasks WHERE parent_id = $1)", id)
+	}
+}
+
+func refuseWithSubtasks(ctx context.Context, q queryer, query string, id domain.TaskID) error {
+	var exists bool
+	if err := q.QueryRowContext(ctx, query, id).Scan(&exists); err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		return customError.ErrInternalServerError
+	}
+
+	if exists {
+		return customError.ErrHasChildren
+	}
+	return nil
+}
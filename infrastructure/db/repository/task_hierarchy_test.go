@@ -0,0 +1,286 @@
+package repository
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/takumi616/go-restapi/domain"
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+const testPlacementQuery = `WITH RECURSIVE ancestors AS (
+	SELECT id, parent_id FROM tasks WHERE id = $1 AND deleted_at IS NULL AND owner_id = $3
+	UNION
+	SELECT t.id, t.parent_id FROM tasks t JOIN ancestors a ON t.id = a.parent_id
+), subtree AS (
+	SELECT id, 1 AS height FROM tasks WHERE id = $2
+	UNION ALL
+	SELECT t.id, s.height + 1 FROM tasks t JOIN subtree s ON t.parent_id = s.id
+)
+SELECT (SELECT COUNT(*) FROM ancestors), (SELECT COUNT(*) FROM ancestors WHERE id = $2),
+	(SELECT COALESCE(MAX(height), 1) FROM subtree)`
+
+func testPlacementRow(depth, loops, height int) *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"depth", "loops", "height"}).AddRow(depth, loops, height)
+}
+
+func TestInsertSubtask(t *testing.T) {
+	type expected struct {
+		task *domain.Task
+		err  error
+	}
+
+	parentId := domain.TaskID("f299e7ed-a22a-4494-b59e-21bb91fdae3b")
+	input := &domain.Task{
+		OwnerId:  testOwnerId,
+		Title:    "Test Subtask",
+		State:    domain.TaskStateTodo,
+		Priority: domain.TaskPriorityP2,
+		ParentId: &parentId,
+	}
+	parentArg := sql.NullString{String: parentId.String(), Valid: true}
+
+	testTable := map[string]struct {
+		mockSetup func(sqlmock.Sqlmock)
+		expected  expected
+	}{
+		"Ok": {
+			mockSetup: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectExec(regexp.QuoteMeta(testLockHierarchy)).WillReturnResult(sqlmock.NewResult(0, 0))
+				m.ExpectQuery(regexp.QuoteMeta(testPlacementQuery)).
+					WithArgs(parentId, sql.NullString{}, testOwnerId).
+					WillReturnRows(testPlacementRow(2, 0, 1))
+				m.ExpectQuery(regexp.QuoteMeta(testInsertQuery)).
+					WithArgs("Test Subtask", "", "todo", "P2", nil, nil, parentArg, testOwnerId).
+					WillReturnRows(
+						sqlmock.NewRows([]string{"id", "title", "description", "state", "priority", "due_at", "estimate_hours", "parent_id", "version", "created_at", "updated_at", "completed_at", "deleted_at", "labels", "blocked_by", "subtasks", "completed_subtasks", "checklist_items", "completed_checklist_items"}).
+							AddRow("6a30b9b0-18bf-47b4-bd23-d72726864def", "Test Subtask", "", "todo", "P2", nil, nil, parentId.String(), 1, testTime, testTime, nil, nil, "[]", "[]", 0, 0, 0, 0),
+					)
+				m.ExpectCommit()
+			},
+			expected: expected{
+				task: &domain.Task{
+					Id:        "6a30b9b0-18bf-47b4-bd23-d72726864def",
+					Title:     "Test Subtask",
+					State:     domain.TaskStateTodo,
+					Priority:  domain.TaskPriorityP2,
+					ParentId:  &parentId,
+					Version:   1,
+					CreatedAt: testTime,
+					UpdatedAt: testTime,
+				},
+				err: nil,
+			},
+		},
+		"ParentNotFound": {
+			mockSetup: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectExec(regexp.QuoteMeta(testLockHierarchy)).WillReturnResult(sqlmock.NewResult(0, 0))
+				m.ExpectQuery(regexp.QuoteMeta(testPlacementQuery)).
+					WithArgs(parentId, sql.NullString{}, testOwnerId).
+					WillReturnRows(testPlacementRow(0, 0, 1))
+				m.ExpectRollback()
+			},
+			expected: expected{
+				task: nil,
+				err:  &customError.FieldError{Field: "parent_id", Err: customError.ErrNotFound},
+			},
+		},
+		"TooDeep": {
+			mockSetup: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectExec(regexp.QuoteMeta(testLockHierarchy)).WillReturnResult(sqlmock.NewResult(0, 0))
+				m.ExpectQuery(regexp.QuoteMeta(testPlacementQuery)).
+					WithArgs(parentId, sql.NullString{}, testOwnerId).
+					WillReturnRows(testPlacementRow(domain.MaxTaskDepth, 0, 1))
+				m.ExpectRollback()
+			},
+			expected: expected{
+				task: nil,
+				err:  &customError.FieldError{Field: "parent_id", Err: customError.ErrTooDeep},
+			},
+		},
+	}
+
+	for n, tt := range testTable {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+			require.NoError(t, err)
+			defer db.Close()
+
+			tt.mockSetup(mock)
+
+			repo := &TaskRepository{Db: db}
+			result, err := repo.Insert(testOwnerCtx, input)
+
+			if tt.expected.err != nil {
+				assert.Nil(t, result)
+				assert.EqualError(t, err, tt.expected.err.Error())
+			} else {
+				assert.Equal(t, tt.expected.task, result)
+				assert.Nil(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMoveTask(t *testing.T) {
+	type expected struct {
+		err error
+	}
+
+	id := domain.TaskID("6a30b9b0-18bf-47b4-bd23-d72726864def")
+	parentId := domain.TaskID("f299e7ed-a22a-4494-b59e-21bb91fdae3b")
+	idArg := sql.NullString{String: id.String(), Valid: true}
+
+	testTable := map[string]struct {
+		placement *sqlmock.Rows
+		expected  expected
+	}{
+		"UnderOwnSubtask": {
+			placement: testPlacementRow(3, 1, 3),
+			expected:  expected{err: &customError.FieldError{Field: "parent_id", Err: customError.ErrCycle}},
+		},
+		"SubtreeTooDeep": {
+			placement: testPlacementRow(2, 0, 4),
+			expected:  expected{err: &customError.FieldError{Field: "parent_id", Err: customError.ErrTooDeep}},
+		},
+	}
+
+	for n, tt := range testTable {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+			require.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectBegin()
+			mock.ExpectExec(regexp.QuoteMeta(testLockHierarchy)).WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectQuery(regexp.QuoteMeta(testPlacementQuery)).
+				WithArgs(parentId, idArg, testOwnerId).
+				WillReturnRows(tt.placement)
+			mock.ExpectRollback()
+
+			repo := &TaskRepository{Db: db}
+			result, err := repo.Update(testOwnerCtx, id, &domain.TaskPatch{ParentId: &parentId})
+
+			assert.Nil(t, result)
+			assert.EqualError(t, err, tt.expected.err.Error())
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSelectTree(t *testing.T) {
+	type expected struct {
+		task *domain.Task
+		err  error
+	}
+
+	rootId := domain.TaskID("f299e7ed-a22a-4494-b59e-21bb91fdae3b")
+	childId := domain.TaskID("6a30b9b0-18bf-47b4-bd23-d72726864def")
+
+	treeQuery := regexp.QuoteMeta(
+		`WITH RECURSIVE tree AS (
+			SELECT id FROM tasks WHERE id = $1 AND deleted_at IS NULL AND owner_id = $2
+			UNION ALL
+			SELECT t.id FROM tasks t JOIN tree ON t.parent_id = tree.id WHERE t.deleted_at IS NULL
+		)
+		SELECT id, title, description, state, priority, due_at, estimate_hours, parent_id, version, created_at, updated_at, completed_at, deleted_at, ` + testComputedColumns +
+			` FROM tasks WHERE id IN (SELECT id FROM tree) ORDER BY created_at, id`,
+	)
+	columns := []string{"id", "title", "description", "state", "priority", "due_at", "estimate_hours", "parent_id", "version", "created_at", "updated_at", "completed_at", "deleted_at", "labels", "blocked_by", "subtasks", "completed_subtasks", "checklist_items", "completed_checklist_items"}
+
+	testTable := map[string]struct {
+		rows     *sqlmock.Rows
+		expected expected
+	}{
+		"Ok": {
+			rows: sqlmock.NewRows(columns).
+				AddRow(rootId.String(), "Test Title", "", "in_progress", "P2", nil, nil, nil, 3, testTime, testTime, nil, nil, "[]", "[]", 1, 1, 0, 0).
+				AddRow(childId.String(), "Test Subtask", "", "done", "P2", nil, nil, rootId.String(), 2, testTime, testTime, testTime, nil, "[]", "[]", 0, 0, 0, 0),
+			expected: expected{
+				task: &domain.Task{
+					Id: rootId, Title: "Test Title",
+					State: domain.TaskStateInProgress, Priority: domain.TaskPriorityP2,
+					Progress: domain.TaskProgress{Completed: 1, Total: 1},
+					Version:  3, CreatedAt: testTime, UpdatedAt: testTime,
+					Subtasks: []*domain.Task{
+						{
+							Id: childId, Title: "Test Subtask",
+							State: domain.TaskStateDone, Priority: domain.TaskPriorityP2, ParentId: &rootId,
+							Version: 2, CreatedAt: testTime, UpdatedAt: testTime, CompletedAt: &testTime,
+						},
+					},
+				},
+				err: nil,
+			},
+		},
+		"NotFound": {
+			rows: sqlmock.NewRows(columns),
+			expected: expected{
+				task: nil,
+				err:  customError.ErrNotFound,
+			},
+		},
+	}
+
+	for n, tt := range testTable {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+			require.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectQuery(treeQuery).WithArgs(rootId, testOwnerId).WillReturnRows(tt.rows)
+
+			repo := &TaskRepository{Db: db}
+			result, err := repo.SelectTree(testOwnerCtx, rootId)
+
+			if tt.expected.err != nil {
+				assert.Nil(t, result)
+				assert.EqualError(t, err, tt.expected.err.Error())
+			} else {
+				assert.Equal(t, tt.expected.task, result)
+				assert.Nil(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSelectSubtasks(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer db.Close()
+
+	parentId := domain.TaskID("f299e7ed-a22a-4494-b59e-21bb91fdae3b")
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*), MAX(updated_at) FROM tasks WHERE deleted_at IS NULL AND parent_id = $1 AND owner_id = $2")).
+		WithArgs(parentId, testOwnerId).
+		WillReturnRows(sqlmock.NewRows([]string{"count", "max"}).AddRow(0, nil))
+	mock.ExpectQuery(regexp.QuoteMeta(
+		"SELECT id, title, description, state, priority, due_at, estimate_hours, parent_id, version, created_at, updated_at, completed_at, deleted_at, "+testComputedColumns+
+			" FROM tasks WHERE deleted_at IS NULL AND parent_id = $1 AND owner_id = $2 ORDER BY title ASC, id ASC LIMIT $3 OFFSET $4",
+	)).
+		WithArgs(parentId, testOwnerId, 20, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "description", "state", "priority", "due_at", "estimate_hours", "parent_id", "version", "created_at", "updated_at", "completed_at", "deleted_at", "labels", "blocked_by", "subtasks", "completed_subtasks", "checklist_items", "completed_checklist_items"}))
+
+	repo := &TaskRepository{Db: db}
+	result, err := repo.SelectAll(testOwnerCtx, &domain.TaskListQuery{
+		Limit: 20, ParentId: &parentId, SortField: domain.TaskSortByTitle,
+	})
+
+	assert.NoError(t, err)
+	assert.Empty(t, result.Tasks)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/takumi616/go-restapi/domain"
+)
+
+// Maps sortable fields to their columns so that
+// only known column names are ever interpolated into SQL.
+// The boolean status of earlier API versions is derived from the state,
+// and tasks without a due date sort as if due at the end of time
+var taskSortColumns = map[domain.TaskSortField]string{
+	domain.TaskSortById:        "id",
+	domain.TaskSortByTitle:     "title",
+	domain.TaskSortByStatus:    "(state = 'done')",
+	domain.TaskSortByState:     "state",
+	domain.TaskSortByPriority:  "priority",
+	domain.TaskSortByDueAt:     "COALESCE(due_at, 'infinity')",
+	domain.TaskSortByCreatedAt: "created_at",
+	domain.TaskSortByUpdatedAt: "updated_at",
+}
+
+// Past due and not finished yet. A task without a due date is never overdue
+const taskOverdueCondition = "COALESCE(due_at < now() AND state NOT IN ('done', 'archived'), false)"
+
+// Labels of a task named in the array bound to the given parameter. Names are
+// unique, so a task has all of them once it matches as many as there are
+const taskLabelMatch = "FROM task_labels tl JOIN labels l ON l.id = tl.label_id WHERE tl.task_id = tasks.id AND l.name = ANY($%d)"
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// Tasks in the trash never show up in the list, nor do those of other owners
+func buildTaskListCondition(owner sql.NullString, query *domain.TaskListQuery) (string, []any) {
+	conditions := []string{"deleted_at IS NULL"}
+	var args []any
+
+	if query.Status != nil {
+		args = append(args, *query.Status)
+		conditions = append(conditions, fmt.Sprintf("(state = 'done') = $%d", len(args)))
+	}
+
+	if query.State != nil {
+		args = append(args, query.State.String())
+		conditions = append(conditions, fmt.Sprintf("state = $%d", len(args)))
+	}
+
+	if query.DueBefore != nil {
+		args = append(args, query.DueBefore.UTC())
+		conditions = append(conditions, fmt.Sprintf("due_at < $%d", len(args)))
+	}
+
+	if query.DueAfter != nil {
+		args = append(args, query.DueAfter.UTC())
+		conditions = append(conditions, fmt.Sprintf("due_at > $%d", len(args)))
+	}
+
+	if query.Overdue != nil {
+		if *query.Overdue {
+			conditions = append(conditions, taskOverdueCondition)
+		} else {
+			conditions = append(conditions, "NOT "+taskOverdueCondition)
+		}
+	}
+
+	if len(query.Labels) > 0 {
+		args = append(args, pq.Array(query.Labels))
+		match := fmt.Sprintf(taskLabelMatch, len(args))
+		if query.LabelMode == domain.TaskLabelModeAll {
+			args = append(args, len(query.Labels))
+			conditions = append(conditions, fmt.Sprintf("(SELECT COUNT(*) %s) = $%d", match, len(args)))
+		} else {
+			conditions = append(conditions, "EXISTS(SELECT 1 "+match+")")
+		}
+	}
+
+	if query.ParentId != nil {
+		args = append(args, *query.ParentId)
+		conditions = append(conditions, fmt.Sprintf("parent_id = $%d", len(args)))
+	}
+
+	if query.Title != "" {
+		args = append(args, "%"+likeEscaper.Replace(query.Title)+"%")
+		conditions = append(conditions, fmt.Sprintf("title ILIKE $%d", len(args)))
+	}
+
+	args = append(args, owner)
+	conditions = append(conditions, fmt.Sprintf("owner_id = $%d", len(args)))
+
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// Restrict the list to rows strictly after the cursor in sort order.
+// The row comparison keeps it a single index range scan
+func appendTaskKeysetCondition(where string, args []any, query *domain.TaskListQuery) (string, []any) {
+	column, ok := taskSortColumns[query.SortField]
+	if !ok {
+		column = taskSortColumns[domain.TaskSortByTitle]
+	}
+
+	operator := ">"
+	if query.SortDesc {
+		operator = "<"
+	}
+
+	var condition string
+	if column == "id" {
+		args = append(args, query.After.Id)
+		condition = fmt.Sprintf("id %s $%d", operator, len(args))
+	} else {
+		args = append(args, query.After.SortValue, query.After.Id)
+		condition = fmt.Sprintf("(%s, id) %s ($%d, $%d)", column, operator, len(args)-1, len(args))
+	}
+
+	return where + " AND " + condition, args
+}
+
+func buildTaskListOrder(query *domain.TaskListQuery) string {
+	column, ok := taskSortColumns[query.SortField]
+	if !ok {
+		column = taskSortColumns[domain.TaskSortByTitle]
+	}
+
+	direction := "ASC"
+	if query.SortDesc {
+		direction = "DESC"
+	}
+
+	// id is appended as a tie-breaker so that pages never overlap
+	if column == "id" {
+		return "id " + direction
+	}
+	return fmt.Sprintf("%s %s, id %s", column, direction, direction)
+}
@@ -6,8 +6,10 @@ import (
 	"errors"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/takumi616/go-restapi/domain"
@@ -15,6 +17,23 @@ import (
 	customError "github.com/takumi616/go-restapi/shared/error"
 )
 
+var testTime = time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+// Every task statement is scoped to the actor making the request
+const testOwnerId = "3b241101-e2bb-4255-8caf-4136c566a962"
+
+var testOwnerCtx = domain.ContextWithActor(context.Background(), &domain.Actor{UserId: testOwnerId})
+
+// Labels, blockers, subtask and checklist progress are read along with every task
+const testComputedColumns = `COALESCE((SELECT json_agg(json_build_object('id', l.id, 'name', l.name, 'color', l.color) ORDER BY l.name)
+	FROM task_labels tl JOIN labels l ON l.id = tl.label_id WHERE tl.task_id = tasks.id), '[]'),
+	COALESCE((SELECT json_agg(json_build_object('id', b.id, 'title', b.title, 'state', b.state) ORDER BY b.title)
+	FROM task_dependencies d JOIN tasks b ON b.id = d.blocker_id WHERE d.blocked_id = tasks.id AND b.deleted_at IS NULL), '[]'),
+	(SELECT COUNT(*) FROM tasks c WHERE c.parent_id = tasks.id AND c.deleted_at IS NULL),
+	(SELECT COUNT(*) FROM tasks c WHERE c.parent_id = tasks.id AND c.deleted_at IS NULL AND c.state = 'done'),
+	(SELECT COUNT(*) FROM task_checklist_items i WHERE i.task_id = tasks.id),
+	(SELECT COUNT(*) FROM task_checklist_items i WHERE i.task_id = tasks.id AND i.done)`
+
 func TestInsert(t *testing.T) {
 	type expected struct {
 		task *domain.Task
@@ -30,18 +49,19 @@ func TestInsert(t *testing.T) {
 			input: &domain.Task{
 				Title:       "Test Title",
 				Description: "Test Description",
-				Status:      false,
+				State:       domain.TaskStateTodo,
+				Priority:    domain.TaskPriorityP2,
 			},
 			mockSetup: func(m sqlmock.Sqlmock, param *model.InsertTaskParam) {
-				rows := sqlmock.NewRows([]string{"id", "title", "description", "status"}).
-					AddRow("6a30b9b0-18bf-47b4-bd23-d72726864def", param.Title, param.Description, param.Status)
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "state", "priority", "due_at", "estimate_hours", "parent_id", "version", "created_at", "updated_at", "completed_at", "deleted_at", "labels", "blocked_by", "subtasks", "completed_subtasks", "checklist_items", "completed_checklist_items"}).
+					AddRow("6a30b9b0-18bf-47b4-bd23-d72726864def", param.Title, param.Description, param.State, param.Priority, nil, nil, nil, 1, testTime, testTime, nil, nil, "[]", "[]", 0, 0, 0, 0)
 
 				m.ExpectQuery(regexp.QuoteMeta(
-					`INSERT INTO tasks(title, description, status)
-					VALUES($1, $2, $3)
-					RETURNING id, title, description, status`,
+					`INSERT INTO tasks(title, description, state, priority, due_at, estimate_hours, parent_id, owner_id)
+					VALUES($1, $2, $3, $4, $5, $6, $7, $8)
+					RETURNING id, title, description, state, priority, due_at, estimate_hours, parent_id, version, created_at, updated_at, completed_at, deleted_at, `+testComputedColumns,
 				)).
-					WithArgs(param.Title, param.Description, param.Status).
+					WithArgs(param.Title, param.Description, param.State, param.Priority, param.DueAt, param.EstimateHours, param.ParentId, param.OwnerId).
 					WillReturnRows(rows)
 			},
 			expected: expected{
@@ -49,7 +69,11 @@ func TestInsert(t *testing.T) {
 					Id:          "6a30b9b0-18bf-47b4-bd23-d72726864def",
 					Title:       "Test Title",
 					Description: "Test Description",
-					Status:      false,
+					State:       domain.TaskStateTodo,
+					Priority:    domain.TaskPriorityP2,
+					Version:     1,
+					CreatedAt:   testTime,
+					UpdatedAt:   testTime,
 				},
 				err: nil,
 			},
@@ -58,26 +82,70 @@ func TestInsert(t *testing.T) {
 			input: &domain.Task{
 				Title:       "Duplicate Title",
 				Description: "Test Description",
-				Status:      false,
+				State:       domain.TaskStateTodo,
+				Priority:    domain.TaskPriorityP2,
 			},
 			mockSetup: func(m sqlmock.Sqlmock, param *model.InsertTaskParam) {
 				m.ExpectQuery(regexp.QuoteMeta(
-					`INSERT INTO tasks(title, description, status)
-					VALUES($1, $2, $3)
-					RETURNING id, title, description, status`,
+					`INSERT INTO tasks(title, description, state, priority, due_at, estimate_hours, parent_id, owner_id)
+					VALUES($1, $2, $3, $4, $5, $6, $7, $8)
+					RETURNING id, title, description, state, priority, due_at, estimate_hours, parent_id, version, created_at, updated_at, completed_at, deleted_at, `+testComputedColumns,
 				)).
-					WithArgs(param.Title, param.Description, param.Status).
-					WillReturnError(
-						errors.New(
-							"pq: duplicate key value violates unique constraint \"tasks_title_key\"",
-						),
-					)
+					WithArgs(param.Title, param.Description, param.State, param.Priority, param.DueAt, param.EstimateHours, param.ParentId, param.OwnerId).
+					WillReturnError(&pq.Error{
+						Code:       "23505",
+						Message:    "duplicate key value violates unique constraint \"tasks_title_key\"",
+						Constraint: "tasks_title_key",
+					})
+			},
+			expected: expected{
+				task: nil,
+				err:  &customError.FieldError{Field: "title", Err: customError.ErrDuplicate},
+			},
+		},
+		"TitleTooLong": {
+			input: &domain.Task{
+				Title:       "Title Longer Than Thirty Characters",
+				Description: "Test Description",
+				State:       domain.TaskStateTodo,
+				Priority:    domain.TaskPriorityP2,
+			},
+			mockSetup: func(m sqlmock.Sqlmock, param *model.InsertTaskParam) {
+				m.ExpectQuery(regexp.QuoteMeta(
+					`INSERT INTO tasks(title, description, state, priority, due_at, estimate_hours, parent_id, owner_id)
+					VALUES($1, $2, $3, $4, $5, $6, $7, $8)
+					RETURNING id, title, description, state, priority, due_at, estimate_hours, parent_id, version, created_at, updated_at, completed_at, deleted_at, `+testComputedColumns,
+				)).
+					WithArgs(param.Title, param.Description, param.State, param.Priority, param.DueAt, param.EstimateHours, param.ParentId, param.OwnerId).
+					WillReturnError(&pq.Error{
+						Code:    "22001",
+						Message: "value too long for type character varying(30)",
+					})
 			},
 			expected: expected{
 				task: nil,
-				err: errors.New(
-					customError.ErrInternalServerError.Error(),
-				),
+				err:  &customError.FieldError{Field: "title", Err: customError.ErrValueTooLong},
+			},
+		},
+		"OtherError": {
+			input: &domain.Task{
+				Title:       "Test Title",
+				Description: "Test Description",
+				State:       domain.TaskStateTodo,
+				Priority:    domain.TaskPriorityP2,
+			},
+			mockSetup: func(m sqlmock.Sqlmock, param *model.InsertTaskParam) {
+				m.ExpectQuery(regexp.QuoteMeta(
+					`INSERT INTO tasks(title, description, state, priority, due_at, estimate_hours, parent_id, owner_id)
+					VALUES($1, $2, $3, $4, $5, $6, $7, $8)
+					RETURNING id, title, description, state, priority, due_at, estimate_hours, parent_id, version, created_at, updated_at, completed_at, deleted_at, `+testComputedColumns,
+				)).
+					WithArgs(param.Title, param.Description, param.State, param.Priority, param.DueAt, param.EstimateHours, param.ParentId, param.OwnerId).
+					WillReturnError(errors.New("pq: connection reset"))
+			},
+			expected: expected{
+				task: nil,
+				err:  customError.ErrInternalServerError,
 			},
 		},
 	}
@@ -92,7 +160,7 @@ func TestInsert(t *testing.T) {
 			tt.mockSetup(mock, model.ToInsertTaskParam(tt.input))
 
 			repo := &TaskRepository{Db: db}
-			result, err := repo.Insert(context.Background(), tt.input)
+			result, err := repo.Insert(testOwnerCtx, tt.input)
 
 			if tt.expected.err != nil {
 				assert.Nil(t, result)
@@ -110,62 +178,285 @@ func TestInsert(t *testing.T) {
 
 func TestSelectAll(t *testing.T) {
 	type expected struct {
-		taskList []*domain.Task
+		taskList *domain.TaskList
 		err      error
 	}
 
+	status := false
+
 	testTable := map[string]struct {
+		query     *domain.TaskListQuery
 		mockSetup func(sqlmock.Sqlmock)
 		expected  expected
 	}{
 		"Ok": {
+			query: &domain.TaskListQuery{Limit: 2, Offset: 0, SortField: domain.TaskSortByTitle},
+			mockSetup: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*), MAX(updated_at) FROM tasks WHERE deleted_at IS NULL AND owner_id = $1")).
+					WithArgs(testOwnerId).
+					WillReturnRows(sqlmock.NewRows([]string{"count", "max"}).AddRow(3, testTime))
+
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "state", "priority", "due_at", "estimate_hours", "parent_id", "version", "created_at", "updated_at", "completed_at", "deleted_at", "labels", "blocked_by", "subtasks", "completed_subtasks", "checklist_items", "completed_checklist_items"}).
+					AddRow("6a30b9b0-18bf-47b4-bd23-d72726864def", "Test Title", "Test Description", "todo", "P2", nil, nil, nil, 1, testTime, testTime, nil, nil, "[]", "[]", 0, 0, 0, 0).
+					AddRow("3e440171-0921-4c88-a7ec-13f4cdab0d69", "Test Title2", "Test Description2", "todo", "P2", nil, nil, nil, 1, testTime, testTime, nil, nil, "[]", "[]", 0, 0, 0, 0)
+
+				m.ExpectQuery(regexp.QuoteMeta(
+					"SELECT id, title, description, state, priority, due_at, estimate_hours, parent_id, version, created_at, updated_at, completed_at, deleted_at, "+testComputedColumns+" FROM tasks WHERE deleted_at IS NULL AND owner_id = $1 ORDER BY title ASC, id ASC LIMIT $2 OFFSET $3",
+				)).WithArgs(testOwnerId, 2, 0).WillReturnRows(rows)
+			},
+			expected: expected{
+				taskList: &domain.TaskList{
+					Tasks: []*domain.Task{
+						{
+							Id:          "6a30b9b0-18bf-47b4-bd23-d72726864def",
+							Title:       "Test Title",
+							Description: "Test Description",
+							State:       domain.TaskStateTodo,
+							Priority:    domain.TaskPriorityP2,
+							Version:     1,
+							CreatedAt:   testTime,
+							UpdatedAt:   testTime,
+						},
+						{
+							Id:          "3e440171-0921-4c88-a7ec-13f4cdab0d69",
+							Title:       "Test Title2",
+							Description: "Test Description2",
+							State:       domain.TaskStateTodo,
+							Priority:    domain.TaskPriorityP2,
+							Version:     1,
+							CreatedAt:   testTime,
+							UpdatedAt:   testTime,
+						},
+					},
+					Total:        3,
+					LastModified: testTime,
+				},
+				err: nil,
+			},
+		},
+		"FilterAndSort": {
+			query: &domain.TaskListQuery{
+				Limit: 10, Offset: 10, Status: &status, Title: "50%_off",
+				SortField: domain.TaskSortByStatus, SortDesc: true,
+			},
 			mockSetup: func(m sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{"id", "title", "description", "status"}).
-					AddRow("6a30b9b0-18bf-47b4-bd23-d72726864def", "Test Title", "Test Description", false).
-					AddRow("3e440171-0921-4c88-a7ec-13f4cdab0d69", "Test Title2", "Test Description2", false)
+				m.ExpectQuery(regexp.QuoteMeta(
+					"SELECT COUNT(*), MAX(updated_at) FROM tasks WHERE deleted_at IS NULL AND (state = 'done') = $1 AND title ILIKE $2 AND owner_id = $3",
+				)).
+					WithArgs(false, `%50\%\_off%`, testOwnerId).
+					WillReturnRows(sqlmock.NewRows([]string{"count", "max"}).AddRow(11, testTime))
+
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "state", "priority", "due_at", "estimate_hours", "parent_id", "version", "created_at", "updated_at", "completed_at", "deleted_at", "labels", "blocked_by", "subtasks", "completed_subtasks", "checklist_items", "completed_checklist_items"}).
+					AddRow("6a30b9b0-18bf-47b4-bd23-d72726864def", "50%_off", "Test Description", "todo", "P2", nil, nil, nil, 1, testTime, testTime, nil, nil, "[]", "[]", 0, 0, 0, 0)
 
 				m.ExpectQuery(regexp.QuoteMeta(
-					"SELECT id, title, description, status FROM tasks",
-				)).WillReturnRows(rows)
+					`SELECT id, title, description, state, priority, due_at, estimate_hours, parent_id, version, created_at, updated_at, completed_at, deleted_at, `+testComputedColumns+` FROM tasks WHERE deleted_at IS NULL AND (state = 'done') = $1 AND title ILIKE $2 AND owner_id = $3
+					ORDER BY (state = 'done') DESC, id DESC LIMIT $4 OFFSET $5`,
+				)).WithArgs(false, `%50\%\_off%`, testOwnerId, 10, 10).WillReturnRows(rows)
 			},
 			expected: expected{
-				taskList: []*domain.Task{
-					{
-						Id:          "6a30b9b0-18bf-47b4-bd23-d72726864def",
-						Title:       "Test Title",
-						Description: "Test Description",
-						Status:      false,
+				taskList: &domain.TaskList{
+					Tasks: []*domain.Task{
+						{
+							Id:          "6a30b9b0-18bf-47b4-bd23-d72726864def",
+							Title:       "50%_off",
+							Description: "Test Description",
+							State:       domain.TaskStateTodo,
+							Priority:    domain.TaskPriorityP2,
+							Version:     1,
+							CreatedAt:   testTime,
+							UpdatedAt:   testTime,
+						},
 					},
-					{
-						Id:          "3e440171-0921-4c88-a7ec-13f4cdab0d69",
-						Title:       "Test Title2",
-						Description: "Test Description2",
-						Status:      false,
+					Total:        11,
+					LastModified: testTime,
+				},
+				err: nil,
+			},
+		},
+		"FilterByState": {
+			query: &domain.TaskListQuery{
+				Limit: 10, State: ptr(domain.TaskStateInProgress), SortField: domain.TaskSortByState,
+			},
+			mockSetup: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(
+					"SELECT COUNT(*), MAX(updated_at) FROM tasks WHERE deleted_at IS NULL AND state = $1 AND owner_id = $2",
+				)).
+					WithArgs("in_progress", testOwnerId).
+					WillReturnRows(sqlmock.NewRows([]string{"count", "max"}).AddRow(1, testTime))
+
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "state", "priority", "due_at", "estimate_hours", "parent_id", "version", "created_at", "updated_at", "completed_at", "deleted_at", "labels", "blocked_by", "subtasks", "completed_subtasks", "checklist_items", "completed_checklist_items"}).
+					AddRow("6a30b9b0-18bf-47b4-bd23-d72726864def", "Test Title", "Test Description", "in_progress", "P2", nil, nil, nil, 2, testTime, testTime, nil, nil, "[]", "[]", 0, 0, 0, 0)
+
+				m.ExpectQuery(regexp.QuoteMeta(
+					`SELECT id, title, description, state, priority, due_at, estimate_hours, parent_id, version, created_at, updated_at, completed_at, deleted_at, `+testComputedColumns+` FROM tasks WHERE deleted_at IS NULL AND state = $1 AND owner_id = $2
+					ORDER BY state ASC, id ASC LIMIT $3 OFFSET $4`,
+				)).WithArgs("in_progress", testOwnerId, 10, 0).WillReturnRows(rows)
+			},
+			expected: expected{
+				taskList: &domain.TaskList{
+					Tasks: []*domain.Task{
+						{
+							Id:          "6a30b9b0-18bf-47b4-bd23-d72726864def",
+							Title:       "Test Title",
+							Description: "Test Description",
+							State:       domain.TaskStateInProgress,
+							Priority:    domain.TaskPriorityP2,
+							Version:     2,
+							CreatedAt:   testTime,
+							UpdatedAt:   testTime,
+						},
+					},
+					Total:        1,
+					LastModified: testTime,
+				},
+				err: nil,
+			},
+		},
+		"OverdueSinceAndSortByDueAt": {
+			query: &domain.TaskListQuery{
+				Limit:     10,
+				DueAfter:  ptr(time.Date(2025, 1, 1, 9, 0, 0, 0, time.FixedZone("JST", 9*60*60))),
+				Overdue:   ptr(true),
+				SortField: domain.TaskSortByDueAt,
+			},
+			mockSetup: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(
+					"SELECT COUNT(*), MAX(updated_at) FROM tasks WHERE deleted_at IS NULL AND due_at > $1 AND COALESCE(due_at < now() AND state NOT IN ('done', 'archived'), false) AND owner_id = $2",
+				)).
+					WithArgs(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), testOwnerId).
+					WillReturnRows(sqlmock.NewRows([]string{"count", "max"}).AddRow(1, testTime))
+
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "state", "priority", "due_at", "estimate_hours", "parent_id", "version", "created_at", "updated_at", "completed_at", "deleted_at", "labels", "blocked_by", "subtasks", "completed_subtasks", "checklist_items", "completed_checklist_items"}).
+					AddRow("6a30b9b0-18bf-47b4-bd23-d72726864def", "Test Title", "Test Description", "todo", "P0", testTime, nil, nil, 1, testTime, testTime, nil, nil, "[]", "[]", 0, 0, 0, 0)
+
+				m.ExpectQuery(regexp.QuoteMeta(
+					`SELECT id, title, description, state, priority, due_at, estimate_hours, parent_id, version, created_at, updated_at, completed_at, deleted_at, `+testComputedColumns+` FROM tasks WHERE deleted_at IS NULL AND due_at > $1 AND COALESCE(due_at < now() AND state NOT IN ('done', 'archived'), false) AND owner_id = $2
+					ORDER BY COALESCE(due_at, 'infinity') ASC, id ASC LIMIT $3 OFFSET $4`,
+				)).WithArgs(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), testOwnerId, 10, 0).WillReturnRows(rows)
+			},
+			expected: expected{
+				taskList: &domain.TaskList{
+					Tasks: []*domain.Task{
+						{
+							Id:          "6a30b9b0-18bf-47b4-bd23-d72726864def",
+							Title:       "Test Title",
+							Description: "Test Description",
+							State:       domain.TaskStateTodo,
+							Priority:    domain.TaskPriorityP0,
+							DueAt:       &testTime,
+							Version:     1,
+							CreatedAt:   testTime,
+							UpdatedAt:   testTime,
+						},
+					},
+					Total:        1,
+					LastModified: testTime,
+				},
+				err: nil,
+			},
+		},
+		"FilterByAllLabels": {
+			query: &domain.TaskListQuery{
+				Limit: 10, Title: "Test", Labels: []string{"backend", "bug"}, LabelMode: domain.TaskLabelModeAll,
+				SortField: domain.TaskSortByTitle,
+			},
+			mockSetup: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(
+					`SELECT COUNT(*), MAX(updated_at) FROM tasks WHERE deleted_at IS NULL
+					AND (SELECT COUNT(*) FROM task_labels tl JOIN labels l ON l.id = tl.label_id WHERE tl.task_id = tasks.id AND l.name = ANY($1)) = $2
+					AND title ILIKE $3 AND owner_id = $4`,
+				)).
+					WithArgs(pq.Array([]string{"backend", "bug"}), 2, "%Test%", testOwnerId).
+					WillReturnRows(sqlmock.NewRows([]string{"count", "max"}).AddRow(1, testTime))
+
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "state", "priority", "due_at", "estimate_hours", "parent_id", "version", "created_at", "updated_at", "completed_at", "deleted_at", "labels", "blocked_by", "subtasks", "completed_subtasks", "checklist_items", "completed_checklist_items"}).
+					AddRow("6a30b9b0-18bf-47b4-bd23-d72726864def", "Test Title", "Test Description", "todo", "P2", nil, nil, nil, 1, testTime, testTime, nil, nil,
+						`[{"id": "0b9b4d6e-5c1f-4b0e-9a43-2f6f0c3d8e71", "name": "backend", "color": "#0000ff"}, {"id": "c6a1f3e2-7d4b-4e8a-b1f9-3a2d5e6c7b80", "name": "bug", "color": "#ff0000"}]`, "[]", 0, 0, 0, 0)
+
+				m.ExpectQuery(regexp.QuoteMeta(
+					`SELECT id, title, description, state, priority, due_at, estimate_hours, parent_id, version, created_at, updated_at, completed_at, deleted_at, `+testComputedColumns+` FROM tasks WHERE deleted_at IS NULL
+					AND (SELECT COUNT(*) FROM task_labels tl JOIN labels l ON l.id = tl.label_id WHERE tl.task_id = tasks.id AND l.name = ANY($1)) = $2
+					AND title ILIKE $3 AND owner_id = $4 ORDER BY title ASC, id ASC LIMIT $5 OFFSET $6`,
+				)).WithArgs(pq.Array([]string{"backend", "bug"}), 2, "%Test%", testOwnerId, 10, 0).WillReturnRows(rows)
+			},
+			expected: expected{
+				taskList: &domain.TaskList{
+					Tasks: []*domain.Task{
+						{
+							Id:          "6a30b9b0-18bf-47b4-bd23-d72726864def",
+							Title:       "Test Title",
+							Description: "Test Description",
+							State:       domain.TaskStateTodo,
+							Priority:    domain.TaskPriorityP2,
+							Labels: []*domain.Label{
+								{Id: "0b9b4d6e-5c1f-4b0e-9a43-2f6f0c3d8e71", Name: "backend", Color: "#0000ff"},
+								{Id: "c6a1f3e2-7d4b-4e8a-b1f9-3a2d5e6c7b80", Name: "bug", Color: "#ff0000"},
+							},
+							Version:   1,
+							CreatedAt: testTime,
+							UpdatedAt: testTime,
+						},
 					},
+					Total:        1,
+					LastModified: testTime,
 				},
 				err: nil,
 			},
 		},
+		"FilterByAnyLabel": {
+			query: &domain.TaskListQuery{
+				Limit: 10, Labels: []string{"bug"}, LabelMode: domain.TaskLabelModeAny, SortField: domain.TaskSortByTitle,
+			},
+			mockSetup: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(
+					`SELECT COUNT(*), MAX(updated_at) FROM tasks WHERE deleted_at IS NULL
+					AND EXISTS(SELECT 1 FROM task_labels tl JOIN labels l ON l.id = tl.label_id WHERE tl.task_id = tasks.id AND l.name = ANY($1)) AND owner_id = $2`,
+				)).
+					WithArgs(pq.Array([]string{"bug"}), testOwnerId).
+					WillReturnRows(sqlmock.NewRows([]string{"count", "max"}).AddRow(0, nil))
+
+				m.ExpectQuery(regexp.QuoteMeta(
+					`SELECT id, title, description, state, priority, due_at, estimate_hours, parent_id, version, created_at, updated_at, completed_at, deleted_at, `+testComputedColumns+` FROM tasks WHERE deleted_at IS NULL
+					AND EXISTS(SELECT 1 FROM task_labels tl JOIN labels l ON l.id = tl.label_id WHERE tl.task_id = tasks.id AND l.name = ANY($1)) AND owner_id = $2
+					ORDER BY title ASC, id ASC LIMIT $3 OFFSET $4`,
+				)).
+					WithArgs(pq.Array([]string{"bug"}), testOwnerId, 10, 0).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "description", "state", "priority", "due_at", "estimate_hours", "parent_id", "version", "created_at", "updated_at", "completed_at", "deleted_at", "labels", "blocked_by", "subtasks", "completed_subtasks", "checklist_items", "completed_checklist_items"}))
+			},
+			expected: expected{
+				taskList: &domain.TaskList{Tasks: []*domain.Task{}, Total: 0},
+				err:      nil,
+			},
+		},
 		"Empty": {
+			query: &domain.TaskListQuery{Limit: 20, Offset: 0, SortField: domain.TaskSortByTitle},
 			mockSetup: func(m sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{"id", "title", "description", "status"})
+				m.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*), MAX(updated_at) FROM tasks WHERE deleted_at IS NULL AND owner_id = $1")).
+					WithArgs(testOwnerId).
+					WillReturnRows(sqlmock.NewRows([]string{"count", "max"}).AddRow(0, nil))
+
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "state", "priority", "due_at", "estimate_hours", "parent_id", "version", "created_at", "updated_at", "completed_at", "deleted_at", "labels", "blocked_by", "subtasks", "completed_subtasks", "checklist_items", "completed_checklist_items"})
 
 				m.ExpectQuery(regexp.QuoteMeta(
-					"SELECT id, title, description, status FROM tasks",
-				)).WillReturnRows(rows)
+					"SELECT id, title, description, state, priority, due_at, estimate_hours, parent_id, version, created_at, updated_at, completed_at, deleted_at, "+testComputedColumns+" FROM tasks WHERE deleted_at IS NULL AND owner_id = $1 ORDER BY title ASC, id ASC LIMIT $2 OFFSET $3",
+				)).WithArgs(testOwnerId, 20, 0).WillReturnRows(rows)
 			},
 			expected: expected{
-				taskList: []*domain.Task{},
+				taskList: &domain.TaskList{Tasks: []*domain.Task{}, Total: 0},
 				err:      nil,
 			},
 		},
 		"InternalServerErr": {
+			query: &domain.TaskListQuery{Limit: 20, Offset: 0, SortField: domain.TaskSortByTitle},
 			mockSetup: func(m sqlmock.Sqlmock) {
-				sqlmock.NewRows([]string{"id", "title", "description", "status"})
+				m.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*), MAX(updated_at) FROM tasks WHERE deleted_at IS NULL AND owner_id = $1")).
+					WithArgs(testOwnerId).
+					WillReturnRows(sqlmock.NewRows([]string{"count", "max"}).AddRow(2, testTime))
 
 				m.ExpectQuery(regexp.QuoteMeta(
-					"SELECT id, title, description, status FROM tasks",
-				)).WillReturnError(errors.New("sql: expected 4 destination arguments in Scan, not 3"))
+					"SELECT id, title, description, state, priority, due_at, estimate_hours, parent_id, version, created_at, updated_at, completed_at, deleted_at, "+testComputedColumns+" FROM tasks WHERE deleted_at IS NULL AND owner_id = $1 ORDER BY title ASC, id ASC LIMIT $2 OFFSET $3",
+				)).WithArgs(testOwnerId, 20, 0).WillReturnError(errors.New("sql: expected 4 destination arguments in Scan, not 3"))
 			},
 			expected: expected{
 				taskList: nil,
@@ -184,7 +475,7 @@ func TestSelectAll(t *testing.T) {
 			tt.mockSetup(mock)
 
 			repo := &TaskRepository{Db: db}
-			result, err := repo.SelectAll(context.Background())
+			result, err := repo.SelectAll(testOwnerCtx, tt.query)
 
 			if tt.expected.err != nil {
 				assert.Nil(t, result)
@@ -200,65 +491,95 @@ func TestSelectAll(t *testing.T) {
 	}
 }
 
-func TestSelectById(t *testing.T) {
+func TestSelectAfter(t *testing.T) {
 	type expected struct {
-		task *domain.Task
-		err  error
+		taskList []*domain.Task
+		err      error
 	}
 
 	testTable := map[string]struct {
-		id        string
-		mockSetup func(sqlmock.Sqlmock, string)
+		query     *domain.TaskListQuery
+		mockSetup func(sqlmock.Sqlmock)
 		expected  expected
 	}{
 		"Ok": {
-			id: "6a30b9b0-18bf-47b4-bd23-d72726864def",
-			mockSetup: func(m sqlmock.Sqlmock, id string) {
-				rows := sqlmock.NewRows([]string{"id", "title", "description", "status"}).
-					AddRow("6a30b9b0-18bf-47b4-bd23-d72726864def", "Test Title", "Test Description", false)
+			query: &domain.TaskListQuery{
+				Limit: 2, SortField: domain.TaskSortByTitle,
+				After: &domain.TaskCursor{
+					SortField: domain.TaskSortByTitle, SortValue: "Test Title",
+					Id: "6a30b9b0-18bf-47b4-bd23-d72726864def",
+				},
+			},
+			mockSetup: func(m sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "state", "priority", "due_at", "estimate_hours", "parent_id", "version", "created_at", "updated_at", "completed_at", "deleted_at", "labels", "blocked_by", "subtasks", "completed_subtasks", "checklist_items", "completed_checklist_items"}).
+					AddRow("3e440171-0921-4c88-a7ec-13f4cdab0d69", "Test Title2", "Test Description2", "todo", "P2", nil, nil, nil, 1, testTime, testTime, nil, nil, "[]", "[]", 0, 0, 0, 0)
 
 				m.ExpectQuery(regexp.QuoteMeta(
-					"SELECT id, title, description, status FROM tasks WHERE id = $1",
-				)).WithArgs(id).WillReturnRows(rows)
+					`SELECT id, title, description, state, priority, due_at, estimate_hours, parent_id, version, created_at, updated_at, completed_at, deleted_at, `+testComputedColumns+` FROM tasks WHERE deleted_at IS NULL AND owner_id = $1 AND (title, id) > ($2, $3)
+					ORDER BY title ASC, id ASC LIMIT $4`,
+				)).
+					WithArgs(testOwnerId, "Test Title", "6a30b9b0-18bf-47b4-bd23-d72726864def", 2).
+					WillReturnRows(rows)
 			},
 			expected: expected{
-				task: &domain.Task{
-					Id:          "6a30b9b0-18bf-47b4-bd23-d72726864def",
-					Title:       "Test Title",
-					Description: "Test Description",
-					Status:      false,
+				taskList: []*domain.Task{
+					{
+						Id:          "3e440171-0921-4c88-a7ec-13f4cdab0d69",
+						Title:       "Test Title2",
+						Description: "Test Description2",
+						State:       domain.TaskStateTodo,
+						Priority:    domain.TaskPriorityP2,
+						Version:     1,
+						CreatedAt:   testTime,
+						UpdatedAt:   testTime,
+					},
 				},
 				err: nil,
 			},
 		},
-		"NotFound": {
-			id: "3e440171-0921-4c88-a7ec-13f4cdab0d69",
-			mockSetup: func(m sqlmock.Sqlmock, id string) {
-				sqlmock.NewRows([]string{"id", "title", "description", "status"}).
-					AddRow("6a30b9b0-18bf-47b4-bd23-d72726864def", "Test Title", "Test Description", false)
+		"FilterAndSortDesc": {
+			query: &domain.TaskListQuery{
+				Limit: 2, Title: "Test", SortField: domain.TaskSortById, SortDesc: true,
+				After: &domain.TaskCursor{
+					SortField: domain.TaskSortById, SortDesc: true,
+					SortValue: "6a30b9b0-18bf-47b4-bd23-d72726864def",
+					Id:        "6a30b9b0-18bf-47b4-bd23-d72726864def",
+				},
+			},
+			mockSetup: func(m sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "state", "priority", "due_at", "estimate_hours", "parent_id", "version", "created_at", "updated_at", "completed_at", "deleted_at", "labels", "blocked_by", "subtasks", "completed_subtasks", "checklist_items", "completed_checklist_items"})
 
 				m.ExpectQuery(regexp.QuoteMeta(
-					"SELECT id, title, description, status FROM tasks WHERE id = $1",
-				)).WithArgs(id).WillReturnError(sql.ErrNoRows)
+					`SELECT id, title, description, state, priority, due_at, estimate_hours, parent_id, version, created_at, updated_at, completed_at, deleted_at, `+testComputedColumns+` FROM tasks WHERE deleted_at IS NULL AND title ILIKE $1 AND owner_id = $2 AND id < $3
+					ORDER BY id DESC LIMIT $4`,
+				)).
+					WithArgs("%Test%", testOwnerId, "6a30b9b0-18bf-47b4-bd23-d72726864def", 2).
+					WillReturnRows(rows)
 			},
 			expected: expected{
-				task: nil,
-				err:  customError.ErrNotFound,
+				taskList: []*domain.Task{},
+				err:      nil,
 			},
 		},
-		"InvalidId": {
-			id: "abc123",
-			mockSetup: func(m sqlmock.Sqlmock, id string) {
-				sqlmock.NewRows([]string{"id", "title", "description", "status"}).
-					AddRow("6a30b9b0-18bf-47b4-bd23-d72726864def", "Test Title", "Test Description", false)
-
+		"InternalServerErr": {
+			query: &domain.TaskListQuery{
+				Limit: 2, SortField: domain.TaskSortByTitle,
+				After: &domain.TaskCursor{
+					SortField: domain.TaskSortByTitle, SortValue: "Test Title",
+					Id: "6a30b9b0-18bf-47b4-bd23-d72726864def",
+				},
+			},
+			mockSetup: func(m sqlmock.Sqlmock) {
 				m.ExpectQuery(regexp.QuoteMeta(
-					"SELECT id, title, description, status FROM tasks WHERE id = $1",
-				)).WithArgs(id).WillReturnError(errors.New("pq: invalid input syntax for type uuid: \"abc123\""))
+					`SELECT id, title, description, state, priority, due_at, estimate_hours, parent_id, version, created_at, updated_at, completed_at, deleted_at, `+testComputedColumns+` FROM tasks WHERE deleted_at IS NULL AND owner_id = $1 AND (title, id) > ($2, $3)
+					ORDER BY title ASC, id ASC LIMIT $4`,
+				)).
+					WithArgs(testOwnerId, "Test Title", "6a30b9b0-18bf-47b4-bd23-d72726864def", 2).
+					WillReturnError(errors.New("pq: connection reset by peer"))
 			},
 			expected: expected{
-				task: nil,
-				err:  customError.ErrInternalServerError,
+				taskList: nil,
+				err:      customError.ErrInternalServerError,
 			},
 		},
 	}
@@ -270,17 +591,17 @@ func TestSelectById(t *testing.T) {
 			require.NoError(t, err)
 			defer db.Close()
 
-			tt.mockSetup(mock, tt.id)
+			tt.mockSetup(mock)
 
 			repo := &TaskRepository{Db: db}
-			result, err := repo.SelectById(context.Background(), tt.id)
+			result, err := repo.SelectAfter(testOwnerCtx, tt.query)
 
 			if tt.expected.err != nil {
 				assert.Nil(t, result)
 				assert.Error(t, err)
 				assert.EqualError(t, err, tt.expected.err.Error())
 			} else {
-				assert.Equal(t, tt.expected.task, result)
+				assert.Equal(t, tt.expected.taskList, result)
 				assert.Nil(t, err)
 			}
 
@@ -289,61 +610,50 @@ func TestSelectById(t *testing.T) {
 	}
 }
 
-func TestUpdate(t *testing.T) {
+func TestSelectById(t *testing.T) {
 	type expected struct {
 		task *domain.Task
 		err  error
 	}
 
 	testTable := map[string]struct {
-		id        string
-		input     *domain.Task
-		mockSetup func(sqlmock.Sqlmock, string, *model.UpdateTaskParam)
+		id        domain.TaskID
+		mockSetup func(sqlmock.Sqlmock, domain.TaskID)
 		expected  expected
 	}{
 		"Ok": {
 			id: "6a30b9b0-18bf-47b4-bd23-d72726864def",
-			input: &domain.Task{
-				Description: "Update Test Description",
-				Status:      true,
-			},
-			mockSetup: func(m sqlmock.Sqlmock, id string, param *model.UpdateTaskParam) {
-				rows := sqlmock.NewRows([]string{"id", "title", "description", "status"}).
-					AddRow("6a30b9b0-18bf-47b4-bd23-d72726864def", "Test Title", param.Description, param.Status)
+			mockSetup: func(m sqlmock.Sqlmock, id domain.TaskID) {
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "state", "priority", "due_at", "estimate_hours", "parent_id", "version", "created_at", "updated_at", "completed_at", "deleted_at", "labels", "blocked_by", "subtasks", "completed_subtasks", "checklist_items", "completed_checklist_items"}).
+					AddRow("6a30b9b0-18bf-47b4-bd23-d72726864def", "Test Title", "Test Description", "todo", "P2", nil, nil, nil, 1, testTime, testTime, nil, nil, "[]", "[]", 0, 0, 0, 0)
 
 				m.ExpectQuery(regexp.QuoteMeta(
-					`UPDATE tasks SET description=$1, status=$2 WHERE id=$3
-					RETURNING id, title, description, status`,
-				)).
-					WithArgs(param.Description, param.Status, id).
-					WillReturnRows(rows)
+					"SELECT id, title, description, state, priority, due_at, estimate_hours, parent_id, version, created_at, updated_at, completed_at, deleted_at, "+testComputedColumns+" FROM tasks WHERE id = $1 AND deleted_at IS NULL AND owner_id = $2",
+				)).WithArgs(id, testOwnerId).WillReturnRows(rows)
 			},
 			expected: expected{
 				task: &domain.Task{
 					Id:          "6a30b9b0-18bf-47b4-bd23-d72726864def",
 					Title:       "Test Title",
-					Description: "Update Test Description",
-					Status:      true,
+					Description: "Test Description",
+					State:       domain.TaskStateTodo,
+					Priority:    domain.TaskPriorityP2,
+					Version:     1,
+					CreatedAt:   testTime,
+					UpdatedAt:   testTime,
 				},
 				err: nil,
 			},
 		},
 		"NotFound": {
 			id: "3e440171-0921-4c88-a7ec-13f4cdab0d69",
-			input: &domain.Task{
-				Description: "Update Test Description",
-				Status:      true,
-			},
-			mockSetup: func(m sqlmock.Sqlmock, id string, param *model.UpdateTaskParam) {
-				sqlmock.NewRows([]string{"id", "title", "description", "status"}).
-					AddRow("6a30b9b0-18bf-47b4-bd23-d72726864def", "Test Title", param.Description, param.Status)
+			mockSetup: func(m sqlmock.Sqlmock, id domain.TaskID) {
+				sqlmock.NewRows([]string{"id", "title", "description", "state", "priority", "due_at", "estimate_hours", "parent_id", "version", "created_at", "updated_at", "completed_at", "deleted_at", "labels", "blocked_by", "subtasks", "completed_subtasks", "checklist_items", "completed_checklist_items"}).
+					AddRow("6a30b9b0-18bf-47b4-bd23-d72726864def", "Test Title", "Test Description", "todo", "P2", nil, nil, nil, 1, testTime, testTime, nil, nil, "[]", "[]", 0, 0, 0, 0)
 
 				m.ExpectQuery(regexp.QuoteMeta(
-					`UPDATE tasks SET description=$1, status=$2 WHERE id=$3
-					RETURNING id, title, description, status`,
-				)).
-					WithArgs(param.Description, param.Status, id).
-					WillReturnError(sql.ErrNoRows)
+					"SELECT id, title, description, state, priority, due_at, estimate_hours, parent_id, version, created_at, updated_at, completed_at, deleted_at, "+testComputedColumns+" FROM tasks WHERE id = $1 AND deleted_at IS NULL AND owner_id = $2",
+				)).WithArgs(id, testOwnerId).WillReturnError(sql.ErrNoRows)
 			},
 			expected: expected{
 				task: nil,
@@ -352,20 +662,13 @@ func TestUpdate(t *testing.T) {
 		},
 		"InvalidId": {
 			id: "abc123",
-			input: &domain.Task{
-				Description: "Update Test Description",
-				Status:      true,
-			},
-			mockSetup: func(m sqlmock.Sqlmock, id string, param *model.UpdateTaskParam) {
-				sqlmock.NewRows([]string{"id", "title", "description", "status"}).
-					AddRow("6a30b9b0-18bf-47b4-bd23-d72726864def", "Test Title", param.Description, param.Status)
+			mockSetup: func(m sqlmock.Sqlmock, id domain.TaskID) {
+				sqlmock.NewRows([]string{"id", "title", "description", "state", "priority", "due_at", "estimate_hours", "parent_id", "version", "created_at", "updated_at", "completed_at", "deleted_at", "labels", "blocked_by", "subtasks", "completed_subtasks", "checklist_items", "completed_checklist_items"}).
+					AddRow("6a30b9b0-18bf-47b4-bd23-d72726864def", "Test Title", "Test Description", "todo", "P2", nil, nil, nil, 1, testTime, testTime, nil, nil, "[]", "[]", 0, 0, 0, 0)
 
 				m.ExpectQuery(regexp.QuoteMeta(
-					`UPDATE tasks SET description=$1, status=$2 WHERE id=$3
-					RETURNING id, title, description, status`,
-				)).
-					WithArgs(param.Description, param.Status, id).
-					WillReturnError(errors.New("pq: invalid input syntax for type uuid: \"abc123\""))
+					"SELECT id, title, description, state, priority, due_at, estimate_hours, parent_id, version, created_at, updated_at, completed_at, deleted_at, "+testComputedColumns+" FROM tasks WHERE id = $1 AND deleted_at IS NULL AND owner_id = $2",
+				)).WithArgs(id, testOwnerId).WillReturnError(errors.New("pq: invalid input syntax for type uuid: \"abc123\""))
 			},
 			expected: expected{
 				task: nil,
@@ -381,10 +684,10 @@ func TestUpdate(t *testing.T) {
 			require.NoError(t, err)
 			defer db.Close()
 
-			tt.mockSetup(mock, tt.id, model.ToUpdateTaskParam(tt.input))
+			tt.mockSetup(mock, tt.id)
 
 			repo := &TaskRepository{Db: db}
-			result, err := repo.Update(context.Background(), tt.id, tt.input)
+			result, err := repo.SelectById(testOwnerCtx, tt.id)
 
 			if tt.expected.err != nil {
 				assert.Nil(t, result)
@@ -400,46 +703,181 @@ func TestUpdate(t *testing.T) {
 	}
 }
 
-func TestDelete(t *testing.T) {
+func TestUpdate(t *testing.T) {
 	type expected struct {
 		task *domain.Task
 		err  error
 	}
 
 	testTable := map[string]struct {
-		id        string
-		mockSetup func(sqlmock.Sqlmock, string)
+		id        domain.TaskID
+		input     *domain.TaskPatch
+		mockSetup func(sqlmock.Sqlmock, domain.TaskID, *model.UpdateTaskParam)
 		expected  expected
 	}{
 		"Ok": {
 			id: "6a30b9b0-18bf-47b4-bd23-d72726864def",
-			mockSetup: func(m sqlmock.Sqlmock, id string) {
-				rows := sqlmock.NewRows([]string{"id"}).
-					AddRow("6a30b9b0-18bf-47b4-bd23-d72726864def")
+			input: &domain.TaskPatch{
+				Description: ptr("Update Test Description"),
+				State:       ptr(domain.TaskStateDone),
+				Version:     1,
+			},
+			mockSetup: func(m sqlmock.Sqlmock, id domain.TaskID, param *model.UpdateTaskParam) {
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "state", "priority", "due_at", "estimate_hours", "parent_id", "version", "created_at", "updated_at", "completed_at", "deleted_at", "labels", "blocked_by", "subtasks", "completed_subtasks", "checklist_items", "completed_checklist_items"}).
+					AddRow("6a30b9b0-18bf-47b4-bd23-d72726864def", "Test Title", "Update Test Description", "done", "P2", nil, nil, nil, 2, testTime, testTime, testTime, nil, "[]", "[]", 0, 0, 0, 0)
 
 				m.ExpectQuery(regexp.QuoteMeta(
-					"DELETE FROM tasks WHERE id=$1 RETURNING id",
+					`UPDATE tasks SET title=COALESCE($1, title), description=COALESCE($2, description),
+					state=COALESCE($3, state), priority=COALESCE($6, priority),
+					due_at=CASE WHEN $8 THEN NULL ELSE COALESCE($7, due_at) END,
+					estimate_hours=CASE WHEN $12 THEN NULL ELSE COALESCE($11, estimate_hours) END,
+					parent_id=CASE WHEN $10 THEN NULL ELSE COALESCE($9, parent_id) END, version=version+1, updated_at=now(),
+					completed_at=CASE COALESCE($3, state) WHEN 'done' THEN COALESCE(completed_at, now()) WHEN 'archived' THEN completed_at END
+					WHERE id=$4 AND deleted_at IS NULL AND ($5 = 0 OR version=$5) AND owner_id=$13
+					RETURNING id, title, description, state, priority, due_at, estimate_hours, parent_id, version, created_at, updated_at, completed_at, deleted_at, `+testComputedColumns,
 				)).
-					WithArgs(id).
+					WithArgs(param.Title, param.Description, param.State, id, param.Version, param.Priority, param.DueAt, param.ClearDueAt, param.ParentId, param.ClearParent, param.EstimateHours, param.ClearEstimate, testOwnerId).
 					WillReturnRows(rows)
 			},
 			expected: expected{
 				task: &domain.Task{
-					Id: "6a30b9b0-18bf-47b4-bd23-d72726864def",
+					Id:          "6a30b9b0-18bf-47b4-bd23-d72726864def",
+					Title:       "Test Title",
+					Description: "Update Test Description",
+					State:       domain.TaskStateDone,
+					Priority:    domain.TaskPriorityP2,
+					Version:     2,
+					CreatedAt:   testTime,
+					UpdatedAt:   testTime,
+					CompletedAt: &testTime,
+				},
+				err: nil,
+			},
+		},
+		"ReprioritizeAndClearDueDate": {
+			id: "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			input: &domain.TaskPatch{
+				Priority:   ptr(domain.TaskPriorityP0),
+				ClearDueAt: true,
+				Version:    1,
+			},
+			mockSetup: func(m sqlmock.Sqlmock, id domain.TaskID, param *model.UpdateTaskParam) {
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "state", "priority", "due_at", "estimate_hours", "parent_id", "version", "created_at", "updated_at", "completed_at", "deleted_at", "labels", "blocked_by", "subtasks", "completed_subtasks", "checklist_items", "completed_checklist_items"}).
+					AddRow("6a30b9b0-18bf-47b4-bd23-d72726864def", "Test Title", "Test Description", "todo", "P0", nil, nil, nil, 2, testTime, testTime, nil, nil, "[]", "[]", 0, 0, 0, 0)
+
+				m.ExpectQuery(regexp.QuoteMeta(
+					`UPDATE tasks SET title=COALESCE($1, title), description=COALESCE($2, description),
+					state=COALESCE($3, state), priority=COALESCE($6, priority),
+					due_at=CASE WHEN $8 THEN NULL ELSE COALESCE($7, due_at) END,
+					estimate_hours=CASE WHEN $12 THEN NULL ELSE COALESCE($11, estimate_hours) END,
+					parent_id=CASE WHEN $10 THEN NULL ELSE COALESCE($9, parent_id) END, version=version+1, updated_at=now(),
+					completed_at=CASE COALESCE($3, state) WHEN 'done' THEN COALESCE(completed_at, now()) WHEN 'archived' THEN completed_at END
+					WHERE id=$4 AND deleted_at IS NULL AND ($5 = 0 OR version=$5) AND owner_id=$13
+					RETURNING id, title, description, state, priority, due_at, estimate_hours, parent_id, version, created_at, updated_at, completed_at, deleted_at, `+testComputedColumns,
+				)).
+					WithArgs(nil, nil, nil, id, param.Version, "P0", nil, true, nil, false, nil, false, testOwnerId).
+					WillReturnRows(rows)
+			},
+			expected: expected{
+				task: &domain.Task{
+					Id:          "6a30b9b0-18bf-47b4-bd23-d72726864def",
+					Title:       "Test Title",
+					Description: "Test Description",
+					State:       domain.TaskStateTodo,
+					Priority:    domain.TaskPriorityP0,
+					Version:     2,
+					CreatedAt:   testTime,
+					UpdatedAt:   testTime,
+				},
+				err: nil,
+			},
+		},
+		"Rename": {
+			id: "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			input: &domain.TaskPatch{
+				Title:   ptr("Renamed Title"),
+				Version: 1,
+			},
+			mockSetup: func(m sqlmock.Sqlmock, id domain.TaskID, param *model.UpdateTaskParam) {
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "state", "priority", "due_at", "estimate_hours", "parent_id", "version", "created_at", "updated_at", "completed_at", "deleted_at", "labels", "blocked_by", "subtasks", "completed_subtasks", "checklist_items", "completed_checklist_items"}).
+					AddRow("6a30b9b0-18bf-47b4-bd23-d72726864def", "Renamed Title", "Test Description", "todo", "P2", nil, nil, nil, 2, testTime, testTime, nil, nil, "[]", "[]", 0, 0, 0, 0)
+
+				m.ExpectQuery(regexp.QuoteMeta(
+					`UPDATE tasks SET title=COALESCE($1, title), description=COALESCE($2, description),
+					state=COALESCE($3, state), priority=COALESCE($6, priority),
+					due_at=CASE WHEN $8 THEN NULL ELSE COALESCE($7, due_at) END,
+					estimate_hours=CASE WHEN $12 THEN NULL ELSE COALESCE($11, estimate_hours) END,
+					parent_id=CASE WHEN $10 THEN NULL ELSE COALESCE($9, parent_id) END, version=version+1, updated_at=now(),
+					completed_at=CASE COALESCE($3, state) WHEN 'done' THEN COALESCE(completed_at, now()) WHEN 'archived' THEN completed_at END
+					WHERE id=$4 AND deleted_at IS NULL AND ($5 = 0 OR version=$5) AND owner_id=$13
+					RETURNING id, title, description, state, priority, due_at, estimate_hours, parent_id, version, created_at, updated_at, completed_at, deleted_at, `+testComputedColumns,
+				)).
+					WithArgs("Renamed Title", nil, nil, id, param.Version, nil, nil, false, nil, false, nil, false, testOwnerId).
+					WillReturnRows(rows)
+			},
+			expected: expected{
+				task: &domain.Task{
+					Id:          "6a30b9b0-18bf-47b4-bd23-d72726864def",
+					Title:       "Renamed Title",
+					Description: "Test Description",
+					State:       domain.TaskStateTodo,
+					Priority:    domain.TaskPriorityP2,
+					Version:     2,
+					CreatedAt:   testTime,
+					UpdatedAt:   testTime,
 				},
 				err: nil,
 			},
 		},
+		"DuplicateTitle": {
+			id: "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			input: &domain.TaskPatch{
+				Title: ptr("Duplicate Title"),
+			},
+			mockSetup: func(m sqlmock.Sqlmock, id domain.TaskID, param *model.UpdateTaskParam) {
+				m.ExpectQuery(regexp.QuoteMeta(
+					`UPDATE tasks SET title=COALESCE($1, title), description=COALESCE($2, description),
+					state=COALESCE($3, state), priority=COALESCE($6, priority),
+					due_at=CASE WHEN $8 THEN NULL ELSE COALESCE($7, due_at) END,
+					estimate_hours=CASE WHEN $12 THEN NULL ELSE COALESCE($11, estimate_hours) END,
+					parent_id=CASE WHEN $10 THEN NULL ELSE COALESCE($9, parent_id) END, version=version+1, updated_at=now(),
+					completed_at=CASE COALESCE($3, state) WHEN 'done' THEN COALESCE(completed_at, now()) WHEN 'archived' THEN completed_at END
+					WHERE id=$4 AND deleted_at IS NULL AND ($5 = 0 OR version=$5) AND owner_id=$13
+					RETURNING id, title, description, state, priority, due_at, estimate_hours, parent_id, version, created_at, updated_at, completed_at, deleted_at, `+testComputedColumns,
+				)).
+					WithArgs(param.Title, param.Description, param.State, id, param.Version, param.Priority, param.DueAt, param.ClearDueAt, param.ParentId, param.ClearParent, param.EstimateHours, param.ClearEstimate, testOwnerId).
+					WillReturnError(&pq.Error{
+						Code:       "23505",
+						Message:    "duplicate key value violates unique constraint \"tasks_title_key\"",
+						Constraint: "tasks_title_key",
+					})
+			},
+			expected: expected{
+				task: nil,
+				err:  &customError.FieldError{Field: "title", Err: customError.ErrDuplicate},
+			},
+		},
 		"NotFound": {
 			id: "3e440171-0921-4c88-a7ec-13f4cdab0d69",
-			mockSetup: func(m sqlmock.Sqlmock, id string) {
-				sqlmock.NewRows([]string{"id"}).
-					AddRow("6a30b9b0-18bf-47b4-bd23-d72726864def")
+			input: &domain.TaskPatch{
+				Description: ptr("Update Test Description"),
+				State:       ptr(domain.TaskStateDone),
+			},
+			mockSetup: func(m sqlmock.Sqlmock, id domain.TaskID, param *model.UpdateTaskParam) {
+				sqlmock.NewRows([]string{"id", "title", "description", "state", "priority", "due_at", "estimate_hours", "parent_id", "version", "created_at", "updated_at", "completed_at", "deleted_at", "labels", "blocked_by", "subtasks", "completed_subtasks", "checklist_items", "completed_checklist_items"}).
+					AddRow("6a30b9b0-18bf-47b4-bd23-d72726864def", "Test Title", "Update Test Description", "done", "P2", nil, nil, nil, 1, testTime, testTime, nil, nil, "[]", "[]", 0, 0, 0, 0)
 
 				m.ExpectQuery(regexp.QuoteMeta(
-					"DELETE FROM tasks WHERE id=$1 RETURNING id",
+					`UPDATE tasks SET title=COALESCE($1, title), description=COALESCE($2, description),
+					state=COALESCE($3, state), priority=COALESCE($6, priority),
+					due_at=CASE WHEN $8 THEN NULL ELSE COALESCE($7, due_at) END,
+					estimate_hours=CASE WHEN $12 THEN NULL ELSE COALESCE($11, estimate_hours) END,
+					parent_id=CASE WHEN $10 THEN NULL ELSE COALESCE($9, parent_id) END, version=version+1, updated_at=now(),
+					completed_at=CASE COALESCE($3, state) WHEN 'done' THEN COALESCE(completed_at, now()) WHEN 'archived' THEN completed_at END
+					WHERE id=$4 AND deleted_at IS NULL AND ($5 = 0 OR version=$5) AND owner_id=$13
+					RETURNING id, title, description, state, priority, due_at, estimate_hours, parent_id, version, created_at, updated_at, completed_at, deleted_at, `+testComputedColumns,
 				)).
-					WithArgs(id).
+					WithArgs(param.Title, param.Description, param.State, id, param.Version, param.Priority, param.DueAt, param.ClearDueAt, param.ParentId, param.ClearParent, param.EstimateHours, param.ClearEstimate, testOwnerId).
 					WillReturnError(sql.ErrNoRows)
 			},
 			expected: expected{
@@ -447,36 +885,610 @@ func TestDelete(t *testing.T) {
 				err:  customError.ErrNotFound,
 			},
 		},
-		"InvalidId": {
-			id: "abc123",
-			mockSetup: func(m sqlmock.Sqlmock, id string) {
-				sqlmock.NewRows([]string{"id"}).
-					AddRow("6a30b9b0-18bf-47b4-bd23-d72726864def")
+		"VersionMismatch": {
+			id: "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			input: &domain.TaskPatch{
+				Description: ptr("Update Test Description"),
+				State:       ptr(domain.TaskStateDone),
+				Version:     1,
+			},
+			mockSetup: func(m sqlmock.Sqlmock, id domain.TaskID, param *model.UpdateTaskParam) {
+				m.ExpectQuery(regexp.QuoteMeta(
+					`UPDATE tasks SET title=COALESCE($1, title), description=COALESCE($2, description),
+					state=COALESCE($3, state), priority=COALESCE($6, priority),
+					due_at=CASE WHEN $8 THEN NULL ELSE COALESCE($7, due_at) END,
+					estimate_hours=CASE WHEN $12 THEN NULL ELSE COALESCE($11, estimate_hours) END,
+					parent_id=CASE WHEN $10 THEN NULL ELSE COALESCE($9, parent_id) END, version=version+1, updated_at=now(),
+					completed_at=CASE COALESCE($3, state) WHEN 'done' THEN COALESCE(completed_at, now()) WHEN 'archived' THEN completed_at END
+					WHERE id=$4 AND deleted_at IS NULL AND ($5 = 0 OR version=$5) AND owner_id=$13
+					RETURNING id, title, description, state, priority, due_at, estimate_hours, parent_id, version, created_at, updated_at, completed_at, deleted_at, `+testComputedColumns,
+				)).
+					WithArgs(param.Title, param.Description, param.State, id, param.Version, param.Priority, param.DueAt, param.ClearDueAt, param.ParentId, param.ClearParent, param.EstimateHours, param.ClearEstimate, testOwnerId).
+					WillReturnError(sql.ErrNoRows)
 
 				m.ExpectQuery(regexp.QuoteMeta(
-					"DELETE FROM tasks WHERE id=$1 RETURNING id",
+					"SELECT EXISTS(SELECT 1 FROM tasks WHERE id = $1 AND deleted_at IS NULL AND owner_id = $2)",
 				)).
-					WithArgs(id).
-					WillReturnError(errors.New("pq: invalid input syntax for type uuid: \"abc123\""))
+					WithArgs(id, testOwnerId).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
 			},
 			expected: expected{
 				task: nil,
-				err:  customError.ErrInternalServerError,
+				err:  customError.ErrVersionMismatch,
 			},
 		},
-	}
-
-	for n, tt := range testTable {
-		tt := tt
-		t.Run(n, func(t *testing.T) {
-			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
-			require.NoError(t, err)
-			defer db.Close()
-
-			tt.mockSetup(mock, tt.id)
+		"ConditionalNotFound": {
+			id: "3e440171-0921-4c88-a7ec-13f4cdab0d69",
+			input: &domain.TaskPatch{
+				Description: ptr("Update Test Description"),
+				State:       ptr(domain.TaskStateDone),
+				Version:     1,
+			},
+			mockSetup: func(m sqlmock.Sqlmock, id domain.TaskID, param *model.UpdateTaskParam) {
+				m.ExpectQuery(regexp.QuoteMeta(
+					`UPDATE tasks SET title=COALESCE($1, title), description=COALESCE($2, description),
+					state=COALESCE($3, state), priority=COALESCE($6, priority),
+					due_at=CASE WHEN $8 THEN NULL ELSE COALESCE($7, due_at) END,
+					estimate_hours=CASE WHEN $12 THEN NULL ELSE COALESCE($11, estimate_hours) END,
+					parent_id=CASE WHEN $10 THEN NULL ELSE COALESCE($9, parent_id) END, version=version+1, updated_at=now(),
+					completed_at=CASE COALESCE($3, state) WHEN 'done' THEN COALESCE(completed_at, now()) WHEN 'archived' THEN completed_at END
+					WHERE id=$4 AND deleted_at IS NULL AND ($5 = 0 OR version=$5) AND owner_id=$13
+					RETURNING id, title, description, state, priority, due_at, estimate_hours, parent_id, version, created_at, updated_at, completed_at, deleted_at, `+testComputedColumns,
+				)).
+					WithArgs(param.Title, param.Description, param.State, id, param.Version, param.Priority, param.DueAt, param.ClearDueAt, param.ParentId, param.ClearParent, param.EstimateHours, param.ClearEstimate, testOwnerId).
+					WillReturnError(sql.ErrNoRows)
 
-			repo := &TaskRepository{Db: db}
-			result, err := repo.Delete(context.Background(), tt.id)
+				m.ExpectQuery(regexp.QuoteMeta(
+					"SELECT EXISTS(SELECT 1 FROM tasks WHERE id = $1 AND deleted_at IS NULL AND owner_id = $2)",
+				)).
+					WithArgs(id, testOwnerId).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+			},
+			expected: expected{
+				task: nil,
+				err:  customError.ErrNotFound,
+			},
+		},
+		"InvalidId": {
+			id: "abc123",
+			input: &domain.TaskPatch{
+				Description: ptr("Update Test Description"),
+				State:       ptr(domain.TaskStateDone),
+			},
+			mockSetup: func(m sqlmock.Sqlmock, id domain.TaskID, param *model.UpdateTaskParam) {
+				sqlmock.NewRows([]string{"id", "title", "description", "state", "priority", "due_at", "estimate_hours", "parent_id", "version", "created_at", "updated_at", "completed_at", "deleted_at", "labels", "blocked_by", "subtasks", "completed_subtasks", "checklist_items", "completed_checklist_items"}).
+					AddRow("6a30b9b0-18bf-47b4-bd23-d72726864def", "Test Title", "Update Test Description", "done", "P2", nil, nil, nil, 1, testTime, testTime, nil, nil, "[]", "[]", 0, 0, 0, 0)
+
+				m.ExpectQuery(regexp.QuoteMeta(
+					`UPDATE tasks SET title=COALESCE($1, title), description=COALESCE($2, description),
+					state=COALESCE($3, state), priority=COALESCE($6, priority),
+					due_at=CASE WHEN $8 THEN NULL ELSE COALESCE($7, due_at) END,
+					estimate_hours=CASE WHEN $12 THEN NULL ELSE COALESCE($11, estimate_hours) END,
+					parent_id=CASE WHEN $10 THEN NULL ELSE COALESCE($9, parent_id) END, version=version+1, updated_at=now(),
+					completed_at=CASE COALESCE($3, state) WHEN 'done' THEN COALESCE(completed_at, now()) WHEN 'archived' THEN completed_at END
+					WHERE id=$4 AND deleted_at IS NULL AND ($5 = 0 OR version=$5) AND owner_id=$13
+					RETURNING id, title, description, state, priority, due_at, estimate_hours, parent_id, version, created_at, updated_at, completed_at, deleted_at, `+testComputedColumns,
+				)).
+					WithArgs(param.Title, param.Description, param.State, id, param.Version, param.Priority, param.DueAt, param.ClearDueAt, param.ParentId, param.ClearParent, param.EstimateHours, param.ClearEstimate, testOwnerId).
+					WillReturnError(errors.New("pq: invalid input syntax for type uuid: \"abc123\""))
+			},
+			expected: expected{
+				task: nil,
+				err:  customError.ErrInternalServerError,
+			},
+		},
+	}
+
+	for n, tt := range testTable {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+			require.NoError(t, err)
+			defer db.Close()
+
+			tt.mockSetup(mock, tt.id, model.ToUpdateTaskParam(tt.input))
+
+			repo := &TaskRepository{Db: db}
+			result, err := repo.Update(testOwnerCtx, tt.id, tt.input)
+
+			if tt.expected.err != nil {
+				assert.Nil(t, result)
+				assert.Error(t, err)
+				assert.EqualError(t, err, tt.expected.err.Error())
+			} else {
+				assert.Equal(t, tt.expected.task, result)
+				assert.Nil(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSetLabels(t *testing.T) {
+	type expected struct {
+		task *domain.Task
+		err  error
+	}
+
+	touchQuery := regexp.QuoteMeta(
+		`UPDATE tasks SET version=version+1, updated_at=now()
+		WHERE id=$1 AND deleted_at IS NULL AND ($2 = 0 OR version=$2) AND owner_id=$3
+		RETURNING id`,
+	)
+	insertQuery := regexp.QuoteMeta(
+		"INSERT INTO task_labels(task_id, label_id) SELECT $1, id FROM labels WHERE id = ANY($2::uuid[]) AND owner_id = $3",
+	)
+
+	testTable := map[string]struct {
+		id        domain.TaskID
+		labelIds  []domain.LabelID
+		version   int
+		mockSetup func(sqlmock.Sqlmock, domain.TaskID, int)
+		expected  expected
+	}{
+		"Ok": {
+			id:       "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			labelIds: []domain.LabelID{"c6a1f3e2-7d4b-4e8a-b1f9-3a2d5e6c7b80"},
+			version:  1,
+			mockSetup: func(m sqlmock.Sqlmock, id domain.TaskID, version int) {
+				m.ExpectBegin()
+				m.ExpectQuery(touchQuery).
+					WithArgs(id, version, testOwnerId).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(id.String()))
+				m.ExpectExec(regexp.QuoteMeta("DELETE FROM task_labels WHERE task_id=$1")).
+					WithArgs(id).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				m.ExpectExec(insertQuery).
+					WithArgs(id, pq.Array([]string{"c6a1f3e2-7d4b-4e8a-b1f9-3a2d5e6c7b80"}), testOwnerId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "state", "priority", "due_at", "estimate_hours", "parent_id", "version", "created_at", "updated_at", "completed_at", "deleted_at", "labels", "blocked_by", "subtasks", "completed_subtasks", "checklist_items", "completed_checklist_items"}).
+					AddRow(id.String(), "Test Title", "Test Description", "todo", "P2", nil, nil, nil, 2, testTime, testTime, nil, nil,
+						`[{"id": "c6a1f3e2-7d4b-4e8a-b1f9-3a2d5e6c7b80", "name": "bug", "color": "#ff0000"}]`, "[]", 0, 0, 0, 0)
+				m.ExpectQuery(regexp.QuoteMeta(
+					"SELECT id, title, description, state, priority, due_at, estimate_hours, parent_id, version, created_at, updated_at, completed_at, deleted_at, " + testComputedColumns + " FROM tasks WHERE id = $1",
+				)).
+					WithArgs(id).
+					WillReturnRows(rows)
+				m.ExpectCommit()
+			},
+			expected: expected{
+				task: &domain.Task{
+					Id:          "6a30b9b0-18bf-47b4-bd23-d72726864def",
+					Title:       "Test Title",
+					Description: "Test Description",
+					State:       domain.TaskStateTodo,
+					Priority:    domain.TaskPriorityP2,
+					Labels: []*domain.Label{
+						{Id: "c6a1f3e2-7d4b-4e8a-b1f9-3a2d5e6c7b80", Name: "bug", Color: "#ff0000"},
+					},
+					Version:   2,
+					CreatedAt: testTime,
+					UpdatedAt: testTime,
+				},
+				err: nil,
+			},
+		},
+		"UnknownLabel": {
+			id: "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			labelIds: []domain.LabelID{
+				"c6a1f3e2-7d4b-4e8a-b1f9-3a2d5e6c7b80", "0b9b4d6e-5c1f-4b0e-9a43-2f6f0c3d8e71", "c6a1f3e2-7d4b-4e8a-b1f9-3a2d5e6c7b80",
+			},
+			mockSetup: func(m sqlmock.Sqlmock, id domain.TaskID, version int) {
+				m.ExpectBegin()
+				m.ExpectQuery(touchQuery).
+					WithArgs(id, version, testOwnerId).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(id.String()))
+				m.ExpectExec(regexp.QuoteMeta("DELETE FROM task_labels WHERE task_id=$1")).
+					WithArgs(id).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				// Missing, or a label of someone else
+				m.ExpectExec(insertQuery).
+					WithArgs(id, pq.Array([]string{"c6a1f3e2-7d4b-4e8a-b1f9-3a2d5e6c7b80", "0b9b4d6e-5c1f-4b0e-9a43-2f6f0c3d8e71"}), testOwnerId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				m.ExpectRollback()
+			},
+			expected: expected{
+				task: nil,
+				err:  &customError.FieldError{Field: "labels", Err: customError.ErrNotFound},
+			},
+		},
+		"VersionMismatch": {
+			id:       "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			labelIds: []domain.LabelID{},
+			version:  3,
+			mockSetup: func(m sqlmock.Sqlmock, id domain.TaskID, version int) {
+				m.ExpectBegin()
+				m.ExpectQuery(touchQuery).
+					WithArgs(id, version, testOwnerId).
+					WillReturnError(sql.ErrNoRows)
+				m.ExpectQuery(regexp.QuoteMeta(
+					"SELECT EXISTS(SELECT 1 FROM tasks WHERE id = $1 AND deleted_at IS NULL AND owner_id = $2)",
+				)).
+					WithArgs(id, testOwnerId).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				m.ExpectRollback()
+			},
+			expected: expected{
+				task: nil,
+				err:  customError.ErrVersionMismatch,
+			},
+		},
+		"NotFound": {
+			id:       "3e440171-0921-4c88-a7ec-13f4cdab0d69",
+			labelIds: []domain.LabelID{},
+			mockSetup: func(m sqlmock.Sqlmock, id domain.TaskID, version int) {
+				m.ExpectBegin()
+				m.ExpectQuery(touchQuery).
+					WithArgs(id, version, testOwnerId).
+					WillReturnError(sql.ErrNoRows)
+				m.ExpectRollback()
+			},
+			expected: expected{
+				task: nil,
+				err:  customError.ErrNotFound,
+			},
+		},
+	}
+
+	for n, tt := range testTable {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+			require.NoError(t, err)
+			defer db.Close()
+
+			tt.mockSetup(mock, tt.id, tt.version)
+
+			repo := &TaskRepository{Db: db}
+			result, err := repo.SetLabels(testOwnerCtx, tt.id, tt.labelIds, tt.version)
+
+			if tt.expected.err != nil {
+				assert.Nil(t, result)
+				assert.Error(t, err)
+				assert.EqualError(t, err, tt.expected.err.Error())
+			} else {
+				assert.Equal(t, tt.expected.task, result)
+				assert.Nil(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	type expected struct {
+		task *domain.Task
+		err  error
+	}
+
+	trashQuery := regexp.QuoteMeta(testDeleteQuery)
+
+	testTable := map[string]struct {
+		id        domain.TaskID
+		version   int
+		mode      domain.SubtaskMode
+		mockSetup func(sqlmock.Sqlmock, domain.TaskID, int)
+		expected  expected
+	}{
+		"Ok": {
+			id: "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			mockSetup: func(m sqlmock.Sqlmock, id domain.TaskID, version int) {
+				m.ExpectBegin()
+				m.ExpectExec(regexp.QuoteMeta(testLockHierarchy)).WillReturnResult(sqlmock.NewResult(0, 0))
+				m.ExpectQuery(trashQuery).
+					WithArgs(id, version, testOwnerId).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(id.String()))
+				m.ExpectQuery(regexp.QuoteMeta(testLiveSubtasksQuery)).
+					WithArgs(id).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+				m.ExpectCommit()
+			},
+			expected: expected{
+				task: &domain.Task{
+					Id: "6a30b9b0-18bf-47b4-bd23-d72726864def",
+				},
+				err: nil,
+			},
+		},
+		"HasSubtasks": {
+			id: "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			mockSetup: func(m sqlmock.Sqlmock, id domain.TaskID, version int) {
+				m.ExpectBegin()
+				m.ExpectExec(regexp.QuoteMeta(testLockHierarchy)).WillReturnResult(sqlmock.NewResult(0, 0))
+				m.ExpectQuery(trashQuery).
+					WithArgs(id, version, testOwnerId).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(id.String()))
+				m.ExpectQuery(regexp.QuoteMeta(testLiveSubtasksQuery)).
+					WithArgs(id).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				m.ExpectRollback()
+			},
+			expected: expected{
+				task: nil,
+				err:  customError.ErrHasChildren,
+			},
+		},
+		"Cascade": {
+			id:   "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			mode: domain.SubtaskModeCascade,
+			mockSetup: func(m sqlmock.Sqlmock, id domain.TaskID, version int) {
+				m.ExpectBegin()
+				m.ExpectExec(regexp.QuoteMeta(testLockHierarchy)).WillReturnResult(sqlmock.NewResult(0, 0))
+				m.ExpectQuery(trashQuery).
+					WithArgs(id, version, testOwnerId).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(id.String()))
+				m.ExpectExec(regexp.QuoteMeta(
+					`WITH RECURSIVE subtree AS (
+					SELECT id FROM tasks WHERE parent_id = $1 AND deleted_at IS NULL
+					UNION ALL
+					SELECT t.id FROM tasks t JOIN subtree s ON t.parent_id = s.id WHERE t.deleted_at IS NULL
+					)
+					UPDATE tasks SET deleted_at=now() WHERE id IN (SELECT id FROM subtree)`,
+				)).
+					WithArgs(id).
+					WillReturnResult(sqlmock.NewResult(0, 3))
+				m.ExpectCommit()
+			},
+			expected: expected{
+				task: &domain.Task{
+					Id: "6a30b9b0-18bf-47b4-bd23-d72726864def",
+				},
+				err: nil,
+			},
+		},
+		"Reparent": {
+			id:   "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			mode: domain.SubtaskModeReparent,
+			mockSetup: func(m sqlmock.Sqlmock, id domain.TaskID, version int) {
+				m.ExpectBegin()
+				m.ExpectExec(regexp.QuoteMeta(testLockHierarchy)).WillReturnResult(sqlmock.NewResult(0, 0))
+				m.ExpectQuery(trashQuery).
+					WithArgs(id, version, testOwnerId).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(id.String()))
+				m.ExpectExec(regexp.QuoteMeta(
+					`UPDATE tasks SET parent_id=(SELECT parent_id FROM tasks WHERE id=$1), version=version+1, updated_at=now()
+					WHERE parent_id=$1 AND deleted_at IS NULL`,
+				)).
+					WithArgs(id).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				m.ExpectCommit()
+			},
+			expected: expected{
+				task: &domain.Task{
+					Id: "6a30b9b0-18bf-47b4-bd23-d72726864def",
+				},
+				err: nil,
+			},
+		},
+		"NotFound": {
+			id: "3e440171-0921-4c88-a7ec-13f4cdab0d69",
+			mockSetup: func(m sqlmock.Sqlmock, id domain.TaskID, version int) {
+				m.ExpectBegin()
+				m.ExpectExec(regexp.QuoteMeta(testLockHierarchy)).WillReturnResult(sqlmock.NewResult(0, 0))
+				m.ExpectQuery(trashQuery).
+					WithArgs(id, version, testOwnerId).
+					WillReturnError(sql.ErrNoRows)
+				m.ExpectRollback()
+			},
+			expected: expected{
+				task: nil,
+				err:  customError.ErrNotFound,
+			},
+		},
+		"VersionMismatch": {
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			version: 3,
+			mockSetup: func(m sqlmock.Sqlmock, id domain.TaskID, version int) {
+				m.ExpectBegin()
+				m.ExpectExec(regexp.QuoteMeta(testLockHierarchy)).WillReturnResult(sqlmock.NewResult(0, 0))
+				m.ExpectQuery(trashQuery).
+					WithArgs(id, version, testOwnerId).
+					WillReturnError(sql.ErrNoRows)
+				m.ExpectQuery(regexp.QuoteMeta(
+					"SELECT EXISTS(SELECT 1 FROM tasks WHERE id = $1 AND deleted_at IS NULL AND owner_id = $2)",
+				)).
+					WithArgs(id, testOwnerId).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				m.ExpectRollback()
+			},
+			expected: expected{
+				task: nil,
+				err:  customError.ErrVersionMismatch,
+			},
+		},
+		"InvalidId": {
+			id: "abc123",
+			mockSetup: func(m sqlmock.Sqlmock, id domain.TaskID, version int) {
+				m.ExpectBegin()
+				m.ExpectExec(regexp.QuoteMeta(testLockHierarchy)).WillReturnResult(sqlmock.NewResult(0, 0))
+				m.ExpectQuery(trashQuery).
+					WithArgs(id, version, testOwnerId).
+					WillReturnError(errors.New("pq: invalid input syntax for type uuid: \"abc123\""))
+				m.ExpectRollback()
+			},
+			expected: expected{
+				task: nil,
+				err:  customError.ErrInternalServerError,
+			},
+		},
+	}
+
+	for n, tt := range testTable {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+			require.NoError(t, err)
+			defer db.Close()
+
+			tt.mockSetup(mock, tt.id, tt.version)
+
+			repo := &TaskRepository{Db: db}
+			result, err := repo.Delete(testOwnerCtx, tt.id, tt.version, tt.mode)
+
+			if tt.expected.err != nil {
+				assert.Nil(t, result)
+				assert.Error(t, err)
+				assert.EqualError(t, err, tt.expected.err.Error())
+			} else {
+				assert.Equal(t, tt.expected.task, result)
+				assert.Nil(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSelectTrash(t *testing.T) {
+	type expected struct {
+		taskList []*domain.Task
+		err      error
+	}
+
+	testTable := map[string]struct {
+		mockSetup func(sqlmock.Sqlmock)
+		expected  expected
+	}{
+		"Ok": {
+			mockSetup: func(m sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "state", "priority", "due_at", "estimate_hours", "parent_id", "version", "created_at", "updated_at", "completed_at", "deleted_at", "labels", "blocked_by", "subtasks", "completed_subtasks", "checklist_items", "completed_checklist_items"}).
+					AddRow("6a30b9b0-18bf-47b4-bd23-d72726864def", "Test Title", "Test Description", "todo", "P2", nil, nil, nil, 1, testTime, testTime, nil, testTime, "[]", "[]", 0, 0, 0, 0)
+
+				m.ExpectQuery(regexp.QuoteMeta(
+					`SELECT id, title, description, state, priority, due_at, estimate_hours, parent_id, version, created_at, updated_at, completed_at, deleted_at, ` + testComputedColumns +
+						` FROM tasks WHERE deleted_at IS NOT NULL AND owner_id = $1 ORDER BY deleted_at DESC, id DESC`,
+				)).WithArgs(testOwnerId).WillReturnRows(rows)
+			},
+			expected: expected{
+				taskList: []*domain.Task{
+					{
+						Id:          "6a30b9b0-18bf-47b4-bd23-d72726864def",
+						Title:       "Test Title",
+						Description: "Test Description",
+						State:       domain.TaskStateTodo,
+						Priority:    domain.TaskPriorityP2,
+						Version:     1,
+						CreatedAt:   testTime,
+						UpdatedAt:   testTime,
+						DeletedAt:   &testTime,
+					},
+				},
+				err: nil,
+			},
+		},
+		"InternalServerErr": {
+			mockSetup: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(regexp.QuoteMeta(
+					`SELECT id, title, description, state, priority, due_at, estimate_hours, parent_id, version, created_at, updated_at, completed_at, deleted_at, ` + testComputedColumns +
+						` FROM tasks WHERE deleted_at IS NOT NULL AND owner_id = $1 ORDER BY deleted_at DESC, id DESC`,
+				)).WithArgs(testOwnerId).WillReturnError(errors.New("pq: connection reset by peer"))
+			},
+			expected: expected{
+				taskList: nil,
+				err:      customError.ErrInternalServerError,
+			},
+		},
+	}
+
+	for n, tt := range testTable {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+			require.NoError(t, err)
+			defer db.Close()
+
+			tt.mockSetup(mock)
+
+			repo := &TaskRepository{Db: db}
+			result, err := repo.SelectTrash(testOwnerCtx)
+
+			if tt.expected.err != nil {
+				assert.Nil(t, result)
+				assert.Error(t, err)
+				assert.EqualError(t, err, tt.expected.err.Error())
+			} else {
+				assert.Equal(t, tt.expected.taskList, result)
+				assert.Nil(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRestore(t *testing.T) {
+	type expected struct {
+		task *domain.Task
+		err  error
+	}
+
+	testTable := map[string]struct {
+		id        domain.TaskID
+		mockSetup func(sqlmock.Sqlmock, domain.TaskID)
+		expected  expected
+	}{
+		"Ok": {
+			id: "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			mockSetup: func(m sqlmock.Sqlmock, id domain.TaskID) {
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "state", "priority", "due_at", "estimate_hours", "parent_id", "version", "created_at", "updated_at", "completed_at", "deleted_at", "labels", "blocked_by", "subtasks", "completed_subtasks", "checklist_items", "completed_checklist_items"}).
+					AddRow("6a30b9b0-18bf-47b4-bd23-d72726864def", "Test Title", "Test Description", "todo", "P2", nil, nil, nil, 2, testTime, testTime, nil, nil, "[]", "[]", 0, 0, 0, 0)
+
+				m.ExpectQuery(regexp.QuoteMeta(
+					`UPDATE tasks SET deleted_at=NULL, version=version+1, updated_at=now(),
+					parent_id=(SELECT p.id FROM tasks p WHERE p.id = tasks.parent_id AND p.deleted_at IS NULL)
+					WHERE id=$1 AND deleted_at IS NOT NULL AND owner_id=$2
+					RETURNING id, title, description, state, priority, due_at, estimate_hours, parent_id, version, created_at, updated_at, completed_at, deleted_at, `+testComputedColumns,
+				)).
+					WithArgs(id, testOwnerId).
+					WillReturnRows(rows)
+			},
+			expected: expected{
+				task: &domain.Task{
+					Id:          "6a30b9b0-18bf-47b4-bd23-d72726864def",
+					Title:       "Test Title",
+					Description: "Test Description",
+					State:       domain.TaskStateTodo,
+					Priority:    domain.TaskPriorityP2,
+					Version:     2,
+					CreatedAt:   testTime,
+					UpdatedAt:   testTime,
+				},
+				err: nil,
+			},
+		},
+		"NotInTrash": {
+			id: "3e440171-0921-4c88-a7ec-13f4cdab0d69",
+			mockSetup: func(m sqlmock.Sqlmock, id domain.TaskID) {
+				m.ExpectQuery(regexp.QuoteMeta(
+					`UPDATE tasks SET deleted_at=NULL, version=version+1, updated_at=now(),
+					parent_id=(SELECT p.id FROM tasks p WHERE p.id = tasks.parent_id AND p.deleted_at IS NULL)
+					WHERE id=$1 AND deleted_at IS NOT NULL AND owner_id=$2
+					RETURNING id, title, description, state, priority, due_at, estimate_hours, parent_id, version, created_at, updated_at, completed_at, deleted_at, `+testComputedColumns,
+				)).
+					WithArgs(id, testOwnerId).
+					WillReturnError(sql.ErrNoRows)
+			},
+			expected: expected{
+				task: nil,
+				err:  customError.ErrNotFound,
+			},
+		},
+	}
+
+	for n, tt := range testTable {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+			require.NoError(t, err)
+			defer db.Close()
+
+			tt.mockSetup(mock, tt.id)
+
+			repo := &TaskRepository{Db: db}
+			result, err := repo.Restore(testOwnerCtx, tt.id)
 
 			if tt.expected.err != nil {
 				assert.Nil(t, result)
@@ -491,3 +1503,234 @@ func TestDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestPurge(t *testing.T) {
+	type expected struct {
+		task *domain.Task
+		err  error
+	}
+
+	lockQuery := regexp.QuoteMeta("SELECT parent_id FROM tasks WHERE id=$1 AND ($2 = 0 OR version=$2) AND owner_id=$3 FOR UPDATE")
+	deleteQuery := regexp.QuoteMeta("DELETE FROM tasks WHERE id=$1")
+
+	testTable := map[string]struct {
+		id        domain.TaskID
+		version   int
+		mode      domain.SubtaskMode
+		mockSetup func(sqlmock.Sqlmock, domain.TaskID, int)
+		expected  expected
+	}{
+		"Ok": {
+			id: "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			mockSetup: func(m sqlmock.Sqlmock, id domain.TaskID, version int) {
+				m.ExpectBegin()
+				m.ExpectExec(regexp.QuoteMeta(testLockHierarchy)).WillReturnResult(sqlmock.NewResult(0, 0))
+				m.ExpectQuery(lockQuery).
+					WithArgs(id, version, testOwnerId).
+					WillReturnRows(sqlmock.NewRows([]string{"parent_id"}).AddRow(nil))
+				m.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS(SELECT 1 FROM tasks WHERE parent_id = $1)")).
+					WithArgs(id).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+				m.ExpectExec(deleteQuery).
+					WithArgs(id).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				m.ExpectCommit()
+			},
+			expected: expected{
+				task: &domain.Task{
+					Id: "6a30b9b0-18bf-47b4-bd23-d72726864def",
+				},
+				err: nil,
+			},
+		},
+		"Cascade": {
+			id:   "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			mode: domain.SubtaskModeCascade,
+			mockSetup: func(m sqlmock.Sqlmock, id domain.TaskID, version int) {
+				m.ExpectBegin()
+				m.ExpectExec(regexp.QuoteMeta(testLockHierarchy)).WillReturnResult(sqlmock.NewResult(0, 0))
+				m.ExpectQuery(lockQuery).
+					WithArgs(id, version, testOwnerId).
+					WillReturnRows(sqlmock.NewRows([]string{"parent_id"}).AddRow(nil))
+				m.ExpectExec(deleteQuery).
+					WithArgs(id).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				m.ExpectCommit()
+			},
+			expected: expected{
+				task: &domain.Task{
+					Id: "6a30b9b0-18bf-47b4-bd23-d72726864def",
+				},
+				err: nil,
+			},
+		},
+		"Reparent": {
+			id:   "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			mode: domain.SubtaskModeReparent,
+			mockSetup: func(m sqlmock.Sqlmock, id domain.TaskID, version int) {
+				m.ExpectBegin()
+				m.ExpectExec(regexp.QuoteMeta(testLockHierarchy)).WillReturnResult(sqlmock.NewResult(0, 0))
+				m.ExpectQuery(lockQuery).
+					WithArgs(id, version, testOwnerId).
+					WillReturnRows(sqlmock.NewRows([]string{"parent_id"}).AddRow("f299e7ed-a22a-4494-b59e-21bb91fdae3b"))
+				m.ExpectExec(regexp.QuoteMeta(
+					"UPDATE tasks SET parent_id=$2, version=version+1, updated_at=now() WHERE parent_id=$1",
+				)).
+					WithArgs(id, sql.NullString{String: "f299e7ed-a22a-4494-b59e-21bb91fdae3b", Valid: true}).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				m.ExpectExec(deleteQuery).
+					WithArgs(id).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				m.ExpectCommit()
+			},
+			expected: expected{
+				task: &domain.Task{
+					Id: "6a30b9b0-18bf-47b4-bd23-d72726864def",
+				},
+				err: nil,
+			},
+		},
+		"HasSubtasks": {
+			id: "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			mockSetup: func(m sqlmock.Sqlmock, id domain.TaskID, version int) {
+				m.ExpectBegin()
+				m.ExpectExec(regexp.QuoteMeta(testLockHierarchy)).WillReturnResult(sqlmock.NewResult(0, 0))
+				m.ExpectQuery(lockQuery).
+					WithArgs(id, version, testOwnerId).
+					WillReturnRows(sqlmock.NewRows([]string{"parent_id"}).AddRow(nil))
+				m.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS(SELECT 1 FROM tasks WHERE parent_id = $1)")).
+					WithArgs(id).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				m.ExpectRollback()
+			},
+			expected: expected{
+				task: nil,
+				err:  customError.ErrHasChildren,
+			},
+		},
+		"VersionMismatchInTrash": {
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			version: 2,
+			mockSetup: func(m sqlmock.Sqlmock, id domain.TaskID, version int) {
+				m.ExpectBegin()
+				m.ExpectExec(regexp.QuoteMeta(testLockHierarchy)).WillReturnResult(sqlmock.NewResult(0, 0))
+				m.ExpectQuery(lockQuery).
+					WithArgs(id, version, testOwnerId).
+					WillReturnError(sql.ErrNoRows)
+				m.ExpectQuery(regexp.QuoteMeta(
+					"SELECT EXISTS(SELECT 1 FROM tasks WHERE id = $1 AND owner_id = $2)",
+				)).
+					WithArgs(id, testOwnerId).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				m.ExpectRollback()
+			},
+			expected: expected{
+				task: nil,
+				err:  customError.ErrVersionMismatch,
+			},
+		},
+		"NotFound": {
+			id: "3e440171-0921-4c88-a7ec-13f4cdab0d69",
+			mockSetup: func(m sqlmock.Sqlmock, id domain.TaskID, version int) {
+				m.ExpectBegin()
+				m.ExpectExec(regexp.QuoteMeta(testLockHierarchy)).WillReturnResult(sqlmock.NewResult(0, 0))
+				m.ExpectQuery(lockQuery).
+					WithArgs(id, version, testOwnerId).
+					WillReturnError(sql.ErrNoRows)
+				m.ExpectRollback()
+			},
+			expected: expected{
+				task: nil,
+				err:  customError.ErrNotFound,
+			},
+		},
+	}
+
+	for n, tt := range testTable {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+			require.NoError(t, err)
+			defer db.Close()
+
+			tt.mockSetup(mock, tt.id, tt.version)
+
+			repo := &TaskRepository{Db: db}
+			result, err := repo.Purge(testOwnerCtx, tt.id, tt.version, tt.mode)
+
+			if tt.expected.err != nil {
+				assert.Nil(t, result)
+				assert.Error(t, err)
+				assert.EqualError(t, err, tt.expected.err.Error())
+			} else {
+				assert.Equal(t, tt.expected.task, result)
+				assert.Nil(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPurgeDeletedBefore(t *testing.T) {
+	type expected struct {
+		purged int64
+		err    error
+	}
+
+	testTable := map[string]struct {
+		before    time.Time
+		mockSetup func(sqlmock.Sqlmock, time.Time)
+		expected  expected
+	}{
+		"Ok": {
+			before: testTime,
+			mockSetup: func(m sqlmock.Sqlmock, before time.Time) {
+				m.ExpectExec(regexp.QuoteMeta(
+					"DELETE FROM tasks WHERE deleted_at < $1",
+				)).
+					WithArgs(before).
+					WillReturnResult(sqlmock.NewResult(0, 3))
+			},
+			expected: expected{purged: 3, err: nil},
+		},
+		"InternalServerErr": {
+			before: testTime,
+			mockSetup: func(m sqlmock.Sqlmock, before time.Time) {
+				m.ExpectExec(regexp.QuoteMeta(
+					"DELETE FROM tasks WHERE deleted_at < $1",
+				)).
+					WithArgs(before).
+					WillReturnError(errors.New("pq: connection reset by peer"))
+			},
+			expected: expected{purged: 0, err: customError.ErrInternalServerError},
+		},
+	}
+
+	for n, tt := range testTable {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+			require.NoError(t, err)
+			defer db.Close()
+
+			tt.mockSetup(mock, tt.before)
+
+			repo := &TaskRepository{Db: db}
+			result, err := repo.PurgeDeletedBefore(testOwnerCtx, tt.before)
+
+			assert.Equal(t, tt.expected.purged, result)
+			if tt.expected.err != nil {
+				assert.EqualError(t, err, tt.expected.err.Error())
+			} else {
+				assert.Nil(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
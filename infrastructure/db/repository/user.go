@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+
+	"github.com/takumi616/go-restapi/domain"
+	"github.com/takumi616/go-restapi/infrastructure/db/repository/model"
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+// Columns read into model.UserResult, in the order scanUser expects
+const userColumns = "id, email, password_hash, role, created_at, updated_at"
+
+func scanUser(row rowScanner, result *model.UserResult) error {
+	return row.Scan(&result.Id, &result.Email, &result.PasswordHash, &result.Role, &result.CreatedAt, &result.UpdatedAt)
+}
+
+type UserRepository struct {
+	Db *sql.DB
+}
+
+func NewUserRepository(db *sql.DB) *UserRepository {
+	return &UserRepository{
+		Db: db,
+	}
+}
+
+func (r *UserRepository) Insert(ctx context.Context, user *domain.User) (*domain.User, error) {
+	var result model.UserResult
+	err := scanUser(r.Db.QueryRowContext(
+		ctx,
+		"INSERT INTO users(email, password_hash, role) VALUES($1, $2, $3) RETURNING "+userColumns,
+		user.Email, user.PasswordHash, user.Role,
+	), &result)
+
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		if constraintErr := userConstraintError(err); constraintErr != nil {
+			return nil, constraintErr
+		}
+		return nil, customError.ErrInternalServerError
+	}
+
+	return model.ToUserDomain(&result), nil
+}
+
+// Upsert records a user of an identity provider under the id given, or
+// updates the role of one recorded before
+func (r *UserRepository) Upsert(ctx context.Context, user *domain.User) (*domain.User, error) {
+	var result model.UserResult
+	err := scanUser(r.Db.QueryRowContext(
+		ctx,
+		`INSERT INTO users(id, role) VALUES($1, $2)
+		ON CONFLICT (id) DO UPDATE SET role=EXCLUDED.role, updated_at=now()
+		RETURNING `+userColumns,
+		user.Id, user.Role,
+	), &result)
+
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		return nil, customError.ErrInternalServerError
+	}
+
+	return model.ToUserDomain(&result), nil
+}
+
+func (r *UserRepository) SelectByEmail(ctx context.Context, email string) (*domain.User, error) {
+	return r.selectUser(ctx, "SELECT "+userColumns+" FROM users WHERE email = $1", email)
+}
+
+func (r *UserRepository) SelectById(ctx context.Context, id domain.UserID) (*domain.User, error) {
+	return r.selectUser(ctx, "SELECT "+userColumns+" FROM users WHERE id = $1", id)
+}
+
+func (r *UserRepository) selectUser(ctx context.Context, query string, arg any) (*domain.User, error) {
+	var result model.UserResult
+	err := scanUser(r.Db.QueryRowContext(ctx, query, arg), &result)
+
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customError.ErrNotFound
+		}
+		return nil, customError.ErrInternalServerError
+	}
+
+	return model.ToUserDomain(&result), nil
+}
+
+// InsertRefreshToken stores the first token of a new family
+func (r *UserRepository) InsertRefreshToken(ctx context.Context, token *domain.RefreshToken) error {
+	_, err := r.Db.ExecContext(
+		ctx,
+		"INSERT INTO refresh_tokens(user_id, token_hash, expires_at) VALUES($1, $2, $3)",
+		token.UserId, token.Hash, token.ExpiresAt,
+	)
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		return customError.ErrInternalServerError
+	}
+	return nil
+}
+
+// RotateRefreshToken uses up the token with the hash and stores next in its
+// place, for the same user and family. A token that was used up already is
+// taken as stolen, and its whole family is revoked before ErrRevoked is
+// returned. The row lock keeps two refreshes with one token from both passing
+func (r *UserRepository) RotateRefreshToken(ctx context.Context, hash string, next *domain.RefreshToken) (domain.UserID, error) {
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		return "", customError.ErrInternalServerError
+	}
+	defer tx.Rollback()
+
+	var id, userId, familyId string
+	var revoked, expired bool
+	err = tx.QueryRowContext(
+		ctx,
+		`SELECT id, user_id, family_id, revoked_at IS NOT NULL, expires_at <= now()
+		FROM refresh_tokens WHERE token_hash = $1 FOR UPDATE`,
+		hash,
+	).Scan(&id, &userId, &familyId, &revoked, &expired)
+
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", customError.ErrNotFound
+		}
+		return "", customError.ErrInternalServerError
+	}
+
+	if revoked {
+		if err := revokeRefreshTokenFamily(ctx, tx, familyId); err != nil {
+			return "", err
+		}
+		if err := tx.Commit(); err != nil {
+			slog.ErrorContext(ctx, err.Error())
+			return "", customError.ErrInternalServerError
+		}
+		return "", customError.ErrRevoked
+	}
+
+	if expired {
+		return "", customError.ErrExpired
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE refresh_tokens SET revoked_at=now() WHERE id=$1", id); err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		return "", customError.ErrInternalServerError
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		"INSERT INTO refresh_tokens(user_id, family_id, token_hash, expires_at) VALUES($1, $2, $3, $4)",
+		userId, familyId, next.Hash, next.ExpiresAt,
+	)
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		return "", customError.ErrInternalServerError
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		return "", customError.ErrInternalServerError
+	}
+
+	return domain.UserID(userId), nil
+}
+
+// RevokeRefreshTokens revokes the token with the hash along with its family
+func (r *UserRepository) RevokeRefreshTokens(ctx context.Context, hash string) error {
+	_, err := r.Db.ExecContext(
+		ctx,
+		`UPDATE refresh_tokens SET revoked_at=now()
+		WHERE family_id = (SELECT family_id FROM refresh_tokens WHERE token_hash = $1) AND revoked_at IS NULL`,
+		hash,
+	)
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		return customError.ErrInternalServerError
+	}
+	return nil
+}
+
+func revokeRefreshTokenFamily(ctx context.Context, q queryer, familyId string) error {
+	_, err := q.ExecContext(
+		ctx, "UPDATE refresh_tokens SET revoked_at=now() WHERE family_id=$1 AND revoked_at IS NULL", familyId,
+	)
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		return customError.ErrInternalServerError
+	}
+	return nil
+}
@@ -0,0 +1,300 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/takumi616/go-restapi/domain"
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+var (
+	selectRefreshTokenQuery = regexp.QuoteMeta(
+		`SELECT id, user_id, family_id, revoked_at IS NOT NULL, expires_at <= now()
+		FROM refresh_tokens WHERE token_hash = $1 FOR UPDATE`,
+	)
+	refreshTokenColumnNames = []string{"id", "user_id", "family_id", "revoked", "expired"}
+)
+
+func testUserRow(id, email string) *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "email", "password_hash", "role", "created_at", "updated_at"}).
+		AddRow(id, email, "$2a$10$abcdefghijklmnopqrstuv", "editor", testTime, testTime)
+}
+
+func TestInsertUser(t *testing.T) {
+	type expected struct {
+		user *domain.User
+		err  error
+	}
+
+	insertQuery := regexp.QuoteMeta(
+		"INSERT INTO users(email, password_hash, role) VALUES($1, $2, $3) RETURNING id, email, password_hash, role, created_at, updated_at",
+	)
+	input := &domain.User{Email: "alice@example.com", PasswordHash: "$2a$10$abcdefghijklmnopqrstuv", Role: domain.RoleEditor}
+
+	testTable := map[string]struct {
+		mockSetup func(sqlmock.Sqlmock)
+		expected  expected
+	}{
+		"Ok": {
+			mockSetup: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(insertQuery).
+					WithArgs("alice@example.com", "$2a$10$abcdefghijklmnopqrstuv", "editor").
+					WillReturnRows(testUserRow("7c9e6679-7425-40de-944b-e07fc1f90ae7", "alice@example.com"))
+			},
+			expected: expected{
+				user: &domain.User{
+					Id:           "7c9e6679-7425-40de-944b-e07fc1f90ae7",
+					Email:        "alice@example.com",
+					PasswordHash: "$2a$10$abcdefghijklmnopqrstuv",
+					Role:         domain.RoleEditor,
+					CreatedAt:    testTime,
+					UpdatedAt:    testTime,
+				},
+			},
+		},
+		"DuplicateEmail": {
+			mockSetup: func(m sqlmock.Sqlmock) {
+				m.ExpectQuery(insertQuery).
+					WithArgs("alice@example.com", "$2a$10$abcdefghijklmnopqrstuv", "editor").
+					WillReturnError(&pq.Error{
+						Code:       "23505",
+						Message:    "duplicate key value violates unique constraint \"users_email_key\"",
+						Constraint: "users_email_key",
+					})
+			},
+			expected: expected{
+				err: &customError.FieldError{Field: "email", Err: customError.ErrDuplicate},
+			},
+		},
+	}
+
+	for n, tt := range testTable {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+			require.NoError(t, err)
+			defer db.Close()
+
+			tt.mockSetup(mock)
+
+			repo := &UserRepository{Db: db}
+			result, err := repo.Insert(context.Background(), input)
+
+			if tt.expected.err != nil {
+				assert.Nil(t, result)
+				assert.EqualError(t, err, tt.expected.err.Error())
+			} else {
+				assert.Equal(t, tt.expected.user, result)
+				assert.Nil(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUpsertUser(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(
+		`INSERT INTO users(id, role) VALUES($1, $2)
+		ON CONFLICT (id) DO UPDATE SET role=EXCLUDED.role, updated_at=now()
+		RETURNING id, email, password_hash, role, created_at, updated_at`,
+	)).
+		WithArgs("105c07a2-65f4-5703-8187-c1d0f0a3d659", "admin").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "password_hash", "role", "created_at", "updated_at"}).
+			AddRow("105c07a2-65f4-5703-8187-c1d0f0a3d659", nil, nil, "admin", testTime, testTime))
+
+	repo := &UserRepository{Db: db}
+	result, err := repo.Upsert(context.Background(), &domain.User{Id: "105c07a2-65f4-5703-8187-c1d0f0a3d659", Role: domain.RoleAdmin})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &domain.User{
+		Id:        "105c07a2-65f4-5703-8187-c1d0f0a3d659",
+		Role:      domain.RoleAdmin,
+		CreatedAt: testTime,
+		UpdatedAt: testTime,
+	}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSelectUserByEmail(t *testing.T) {
+	selectQuery := regexp.QuoteMeta("SELECT id, email, password_hash, role, created_at, updated_at FROM users WHERE email = $1")
+
+	t.Run("Ok", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(selectQuery).
+			WithArgs("alice@example.com").
+			WillReturnRows(testUserRow("7c9e6679-7425-40de-944b-e07fc1f90ae7", "alice@example.com"))
+
+		repo := &UserRepository{Db: db}
+		result, err := repo.SelectByEmail(context.Background(), "alice@example.com")
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.UserID("7c9e6679-7425-40de-944b-e07fc1f90ae7"), result.Id)
+		assert.Equal(t, domain.RoleEditor, result.Role)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(selectQuery).
+			WithArgs("bob@example.com").
+			WillReturnError(sql.ErrNoRows)
+
+		repo := &UserRepository{Db: db}
+		result, err := repo.SelectByEmail(context.Background(), "bob@example.com")
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, customError.ErrNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRotateRefreshToken(t *testing.T) {
+	type expected struct {
+		userId domain.UserID
+		err    error
+	}
+
+	next := &domain.RefreshToken{
+		UserId:    "7c9e6679-7425-40de-944b-e07fc1f90ae7",
+		Hash:      "b5bb9d8014a0f9b1d61e21e796d78dccdf1352f23cd32812f4850b878ae4944c",
+		ExpiresAt: testTime.Add(30 * 24 * time.Hour),
+	}
+	const hash = "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"
+
+	testTable := map[string]struct {
+		mockSetup func(sqlmock.Sqlmock)
+		expected  expected
+	}{
+		"Ok": {
+			mockSetup: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(selectRefreshTokenQuery).
+					WithArgs(hash).
+					WillReturnRows(sqlmock.NewRows(refreshTokenColumnNames).AddRow(
+						"3f2b8c1e-9d4a-4e6b-8a7c-5d1e2f3a4b5c", "7c9e6679-7425-40de-944b-e07fc1f90ae7",
+						"a4b3c2d1-e5f6-4a7b-9c8d-0e1f2a3b4c5d", false, false,
+					))
+				m.ExpectExec(regexp.QuoteMeta("UPDATE refresh_tokens SET revoked_at=now() WHERE id=$1")).
+					WithArgs("3f2b8c1e-9d4a-4e6b-8a7c-5d1e2f3a4b5c").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				m.ExpectExec(regexp.QuoteMeta(
+					"INSERT INTO refresh_tokens(user_id, family_id, token_hash, expires_at) VALUES($1, $2, $3, $4)",
+				)).
+					WithArgs("7c9e6679-7425-40de-944b-e07fc1f90ae7", "a4b3c2d1-e5f6-4a7b-9c8d-0e1f2a3b4c5d", next.Hash, next.ExpiresAt).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				m.ExpectCommit()
+			},
+			expected: expected{
+				userId: "7c9e6679-7425-40de-944b-e07fc1f90ae7",
+			},
+		},
+		"ReusedRevokesFamily": {
+			mockSetup: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(selectRefreshTokenQuery).
+					WithArgs(hash).
+					WillReturnRows(sqlmock.NewRows(refreshTokenColumnNames).AddRow(
+						"3f2b8c1e-9d4a-4e6b-8a7c-5d1e2f3a4b5c", "7c9e6679-7425-40de-944b-e07fc1f90ae7",
+						"a4b3c2d1-e5f6-4a7b-9c8d-0e1f2a3b4c5d", true, false,
+					))
+				m.ExpectExec(regexp.QuoteMeta(
+					"UPDATE refresh_tokens SET revoked_at=now() WHERE family_id=$1 AND revoked_at IS NULL",
+				)).
+					WithArgs("a4b3c2d1-e5f6-4a7b-9c8d-0e1f2a3b4c5d").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				m.ExpectCommit()
+			},
+			expected: expected{
+				err: customError.ErrRevoked,
+			},
+		},
+		"Expired": {
+			mockSetup: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(selectRefreshTokenQuery).
+					WithArgs(hash).
+					WillReturnRows(sqlmock.NewRows(refreshTokenColumnNames).AddRow(
+						"3f2b8c1e-9d4a-4e6b-8a7c-5d1e2f3a4b5c", "7c9e6679-7425-40de-944b-e07fc1f90ae7",
+						"a4b3c2d1-e5f6-4a7b-9c8d-0e1f2a3b4c5d", false, true,
+					))
+				m.ExpectRollback()
+			},
+			expected: expected{
+				err: customError.ErrExpired,
+			},
+		},
+		"NotFound": {
+			mockSetup: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(selectRefreshTokenQuery).
+					WithArgs(hash).
+					WillReturnError(sql.ErrNoRows)
+				m.ExpectRollback()
+			},
+			expected: expected{
+				err: customError.ErrNotFound,
+			},
+		},
+	}
+
+	for n, tt := range testTable {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+			require.NoError(t, err)
+			defer db.Close()
+
+			tt.mockSetup(mock)
+
+			repo := &UserRepository{Db: db}
+			userId, err := repo.RotateRefreshToken(context.Background(), hash, next)
+
+			assert.Equal(t, tt.expected.userId, userId)
+			if tt.expected.err != nil {
+				assert.ErrorIs(t, err, tt.expected.err)
+			} else {
+				assert.Nil(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRevokeRefreshTokens(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE refresh_tokens SET revoked_at=now()
+		WHERE family_id = (SELECT family_id FROM refresh_tokens WHERE token_hash = $1) AND revoked_at IS NULL`,
+	)).
+		WithArgs("2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	repo := &UserRepository{Db: db}
+	err = repo.RevokeRefreshTokens(context.Background(), "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae")
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
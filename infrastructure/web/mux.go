@@ -3,27 +3,78 @@ package web
 import (
 	"net/http"
 
+	"github.com/takumi616/go-restapi/domain"
 	"github.com/takumi616/go-restapi/interface/handler"
 )
 
 type ServeMux struct {
-	TaskHandler *handler.TaskHandler
+	TaskHandler  *handler.TaskHandler
+	LabelHandler *handler.LabelHandler
+	AuthHandler  *handler.AuthHandler
 }
 
-func NewServeMux(handler *handler.TaskHandler) *ServeMux {
+func NewServeMux(taskHandler *handler.TaskHandler, labelHandler *handler.LabelHandler, authHandler *handler.AuthHandler) *ServeMux {
 	return &ServeMux{
-		TaskHandler: handler,
+		TaskHandler:  taskHandler,
+		LabelHandler: labelHandler,
+		AuthHandler:  authHandler,
 	}
 }
 
+// RegisterHandler routes requests to the handlers. Only the auth routes
+// are open to anonymous clients. Requests made with a personal access token
+// also need the scope of their route: tasks:read to read tasks and labels,
+// and tasks:write to change them
 func (s ServeMux) RegisterHandler() http.Handler {
+	read := func(next http.HandlerFunc) http.Handler {
+		return s.AuthHandler.RequireScope(domain.ScopeTasksRead, next)
+	}
+	write := func(next http.HandlerFunc) http.Handler {
+		return s.AuthHandler.RequireScope(domain.ScopeTasksWrite, next)
+	}
+
 	mux := http.NewServeMux()
 
-	mux.HandleFunc("POST /tasks", s.TaskHandler.AddTask)
-	mux.HandleFunc("GET /tasks", s.TaskHandler.GetTaskList)
-	mux.HandleFunc("GET /tasks/{id}", s.TaskHandler.GetTaskById)
-	mux.HandleFunc("PATCH /tasks/{id}", s.TaskHandler.UpdateTask)
-	mux.HandleFunc("DELETE /tasks/{id}", s.TaskHandler.DeleteTask)
+	mux.Handle("POST /tasks", write(s.TaskHandler.AddTask))
+	mux.Handle("GET /tasks", read(s.TaskHandler.GetTaskList))
+	mux.Handle("POST /tasks:batchCreate", write(s.TaskHandler.AddTasks))
+	mux.Handle("PATCH /tasks:batchUpdate", write(s.TaskHandler.UpdateTasks))
+	mux.Handle("POST /tasks:batchDelete", write(s.TaskHandler.DeleteTasks))
+	mux.Handle("GET /tasks/trash", read(s.TaskHandler.GetTrash))
+	mux.Handle("GET /tasks/overdue", read(s.TaskHandler.GetOverdueTasks))
+	mux.Handle("GET /tasks/schedule", read(s.TaskHandler.GetTaskSchedule))
+	mux.Handle("GET /tasks/{id}", read(s.TaskHandler.GetTaskById))
+	mux.Handle("PATCH /tasks/{id}", write(s.TaskHandler.UpdateTask))
+	mux.Handle("DELETE /tasks/{id}", write(s.TaskHandler.DeleteTask))
+	mux.Handle("GET /tasks/{id}/subtasks", read(s.TaskHandler.GetSubtasks))
+	mux.Handle("POST /tasks/{id}/restore", write(s.TaskHandler.RestoreTask))
+	mux.Handle("POST /tasks/{id}/transitions", write(s.TaskHandler.TransitionTask))
+	mux.Handle("PUT /tasks/{id}/labels", write(s.TaskHandler.SetTaskLabels))
+	mux.Handle("POST /tasks/{id}/dependencies", write(s.TaskHandler.AddTaskDependency))
+	mux.Handle("DELETE /tasks/{id}/dependencies/{blocker_id}", write(s.TaskHandler.RemoveTaskDependency))
+	mux.Handle("GET /tasks/{id}/checklist", read(s.TaskHandler.GetChecklist))
+	mux.Handle("POST /tasks/{id}/checklist", write(s.TaskHandler.AddChecklistItem))
+	mux.Handle("PUT /tasks/{id}/checklist/order", write(s.TaskHandler.ReorderChecklist))
+	mux.Handle("PATCH /tasks/{id}/checklist/{item_id}", write(s.TaskHandler.UpdateChecklistItem))
+	mux.Handle("DELETE /tasks/{id}/checklist/{item_id}", write(s.TaskHandler.RemoveChecklistItem))
+
+	mux.Handle("POST /labels", write(s.LabelHandler.AddLabel))
+	mux.Handle("GET /labels", read(s.LabelHandler.GetLabels))
+	mux.Handle("GET /labels/{id}", read(s.LabelHandler.GetLabelById))
+	mux.Handle("PATCH /labels/{id}", write(s.LabelHandler.UpdateLabel))
+	mux.Handle("DELETE /labels/{id}", write(s.LabelHandler.DeleteLabel))
+
+	// Tokens are managed by their user in person, never with another token
+	mux.HandleFunc("POST /me/tokens", s.AuthHandler.CreatePersonalAccessToken)
+	mux.HandleFunc("GET /me/tokens", s.AuthHandler.GetPersonalAccessTokens)
+	mux.HandleFunc("DELETE /me/tokens/{id}", s.AuthHandler.RevokePersonalAccessToken)
+
+	root := http.NewServeMux()
+	root.HandleFunc("POST /auth/register", s.AuthHandler.Register)
+	root.HandleFunc("POST /auth/login", s.AuthHandler.Login)
+	root.HandleFunc("POST /auth/refresh", s.AuthHandler.Refresh)
+	root.HandleFunc("POST /auth/logout", s.AuthHandler.Logout)
+	root.Handle("/", s.AuthHandler.RequireAuth(mux))
 
-	return mux
+	return root
 }
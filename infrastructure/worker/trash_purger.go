@@ -0,0 +1,47 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/takumi616/go-restapi/shared/config"
+)
+
+// TrashPurger periodically removes tasks that outlived the trash retention
+type TrashPurger struct {
+	usecase       TrashUsecase
+	retention     time.Duration
+	purgeInterval time.Duration
+}
+
+func NewTrashPurger(trashConf *config.TrashConfig, usecase TrashUsecase) *TrashPurger {
+	return &TrashPurger{
+		usecase:       usecase,
+		retention:     trashConf.Retention,
+		purgeInterval: trashConf.PurgeInterval,
+	}
+}
+
+// Run blocks until ctx is canceled
+func (p *TrashPurger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.purgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := p.usecase.PurgeExpiredTrash(ctx, p.retention)
+			if err != nil {
+				slog.ErrorContext(ctx, err.Error())
+				continue
+			}
+
+			if purged > 0 {
+				slog.InfoContext(ctx, "purged expired tasks from trash", slog.Int64("count", purged))
+			}
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package worker
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/takumi616/go-restapi/shared/config"
+)
+
+type stubTrashUsecase struct {
+	calls     atomic.Int32
+	retention atomic.Int64
+}
+
+func (s *stubTrashUsecase) PurgeExpiredTrash(ctx context.Context, retention time.Duration) (int64, error) {
+	s.calls.Add(1)
+	s.retention.Store(int64(retention))
+	return 1, nil
+}
+
+func TestRunPurgesPeriodically(t *testing.T) {
+	usecase := &stubTrashUsecase{}
+	trashConf := &config.TrashConfig{
+		Retention:     24 * time.Hour,
+		PurgeInterval: 20 * time.Millisecond,
+	}
+
+	purger := NewTrashPurger(trashConf, usecase)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		purger.Run(ctx)
+	}()
+
+	time.Sleep(110 * time.Millisecond)
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("purger did not stop after context cancellation")
+	}
+
+	assert.GreaterOrEqual(t, usecase.calls.Load(), int32(2))
+	assert.Equal(t, int64(24*time.Hour), usecase.retention.Load())
+}
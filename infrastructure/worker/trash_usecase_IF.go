@@ -0,0 +1,10 @@
+package worker
+
+import (
+	"context"
+	"time"
+)
+
+type TrashUsecase interface {
+	PurgeExpiredTrash(ctx context.Context, retention time.Duration) (int64, error)
+}
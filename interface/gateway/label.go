@@ -0,0 +1,35 @@
+package gateway
+
+import (
+	"context"
+
+	"github.com/takumi616/go-restapi/domain"
+)
+
+type LabelGateway struct {
+	repository LabelRepository
+}
+
+func NewLabelGateway(repository LabelRepository) *LabelGateway {
+	return &LabelGateway{repository: repository}
+}
+
+func (g *LabelGateway) AddLabel(ctx context.Context, label *domain.Label) (*domain.Label, error) {
+	return g.repository.Insert(ctx, label)
+}
+
+func (g *LabelGateway) GetLabels(ctx context.Context) ([]*domain.Label, error) {
+	return g.repository.SelectAll(ctx)
+}
+
+func (g *LabelGateway) GetLabelById(ctx context.Context, id domain.LabelID) (*domain.Label, error) {
+	return g.repository.SelectById(ctx, id)
+}
+
+func (g *LabelGateway) UpdateLabel(ctx context.Context, id domain.LabelID, patch *domain.LabelPatch) (*domain.Label, error) {
+	return g.repository.Update(ctx, id, patch)
+}
+
+func (g *LabelGateway) DeleteLabel(ctx context.Context, id domain.LabelID) (*domain.Label, error) {
+	return g.repository.Delete(ctx, id)
+}
@@ -0,0 +1,15 @@
+package gateway
+
+import (
+	"context"
+
+	"github.com/takumi616/go-restapi/domain"
+)
+
+type LabelRepository interface {
+	Insert(ctx context.Context, label *domain.Label) (*domain.Label, error)
+	SelectAll(ctx context.Context) ([]*domain.Label, error)
+	SelectById(ctx context.Context, id domain.LabelID) (*domain.Label, error)
+	Update(ctx context.Context, id domain.LabelID, patch *domain.LabelPatch) (*domain.Label, error)
+	Delete(ctx context.Context, id domain.LabelID) (*domain.Label, error)
+}
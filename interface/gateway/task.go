@@ -2,6 +2,7 @@ package gateway
 
 import (
 	"context"
+	"time"
 
 	"github.com/takumi616/go-restapi/domain"
 )
@@ -18,18 +19,94 @@ func (g *TaskGateway) AddTask(ctx context.Context, task *domain.Task) (*domain.T
 	return g.repository.Insert(ctx, task)
 }
 
-func (g *TaskGateway) GetTaskList(ctx context.Context) ([]*domain.Task, error) {
-	return g.repository.SelectAll(ctx)
+func (g *TaskGateway) GetTaskList(ctx context.Context, query *domain.TaskListQuery) (*domain.TaskList, error) {
+	return g.repository.SelectAll(ctx, query)
 }
 
-func (g *TaskGateway) GetTaskById(ctx context.Context, id string) (*domain.Task, error) {
+func (g *TaskGateway) GetTaskListAfter(ctx context.Context, query *domain.TaskListQuery) ([]*domain.Task, error) {
+	return g.repository.SelectAfter(ctx, query)
+}
+
+func (g *TaskGateway) GetTaskById(ctx context.Context, id domain.TaskID) (*domain.Task, error) {
 	return g.repository.SelectById(ctx, id)
 }
 
-func (g *TaskGateway) UpdateTask(ctx context.Context, id string, task *domain.Task) (*domain.Task, error) {
-	return g.repository.Update(ctx, id, task)
+func (g *TaskGateway) GetTaskTree(ctx context.Context, id domain.TaskID) (*domain.Task, error) {
+	return g.repository.SelectTree(ctx, id)
+}
+
+func (g *TaskGateway) UpdateTask(ctx context.Context, id domain.TaskID, patch *domain.TaskPatch) (*domain.Task, error) {
+	return g.repository.Update(ctx, id, patch)
+}
+
+func (g *TaskGateway) SetTaskLabels(ctx context.Context, id domain.TaskID, labelIds []domain.LabelID, version int) (*domain.Task, error) {
+	return g.repository.SetLabels(ctx, id, labelIds, version)
+}
+
+func (g *TaskGateway) GetOpenTasks(ctx context.Context) ([]*domain.Task, error) {
+	return g.repository.SelectOpen(ctx)
+}
+
+func (g *TaskGateway) GetTaskDependencies(ctx context.Context) ([]*domain.TaskDependency, error) {
+	return g.repository.SelectDependencies(ctx)
+}
+
+func (g *TaskGateway) AddTaskDependency(ctx context.Context, dependency *domain.TaskDependency) (*domain.Task, error) {
+	return g.repository.InsertDependency(ctx, dependency)
+}
+
+func (g *TaskGateway) RemoveTaskDependency(ctx context.Context, dependency *domain.TaskDependency) (*domain.Task, error) {
+	return g.repository.DeleteDependency(ctx, dependency)
+}
+
+func (g *TaskGateway) GetChecklist(ctx context.Context, taskId domain.TaskID) (*domain.Checklist, error) {
+	return g.repository.SelectChecklist(ctx, taskId)
+}
+
+func (g *TaskGateway) AddChecklistItem(ctx context.Context, taskId domain.TaskID, text string, position *int, version int) (*domain.Checklist, error) {
+	return g.repository.InsertChecklistItem(ctx, taskId, text, position, version)
+}
+
+func (g *TaskGateway) UpdateChecklistItem(ctx context.Context, taskId domain.TaskID, itemId domain.ChecklistItemID, patch *domain.ChecklistItemPatch, version int) (*domain.Checklist, error) {
+	return g.repository.UpdateChecklistItem(ctx, taskId, itemId, patch, version)
+}
+
+func (g *TaskGateway) RemoveChecklistItem(ctx context.Context, taskId domain.TaskID, itemId domain.ChecklistItemID, version int) (*domain.Checklist, error) {
+	return g.repository.DeleteChecklistItem(ctx, taskId, itemId, version)
+}
+
+func (g *TaskGateway) ReorderChecklist(ctx context.Context, taskId domain.TaskID, itemIds []domain.ChecklistItemID, version int) (*domain.Checklist, error) {
+	return g.repository.ReorderChecklist(ctx, taskId, itemIds, version)
+}
+
+func (g *TaskGateway) DeleteTask(ctx context.Context, id domain.TaskID, version int, mode domain.SubtaskMode) (*domain.Task, error) {
+	return g.repository.Delete(ctx, id, version, mode)
+}
+
+func (g *TaskGateway) GetTrash(ctx context.Context) ([]*domain.Task, error) {
+	return g.repository.SelectTrash(ctx)
+}
+
+func (g *TaskGateway) RestoreTask(ctx context.Context, id domain.TaskID) (*domain.Task, error) {
+	return g.repository.Restore(ctx, id)
+}
+
+func (g *TaskGateway) PurgeTask(ctx context.Context, id domain.TaskID, version int, mode domain.SubtaskMode) (*domain.Task, error) {
+	return g.repository.Purge(ctx, id, version, mode)
+}
+
+func (g *TaskGateway) PurgeTrash(ctx context.Context, before time.Time) (int64, error) {
+	return g.repository.PurgeDeletedBefore(ctx, before)
+}
+
+func (g *TaskGateway) AddTasks(ctx context.Context, tasks []*domain.Task, atomic bool) ([]*domain.TaskBatchResult, error) {
+	return g.repository.InsertBatch(ctx, tasks, atomic)
+}
+
+func (g *TaskGateway) UpdateTasks(ctx context.Context, updates []*domain.TaskBatchUpdate, atomic bool) ([]*domain.TaskBatchResult, error) {
+	return g.repository.UpdateBatch(ctx, updates, atomic)
 }
 
-func (g *TaskGateway) DeleteTask(ctx context.Context, id string) (*domain.Task, error) {
-	return g.repository.Delete(ctx, id)
+func (g *TaskGateway) DeleteTasks(ctx context.Context, deletes []*domain.TaskBatchDelete, atomic bool) ([]*domain.TaskBatchResult, error) {
+	return g.repository.DeleteBatch(ctx, deletes, atomic)
 }
@@ -2,14 +2,34 @@ package gateway
 
 import (
 	"context"
+	"time"
 
 	"github.com/takumi616/go-restapi/domain"
 )
 
 type TaskRepository interface {
 	Insert(ctx context.Context, task *domain.Task) (*domain.Task, error)
-	SelectAll(ctx context.Context) ([]*domain.Task, error)
-	SelectById(ctx context.Context, id string) (*domain.Task, error)
-	Update(ctx context.Context, id string, task *domain.Task) (*domain.Task, error)
-	Delete(ctx context.Context, id string) (*domain.Task, error)
+	SelectAll(ctx context.Context, query *domain.TaskListQuery) (*domain.TaskList, error)
+	SelectAfter(ctx context.Context, query *domain.TaskListQuery) ([]*domain.Task, error)
+	SelectById(ctx context.Context, id domain.TaskID) (*domain.Task, error)
+	SelectTree(ctx context.Context, id domain.TaskID) (*domain.Task, error)
+	Update(ctx context.Context, id domain.TaskID, patch *domain.TaskPatch) (*domain.Task, error)
+	SetLabels(ctx context.Context, id domain.TaskID, labelIds []domain.LabelID, version int) (*domain.Task, error)
+	SelectOpen(ctx context.Context) ([]*domain.Task, error)
+	SelectDependencies(ctx context.Context) ([]*domain.TaskDependency, error)
+	InsertDependency(ctx context.Context, dependency *domain.TaskDependency) (*domain.Task, error)
+	DeleteDependency(ctx context.Context, dependency *domain.TaskDependency) (*domain.Task, error)
+	SelectChecklist(ctx context.Context, taskId domain.TaskID) (*domain.Checklist, error)
+	InsertChecklistItem(ctx context.Context, taskId domain.TaskID, text string, position *int, version int) (*domain.Checklist, error)
+	UpdateChecklistItem(ctx context.Context, taskId domain.TaskID, itemId domain.ChecklistItemID, patch *domain.ChecklistItemPatch, version int) (*domain.Checklist, error)
+	DeleteChecklistItem(ctx context.Context, taskId domain.TaskID, itemId domain.ChecklistItemID, version int) (*domain.Checklist, error)
+	ReorderChecklist(ctx context.Context, taskId domain.TaskID, itemIds []domain.ChecklistItemID, version int) (*domain.Checklist, error)
+	Delete(ctx context.Context, id domain.TaskID, version int, mode domain.SubtaskMode) (*domain.Task, error)
+	SelectTrash(ctx context.Context) ([]*domain.Task, error)
+	Restore(ctx context.Context, id domain.TaskID) (*domain.Task, error)
+	Purge(ctx context.Context, id domain.TaskID, version int, mode domain.SubtaskMode) (*domain.Task, error)
+	PurgeDeletedBefore(ctx context.Context, before time.Time) (int64, error)
+	InsertBatch(ctx context.Context, tasks []*domain.Task, atomic bool) ([]*domain.TaskBatchResult, error)
+	UpdateBatch(ctx context.Context, updates []*domain.TaskBatchUpdate, atomic bool) ([]*domain.TaskBatchResult, error)
+	DeleteBatch(ctx context.Context, deletes []*domain.TaskBatchDelete, atomic bool) ([]*domain.TaskBatchResult, error)
 }
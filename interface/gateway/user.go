@@ -0,0 +1,59 @@
+package gateway
+
+import (
+	"context"
+
+	"github.com/takumi616/go-restapi/domain"
+)
+
+type UserGateway struct {
+	repository UserRepository
+}
+
+func NewUserGateway(repository UserRepository) *UserGateway {
+	return &UserGateway{repository: repository}
+}
+
+func (g *UserGateway) AddUser(ctx context.Context, user *domain.User) (*domain.User, error) {
+	return g.repository.Insert(ctx, user)
+}
+
+func (g *UserGateway) SaveUser(ctx context.Context, user *domain.User) (*domain.User, error) {
+	return g.repository.Upsert(ctx, user)
+}
+
+func (g *UserGateway) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
+	return g.repository.SelectByEmail(ctx, email)
+}
+
+func (g *UserGateway) GetUserById(ctx context.Context, id domain.UserID) (*domain.User, error) {
+	return g.repository.SelectById(ctx, id)
+}
+
+func (g *UserGateway) AddRefreshToken(ctx context.Context, token *domain.RefreshToken) error {
+	return g.repository.InsertRefreshToken(ctx, token)
+}
+
+func (g *UserGateway) RotateRefreshToken(ctx context.Context, hash string, next *domain.RefreshToken) (domain.UserID, error) {
+	return g.repository.RotateRefreshToken(ctx, hash, next)
+}
+
+func (g *UserGateway) RevokeRefreshTokens(ctx context.Context, hash string) error {
+	return g.repository.RevokeRefreshTokens(ctx, hash)
+}
+
+func (g *UserGateway) AddPersonalAccessToken(ctx context.Context, token *domain.PersonalAccessToken) (*domain.PersonalAccessToken, error) {
+	return g.repository.InsertPersonalAccessToken(ctx, token)
+}
+
+func (g *UserGateway) GetPersonalAccessTokens(ctx context.Context, userId domain.UserID) ([]*domain.PersonalAccessToken, error) {
+	return g.repository.SelectPersonalAccessTokens(ctx, userId)
+}
+
+func (g *UserGateway) UsePersonalAccessToken(ctx context.Context, hash string) (*domain.PersonalAccessToken, error) {
+	return g.repository.UsePersonalAccessToken(ctx, hash)
+}
+
+func (g *UserGateway) RevokePersonalAccessToken(ctx context.Context, userId domain.UserID, id domain.PersonalAccessTokenID) error {
+	return g.repository.RevokePersonalAccessToken(ctx, userId, id)
+}
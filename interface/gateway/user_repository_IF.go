@@ -0,0 +1,21 @@
+package gateway
+
+import (
+	"context"
+
+	"github.com/takumi616/go-restapi/domain"
+)
+
+type UserRepository interface {
+	Insert(ctx context.Context, user *domain.User) (*domain.User, error)
+	Upsert(ctx context.Context, user *domain.User) (*domain.User, error)
+	SelectByEmail(ctx context.Context, email string) (*domain.User, error)
+	SelectById(ctx context.Context, id domain.UserID) (*domain.User, error)
+	InsertRefreshToken(ctx context.Context, token *domain.RefreshToken) error
+	RotateRefreshToken(ctx context.Context, hash string, next *domain.RefreshToken) (domain.UserID, error)
+	RevokeRefreshTokens(ctx context.Context, hash string) error
+	InsertPersonalAccessToken(ctx context.Context, token *domain.PersonalAccessToken) (*domain.PersonalAccessToken, error)
+	SelectPersonalAccessTokens(ctx context.Context, userId domain.UserID) ([]*domain.PersonalAccessToken, error)
+	UsePersonalAccessToken(ctx context.Context, hash string) (*domain.PersonalAccessToken, error)
+	RevokePersonalAccessToken(ctx context.Context, userId domain.UserID, id domain.PersonalAccessTokenID) error
+}
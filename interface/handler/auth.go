@@ -0,0 +1,249 @@
+package handler
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/takumi616/go-restapi/domain"
+	"github.com/takumi616/go-restapi/interface/handler/helper"
+	"github.com/takumi616/go-restapi/interface/handler/request"
+	"github.com/takumi616/go-restapi/interface/handler/response"
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+// Headers the gateway in front of the API names the caller and their role in
+const (
+	userIdHeader   = "X-User-ID"
+	userRoleHeader = "X-User-Role"
+)
+
+type AuthHandler struct {
+	usecase AuthUsecase
+	// Whether to take the caller from userIdHeader. Only safe when every
+	// request comes through a gateway that sets it and strips it from clients
+	trustUserIdHeader bool
+}
+
+func NewAuthHandler(usecase AuthUsecase, trustUserIdHeader bool) *AuthHandler {
+	return &AuthHandler{
+		usecase:           usecase,
+		trustUserIdHeader: trustUserIdHeader,
+	}
+}
+
+// Register signs up a user with an email and password
+func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req request.RegisterReq
+	if err := helper.DecodeJSON(w, r, &req); err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	if err := helper.Validate(req, customError.AuthBadRequest); err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	user, err := h.usecase.Register(ctx, req.Email, req.Password)
+	if err != nil {
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	helper.WriteResponse(ctx, w, http.StatusCreated, response.ToUserRes(user))
+}
+
+// Login starts a session, returning an access token along with
+// the refresh token that renews it
+func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req request.LoginReq
+	if err := helper.DecodeJSON(w, r, &req); err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	if err := helper.Validate(req, customError.AuthBadRequest); err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	session, err := h.usecase.Login(ctx, req.Email, req.Password)
+	if err != nil {
+		writeAuthProblem(w, r, err)
+		return
+	}
+
+	writeSession(w, r, session)
+}
+
+// Refresh trades a refresh token for a new session. The token given
+// cannot be used again
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req request.RefreshTokenReq
+	if err := helper.DecodeJSON(w, r, &req); err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	if err := helper.Validate(req, customError.AuthBadRequest); err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	session, err := h.usecase.Refresh(ctx, req.RefreshToken)
+	if err != nil {
+		writeAuthProblem(w, r, err)
+		return
+	}
+
+	writeSession(w, r, session)
+}
+
+// Logout ends the session a refresh token belongs to
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req request.RefreshTokenReq
+	if err := helper.DecodeJSON(w, r, &req); err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	if err := helper.Validate(req, customError.AuthBadRequest); err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	if err := h.usecase.Logout(ctx, req.RefreshToken); err != nil {
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RequireAuth lets a request through only with a valid bearer access token
+// or personal access token, and records who it was issued to in the request
+// context as the actor.
+// Behind a trusted gateway, a caller named in the X-User-ID header is taken
+// as the actor instead, in the role given by X-User-Role or the default one
+func (h *AuthHandler) RequireAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		if header := r.Header.Get(userIdHeader); h.trustUserIdHeader && header != "" {
+			actor, err := actorFromHeaders(r.Header)
+			if err != nil {
+				slog.ErrorContext(ctx, err.Error())
+				writeAuthProblem(w, r, customError.ErrUnauthenticated)
+				return
+			}
+
+			setPrivate(w, userIdHeader+", "+userRoleHeader)
+			next.ServeHTTP(w, r.WithContext(domain.ContextWithActor(ctx, actor)))
+			return
+		}
+
+		scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+			writeAuthProblem(w, r, customError.ErrUnauthenticated)
+			return
+		}
+
+		actor, err := h.usecase.Authenticate(ctx, token)
+		if err != nil {
+			slog.ErrorContext(ctx, err.Error())
+			writeAuthProblem(w, r, err)
+			return
+		}
+
+		setPrivate(w, "Authorization")
+		next.ServeHTTP(w, r.WithContext(domain.ContextWithActor(ctx, actor)))
+	})
+}
+
+// What a caller is given depends on who they are, so shared caches must not
+// keep it for others. Private caches still may, keyed on the headers that
+// name the caller. Handlers that must not be cached at all override it
+func setPrivate(w http.ResponseWriter, vary string) {
+	w.Header().Set("Cache-Control", "private")
+	w.Header().Add("Vary", vary)
+}
+
+// RequireScope lets a request through only if its actor may make requests
+// that need scope, which only limits personal access tokens. It has to be
+// behind RequireAuth
+func (h *AuthHandler) RequireScope(scope domain.TokenScope, next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		actor, ok := domain.ActorFromContext(ctx)
+		if !ok {
+			writeAuthProblem(w, r, customError.ErrUnauthenticated)
+			return
+		}
+
+		if !actor.HasScope(scope) {
+			slog.WarnContext(
+				ctx, "token scope insufficient",
+				slog.String("user_id", actor.UserId.String()),
+				slog.String("scope", string(scope)),
+			)
+			// As RFC 6750 has it, so that clients know which scope to ask for
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="go-restapi", error="insufficient_scope", scope="%s"`, scope))
+			helper.WriteProblem(ctx, w, r, fmt.Errorf("%w: %s is required", customError.ErrInsufficientScope, scope))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// The actor a trusted gateway names in the request headers
+func actorFromHeaders(header http.Header) (*domain.Actor, error) {
+	userId, err := domain.ParseUserID(header.Get(userIdHeader))
+	if err != nil {
+		return nil, err
+	}
+
+	role := domain.DefaultRole
+	if value := header.Get(userRoleHeader); value != "" {
+		if role, err = domain.ParseRole(value); err != nil {
+			return nil, err
+		}
+	}
+
+	return &domain.Actor{UserId: userId, Role: role}, nil
+}
+
+// Tokens must not be kept by caches along the way
+func writeSession(w http.ResponseWriter, r *http.Request, session *domain.Session) {
+	w.Header().Set("Cache-Control", "no-store")
+	helper.WriteResponse(r.Context(), w, http.StatusOK, response.ToSessionRes(session))
+}
+
+// Problems that come down to missing or bad credentials tell the
+// client how to authenticate, as 401 responses have to
+func writeAuthProblem(w http.ResponseWriter, r *http.Request, err error) {
+	problem := helper.NewProblem(r, err)
+	if problem.Status == http.StatusUnauthorized {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="go-restapi"`)
+	}
+	helper.WriteProblem(r.Context(), w, r, err)
+}
@@ -0,0 +1,19 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/takumi616/go-restapi/domain"
+)
+
+type AuthUsecase interface {
+	Register(ctx context.Context, email string, password string) (*domain.User, error)
+	Login(ctx context.Context, email string, password string) (*domain.Session, error)
+	Refresh(ctx context.Context, refreshToken string) (*domain.Session, error)
+	Logout(ctx context.Context, refreshToken string) error
+	Authenticate(ctx context.Context, token string) (*domain.Actor, error)
+	CreatePersonalAccessToken(ctx context.Context, name string, scopes []domain.TokenScope, expiresIn time.Duration) (*domain.PersonalAccessToken, string, error)
+	GetPersonalAccessTokens(ctx context.Context) ([]*domain.PersonalAccessToken, error)
+	RevokePersonalAccessToken(ctx context.Context, id domain.PersonalAccessTokenID) error
+}
@@ -0,0 +1,81 @@
+package cursor
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/takumi616/go-restapi/domain"
+)
+
+var (
+	ErrInvalidCursor = errors.New("cursor is invalid")
+	ErrSortMismatch  = errors.New("cursor was issued for a different sort order")
+)
+
+type payload struct {
+	SortField string `json:"f"`
+	SortDesc  bool   `json:"d"`
+	SortValue string `json:"v"`
+	Id        string `json:"i"`
+}
+
+// Codec turns task cursors into opaque tokens signed with HMAC-SHA256,
+// so that clients can neither read nor forge a position in the list
+type Codec struct {
+	secret []byte
+}
+
+func NewCodec(secret string) *Codec {
+	return &Codec{secret: []byte(secret)}
+}
+
+func (c *Codec) Encode(cursor *domain.TaskCursor) string {
+	body, _ := json.Marshal(payload{
+		SortField: string(cursor.SortField),
+		SortDesc:  cursor.SortDesc,
+		SortValue: cursor.SortValue,
+		Id:        cursor.Id,
+	})
+
+	encoded := base64.RawURLEncoding.EncodeToString(body)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(c.sign(encoded))
+}
+
+func (c *Codec) Decode(token string) (*domain.TaskCursor, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(sig, c.sign(encoded)) {
+		return nil, ErrInvalidCursor
+	}
+
+	body, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var p payload
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &domain.TaskCursor{
+		SortField: domain.TaskSortField(p.SortField),
+		SortDesc:  p.SortDesc,
+		SortValue: p.SortValue,
+		Id:        p.Id,
+	}, nil
+}
+
+func (c *Codec) sign(encoded string) []byte {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write([]byte(encoded))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,84 @@
+package helper
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Validators identify the state of a representation for conditional requests
+type Validators struct {
+	ETag         string
+	LastModified time.Time
+}
+
+func TaskValidators(version int, updatedAt time.Time) Validators {
+	return Validators{ETag: taskETag(version), LastModified: updatedAt}
+}
+
+// A list changes whenever a row is added, removed or updated,
+// which always moves either the row count or the latest update time
+func TaskListValidators(total int, lastModified time.Time) Validators {
+	return Validators{
+		ETag:         fmt.Sprintf(`W/"%d-%d"`, total, lastModified.UnixNano()),
+		LastModified: lastModified,
+	}
+}
+
+// WriteCacheableResponse emits the validators and answers 304 Not Modified
+// instead of the body when the client's cached copy is still current
+func WriteCacheableResponse(ctx context.Context, w http.ResponseWriter, r *http.Request, status int, body any, v Validators) {
+	if v.ETag != "" {
+		w.Header().Set("ETag", v.ETag)
+	}
+	if !v.LastModified.IsZero() {
+		w.Header().Set("Last-Modified", v.LastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if notModified(r, v) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	WriteResponse(ctx, w, status, body)
+}
+
+func notModified(r *http.Request, v Validators) bool {
+	// If-None-Match takes precedence over If-Modified-Since when both are sent
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		if v.ETag == "" {
+			return false
+		}
+
+		for _, etag := range strings.Split(ifNoneMatch, ",") {
+			etag = strings.TrimSpace(etag)
+			if etag == "*" || weakETag(etag) == weakETag(v.ETag) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ifModifiedSince := r.Header.Get("If-Modified-Since"); ifModifiedSince != "" {
+		if v.LastModified.IsZero() {
+			return false
+		}
+
+		since, err := http.ParseTime(ifModifiedSince)
+		if err != nil {
+			return false
+		}
+
+		// HTTP dates only have second precision
+		return !v.LastModified.Truncate(time.Second).After(since)
+	}
+
+	return false
+}
+
+// If-None-Match uses the weak comparison, which ignores the W/ prefix
+func weakETag(etag string) string {
+	return strings.TrimPrefix(etag, "W/")
+}
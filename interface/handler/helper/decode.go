@@ -0,0 +1,77 @@
+package helper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+// Largest request body a handler reads, in bytes
+const MaxRequestBodySize = 1 << 20
+
+// DecodeJSON strictly decodes a JSON request body into dst. The body must be
+// sent as application/json, fit within MaxRequestBodySize, hold exactly one
+// JSON value and only use fields dst knows about
+func DecodeJSON(w http.ResponseWriter, r *http.Request, dst any) error {
+	if mediaType := MediaType(r); mediaType != "application/json" {
+		return fmt.Errorf("%w: expected application/json but got '%s'", customError.UnsupportedMediaType, mediaType)
+	}
+
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, MaxRequestBodySize))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(dst); err != nil {
+		return decodeError(err)
+	}
+
+	// Anything after the first value, even another value, is malformed
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return decodeError(err)
+		}
+		return fmt.Errorf("%w: request body must contain a single JSON value", customError.InvalidRequestFormat)
+	}
+
+	return nil
+}
+
+// ReadBody reads a request body of at most MaxRequestBodySize bytes
+func ReadBody(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxRequestBodySize))
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	return body, nil
+}
+
+// Explain why a body could not be decoded, precisely enough for a client to fix it
+func decodeError(err error) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var maxBytesErr *http.MaxBytesError
+
+	switch {
+	case errors.As(err, &syntaxErr):
+		return fmt.Errorf("%w: malformed JSON at byte offset %d", customError.InvalidRequestFormat, syntaxErr.Offset)
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return fmt.Errorf("%w: request body ends in the middle of a JSON value", customError.InvalidRequestFormat)
+	case errors.Is(err, io.EOF):
+		return fmt.Errorf("%w: request body is empty", customError.InvalidRequestFormat)
+	case errors.As(err, &typeErr):
+		return fmt.Errorf("%w: field '%s' must be of type %s (at byte offset %d)", customError.InvalidRequestFormat, typeErr.Field, typeErr.Type, typeErr.Offset)
+	case errors.As(err, &maxBytesErr):
+		return fmt.Errorf("%w: request body must not exceed %d bytes", customError.RequestBodyTooLarge, maxBytesErr.Limit)
+	default:
+		// The decoder reports unknown fields only as text
+		if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+			return fmt.Errorf("%w: unknown field '%s'", customError.InvalidRequestFormat, strings.Trim(field, `"`))
+		}
+		return fmt.Errorf("%w: %w", customError.InvalidRequestFormat, err)
+	}
+}
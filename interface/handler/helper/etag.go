@@ -0,0 +1,44 @@
+package helper
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+func SetETag(w http.ResponseWriter, version int) {
+	w.Header().Set("ETag", taskETag(version))
+}
+
+func taskETag(version int) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// Read the task version a client expects from the If-Match header.
+// 0 is returned when the header is absent or "*", meaning no precondition
+func ParseIfMatch(r *http.Request) (int, error) {
+	ifMatch := strings.TrimSpace(r.Header.Get("If-Match"))
+	if ifMatch == "" || ifMatch == "*" {
+		return 0, nil
+	}
+
+	// Weak validators never match under the strong comparison If-Match requires
+	unquoted, ok := strings.CutPrefix(ifMatch, `"`)
+	if !ok {
+		return 0, customError.ErrTaskVersionMismatch
+	}
+	unquoted, ok = strings.CutSuffix(unquoted, `"`)
+	if !ok {
+		return 0, customError.ErrTaskVersionMismatch
+	}
+
+	version, err := strconv.Atoi(unquoted)
+	if err != nil || version < 1 {
+		return 0, customError.ErrTaskVersionMismatch
+	}
+
+	return version, nil
+}
@@ -0,0 +1,21 @@
+package helper
+
+import (
+	"mime"
+	"net/http"
+)
+
+// Media type of the request body without parameters such as charset.
+// A malformed header is returned as is so that it matches no known type
+func MediaType(r *http.Request) string {
+	contentType := r.Header.Get("Content-Type")
+	if contentType == "" {
+		return ""
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return contentType
+	}
+	return mediaType
+}
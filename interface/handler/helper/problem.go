@@ -0,0 +1,136 @@
+package helper
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/takumi616/go-restapi/interface/handler/response"
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+const problemContentType = "application/problem+json"
+
+type problemType struct {
+	err    error
+	status int
+	name   string
+	// Rule reported for the field named by a customError.FieldError
+	rule string
+}
+
+// The problem reported for each error a handler may run into. Errors are
+// matched with errors.Is in order, and anything unknown becomes a 500
+var problemTypes = []problemType{
+	{err: customError.InvalidRequestFormat, status: http.StatusBadRequest, name: "invalid-request-format"},
+	{err: customError.RequestBodyTooLarge, status: http.StatusRequestEntityTooLarge, name: "request-body-too-large"},
+	{err: customError.TaskBadRequest, status: http.StatusBadRequest, name: "invalid-task"},
+	{err: customError.InvalidTaskId, status: http.StatusBadRequest, name: "invalid-task-id"},
+	{err: customError.LabelBadRequest, status: http.StatusBadRequest, name: "invalid-label"},
+	{err: customError.InvalidLabelId, status: http.StatusBadRequest, name: "invalid-label-id"},
+	{err: customError.ChecklistBadRequest, status: http.StatusBadRequest, name: "invalid-checklist"},
+	{err: customError.InvalidChecklistItemId, status: http.StatusBadRequest, name: "invalid-checklist-item-id"},
+	{err: customError.AuthBadRequest, status: http.StatusBadRequest, name: "invalid-auth"},
+	{err: customError.TokenBadRequest, status: http.StatusBadRequest, name: "invalid-token"},
+	{err: customError.InvalidTokenId, status: http.StatusBadRequest, name: "invalid-token-id"},
+	{err: customError.InvalidQueryParameter, status: http.StatusBadRequest, name: "invalid-query-parameter"},
+	{err: customError.InvalidPatchDocument, status: http.StatusBadRequest, name: "invalid-patch-document"},
+	{err: customError.ErrUnauthenticated, status: http.StatusUnauthorized, name: "unauthenticated"},
+	{err: customError.ErrInvalidCredentials, status: http.StatusUnauthorized, name: "invalid-credentials"},
+	{err: customError.ErrInvalidRefresh, status: http.StatusUnauthorized, name: "invalid-refresh-token"},
+	{err: customError.ErrForbidden, status: http.StatusForbidden, name: "forbidden"},
+	{err: customError.ErrInsufficientScope, status: http.StatusForbidden, name: "insufficient-scope"},
+	{err: customError.ErrPasswordAuthOff, status: http.StatusForbidden, name: "password-auth-off"},
+	{err: customError.ErrTaskNotFound, status: http.StatusNotFound, name: "task-not-found"},
+	{err: customError.ErrTaskNotInTrash, status: http.StatusNotFound, name: "task-not-in-trash"},
+	{err: customError.ErrLabelNotFound, status: http.StatusNotFound, name: "label-not-found"},
+	{err: customError.ErrTaskDependencyNotFound, status: http.StatusNotFound, name: "task-dependency-not-found"},
+	{err: customError.ErrChecklistItemNotFound, status: http.StatusNotFound, name: "checklist-item-not-found"},
+	{err: customError.ErrTokenNotFound, status: http.StatusNotFound, name: "token-not-found"},
+	{err: customError.ErrTaskConcurrentUpdate, status: http.StatusConflict, name: "task-concurrent-update"},
+	{err: customError.ErrTaskConflict, status: http.StatusConflict, name: "task-conflict", rule: "unique"},
+	{err: customError.ErrEmailTaken, status: http.StatusConflict, name: "email-taken", rule: "unique"},
+	{err: customError.ErrLabelConflict, status: http.StatusConflict, name: "label-conflict", rule: "unique"},
+	{err: customError.PatchConflict, status: http.StatusConflict, name: "patch-conflict"},
+	{err: customError.ErrTaskTransitionNotAllowed, status: http.StatusConflict, name: "task-transition-not-allowed"},
+	{err: customError.ErrTaskHasSubtasks, status: http.StatusConflict, name: "task-has-subtasks"},
+	{err: customError.ErrTaskBlocked, status: http.StatusConflict, name: "task-blocked"},
+	{err: customError.ErrChecklistFull, status: http.StatusConflict, name: "checklist-full"},
+	{err: customError.ErrTaskVersionMismatch, status: http.StatusPreconditionFailed, name: "task-version-mismatch"},
+	{err: customError.UnsupportedMediaType, status: http.StatusUnsupportedMediaType, name: "unsupported-media-type"},
+	{err: customError.ErrTaskValueMissing, status: http.StatusUnprocessableEntity, name: "task-value-missing", rule: "required"},
+	{err: customError.ErrTaskValueTooLong, status: http.StatusUnprocessableEntity, name: "task-value-too-long", rule: "max"},
+	{err: customError.ErrLabelValueTooLong, status: http.StatusUnprocessableEntity, name: "label-value-too-long", rule: "max"},
+	{err: customError.ErrTaskLabelNotFound, status: http.StatusUnprocessableEntity, name: "task-label-not-found", rule: "exists"},
+	{err: customError.ErrTaskParentNotFound, status: http.StatusUnprocessableEntity, name: "task-parent-not-found", rule: "exists"},
+	{err: customError.ErrTaskParentCycle, status: http.StatusUnprocessableEntity, name: "task-parent-cycle", rule: "acyclic"},
+	{err: customError.ErrTaskTooDeep, status: http.StatusUnprocessableEntity, name: "task-too-deep", rule: "max_depth"},
+	{err: customError.ErrTaskBlockerNotFound, status: http.StatusUnprocessableEntity, name: "task-blocker-not-found", rule: "exists"},
+	{err: customError.ErrTaskDependencyCycle, status: http.StatusUnprocessableEntity, name: "task-dependency-cycle", rule: "acyclic"},
+	{err: customError.ErrChecklistOrderMismatch, status: http.StatusUnprocessableEntity, name: "checklist-order-mismatch", rule: "permutation"},
+	{err: customError.UnprocessablePatch, status: http.StatusUnprocessableEntity, name: "unprocessable-patch"},
+	{err: customError.ErrBatchAborted, status: http.StatusFailedDependency, name: "batch-aborted"},
+}
+
+// NewProblem describes err as a problem that occurred while serving r
+func NewProblem(r *http.Request, err error) *response.Problem {
+	problem := &response.Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(http.StatusInternalServerError),
+		Status:   http.StatusInternalServerError,
+		Detail:   err.Error(),
+		Instance: r.URL.Path,
+	}
+
+	for _, pt := range problemTypes {
+		if !errors.Is(err, pt.err) {
+			continue
+		}
+
+		problem.Type = "/problems/" + pt.name
+		problem.Title = pt.err.Error()
+		problem.Status = pt.status
+		problem.Detail = detailOf(err, pt.err)
+
+		var fieldErr *customError.FieldError
+		if pt.rule != "" && errors.As(err, &fieldErr) {
+			problem.Detail = ""
+			problem.Errors = []*response.FieldProblem{{Field: fieldErr.Field, Rule: pt.rule}}
+		}
+		break
+	}
+
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		problem.Errors = validationErr.Fields
+	}
+
+	var transitionErr *customError.TransitionError
+	if errors.As(err, &transitionErr) {
+		problem.AllowedStates = transitionErr.Allowed
+	}
+
+	var blockedErr *customError.BlockedError
+	if errors.As(err, &blockedErr) {
+		problem.BlockedBy = blockedErr.Blockers
+	}
+
+	return problem
+}
+
+// What a wrapping error adds to the problem type it wraps
+func detailOf(err, typeErr error) string {
+	detail := strings.TrimPrefix(err.Error(), typeErr.Error())
+	detail = strings.TrimPrefix(detail, ": ")
+	if detail == typeErr.Error() {
+		return ""
+	}
+	return detail
+}
+
+// WriteProblem writes err as an application/problem+json response
+func WriteProblem(ctx context.Context, w http.ResponseWriter, r *http.Request, err error) {
+	problem := NewProblem(r, err)
+	writeJSON(ctx, w, problem.Status, problemContentType, problem)
+}
@@ -0,0 +1,153 @@
+package helper
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/takumi616/go-restapi/domain"
+	"github.com/takumi616/go-restapi/interface/handler/request"
+	"github.com/takumi616/go-restapi/interface/handler/response"
+)
+
+// Shared across requests, as validator caches struct metadata
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+
+	// Report fields by the name clients send them under
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		for _, tag := range []string{"json", "query"} {
+			name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+			if name != "" && name != "-" {
+				return name
+			}
+		}
+		return ""
+	})
+
+	// States are checked against the domain rather than a copy of its list
+	v.RegisterValidation("task_state", func(fl validator.FieldLevel) bool {
+		_, err := domain.ParseTaskState(fl.Field().String())
+		return err == nil
+	})
+
+	v.RegisterValidation("task_priority", func(fl validator.FieldLevel) bool {
+		_, err := domain.ParseTaskPriority(fl.Field().String())
+		return err == nil
+	})
+
+	// Times carry an offset so they can be stored in UTC. An explicitly
+	// null optional time is valid, as it clears the time
+	v.RegisterValidation("rfc3339", func(fl validator.FieldLevel) bool {
+		s, null, ok := optionalString(fl)
+		if null {
+			return true
+		}
+		if !ok {
+			return false
+		}
+		_, err := time.Parse(time.RFC3339, s)
+		return err == nil
+	})
+
+	// Ids of other tasks are read the way ids in paths are. An explicitly
+	// null optional id is valid, as it clears the reference
+	v.RegisterValidation("task_id", func(fl validator.FieldLevel) bool {
+		s, null, ok := optionalString(fl)
+		if null {
+			return true
+		}
+		if !ok {
+			return false
+		}
+		_, err := domain.ParseTaskID(s)
+		return err == nil
+	})
+
+	// Estimates are whole hours up to a limit. An explicitly null
+	// optional estimate is valid, as it clears the estimate
+	v.RegisterValidation("task_estimate", func(fl validator.FieldLevel) bool {
+		value := fl.Field().Interface()
+		if optional, isOptional := value.(request.Optional[int]); isOptional {
+			if optional.Value == nil {
+				return true
+			}
+			value = *optional.Value
+		}
+
+		hours, ok := value.(int)
+		return ok && hours >= 0 && hours <= domain.MaxTaskEstimateHours
+	})
+
+	return v
+}
+
+// The string held by a string field or a request.Optional[string] one,
+// and whether the optional one is explicitly null
+func optionalString(fl validator.FieldLevel) (s string, null bool, ok bool) {
+	value := fl.Field().Interface()
+	if optional, isOptional := value.(request.Optional[string]); isOptional {
+		if optional.Value == nil {
+			return "", true, false
+		}
+		value = *optional.Value
+	}
+
+	s, ok = value.(string)
+	return s, false, ok
+}
+
+// ValidationError lists every field of a request that failed validation
+type ValidationError struct {
+	Err    error
+	Fields []*response.FieldProblem
+}
+
+func (e *ValidationError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// Validate checks req against its validate tags. Failures are reported as
+// a ValidationError wrapping typeErr, which decides the problem type
+func Validate(req any, typeErr error) error {
+	err := validate.Struct(req)
+	if err == nil {
+		return nil
+	}
+
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return err
+	}
+
+	validationErr := &ValidationError{Err: typeErr}
+	for _, fe := range fieldErrs {
+		validationErr.Fields = append(validationErr.Fields, &response.FieldProblem{
+			Field: fieldPath(fe.Namespace()),
+			Rule:  fe.Tag(),
+			Param: fe.Param(),
+		})
+	}
+	return validationErr
+}
+
+// Turn a validator namespace such as "BatchUpdateTasksReq.tasks[0].UpdateTaskReq.title"
+// into "tasks[0].title". Segments named after Go types, the request itself
+// and embedded structs, are not part of the client's view of the body
+func fieldPath(namespace string) string {
+	var path []string
+	for _, segment := range strings.Split(namespace, ".") {
+		if segment != "" && strings.ToLower(segment[:1]) == segment[:1] {
+			path = append(path, segment)
+		}
+	}
+	return strings.Join(path, ".")
+}
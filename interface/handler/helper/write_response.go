@@ -10,14 +10,19 @@ import (
 )
 
 func WriteResponse(ctx context.Context, w http.ResponseWriter, status int, body any) {
-	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	writeJSON(ctx, w, status, "application/json", body)
+}
 
+func writeJSON(ctx context.Context, w http.ResponseWriter, status int, contentType string, body any) {
 	bodyBytes, err := json.Marshal(body)
 	if err != nil {
 		fmt.Printf("Failed to encode response correctly: %v", err)
+		w.Header().Set("Content-Type", problemContentType)
 		w.WriteHeader(http.StatusInternalServerError)
-		rsp := response.ErrResponse{
-			Message: http.StatusText(http.StatusInternalServerError),
+		rsp := response.Problem{
+			Type:   "about:blank",
+			Title:  http.StatusText(http.StatusInternalServerError),
+			Status: http.StatusInternalServerError,
 		}
 
 		if err := json.NewEncoder(w).Encode(rsp); err != nil {
@@ -26,6 +31,7 @@ func WriteResponse(ctx context.Context, w http.ResponseWriter, status int, body
 		return
 	}
 
+	w.Header().Set("Content-Type", contentType+"; charset=utf-8")
 	w.WriteHeader(status)
 	if _, err := fmt.Fprintf(w, "%s", bodyBytes); err != nil {
 		fmt.Printf("Failed to write response correctly: %v", err)
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/takumi616/go-restapi/domain"
+	"github.com/takumi616/go-restapi/interface/handler/helper"
+	"github.com/takumi616/go-restapi/interface/handler/request"
+	"github.com/takumi616/go-restapi/interface/handler/response"
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+type LabelHandler struct {
+	usecase LabelUsecase
+}
+
+func NewLabelHandler(usecase LabelUsecase) *LabelHandler {
+	return &LabelHandler{
+		usecase: usecase,
+	}
+}
+
+func (h *LabelHandler) AddLabel(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req request.AddLabelReq
+	if err := helper.DecodeJSON(w, r, &req); err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	if err := helper.Validate(req, customError.LabelBadRequest); err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	added, err := h.usecase.AddLabel(ctx, (&req).ToDomain())
+	if err != nil {
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	helper.WriteResponse(ctx, w, http.StatusCreated, response.ToLabelRes(added))
+}
+
+// GetLabels lists every label by name
+func (h *LabelHandler) GetLabels(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	labels, err := h.usecase.GetLabels(ctx)
+	if err != nil {
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	labelResList := []*response.LabelRes{}
+	for _, label := range labels {
+		labelResList = append(labelResList, response.ToLabelRes(label))
+	}
+
+	helper.WriteResponse(ctx, w, http.StatusOK, labelResList)
+}
+
+func (h *LabelHandler) GetLabelById(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := domain.ParseLabelID(r.PathValue("id"))
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	label, err := h.usecase.GetLabelById(ctx, id)
+	if err != nil {
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	helper.WriteResponse(ctx, w, http.StatusOK, response.ToLabelRes(label))
+}
+
+func (h *LabelHandler) UpdateLabel(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := domain.ParseLabelID(r.PathValue("id"))
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	var req request.UpdateLabelReq
+	if err := helper.DecodeJSON(w, r, &req); err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	if err := helper.Validate(req, customError.LabelBadRequest); err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	updated, err := h.usecase.UpdateLabel(ctx, id, (&req).ToDomain())
+	if err != nil {
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	helper.WriteResponse(ctx, w, http.StatusOK, response.ToLabelRes(updated))
+}
+
+// DeleteLabel removes a label, taking it off every task that carries it
+func (h *LabelHandler) DeleteLabel(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := domain.ParseLabelID(r.PathValue("id"))
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	deleted, err := h.usecase.DeleteLabel(ctx, id)
+	if err != nil {
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	helper.WriteResponse(ctx, w, http.StatusOK, response.ToLabelIdRes(deleted))
+}
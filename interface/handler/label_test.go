@@ -0,0 +1,300 @@
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/takumi616/go-restapi/domain"
+	"github.com/takumi616/go-restapi/interface/handler/test/helper"
+	"github.com/takumi616/go-restapi/interface/handler/test/mock"
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+func TestAddLabel(t *testing.T) {
+	type expected struct {
+		status  int
+		resFile string
+	}
+
+	type mockData struct {
+		param, returned *domain.Label
+		err             error
+	}
+
+	testTable := map[string]struct {
+		reqFile  string
+		expected expected
+		mockData mockData
+		mockUse  bool
+	}{
+		"Ok": {
+			reqFile: "test/data/add_label/ok_req.json.golden",
+			expected: expected{
+				status:  http.StatusCreated,
+				resFile: "test/data/add_label/ok_res.json.golden",
+			},
+			mockData: mockData{
+				param: &domain.Label{Name: "bug", Color: "#ff0000"},
+				returned: &domain.Label{
+					Id:   "c6a1f3e2-7d4b-4e8a-b1f9-3a2d5e6c7b80",
+					Name: "bug", Color: "#ff0000",
+					CreatedAt: testCreatedAt, UpdatedAt: testCreatedAt,
+				},
+			},
+			mockUse: true,
+		},
+		"DuplicateErr": {
+			reqFile: "test/data/add_label/ok_req.json.golden",
+			expected: expected{
+				status:  http.StatusConflict,
+				resFile: "test/data/add_label/duplicate_err_res.json.golden",
+			},
+			mockData: mockData{
+				param: &domain.Label{Name: "bug", Color: "#ff0000"},
+				err:   &customError.FieldError{Field: "name", Err: customError.ErrLabelConflict},
+			},
+			mockUse: true,
+		},
+		"BadRequest": {
+			reqFile: "test/data/add_label/bad_req_req.json.golden",
+			expected: expected{
+				status:  http.StatusBadRequest,
+				resFile: "test/data/add_label/bad_req_res.json.golden",
+			},
+			mockUse: false,
+		},
+	}
+
+	for n, tt := range testTable {
+		tt := tt
+
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodPost,
+				"/labels",
+				bytes.NewReader(helper.LoadFile(t, tt.reqFile)),
+			)
+			r.Header.Set("Content-Type", "application/json")
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockLabelUsecase := mock.NewMockLabelUsecase(mockCtrl)
+			if tt.mockUse {
+				mockLabelUsecase.EXPECT().AddLabel(r.Context(), tt.mockData.param).
+					Return(tt.mockData.returned, tt.mockData.err)
+			}
+
+			sut := NewLabelHandler(mockLabelUsecase)
+			sut.AddLabel(w, r)
+
+			helper.AssertResponse(t,
+				w.Result(), tt.expected.status, helper.LoadFile(t, tt.expected.resFile),
+			)
+		})
+	}
+}
+
+func TestGetLabels(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/labels", nil)
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockLabelUsecase := mock.NewMockLabelUsecase(mockCtrl)
+	mockLabelUsecase.EXPECT().GetLabels(r.Context()).Return([]*domain.Label{
+		{
+			Id:   "0b9b4d6e-5c1f-4b0e-9a43-2f6f0c3d8e71",
+			Name: "backend", Color: "#0000ff",
+			CreatedAt: testCreatedAt, UpdatedAt: testCreatedAt,
+		},
+		{
+			Id:   "c6a1f3e2-7d4b-4e8a-b1f9-3a2d5e6c7b80",
+			Name: "bug", Color: "#ff0000",
+			CreatedAt: testCreatedAt, UpdatedAt: testUpdatedAt,
+		},
+	}, nil)
+
+	sut := NewLabelHandler(mockLabelUsecase)
+	sut.GetLabels(w, r)
+
+	helper.AssertResponse(t,
+		w.Result(), http.StatusOK, helper.LoadFile(t, "test/data/get_labels/ok_res.json.golden"),
+	)
+}
+
+func TestUpdateLabel(t *testing.T) {
+	type expected struct {
+		status  int
+		resFile string
+	}
+
+	type mockData struct {
+		patch    *domain.LabelPatch
+		returned *domain.Label
+		err      error
+	}
+
+	testTable := map[string]struct {
+		id       string
+		reqFile  string
+		expected expected
+		mockData mockData
+		mockUse  bool
+	}{
+		"Ok": {
+			id:      "c6a1f3e2-7d4b-4e8a-b1f9-3a2d5e6c7b80",
+			reqFile: "test/data/update_label/ok_req.json.golden",
+			expected: expected{
+				status:  http.StatusOK,
+				resFile: "test/data/update_label/ok_res.json.golden",
+			},
+			mockData: mockData{
+				patch: &domain.LabelPatch{Color: ptr("#00ff00")},
+				returned: &domain.Label{
+					Id:   "c6a1f3e2-7d4b-4e8a-b1f9-3a2d5e6c7b80",
+					Name: "bug", Color: "#00ff00",
+					CreatedAt: testCreatedAt, UpdatedAt: testUpdatedAt,
+				},
+			},
+			mockUse: true,
+		},
+		"NotFound": {
+			id:      "3e440171-0921-4c88-a7ec-13f4cdab0d69",
+			reqFile: "test/data/update_label/ok_req.json.golden",
+			expected: expected{
+				status:  http.StatusNotFound,
+				resFile: "test/data/update_label/not_found_res.json.golden",
+			},
+			mockData: mockData{
+				patch: &domain.LabelPatch{Color: ptr("#00ff00")},
+				err:   customError.ErrLabelNotFound,
+			},
+			mockUse: true,
+		},
+		"EmptyBody": {
+			id:      "c6a1f3e2-7d4b-4e8a-b1f9-3a2d5e6c7b80",
+			reqFile: "test/data/update_label/empty_body_req.json.golden",
+			expected: expected{
+				status:  http.StatusBadRequest,
+				resFile: "test/data/update_label/empty_body_res.json.golden",
+			},
+			mockUse: false,
+		},
+		"InvalidId": {
+			id:      "abc123",
+			reqFile: "test/data/update_label/ok_req.json.golden",
+			expected: expected{
+				status:  http.StatusBadRequest,
+				resFile: "test/data/update_label/invalid_id_res.json.golden",
+			},
+			mockUse: false,
+		},
+	}
+
+	for n, tt := range testTable {
+		tt := tt
+
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodPatch,
+				fmt.Sprintf("/labels/%s", tt.id),
+				bytes.NewReader(helper.LoadFile(t, tt.reqFile)),
+			)
+			r.SetPathValue("id", tt.id)
+			r.Header.Set("Content-Type", "application/json")
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockLabelUsecase := mock.NewMockLabelUsecase(mockCtrl)
+			if tt.mockUse {
+				mockLabelUsecase.EXPECT().UpdateLabel(r.Context(), domain.LabelID(tt.id), tt.mockData.patch).
+					Return(tt.mockData.returned, tt.mockData.err)
+			}
+
+			sut := NewLabelHandler(mockLabelUsecase)
+			sut.UpdateLabel(w, r)
+
+			helper.AssertResponse(t,
+				w.Result(), tt.expected.status, helper.LoadFile(t, tt.expected.resFile),
+			)
+		})
+	}
+}
+
+func TestDeleteLabel(t *testing.T) {
+	type expected struct {
+		status  int
+		resFile string
+	}
+
+	testTable := map[string]struct {
+		id       string
+		label    *domain.Label
+		err      error
+		expected expected
+	}{
+		"Ok": {
+			id:    "c6a1f3e2-7d4b-4e8a-b1f9-3a2d5e6c7b80",
+			label: &domain.Label{Id: "c6a1f3e2-7d4b-4e8a-b1f9-3a2d5e6c7b80"},
+			expected: expected{
+				status:  http.StatusOK,
+				resFile: "test/data/delete_label/ok_res.json.golden",
+			},
+		},
+		"NotFound": {
+			id:  "3e440171-0921-4c88-a7ec-13f4cdab0d69",
+			err: customError.ErrLabelNotFound,
+			expected: expected{
+				status:  http.StatusNotFound,
+				resFile: "test/data/delete_label/not_found_res.json.golden",
+			},
+		},
+		"Forbidden": {
+			id:  "c6a1f3e2-7d4b-4e8a-b1f9-3a2d5e6c7b80",
+			err: fmt.Errorf("%w: role 'editor' may not delete_label", customError.ErrForbidden),
+			expected: expected{
+				status:  http.StatusForbidden,
+				resFile: "test/data/delete_label/forbidden_res.json.golden",
+			},
+		},
+	}
+
+	for n, tt := range testTable {
+		tt := tt
+
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/labels/%s", tt.id), nil)
+			r.SetPathValue("id", tt.id)
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockLabelUsecase := mock.NewMockLabelUsecase(mockCtrl)
+			mockLabelUsecase.EXPECT().DeleteLabel(r.Context(), domain.LabelID(tt.id)).
+				Return(tt.label, tt.err)
+
+			sut := NewLabelHandler(mockLabelUsecase)
+			sut.DeleteLabel(w, r)
+
+			helper.AssertResponse(t,
+				w.Result(), tt.expected.status, helper.LoadFile(t, tt.expected.resFile),
+			)
+		})
+	}
+}
@@ -0,0 +1,15 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/takumi616/go-restapi/domain"
+)
+
+type LabelUsecase interface {
+	AddLabel(ctx context.Context, label *domain.Label) (*domain.Label, error)
+	GetLabels(ctx context.Context) ([]*domain.Label, error)
+	GetLabelById(ctx context.Context, id domain.LabelID) (*domain.Label, error)
+	UpdateLabel(ctx context.Context, id domain.LabelID, patch *domain.LabelPatch) (*domain.Label, error)
+	DeleteLabel(ctx context.Context, id domain.LabelID) (*domain.Label, error)
+}
@@ -0,0 +1,16 @@
+package patch
+
+import "errors"
+
+// Media types of the patch formats tasks accept
+const (
+	MergePatchType = "application/merge-patch+json"
+	JSONPatchType  = "application/json-patch+json"
+)
+
+var (
+	// The patch document itself is malformed
+	ErrInvalidPatch = errors.New("patch document is invalid")
+	// The patch is well formed but cannot be applied to the document
+	ErrPatchConflict = errors.New("patch cannot be applied")
+)
@@ -0,0 +1,183 @@
+package patch
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyMergePatch(t *testing.T) {
+	testTable := map[string]struct {
+		doc, patch string
+		expected   string
+		err        error
+	}{
+		"ReplaceMember": {
+			doc:      `{"a":"b"}`,
+			patch:    `{"a":"c"}`,
+			expected: `{"a":"c"}`,
+		},
+		"AddMember": {
+			doc:      `{"a":"b"}`,
+			patch:    `{"b":"c"}`,
+			expected: `{"a":"b","b":"c"}`,
+		},
+		"RemoveMember": {
+			doc:      `{"a":"b","b":"c"}`,
+			patch:    `{"a":null}`,
+			expected: `{"b":"c"}`,
+		},
+		"NestedObject": {
+			doc:      `{"a":{"b":"c","d":"e"}}`,
+			patch:    `{"a":{"b":null,"f":"g"}}`,
+			expected: `{"a":{"d":"e","f":"g"}}`,
+		},
+		"ArrayIsReplaced": {
+			doc:      `{"a":[{"b":"c"}]}`,
+			patch:    `{"a":[1]}`,
+			expected: `{"a":[1]}`,
+		},
+		"NonObjectPatch": {
+			doc:      `{"a":"b"}`,
+			patch:    `["c"]`,
+			expected: `["c"]`,
+		},
+		"InvalidPatch": {
+			doc:   `{"a":"b"}`,
+			patch: `{"a":`,
+			err:   ErrInvalidPatch,
+		},
+	}
+
+	for n, tt := range testTable {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := ApplyMergePatch([]byte(tt.doc), []byte(tt.patch))
+			if tt.err != nil {
+				assert.True(t, errors.Is(err, tt.err), err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.expected, string(actual))
+		})
+	}
+}
+
+func TestApplyJSONPatch(t *testing.T) {
+	testTable := map[string]struct {
+		doc, patch string
+		expected   string
+		err        error
+	}{
+		"AddMember": {
+			doc:      `{"foo":"bar"}`,
+			patch:    `[{"op":"add","path":"/baz","value":"qux"}]`,
+			expected: `{"foo":"bar","baz":"qux"}`,
+		},
+		"AddArrayElement": {
+			doc:      `{"foo":["bar","baz"]}`,
+			patch:    `[{"op":"add","path":"/foo/1","value":"qux"}]`,
+			expected: `{"foo":["bar","qux","baz"]}`,
+		},
+		"AppendArrayElement": {
+			doc:      `{"foo":["bar"]}`,
+			patch:    `[{"op":"add","path":"/foo/-","value":"qux"}]`,
+			expected: `{"foo":["bar","qux"]}`,
+		},
+		"RemoveArrayElement": {
+			doc:      `{"foo":["bar","qux","baz"]}`,
+			patch:    `[{"op":"remove","path":"/foo/1"}]`,
+			expected: `{"foo":["bar","baz"]}`,
+		},
+		"Replace": {
+			doc:      `{"baz":"qux","foo":"bar"}`,
+			patch:    `[{"op":"replace","path":"/baz","value":"boo"}]`,
+			expected: `{"baz":"boo","foo":"bar"}`,
+		},
+		"ReplaceWithNull": {
+			doc:      `{"due_at":"2025-01-02T03:04:05Z","title":"Test Title"}`,
+			patch:    `[{"op":"replace","path":"/due_at","value":null}]`,
+			expected: `{"due_at":null,"title":"Test Title"}`,
+		},
+		"AddNull": {
+			doc:      `{"title":"Test Title"}`,
+			patch:    `[{"op":"add","path":"/parent_id","value":null},{"op":"test","path":"/parent_id","value":null}]`,
+			expected: `{"parent_id":null,"title":"Test Title"}`,
+		},
+		"Move": {
+			doc:      `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`,
+			patch:    `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+			expected: `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`,
+		},
+		"Copy": {
+			doc:      `{"foo":{"bar":1}}`,
+			patch:    `[{"op":"copy","from":"/foo","path":"/baz"},{"op":"replace","path":"/baz/bar","value":2}]`,
+			expected: `{"foo":{"bar":1},"baz":{"bar":2}}`,
+		},
+		"EscapedPointer": {
+			doc:      `{"a/b":1,"m~n":2}`,
+			patch:    `[{"op":"replace","path":"/a~1b","value":3},{"op":"remove","path":"/m~0n"}]`,
+			expected: `{"a/b":3}`,
+		},
+		"TestSucceeds": {
+			doc:      `{"baz":"qux","foo":["a",2,"c"]}`,
+			patch:    `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2}]`,
+			expected: `{"baz":"qux","foo":["a",2,"c"]}`,
+		},
+		"TestFails": {
+			doc:   `{"baz":"qux"}`,
+			patch: `[{"op":"test","path":"/baz","value":"bar"}]`,
+			err:   ErrPatchConflict,
+		},
+		"ReplaceMissingMember": {
+			doc:   `{"foo":"bar"}`,
+			patch: `[{"op":"replace","path":"/baz","value":"qux"}]`,
+			err:   ErrPatchConflict,
+		},
+		"AddToMissingParent": {
+			doc:   `{"foo":"bar"}`,
+			patch: `[{"op":"add","path":"/baz/bat","value":"qux"}]`,
+			err:   ErrPatchConflict,
+		},
+		"MoveIntoItself": {
+			doc:   `{"foo":{"bar":1}}`,
+			patch: `[{"op":"move","from":"/foo","path":"/foo/baz"}]`,
+			err:   ErrPatchConflict,
+		},
+		"UnknownOp": {
+			doc:   `{"foo":"bar"}`,
+			patch: `[{"op":"invalid","path":"/foo"}]`,
+			err:   ErrInvalidPatch,
+		},
+		"MissingValue": {
+			doc:   `{"foo":"bar"}`,
+			patch: `[{"op":"add","path":"/baz"}]`,
+			err:   ErrInvalidPatch,
+		},
+		"NotAnArray": {
+			doc:   `{"foo":"bar"}`,
+			patch: `{"op":"add","path":"/baz","value":1}`,
+			err:   ErrInvalidPatch,
+		},
+	}
+
+	for n, tt := range testTable {
+		tt := tt
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := ApplyJSONPatch([]byte(tt.doc), []byte(tt.patch))
+			if tt.err != nil {
+				assert.True(t, errors.Is(err, tt.err), err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.expected, string(actual))
+		})
+	}
+}
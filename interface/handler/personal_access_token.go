@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/takumi616/go-restapi/domain"
+	"github.com/takumi616/go-restapi/interface/handler/helper"
+	"github.com/takumi616/go-restapi/interface/handler/request"
+	"github.com/takumi616/go-restapi/interface/handler/response"
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+// CreatePersonalAccessToken mints a token for the signed-in user. The token
+// is in this response only, so it must not be kept by caches either
+func (h *AuthHandler) CreatePersonalAccessToken(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req request.CreatePersonalAccessTokenReq
+	if err := helper.DecodeJSON(w, r, &req); err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	if err := helper.Validate(req, customError.TokenBadRequest); err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	stored, token, err := h.usecase.CreatePersonalAccessToken(ctx, req.Name, req.ToScopes(), req.ExpiresIn())
+	if err != nil {
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	helper.WriteResponse(ctx, w, http.StatusCreated, response.ToCreatedPersonalAccessTokenRes(stored, token))
+}
+
+// GetPersonalAccessTokens lists the tokens of the signed-in user
+func (h *AuthHandler) GetPersonalAccessTokens(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	tokens, err := h.usecase.GetPersonalAccessTokens(ctx)
+	if err != nil {
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	tokenResList := []*response.PersonalAccessTokenRes{}
+	for _, token := range tokens {
+		tokenResList = append(tokenResList, response.ToPersonalAccessTokenRes(token))
+	}
+
+	helper.WriteResponse(ctx, w, http.StatusOK, tokenResList)
+}
+
+// RevokePersonalAccessToken revokes a token of the signed-in user
+func (h *AuthHandler) RevokePersonalAccessToken(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := domain.ParsePersonalAccessTokenID(r.PathValue("id"))
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	if err := h.usecase.RevokePersonalAccessToken(ctx, id); err != nil {
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
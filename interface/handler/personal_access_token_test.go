@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/takumi616/go-restapi/domain"
+	"github.com/takumi616/go-restapi/interface/handler/test/helper"
+	"github.com/takumi616/go-restapi/interface/handler/test/mock"
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+var testLastUsedAt = time.Date(2025, 2, 3, 4, 5, 6, 0, time.UTC)
+
+var testPersonalAccessToken = &domain.PersonalAccessToken{
+	Id:        "0b7d9c2e-5f1a-4e3b-9c8d-7a6b5c4d3e2f",
+	UserId:    "7c9e6679-7425-40de-944b-e07fc1f90ae7",
+	Name:      "ci",
+	Scopes:    []domain.TokenScope{domain.ScopeTasksRead, domain.ScopeTasksWrite},
+	ExpiresAt: time.Date(2025, 4, 2, 3, 4, 5, 0, time.UTC),
+	CreatedAt: testCreatedAt,
+}
+
+func TestCreatePersonalAccessToken(t *testing.T) {
+	type expected struct {
+		status  int
+		resFile string
+	}
+
+	type mockData struct {
+		token  *domain.PersonalAccessToken
+		secret string
+		err    error
+	}
+
+	testTable := map[string]struct {
+		reqFile  string
+		expected expected
+		mockData mockData
+		mockUse  bool
+	}{
+		"Ok": {
+			reqFile: "test/data/create_token/ok_req.json.golden",
+			expected: expected{
+				status:  http.StatusCreated,
+				resFile: "test/data/create_token/ok_res.json.golden",
+			},
+			mockData: mockData{
+				token:  testPersonalAccessToken,
+				secret: "pat_Zm9vYmFyYmF6cXV4",
+			},
+			mockUse: true,
+		},
+		"WithToken": {
+			reqFile: "test/data/create_token/ok_req.json.golden",
+			expected: expected{
+				status:  http.StatusForbidden,
+				resFile: "test/data/create_token/with_token_res.json.golden",
+			},
+			mockData: mockData{
+				err: fmt.Errorf("%w: personal access tokens cannot manage tokens", customError.ErrInsufficientScope),
+			},
+			mockUse: true,
+		},
+		"BadRequest": {
+			reqFile: "test/data/create_token/bad_req_req.json.golden",
+			expected: expected{
+				status:  http.StatusBadRequest,
+				resFile: "test/data/create_token/bad_req_res.json.golden",
+			},
+			mockUse: false,
+		},
+	}
+
+	for n, tt := range testTable {
+		tt := tt
+
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodPost,
+				"/me/tokens",
+				bytes.NewReader(helper.LoadFile(t, tt.reqFile)),
+			)
+			r.Header.Set("Content-Type", "application/json")
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockAuthUsecase := mock.NewMockAuthUsecase(mockCtrl)
+			if tt.mockUse {
+				mockAuthUsecase.EXPECT().CreatePersonalAccessToken(
+					r.Context(), "ci", []domain.TokenScope{domain.ScopeTasksRead, domain.ScopeTasksWrite}, 60*24*time.Hour,
+				).Return(tt.mockData.token, tt.mockData.secret, tt.mockData.err)
+			}
+
+			sut := NewAuthHandler(mockAuthUsecase, false)
+			sut.CreatePersonalAccessToken(w, r)
+
+			actualRes := w.Result()
+			if tt.expected.status == http.StatusCreated && actualRes.Header.Get("Cache-Control") != "no-store" {
+				t.Errorf("expected Cache-Control no-store, but actual %q", actualRes.Header.Get("Cache-Control"))
+			}
+			helper.AssertResponse(t,
+				actualRes, tt.expected.status, helper.LoadFile(t, tt.expected.resFile),
+			)
+		})
+	}
+}
+
+func TestGetPersonalAccessTokens(t *testing.T) {
+	used := *testPersonalAccessToken
+	used.Id = "5d4c3b2a-1f0e-4d9c-8b7a-6f5e4d3c2b1a"
+	used.Name = "nightly report"
+	used.Scopes = []domain.TokenScope{domain.ScopeTasksRead}
+	used.LastUsedAt = &testLastUsedAt
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/me/tokens", nil)
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockAuthUsecase := mock.NewMockAuthUsecase(mockCtrl)
+	mockAuthUsecase.EXPECT().GetPersonalAccessTokens(r.Context()).
+		Return([]*domain.PersonalAccessToken{testPersonalAccessToken, &used}, nil)
+
+	sut := NewAuthHandler(mockAuthUsecase, false)
+	sut.GetPersonalAccessTokens(w, r)
+
+	helper.AssertResponse(t,
+		w.Result(), http.StatusOK, helper.LoadFile(t, "test/data/get_tokens/ok_res.json.golden"),
+	)
+}
+
+func TestRevokePersonalAccessToken(t *testing.T) {
+	type expected struct {
+		status  int
+		resFile string
+	}
+
+	testTable := map[string]struct {
+		id       string
+		expected expected
+		mockErr  error
+		mockUse  bool
+	}{
+		"Ok": {
+			id: "0B7D9C2E-5F1A-4E3B-9C8D-7A6B5C4D3E2F",
+			expected: expected{
+				status: http.StatusNoContent,
+			},
+			mockUse: true,
+		},
+		"NotFound": {
+			id: "0b7d9c2e-5f1a-4e3b-9c8d-7a6b5c4d3e2f",
+			expected: expected{
+				status:  http.StatusNotFound,
+				resFile: "test/data/revoke_token/not_found_res.json.golden",
+			},
+			mockErr: customError.ErrTokenNotFound,
+			mockUse: true,
+		},
+		"InvalidId": {
+			id: "ci",
+			expected: expected{
+				status:  http.StatusBadRequest,
+				resFile: "test/data/revoke_token/invalid_id_res.json.golden",
+			},
+			mockUse: false,
+		},
+	}
+
+	for n, tt := range testTable {
+		tt := tt
+
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodDelete, "/me/tokens/"+tt.id, nil)
+			r.SetPathValue("id", tt.id)
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockAuthUsecase := mock.NewMockAuthUsecase(mockCtrl)
+			if tt.mockUse {
+				mockAuthUsecase.EXPECT().
+					RevokePersonalAccessToken(r.Context(), domain.PersonalAccessTokenID("0b7d9c2e-5f1a-4e3b-9c8d-7a6b5c4d3e2f")).
+					Return(tt.mockErr)
+			}
+
+			sut := NewAuthHandler(mockAuthUsecase, false)
+			sut.RevokePersonalAccessToken(w, r)
+
+			var expectedBody []byte
+			if tt.expected.resFile != "" {
+				expectedBody = helper.LoadFile(t, tt.expected.resFile)
+			}
+			helper.AssertResponse(t, w.Result(), tt.expected.status, expectedBody)
+		})
+	}
+}
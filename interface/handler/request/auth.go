@@ -0,0 +1,17 @@
+package request
+
+// Passwords are limited to the 72 bytes bcrypt reads
+type RegisterReq struct {
+	Email    string `json:"email" validate:"required,email,max=254"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
+}
+
+type LoginReq struct {
+	Email    string `json:"email" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+// Names the session to refresh or end
+type RefreshTokenReq struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
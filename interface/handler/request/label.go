@@ -0,0 +1,42 @@
+package request
+
+import "github.com/takumi616/go-restapi/domain"
+
+// Colors are written as #rrggbb and are optional
+type AddLabelReq struct {
+	Name  string `json:"name" validate:"required,max=30"`
+	Color string `json:"color" validate:"omitempty,hexcolor,len=7"`
+}
+
+func (a *AddLabelReq) ToDomain() *domain.Label {
+	return &domain.Label{
+		Name:  a.Name,
+		Color: a.Color,
+	}
+}
+
+// Only the fields present in the request body are updated
+type UpdateLabelReq struct {
+	Name  *string `json:"name" validate:"required_without=Color,omitnil,min=1,max=30"`
+	Color *string `json:"color" validate:"omitnil,hexcolor,len=7"`
+}
+
+func (u *UpdateLabelReq) ToDomain() *domain.LabelPatch {
+	return &domain.LabelPatch{
+		Name:  u.Name,
+		Color: u.Color,
+	}
+}
+
+// The complete set of labels a task should carry. An empty list removes them all
+type SetTaskLabelsReq struct {
+	Labels []string `json:"labels" validate:"required,max=20,unique,dive,uuid_rfc4122"`
+}
+
+func (s *SetTaskLabelsReq) ToDomain() []domain.LabelID {
+	labelIds := make([]domain.LabelID, len(s.Labels))
+	for i, id := range s.Labels {
+		labelIds[i], _ = domain.ParseLabelID(id)
+	}
+	return labelIds
+}
@@ -0,0 +1,15 @@
+package request
+
+import "encoding/json"
+
+// Optional tells a field that is absent from a request body apart from one
+// that is explicitly null, which a pointer alone cannot
+type Optional[T any] struct {
+	Present bool
+	Value   *T
+}
+
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	o.Present = true
+	return json.Unmarshal(data, &o.Value)
+}
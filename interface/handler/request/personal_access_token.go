@@ -0,0 +1,27 @@
+package request
+
+import (
+	"time"
+
+	"github.com/takumi616/go-restapi/domain"
+)
+
+// Tokens expire after at most a year, so that a forgotten one does not
+// stay usable forever
+type CreatePersonalAccessTokenReq struct {
+	Name          string   `json:"name" validate:"required,max=100"`
+	Scopes        []string `json:"scopes" validate:"required,min=1,unique,dive,oneof=tasks:read tasks:write"`
+	ExpiresInDays int      `json:"expires_in_days" validate:"required,min=1,max=366"`
+}
+
+func (c *CreatePersonalAccessTokenReq) ToScopes() []domain.TokenScope {
+	scopes := make([]domain.TokenScope, len(c.Scopes))
+	for i, scope := range c.Scopes {
+		scopes[i] = domain.TokenScope(scope)
+	}
+	return scopes
+}
+
+func (c *CreatePersonalAccessTokenReq) ExpiresIn() time.Duration {
+	return time.Duration(c.ExpiresInDays) * 24 * time.Hour
+}
@@ -1,27 +1,117 @@
 package request
 
-import "github.com/takumi616/go-restapi/domain"
+import (
+	"time"
 
+	"github.com/takumi616/go-restapi/domain"
+)
+
+// Priority defaults to P2 when absent. The due date is optional and
+// may be given with any UTC offset. A parent makes the task a subtask,
+// and the task waits on the tasks it depends on
 type AddTaskReq struct {
-	Title       string `json:"title" validate:"required"`
-	Description string `json:"description"`
+	Title         string   `json:"title" validate:"required"`
+	Description   string   `json:"description"`
+	Priority      string   `json:"priority" validate:"omitempty,task_priority"`
+	DueAt         string   `json:"due_at" validate:"omitempty,rfc3339"`
+	EstimateHours *int     `json:"estimate_hours" validate:"omitnil,task_estimate"`
+	ParentId      string   `json:"parent_id" validate:"omitempty,task_id"`
+	DependsOn     []string `json:"depends_on" validate:"omitempty,max=20,unique,dive,task_id"`
 }
 
 func (a *AddTaskReq) ToDomain() *domain.Task {
-	return &domain.Task{
-		Title:       a.Title,
-		Description: a.Description,
+	task := &domain.Task{
+		Title:         a.Title,
+		Description:   a.Description,
+		Priority:      domain.TaskPriority(a.Priority),
+		DueAt:         parseOptionalTime(&a.DueAt),
+		EstimateHours: a.EstimateHours,
+		ParentId:      parseOptionalTaskID(&a.ParentId),
+	}
+
+	for _, id := range a.DependsOn {
+		blockerId, _ := domain.ParseTaskID(id)
+		task.BlockedBy = append(task.BlockedBy, &domain.TaskBlocker{Id: blockerId})
 	}
+
+	return task
 }
 
+// Only the fields present in the request body are updated.
+// The boolean status of earlier API versions is still accepted
+// and moves the task to done or back to todo. A null due date or estimate
+// removes it, and a null parent makes the task top-level
 type UpdateTaskReq struct {
-	Description string `json:"description"`
-	Status      *bool  `json:"status" validate:"required"`
+	Title         *string          `json:"title" validate:"required_without_all=Description Status State Priority DueAt.Present EstimateHours.Present ParentId.Present,omitnil,min=1"`
+	Description   *string          `json:"description"`
+	Status        *bool            `json:"status" validate:"excluded_with=State"`
+	State         *string          `json:"state" validate:"omitnil,task_state"`
+	Priority      *string          `json:"priority" validate:"omitnil,task_priority"`
+	DueAt         Optional[string] `json:"due_at" validate:"rfc3339"`
+	EstimateHours Optional[int]    `json:"estimate_hours" validate:"task_estimate"`
+	ParentId      Optional[string] `json:"parent_id" validate:"task_id"`
+}
+
+func (u *UpdateTaskReq) ToDomain() *domain.TaskPatch {
+	patch := &domain.TaskPatch{
+		Title:         u.Title,
+		Description:   u.Description,
+		DueAt:         parseOptionalTime(u.DueAt.Value),
+		ClearDueAt:    u.DueAt.Present && u.DueAt.Value == nil,
+		EstimateHours: u.EstimateHours.Value,
+		ClearEstimate: u.EstimateHours.Present && u.EstimateHours.Value == nil,
+		ParentId:      parseOptionalTaskID(u.ParentId.Value),
+		ClearParent:   u.ParentId.Present && u.ParentId.Value == nil,
+	}
+
+	if u.Priority != nil {
+		priority := domain.TaskPriority(*u.Priority)
+		patch.Priority = &priority
+	}
+
+	if u.State != nil {
+		state := domain.TaskState(*u.State)
+		patch.State = &state
+	} else if u.Status != nil {
+		state := stateOfStatus(*u.Status)
+		patch.State = &state
+	}
+
+	return patch
 }
 
-func (u *UpdateTaskReq) ToDomain() *domain.Task {
-	return &domain.Task{
-		Description: u.Description,
-		Status:      *u.Status,
+// The state a boolean status stands for
+func stateOfStatus(status bool) domain.TaskState {
+	if status {
+		return domain.TaskStateDone
+	}
+	return domain.TaskStateTodo
+}
+
+// Parse a validated RFC 3339 time into UTC. Nil and empty strings are no time
+func parseOptionalTime(s *string) *time.Time {
+	if s == nil || *s == "" {
+		return nil
+	}
+
+	t, err := time.Parse(time.RFC3339, *s)
+	if err != nil {
+		return nil
+	}
+
+	t = t.UTC()
+	return &t
+}
+
+// Parse a validated task id. Nil and empty strings are no id
+func parseOptionalTaskID(s *string) *domain.TaskID {
+	if s == nil || *s == "" {
+		return nil
+	}
+
+	id, err := domain.ParseTaskID(*s)
+	if err != nil {
+		return nil
 	}
+	return &id
 }
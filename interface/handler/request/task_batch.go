@@ -0,0 +1,64 @@
+package request
+
+import "github.com/takumi616/go-restapi/domain"
+
+type BatchAddTasksReq struct {
+	Tasks []*AddTaskReq `json:"tasks" validate:"required,min=1,max=100,dive,required"`
+}
+
+func (b *BatchAddTasksReq) ToDomain() []*domain.Task {
+	tasks := make([]*domain.Task, len(b.Tasks))
+	for i, t := range b.Tasks {
+		tasks[i] = t.ToDomain()
+	}
+	return tasks
+}
+
+// One task of a batch update, with the version it was read at (0 for none)
+type BatchUpdateTaskReq struct {
+	Id      string `json:"id" validate:"required,uuid_rfc4122"`
+	Version int    `json:"version" validate:"min=0"`
+	UpdateTaskReq
+}
+
+type BatchUpdateTasksReq struct {
+	Tasks []*BatchUpdateTaskReq `json:"tasks" validate:"required,min=1,max=100,dive,required"`
+}
+
+func (b *BatchUpdateTasksReq) ToDomain() []*domain.TaskBatchUpdate {
+	updates := make([]*domain.TaskBatchUpdate, len(b.Tasks))
+	for i, t := range b.Tasks {
+		patch := t.UpdateTaskReq.ToDomain()
+		patch.Version = t.Version
+		updates[i] = &domain.TaskBatchUpdate{Id: toTaskID(t.Id), Patch: patch}
+	}
+	return updates
+}
+
+// One task of a batch delete. Subtasks says what becomes of its subtasks,
+// as the subtasks query parameter of a single delete does
+type BatchDeleteTaskReq struct {
+	Id       string `json:"id" validate:"required,uuid_rfc4122"`
+	Version  int    `json:"version" validate:"min=0"`
+	Subtasks string `json:"subtasks" validate:"omitempty,oneof=cascade reparent"`
+}
+
+type BatchDeleteTasksReq struct {
+	Tasks []*BatchDeleteTaskReq `json:"tasks" validate:"required,min=1,max=100,dive,required"`
+}
+
+func (b *BatchDeleteTasksReq) ToDomain() []*domain.TaskBatchDelete {
+	deletes := make([]*domain.TaskBatchDelete, len(b.Tasks))
+	for i, t := range b.Tasks {
+		deletes[i] = &domain.TaskBatchDelete{
+			Id: toTaskID(t.Id), Version: t.Version, Subtasks: domain.SubtaskMode(t.Subtasks),
+		}
+	}
+	return deletes
+}
+
+// Convert an id the request was validated to hold as a UUID
+func toTaskID(id string) domain.TaskID {
+	taskId, _ := domain.ParseTaskID(id)
+	return taskId
+}
@@ -0,0 +1,35 @@
+package request
+
+import "github.com/takumi616/go-restapi/domain"
+
+// Items go at the end of the checklist unless a position, counting from 0, is given
+type AddChecklistItemReq struct {
+	Text     string `json:"text" validate:"required,max=200"`
+	Position *int   `json:"position" validate:"omitnil,min=0"`
+}
+
+// Only the fields present in the request body are updated
+type UpdateChecklistItemReq struct {
+	Text *string `json:"text" validate:"required_without=Done,omitnil,min=1,max=200"`
+	Done *bool   `json:"done"`
+}
+
+func (u *UpdateChecklistItemReq) ToDomain() *domain.ChecklistItemPatch {
+	return &domain.ChecklistItemPatch{
+		Text: u.Text,
+		Done: u.Done,
+	}
+}
+
+// Every item of the checklist, in the order they should be shown
+type ReorderChecklistReq struct {
+	Items []string `json:"items" validate:"required,max=50,unique,dive,uuid_rfc4122"`
+}
+
+func (o *ReorderChecklistReq) ToDomain() []domain.ChecklistItemID {
+	itemIds := make([]domain.ChecklistItemID, len(o.Items))
+	for i, id := range o.Items {
+		itemIds[i], _ = domain.ParseChecklistItemID(id)
+	}
+	return itemIds
+}
@@ -0,0 +1,13 @@
+package request
+
+import "github.com/takumi616/go-restapi/domain"
+
+// Task that the task in the path is to wait on
+type AddTaskDependencyReq struct {
+	BlockerId string `json:"blocker_id" validate:"required,task_id"`
+}
+
+func (a *AddTaskDependencyReq) ToDomain() domain.TaskID {
+	id, _ := domain.ParseTaskID(a.BlockerId)
+	return id
+}
@@ -0,0 +1,72 @@
+package request
+
+import (
+	"time"
+
+	"github.com/takumi616/go-restapi/domain"
+)
+
+// Patchable view of a task that merge patches and JSON patches are applied to.
+// Every field must survive the patch, so removing one is rejected
+type TaskDocument struct {
+	Title       *string `json:"title" validate:"required,min=1"`
+	Description *string `json:"description" validate:"required"`
+	Status      *bool   `json:"status" validate:"required"`
+	State       *string `json:"state" validate:"required,task_state"`
+	Priority    *string `json:"priority" validate:"required,task_priority"`
+	// Unlike the other fields, the due date, estimate and parent may be removed
+	DueAt         *string `json:"due_at" validate:"omitnil,rfc3339"`
+	EstimateHours *int    `json:"estimate_hours" validate:"omitnil,task_estimate"`
+	ParentId      *string `json:"parent_id" validate:"omitnil,task_id"`
+}
+
+func NewTaskDocument(task *domain.Task) *TaskDocument {
+	status := task.State.Done()
+	state := task.State.String()
+	priority := task.Priority.String()
+	doc := &TaskDocument{
+		Title:         &task.Title,
+		Description:   &task.Description,
+		Status:        &status,
+		State:         &state,
+		Priority:      &priority,
+		EstimateHours: task.EstimateHours,
+	}
+
+	if task.DueAt != nil {
+		dueAt := task.DueAt.UTC().Format(time.RFC3339)
+		doc.DueAt = &dueAt
+	}
+
+	if task.ParentId != nil {
+		parentId := task.ParentId.String()
+		doc.ParentId = &parentId
+	}
+
+	return doc
+}
+
+// A patch may change either the state or the boolean status of earlier
+// API versions. A status that no longer agrees with the state is the one
+// that was patched, so it decides the new state
+func (d *TaskDocument) ToDomain() *domain.TaskPatch {
+	state := domain.TaskState(*d.State)
+	if state.Done() != *d.Status {
+		state = stateOfStatus(*d.Status)
+	}
+
+	priority := domain.TaskPriority(*d.Priority)
+
+	return &domain.TaskPatch{
+		Title:         d.Title,
+		Description:   d.Description,
+		State:         &state,
+		Priority:      &priority,
+		DueAt:         parseOptionalTime(d.DueAt),
+		ClearDueAt:    d.DueAt == nil,
+		EstimateHours: d.EstimateHours,
+		ClearEstimate: d.EstimateHours == nil,
+		ParentId:      parseOptionalTaskID(d.ParentId),
+		ClearParent:   d.ParentId == nil,
+	}
+}
@@ -0,0 +1,142 @@
+package request
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/takumi616/go-restapi/domain"
+)
+
+const DefaultTaskListLimit = 20
+
+type GetTaskListReq struct {
+	Limit     int        `query:"limit" validate:"min=1,max=100"`
+	Offset    int        `query:"offset" validate:"min=0,excluded_with=Cursor"`
+	Status    *bool      `query:"status" validate:"excluded_with=State"`
+	State     string     `query:"state" validate:"omitempty,task_state"`
+	Title     string     `query:"title" validate:"max=30"`
+	DueBefore *time.Time `query:"due_before"`
+	DueAfter  *time.Time `query:"due_after"`
+	Overdue   *bool      `query:"overdue"`
+	Labels    []string   `query:"label" validate:"max=20,dive,min=1,max=30"`
+	LabelMode string     `query:"label_mode" validate:"omitempty,oneof=any all"`
+	Sort      string     `query:"sort" validate:"omitempty,oneof=id -id title -title status -status state -state priority -priority due_at -due_at created_at -created_at updated_at -updated_at"`
+	Cursor    string     `query:"cursor"`
+	// Set by the subtask list rather than from a query parameter
+	ParentId *domain.TaskID
+}
+
+// Build a request from query parameters, applying defaults for absent ones
+func NewGetTaskListReq(query url.Values) (*GetTaskListReq, error) {
+	req := &GetTaskListReq{
+		Limit:     DefaultTaskListLimit,
+		State:     query.Get("state"),
+		Title:     query.Get("title"),
+		Labels:    splitLabels(query["label"]),
+		LabelMode: query.Get("label_mode"),
+		Sort:      query.Get("sort"),
+		Cursor:    query.Get("cursor"),
+	}
+
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("limit must be an integer: '%s'", v)
+		}
+		req.Limit = limit
+	}
+
+	if v := query.Get("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("offset must be an integer: '%s'", v)
+		}
+		req.Offset = offset
+	}
+
+	if v := query.Get("status"); v != "" {
+		status, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("status must be a boolean: '%s'", v)
+		}
+		req.Status = &status
+	}
+
+	if v := query.Get("due_before"); v != "" {
+		dueBefore, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, fmt.Errorf("due_before must be an RFC 3339 time: '%s'", v)
+		}
+		req.DueBefore = &dueBefore
+	}
+
+	if v := query.Get("due_after"); v != "" {
+		dueAfter, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, fmt.Errorf("due_after must be an RFC 3339 time: '%s'", v)
+		}
+		req.DueAfter = &dueAfter
+	}
+
+	if v := query.Get("overdue"); v != "" {
+		overdue, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("overdue must be a boolean: '%s'", v)
+		}
+		req.Overdue = &overdue
+	}
+
+	return req, nil
+}
+
+// Labels may be given as a comma separated list, as repeated parameters or
+// both. Each name counts once
+func splitLabels(values []string) []string {
+	var labels []string
+	for _, value := range values {
+		for _, label := range strings.Split(value, ",") {
+			if !slices.Contains(labels, label) {
+				labels = append(labels, label)
+			}
+		}
+	}
+	return labels
+}
+
+func (g *GetTaskListReq) ToDomain() *domain.TaskListQuery {
+	query := &domain.TaskListQuery{
+		Limit:     g.Limit,
+		Offset:    g.Offset,
+		Status:    g.Status,
+		Title:     g.Title,
+		DueBefore: g.DueBefore,
+		DueAfter:  g.DueAfter,
+		Overdue:   g.Overdue,
+		ParentId:  g.ParentId,
+		SortField: domain.TaskSortByTitle,
+	}
+
+	if g.State != "" {
+		state := domain.TaskState(g.State)
+		query.State = &state
+	}
+
+	if len(g.Labels) > 0 {
+		query.Labels = g.Labels
+		query.LabelMode = domain.TaskLabelModeAny
+		if g.LabelMode != "" {
+			query.LabelMode = domain.TaskLabelMode(g.LabelMode)
+		}
+	}
+
+	if g.Sort != "" {
+		query.SortDesc = strings.HasPrefix(g.Sort, "-")
+		query.SortField = domain.TaskSortField(strings.TrimPrefix(g.Sort, "-"))
+	}
+
+	return query
+}
@@ -0,0 +1,12 @@
+package request
+
+import "github.com/takumi616/go-restapi/domain"
+
+// Move of a task to another state of its workflow
+type TransitionTaskReq struct {
+	To string `json:"to" validate:"required,task_state"`
+}
+
+func (t *TransitionTaskReq) ToDomain() domain.TaskState {
+	return domain.TaskState(t.To)
+}
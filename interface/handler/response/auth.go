@@ -0,0 +1,40 @@
+package response
+
+import "github.com/takumi616/go-restapi/domain"
+
+// A user as shown to clients, without anything about their password
+type UserRes struct {
+	Id        string `json:"id"`
+	Email     string `json:"email"`
+	Role      string `json:"role"`
+	CreatedAt string `json:"created_at"`
+}
+
+func ToUserRes(user *domain.User) *UserRes {
+	return &UserRes{
+		Id:        user.Id.String(),
+		Email:     user.Email,
+		Role:      user.Role.String(),
+		CreatedAt: formatTime(user.CreatedAt),
+	}
+}
+
+type SessionRes struct {
+	AccessToken           string   `json:"access_token"`
+	TokenType             string   `json:"token_type"`
+	ExpiresAt             string   `json:"expires_at"`
+	RefreshToken          string   `json:"refresh_token"`
+	RefreshTokenExpiresAt string   `json:"refresh_token_expires_at"`
+	User                  *UserRes `json:"user"`
+}
+
+func ToSessionRes(session *domain.Session) *SessionRes {
+	return &SessionRes{
+		AccessToken:           session.AccessToken,
+		TokenType:             "Bearer",
+		ExpiresAt:             formatTime(session.AccessTokenExpiresAt),
+		RefreshToken:          session.RefreshToken,
+		RefreshTokenExpiresAt: formatTime(session.RefreshTokenExpiresAt),
+		User:                  ToUserRes(session.User),
+	}
+}
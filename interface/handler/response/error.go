@@ -1,6 +0,0 @@
-package response
-
-type ErrResponse struct {
-	Message string   `json:"message"`
-	Details []string `json:"details,omitempty"`
-}
@@ -0,0 +1,50 @@
+package response
+
+import "github.com/takumi616/go-restapi/domain"
+
+type LabelRes struct {
+	Id        string `json:"id"`
+	Name      string `json:"name"`
+	Color     string `json:"color"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+func ToLabelRes(label *domain.Label) *LabelRes {
+	return &LabelRes{
+		Id:        label.Id.String(),
+		Name:      label.Name,
+		Color:     label.Color,
+		CreatedAt: formatTime(label.CreatedAt),
+		UpdatedAt: formatTime(label.UpdatedAt),
+	}
+}
+
+// A label as shown on the tasks that carry it
+type TaskLabelRes struct {
+	Id    string `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+func toTaskLabelResList(labels []*domain.Label) []*TaskLabelRes {
+	labelResList := []*TaskLabelRes{}
+	for _, label := range labels {
+		labelResList = append(labelResList, &TaskLabelRes{
+			Id:    label.Id.String(),
+			Name:  label.Name,
+			Color: label.Color,
+		})
+	}
+	return labelResList
+}
+
+type LabelIdRes struct {
+	Id string `json:"id"`
+}
+
+func ToLabelIdRes(label *domain.Label) *LabelIdRes {
+	return &LabelIdRes{
+		label.Id.String(),
+	}
+}
@@ -0,0 +1,42 @@
+package response
+
+import "github.com/takumi616/go-restapi/domain"
+
+// A personal access token as listed, without the token itself
+type PersonalAccessTokenRes struct {
+	Id         string   `json:"id"`
+	Name       string   `json:"name"`
+	Scopes     []string `json:"scopes"`
+	ExpiresAt  string   `json:"expires_at"`
+	LastUsedAt *string  `json:"last_used_at"`
+	CreatedAt  string   `json:"created_at"`
+}
+
+func ToPersonalAccessTokenRes(token *domain.PersonalAccessToken) *PersonalAccessTokenRes {
+	scopes := make([]string, len(token.Scopes))
+	for i, scope := range token.Scopes {
+		scopes[i] = string(scope)
+	}
+
+	return &PersonalAccessTokenRes{
+		Id:         token.Id.String(),
+		Name:       token.Name,
+		Scopes:     scopes,
+		ExpiresAt:  formatTime(token.ExpiresAt),
+		LastUsedAt: formatOptionalTime(token.LastUsedAt),
+		CreatedAt:  formatTime(token.CreatedAt),
+	}
+}
+
+// A token just created, the only response that carries the token itself
+type CreatedPersonalAccessTokenRes struct {
+	*PersonalAccessTokenRes
+	Token string `json:"token"`
+}
+
+func ToCreatedPersonalAccessTokenRes(stored *domain.PersonalAccessToken, token string) *CreatedPersonalAccessTokenRes {
+	return &CreatedPersonalAccessTokenRes{
+		PersonalAccessTokenRes: ToPersonalAccessTokenRes(stored),
+		Token:                  token,
+	}
+}
@@ -0,0 +1,22 @@
+package response
+
+// Problem is an RFC 7807 problem details object
+type Problem struct {
+	Type     string          `json:"type"`
+	Title    string          `json:"title"`
+	Status   int             `json:"status"`
+	Detail   string          `json:"detail,omitempty"`
+	Instance string          `json:"instance,omitempty"`
+	Errors   []*FieldProblem `json:"errors,omitempty"`
+	// States a task could have moved to instead of the one requested
+	AllowedStates []string `json:"allowed_states,omitempty"`
+	// Open tasks that keep a task from being completed
+	BlockedBy []string `json:"blocked_by,omitempty"`
+}
+
+// A request field that failed, with the rule it broke
+type FieldProblem struct {
+	Field string `json:"field"`
+	Rule  string `json:"rule"`
+	Param string `json:"param,omitempty"`
+}
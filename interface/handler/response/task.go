@@ -1,18 +1,116 @@
 package response
 
-import "github.com/takumi616/go-restapi/domain"
+import (
+	"time"
+
+	"github.com/takumi616/go-restapi/domain"
+)
 
 type TaskRes struct {
-	Id          string `json:"id"`
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	Status      bool   `json:"status"`
+	Id            string            `json:"id"`
+	Title         string            `json:"title"`
+	Description   string            `json:"description"`
+	Status        bool              `json:"status"`
+	State         string            `json:"state"`
+	Priority      string            `json:"priority"`
+	DueAt         *string           `json:"due_at,omitempty"`
+	EstimateHours *int              `json:"estimate_hours,omitempty"`
+	ParentId      *string           `json:"parent_id,omitempty"`
+	Labels        []*TaskLabelRes   `json:"labels"`
+	Blocked       bool              `json:"blocked"`
+	BlockedBy     []*TaskBlockerRes `json:"blocked_by"`
+	Progress      *TaskProgressRes  `json:"progress,omitempty"`
+	// Only tasks with a checklist have checklist progress
+	ChecklistProgress *ChecklistProgressRes `json:"checklist_progress,omitempty"`
+	CreatedAt         string                `json:"created_at"`
+	UpdatedAt         string                `json:"updated_at"`
+	CompletedAt       *string               `json:"completed_at,omitempty"`
+	DeletedAt         *string               `json:"deleted_at,omitempty"`
+	// Only filled in when the subtree is expanded
+	Subtasks []*TaskRes `json:"subtasks,omitempty"`
 }
 
 func ToTaskRes(task *domain.Task) *TaskRes {
-	return &TaskRes{
-		task.Id, task.Title, task.Description, task.Status,
+	res := &TaskRes{
+		Id:            task.Id.String(),
+		Title:         task.Title,
+		Description:   task.Description,
+		Status:        task.State.Done(),
+		State:         task.State.String(),
+		Priority:      task.Priority.String(),
+		EstimateHours: task.EstimateHours,
+		Labels:        toTaskLabelResList(task.Labels),
+		Blocked:       task.Blocked(),
+		BlockedBy:     toTaskBlockerResList(task.BlockedBy),
+		CreatedAt:     formatTime(task.CreatedAt),
+		UpdatedAt:     formatTime(task.UpdatedAt),
+	}
+
+	res.DueAt = formatOptionalTime(task.DueAt)
+	res.CompletedAt = formatOptionalTime(task.CompletedAt)
+	res.DeletedAt = formatOptionalTime(task.DeletedAt)
+
+	if task.ParentId != nil {
+		parentId := task.ParentId.String()
+		res.ParentId = &parentId
+	}
+
+	// Only tasks with subtasks have progress
+	if task.Progress.Total > 0 {
+		res.Progress = &TaskProgressRes{
+			Completed: task.Progress.Completed,
+			Total:     task.Progress.Total,
+		}
+	}
+
+	if task.ChecklistProgress.Total > 0 {
+		res.ChecklistProgress = toChecklistProgressRes(task.ChecklistProgress)
+	}
+
+	for _, subtask := range task.Subtasks {
+		res.Subtasks = append(res.Subtasks, ToTaskRes(subtask))
+	}
+
+	return res
+}
+
+// Done subtasks out of the live direct subtasks of a task
+type TaskProgressRes struct {
+	Completed int `json:"completed"`
+	Total     int `json:"total"`
+}
+
+// A task waited on, finished or not
+type TaskBlockerRes struct {
+	Id    string `json:"id"`
+	Title string `json:"title"`
+	State string `json:"state"`
+}
+
+func toTaskBlockerResList(blockers []*domain.TaskBlocker) []*TaskBlockerRes {
+	blockerResList := []*TaskBlockerRes{}
+	for _, blocker := range blockers {
+		blockerResList = append(blockerResList, &TaskBlockerRes{
+			Id:    blocker.Id.String(),
+			Title: blocker.Title,
+			State: blocker.State.String(),
+		})
 	}
+	return blockerResList
+}
+
+// Timestamps are always rendered in UTC as RFC 3339
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+func formatOptionalTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+
+	formatted := formatTime(*t)
+	return &formatted
 }
 
 type TaskIdRes struct {
@@ -21,6 +119,6 @@ type TaskIdRes struct {
 
 func ToTaskIdRes(task *domain.Task) *TaskIdRes {
 	return &TaskIdRes{
-		task.Id,
+		task.Id.String(),
 	}
 }
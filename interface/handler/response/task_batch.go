@@ -0,0 +1,17 @@
+package response
+
+// Per-item outcome of a batch write, in request order.
+// Task holds the written task and Error why the item was not written
+type TaskBatchItemRes struct {
+	Index  int      `json:"index"`
+	Status int      `json:"status"`
+	Task   any      `json:"task,omitempty"`
+	Error  *Problem `json:"error,omitempty"`
+}
+
+type TaskBatchRes struct {
+	Atomic    bool                `json:"atomic"`
+	Succeeded int                 `json:"succeeded"`
+	Failed    int                 `json:"failed"`
+	Results   []*TaskBatchItemRes `json:"results"`
+}
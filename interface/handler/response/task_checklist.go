@@ -0,0 +1,51 @@
+package response
+
+import "github.com/takumi616/go-restapi/domain"
+
+type ChecklistRes struct {
+	TaskId   string                `json:"task_id"`
+	Items    []*ChecklistItemRes   `json:"items"`
+	Progress *ChecklistProgressRes `json:"progress"`
+}
+
+func ToChecklistRes(checklist *domain.Checklist) *ChecklistRes {
+	items := []*ChecklistItemRes{}
+	for _, item := range checklist.Items {
+		items = append(items, &ChecklistItemRes{
+			Id:        item.Id.String(),
+			Text:      item.Text,
+			Done:      item.Done,
+			Position:  item.Position,
+			CreatedAt: formatTime(item.CreatedAt),
+			UpdatedAt: formatTime(item.UpdatedAt),
+		})
+	}
+
+	return &ChecklistRes{
+		TaskId:   checklist.TaskId.String(),
+		Items:    items,
+		Progress: toChecklistProgressRes(checklist.Progress()),
+	}
+}
+
+type ChecklistItemRes struct {
+	Id        string `json:"id"`
+	Text      string `json:"text"`
+	Done      bool   `json:"done"`
+	Position  int    `json:"position"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// Done items out of all the items of a checklist
+type ChecklistProgressRes struct {
+	Completed int `json:"completed"`
+	Total     int `json:"total"`
+}
+
+func toChecklistProgressRes(progress domain.ChecklistProgress) *ChecklistProgressRes {
+	return &ChecklistProgressRes{
+		Completed: progress.Completed,
+		Total:     progress.Total,
+	}
+}
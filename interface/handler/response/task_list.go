@@ -0,0 +1,77 @@
+package response
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/takumi616/go-restapi/domain"
+)
+
+type TaskListRes struct {
+	Tasks      []*TaskRes `json:"tasks"`
+	Total      *int       `json:"total,omitempty"`
+	Limit      int        `json:"limit"`
+	Offset     *int       `json:"offset,omitempty"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+	Links      PageLinks  `json:"links"`
+}
+
+type PageLinks struct {
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
+}
+
+// Convert a page of tasks into a response, deriving next/prev links
+// from the requested url so that other query parameters are kept.
+// Pages fetched by cursor carry no total or offset, since counting
+// rows is exactly what keyset pagination avoids
+func ToTaskListRes(taskList *domain.TaskList, query *domain.TaskListQuery, reqURL *url.URL, nextCursor string) *TaskListRes {
+	taskResList := []*TaskRes{}
+	for _, task := range taskList.Tasks {
+		taskResList = append(taskResList, ToTaskRes(task))
+	}
+
+	res := &TaskListRes{
+		Tasks:      taskResList,
+		Limit:      query.Limit,
+		NextCursor: nextCursor,
+	}
+
+	if query.After != nil {
+		if nextCursor != "" {
+			res.Links.Next = cursorLink(reqURL, query.Limit, nextCursor)
+		}
+		return res
+	}
+
+	res.Total = &taskList.Total
+	res.Offset = &query.Offset
+
+	if query.Offset+query.Limit < taskList.Total {
+		res.Links.Next = pageLink(reqURL, query.Limit, query.Offset+query.Limit)
+	}
+
+	if query.Offset > 0 {
+		res.Links.Prev = pageLink(reqURL, query.Limit, max(query.Offset-query.Limit, 0))
+	}
+
+	return res
+}
+
+func pageLink(reqURL *url.URL, limit, offset int) string {
+	values := reqURL.Query()
+	values.Set("limit", strconv.Itoa(limit))
+	values.Set("offset", strconv.Itoa(offset))
+
+	link := url.URL{Path: reqURL.Path, RawQuery: values.Encode()}
+	return link.String()
+}
+
+func cursorLink(reqURL *url.URL, limit int, cursor string) string {
+	values := reqURL.Query()
+	values.Set("limit", strconv.Itoa(limit))
+	values.Set("cursor", cursor)
+
+	link := url.URL{Path: reqURL.Path, RawQuery: values.Encode()}
+	return link.String()
+}
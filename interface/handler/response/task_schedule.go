@@ -0,0 +1,59 @@
+package response
+
+import "github.com/takumi616/go-restapi/domain"
+
+// Plan of the open tasks. Times are in hours from the start of the plan
+type TaskScheduleRes struct {
+	DurationHours int                    `json:"duration_hours"`
+	CriticalPath  []string               `json:"critical_path"`
+	Tasks         []*TaskScheduleItemRes `json:"tasks"`
+}
+
+// A planned task, listed after every task it depends on
+type TaskScheduleItemRes struct {
+	Id             string   `json:"id"`
+	Title          string   `json:"title"`
+	State          string   `json:"state"`
+	EstimateHours  *int     `json:"estimate_hours,omitempty"`
+	DependsOn      []string `json:"depends_on"`
+	EarliestStart  int      `json:"earliest_start"`
+	EarliestFinish int      `json:"earliest_finish"`
+	LatestStart    int      `json:"latest_start"`
+	LatestFinish   int      `json:"latest_finish"`
+	Slack          int      `json:"slack"`
+	Critical       bool     `json:"critical"`
+}
+
+func ToTaskScheduleRes(schedule *domain.TaskSchedule) *TaskScheduleRes {
+	res := &TaskScheduleRes{
+		DurationHours: schedule.Duration,
+		CriticalPath:  toTaskIdList(schedule.CriticalPath),
+		Tasks:         []*TaskScheduleItemRes{},
+	}
+
+	for _, item := range schedule.Items {
+		res.Tasks = append(res.Tasks, &TaskScheduleItemRes{
+			Id:             item.Task.Id.String(),
+			Title:          item.Task.Title,
+			State:          item.Task.State.String(),
+			EstimateHours:  item.Task.EstimateHours,
+			DependsOn:      toTaskIdList(item.DependsOn),
+			EarliestStart:  item.EarliestStart,
+			EarliestFinish: item.EarliestFinish,
+			LatestStart:    item.LatestStart,
+			LatestFinish:   item.LatestFinish,
+			Slack:          item.Slack(),
+			Critical:       item.Critical(),
+		})
+	}
+
+	return res
+}
+
+func toTaskIdList(ids []domain.TaskID) []string {
+	list := []string{}
+	for _, id := range ids {
+		list = append(list, id.String())
+	}
+	return list
+}
@@ -1,13 +1,19 @@
 package handler
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"strconv"
+	"strings"
 
-	"github.com/go-playground/validator/v10"
+	"github.com/takumi616/go-restapi/domain"
+	"github.com/takumi616/go-restapi/interface/handler/cursor"
 	"github.com/takumi616/go-restapi/interface/handler/helper"
+	"github.com/takumi616/go-restapi/interface/handler/patch"
 	"github.com/takumi616/go-restapi/interface/handler/request"
 	"github.com/takumi616/go-restapi/interface/handler/response"
 	customError "github.com/takumi616/go-restapi/shared/error"
@@ -15,11 +21,13 @@ import (
 
 type TaskHandler struct {
 	usecase TaskUsecase
+	cursor  *cursor.Codec
 }
 
-func NewTaskHandler(usecase TaskUsecase) *TaskHandler {
+func NewTaskHandler(usecase TaskUsecase, cursor *cursor.Codec) *TaskHandler {
 	return &TaskHandler{
 		usecase: usecase,
+		cursor:  cursor,
 	}
 }
 
@@ -27,23 +35,15 @@ func (h *TaskHandler) AddTask(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var req request.AddTaskReq
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := helper.DecodeJSON(w, r, &req); err != nil {
 		slog.ErrorContext(ctx, err.Error())
-		helper.WriteResponse(
-			ctx, w, http.StatusInternalServerError,
-			response.ErrResponse{Message: customError.InvalidRequestFormat.Error()},
-		)
+		helper.WriteProblem(ctx, w, r, err)
 		return
 	}
-	defer r.Body.Close()
 
-	err := validator.New().Struct(req)
-	if err != nil {
+	if err := helper.Validate(req, customError.TaskBadRequest); err != nil {
 		slog.ErrorContext(ctx, err.Error())
-		helper.WriteResponse(
-			ctx, w, http.StatusBadRequest,
-			response.ErrResponse{Message: customError.TaskBadRequest.Error()},
-		)
+		helper.WriteProblem(ctx, w, r, err)
 		return
 	}
 
@@ -51,13 +51,11 @@ func (h *TaskHandler) AddTask(w http.ResponseWriter, r *http.Request) {
 
 	added, err := h.usecase.AddTask(ctx, task)
 	if err != nil {
-		helper.WriteResponse(
-			ctx, w, http.StatusInternalServerError,
-			response.ErrResponse{Message: err.Error()},
-		)
+		helper.WriteProblem(ctx, w, r, err)
 		return
 	}
 
+	helper.SetETag(w, added.Version)
 	helper.WriteResponse(
 		ctx, w, http.StatusCreated,
 		response.ToTaskRes(added),
@@ -65,119 +63,470 @@ func (h *TaskHandler) AddTask(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *TaskHandler) GetTaskList(w http.ResponseWriter, r *http.Request) {
+	h.listTasks(w, r, nil)
+}
+
+// Accepts the same query parameters as GetTaskList, but only lists tasks
+// that are past due and unfinished, by default the longest overdue first
+func (h *TaskHandler) GetOverdueTasks(w http.ResponseWriter, r *http.Request) {
+	h.listTasks(w, r, func(req *request.GetTaskListReq) {
+		overdue := true
+		req.Overdue = &overdue
+		if req.Sort == "" {
+			req.Sort = string(domain.TaskSortByDueAt)
+		}
+	})
+}
+
+// Accepts the same query parameters as GetTaskList, but only lists the
+// direct subtasks of a task
+func (h *TaskHandler) GetSubtasks(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	taskList, err := h.usecase.GetTaskList(ctx)
+	id, err := domain.ParseTaskID(r.PathValue("id"))
 	if err != nil {
-		helper.WriteResponse(
-			ctx, w, http.StatusInternalServerError,
-			response.ErrResponse{Message: err.Error()},
-		)
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
 		return
 	}
 
-	taskResList := []*response.TaskRes{}
-	for _, task := range taskList {
-		taskResList = append(taskResList, response.ToTaskRes(task))
+	h.listTasks(w, r, func(req *request.GetTaskListReq) {
+		req.ParentId = &id
+	})
+}
+
+// Lists tasks by the query parameters, after scope narrows the request down
+func (h *TaskHandler) listTasks(w http.ResponseWriter, r *http.Request, scope func(*request.GetTaskListReq)) {
+	ctx := r.Context()
+
+	req, err := request.NewGetTaskListReq(r.URL.Query())
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, fmt.Errorf("%w: %w", customError.InvalidQueryParameter, err))
+		return
 	}
 
-	helper.WriteResponse(ctx, w, http.StatusOK, taskResList)
+	if scope != nil {
+		scope(req)
+	}
+
+	if err := helper.Validate(req, customError.InvalidQueryParameter); err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	query := req.ToDomain()
+
+	if req.Cursor != "" {
+		after, err := h.cursor.Decode(req.Cursor)
+		if err == nil && (after.SortField != query.SortField || after.SortDesc != query.SortDesc) {
+			err = cursor.ErrSortMismatch
+		}
+
+		if err != nil {
+			slog.ErrorContext(ctx, err.Error())
+			helper.WriteProblem(ctx, w, r, fmt.Errorf("%w: %w", customError.InvalidQueryParameter, err))
+			return
+		}
+
+		query.After = after
+	}
+
+	taskList, err := h.usecase.GetTaskList(ctx, query)
+	if err != nil {
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	var nextCursor string
+	if taskList.NextCursor != nil {
+		nextCursor = h.cursor.Encode(taskList.NextCursor)
+	}
+
+	res := response.ToTaskListRes(taskList, query, r.URL, nextCursor)
+
+	// Pages fetched by cursor are not counted, so they carry no validator
+	if query.After != nil {
+		helper.WriteResponse(ctx, w, http.StatusOK, res)
+		return
+	}
+
+	helper.WriteCacheableResponse(
+		ctx, w, r, http.StatusOK,
+		res, helper.TaskListValidators(taskList.Total, taskList.LastModified),
+	)
 }
 
+// GetTaskById reads a task. With ?expand=subtasks the whole live subtree
+// below it is nested in the response
 func (h *TaskHandler) GetTaskById(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	id := r.PathValue("id")
+	id, err := domain.ParseTaskID(r.PathValue("id"))
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	switch expand := r.URL.Query().Get("expand"); expand {
+	case "":
+	case "subtasks":
+		h.getTaskTree(w, r, id)
+		return
+	default:
+		err := fmt.Errorf("%w: expand must be subtasks: '%s'", customError.InvalidQueryParameter, expand)
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
 	task, err := h.usecase.GetTaskById(ctx, id)
 	if err != nil {
-		if errors.Is(err, customError.ErrTaskNotFound) {
-			helper.WriteResponse(
-				ctx, w, http.StatusNotFound,
-				response.ErrResponse{Message: err.Error()},
-			)
-		} else {
-			helper.WriteResponse(
-				ctx, w, http.StatusInternalServerError,
-				response.ErrResponse{Message: err.Error()},
-			)
-		}
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	helper.WriteCacheableResponse(
+		ctx, w, r, http.StatusOK,
+		response.ToTaskRes(task), helper.TaskValidators(task.Version, task.UpdatedAt),
+	)
+}
+
+// A tree spans many tasks, and the version of its root does not change
+// when a task further down does, so it carries no validator
+func (h *TaskHandler) getTaskTree(w http.ResponseWriter, r *http.Request, id domain.TaskID) {
+	ctx := r.Context()
 
+	tree, err := h.usecase.GetTaskTree(ctx, id)
+	if err != nil {
+		helper.WriteProblem(ctx, w, r, err)
 		return
 	}
 
-	helper.WriteResponse(ctx, w, http.StatusOK, response.ToTaskRes(task))
+	helper.WriteResponse(ctx, w, http.StatusOK, response.ToTaskRes(tree))
 }
 
+// Patch formats UpdateTask accepts, advertised when another one is sent
+var acceptedPatchTypes = []string{"application/json", patch.MergePatchType, patch.JSONPatchType}
+
+// UpdateTask partially updates a task. Plain JSON bodies set the fields
+// they contain, while merge patches and JSON patches are applied to the
+// current task and the result is written back only if it is still current.
+// A task waiting on open tasks is only completed with ?force=true
 func (h *TaskHandler) UpdateTask(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	id := r.PathValue("id")
-	var req request.UpdateTaskReq
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	id, err := domain.ParseTaskID(r.PathValue("id"))
+	if err != nil {
 		slog.ErrorContext(ctx, err.Error())
-		helper.WriteResponse(
-			ctx, w, http.StatusInternalServerError,
-			response.ErrResponse{Message: customError.InvalidRequestFormat.Error()},
-		)
+		helper.WriteProblem(ctx, w, r, err)
 		return
 	}
-	defer r.Body.Close()
 
-	err := validator.New().Struct(req)
+	version, err := helper.ParseIfMatch(r)
 	if err != nil {
-		slog.ErrorContext(ctx, err.Error())
-		helper.WriteResponse(
-			ctx, w, http.StatusBadRequest,
-			response.ErrResponse{Message: customError.TaskBadRequest.Error()},
-		)
+		helper.WriteProblem(ctx, w, r, err)
 		return
 	}
 
-	task := (&req).ToDomain()
+	force, ok := parseBoolParam(w, r, "force", false)
+	if !ok {
+		return
+	}
 
-	updated, err := h.usecase.UpdateTask(ctx, id, task)
-	if err != nil {
-		if errors.Is(err, customError.ErrTaskNotFound) {
-			helper.WriteResponse(
-				ctx, w, http.StatusNotFound,
-				response.ErrResponse{Message: err.Error()},
-			)
-		} else {
-			helper.WriteResponse(
-				ctx, w, http.StatusInternalServerError,
-				response.ErrResponse{Message: err.Error()},
-			)
-		}
+	var taskPatch *domain.TaskPatch
+	switch mediaType := helper.MediaType(r); mediaType {
+	case "application/json":
+		taskPatch, err = decodeTaskPatch(w, r)
+	case patch.MergePatchType, patch.JSONPatchType:
+		taskPatch, err = h.applyTaskPatch(w, r, id, mediaType, version)
+	default:
+		w.Header().Set("Accept-Patch", strings.Join(acceptedPatchTypes, ", "))
+		err = fmt.Errorf("%w: accepted types are %s", customError.UnsupportedMediaType, strings.Join(acceptedPatchTypes, ", "))
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
 
+	if version != 0 {
+		taskPatch.Version = version
+	}
+	taskPatch.Force = force
+
+	updated, err := h.usecase.UpdateTask(ctx, id, taskPatch)
+	if err != nil {
+		helper.WriteProblem(ctx, w, r, unlessConditional(err, version))
 		return
 	}
 
+	helper.SetETag(w, updated.Version)
 	helper.WriteResponse(
 		ctx, w, http.StatusOK,
 		response.ToTaskRes(updated),
 	)
 }
 
+// Read a boolean query parameter, which is fallback when not given.
+// A problem has been written when it is not a boolean
+func parseBoolParam(w http.ResponseWriter, r *http.Request, name string, fallback bool) (bool, bool) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return fallback, true
+	}
+
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		slog.ErrorContext(r.Context(), err.Error())
+		helper.WriteProblem(r.Context(), w, r, fmt.Errorf("%w: %s must be a boolean: '%s'", customError.InvalidQueryParameter, name, v))
+		return false, false
+	}
+
+	return b, true
+}
+
+// Without If-Match the client asked for no precondition, so a concurrent
+// change to the task being written is a conflict rather than a failed one
+func unlessConditional(err error, version int) error {
+	if errors.Is(err, customError.ErrTaskVersionMismatch) && version == 0 {
+		return customError.ErrTaskConcurrentUpdate
+	}
+	return err
+}
+
+// TransitionTask moves a task to another state of its workflow. Moves the
+// workflow does not allow are refused with the states that are allowed, and
+// a task waiting on open tasks is only moved to done with ?force=true
+func (h *TaskHandler) TransitionTask(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := domain.ParseTaskID(r.PathValue("id"))
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	version, err := helper.ParseIfMatch(r)
+	if err != nil {
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	force, ok := parseBoolParam(w, r, "force", false)
+	if !ok {
+		return
+	}
+
+	var req request.TransitionTaskReq
+	if err := helper.DecodeJSON(w, r, &req); err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	if err := helper.Validate(req, customError.TaskBadRequest); err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	to := req.ToDomain()
+	updated, err := h.usecase.UpdateTask(ctx, id, &domain.TaskPatch{State: &to, Force: force, Version: version})
+	if err != nil {
+		helper.WriteProblem(ctx, w, r, unlessConditional(err, version))
+		return
+	}
+
+	helper.SetETag(w, updated.Version)
+	helper.WriteResponse(ctx, w, http.StatusOK, response.ToTaskRes(updated))
+}
+
+// SetTaskLabels replaces the labels of a task with the ones listed
+func (h *TaskHandler) SetTaskLabels(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := domain.ParseTaskID(r.PathValue("id"))
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	version, err := helper.ParseIfMatch(r)
+	if err != nil {
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	var req request.SetTaskLabelsReq
+	if err := helper.DecodeJSON(w, r, &req); err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	if err := helper.Validate(req, customError.TaskBadRequest); err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	updated, err := h.usecase.SetTaskLabels(ctx, id, req.ToDomain(), version)
+	if err != nil {
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	helper.SetETag(w, updated.Version)
+	helper.WriteResponse(ctx, w, http.StatusOK, response.ToTaskRes(updated))
+}
+
+// Read a plain JSON partial update
+func decodeTaskPatch(w http.ResponseWriter, r *http.Request) (*domain.TaskPatch, error) {
+	var req request.UpdateTaskReq
+	if err := helper.DecodeJSON(w, r, &req); err != nil {
+		return nil, err
+	}
+
+	if err := helper.Validate(req, customError.TaskBadRequest); err != nil {
+		return nil, err
+	}
+
+	return (&req).ToDomain(), nil
+}
+
+// Apply a merge patch or JSON patch to the current task. The returned patch
+// carries the version the task was read at, so that it is only written if
+// nothing changed in between
+func (h *TaskHandler) applyTaskPatch(w http.ResponseWriter, r *http.Request, id domain.TaskID, mediaType string, version int) (*domain.TaskPatch, error) {
+	body, err := helper.ReadBody(w, r)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := h.usecase.GetTaskById(r.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+
+	if version != 0 && version != current.Version {
+		return nil, customError.ErrTaskVersionMismatch
+	}
+
+	doc, _ := json.Marshal(request.NewTaskDocument(current))
+
+	var patched []byte
+	if mediaType == patch.MergePatchType {
+		patched, err = patch.ApplyMergePatch(doc, body)
+	} else {
+		patched, err = patch.ApplyJSONPatch(doc, body)
+	}
+	if errors.Is(err, patch.ErrPatchConflict) {
+		return nil, fmt.Errorf("%w: %w", customError.PatchConflict, err)
+	} else if err != nil {
+		return nil, fmt.Errorf("%w: %w", customError.InvalidPatchDocument, err)
+	}
+
+	// The patched document must still be a valid task with no extra members
+	var result request.TaskDocument
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&result); err != nil {
+		return nil, fmt.Errorf("%w: %w", customError.UnprocessablePatch, err)
+	}
+
+	if err := helper.Validate(result, customError.UnprocessablePatch); err != nil {
+		return nil, err
+	}
+
+	taskPatch := (&result).ToDomain()
+	taskPatch.Version = current.Version
+	return taskPatch, nil
+}
+
+// DeleteTask moves a task to the trash, or removes it for good with ?purge=true.
+// A task with subtasks is only deleted with ?subtasks=cascade, which deletes
+// them too, or ?subtasks=reparent, which moves them up to the task's parent
 func (h *TaskHandler) DeleteTask(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	id := r.PathValue("id")
-	deleted, err := h.usecase.DeleteTask(ctx, id)
-	if err != nil {
-		if errors.Is(err, customError.ErrTaskNotFound) {
-			helper.WriteResponse(
-				ctx, w, http.StatusNotFound,
-				response.ErrResponse{Message: err.Error()},
-			)
-		} else {
-			helper.WriteResponse(
-				ctx, w, http.StatusInternalServerError,
-				response.ErrResponse{Message: err.Error()},
-			)
-		}
+	id, err := domain.ParseTaskID(r.PathValue("id"))
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
 
+	purge, ok := parseBoolParam(w, r, "purge", false)
+	if !ok {
+		return
+	}
+
+	mode, err := domain.ParseSubtaskMode(r.URL.Query().Get("subtasks"))
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, fmt.Errorf("%w: %w", customError.InvalidQueryParameter, err))
+		return
+	}
+
+	version, err := helper.ParseIfMatch(r)
+	if err != nil {
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	var deleted *domain.Task
+	if purge {
+		deleted, err = h.usecase.PurgeTask(ctx, id, version, mode)
+	} else {
+		deleted, err = h.usecase.DeleteTask(ctx, id, version, mode)
+	}
+
+	if err != nil {
+		helper.WriteProblem(ctx, w, r, err)
 		return
 	}
 
 	helper.WriteResponse(ctx, w, http.StatusOK, response.ToTaskIdRes(deleted))
 }
+
+func (h *TaskHandler) GetTrash(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	taskList, err := h.usecase.GetTrash(ctx)
+	if err != nil {
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	taskResList := []*response.TaskRes{}
+	for _, task := range taskList {
+		taskResList = append(taskResList, response.ToTaskRes(task))
+	}
+
+	helper.WriteResponse(ctx, w, http.StatusOK, taskResList)
+}
+
+func (h *TaskHandler) RestoreTask(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := domain.ParseTaskID(r.PathValue("id"))
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	restored, err := h.usecase.RestoreTask(ctx, id)
+	if err != nil {
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	helper.SetETag(w, restored.Version)
+	helper.WriteResponse(ctx, w, http.StatusOK, response.ToTaskRes(restored))
+}
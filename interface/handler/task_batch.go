@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/takumi616/go-restapi/domain"
+	"github.com/takumi616/go-restapi/interface/handler/helper"
+	"github.com/takumi616/go-restapi/interface/handler/request"
+	"github.com/takumi616/go-restapi/interface/handler/response"
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+// AddTasks creates up to domain.MaxTaskBatchSize tasks in one transaction
+func (h *TaskHandler) AddTasks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	atomic, ok := parseAtomic(w, r)
+	if !ok {
+		return
+	}
+
+	var req request.BatchAddTasksReq
+	if !decodeBatchReq(w, r, &req) {
+		return
+	}
+
+	results, err := h.usecase.AddTasks(ctx, (&req).ToDomain(), atomic)
+	if err != nil {
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	writeTaskBatchResponse(w, r, results, atomic, http.StatusCreated, func(task *domain.Task) any {
+		return response.ToTaskRes(task)
+	})
+}
+
+// UpdateTasks partially updates up to domain.MaxTaskBatchSize tasks in one transaction
+func (h *TaskHandler) UpdateTasks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	atomic, ok := parseAtomic(w, r)
+	if !ok {
+		return
+	}
+
+	force, ok := parseBoolParam(w, r, "force", false)
+	if !ok {
+		return
+	}
+
+	var req request.BatchUpdateTasksReq
+	if !decodeBatchReq(w, r, &req) {
+		return
+	}
+
+	updates := (&req).ToDomain()
+	for _, update := range updates {
+		update.Patch.Force = force
+	}
+
+	results, err := h.usecase.UpdateTasks(ctx, updates, atomic)
+	if err != nil {
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	writeTaskBatchResponse(w, r, results, atomic, http.StatusOK, func(task *domain.Task) any {
+		return response.ToTaskRes(task)
+	})
+}
+
+// DeleteTasks moves up to domain.MaxTaskBatchSize tasks to the trash in one transaction
+func (h *TaskHandler) DeleteTasks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	atomic, ok := parseAtomic(w, r)
+	if !ok {
+		return
+	}
+
+	var req request.BatchDeleteTasksReq
+	if !decodeBatchReq(w, r, &req) {
+		return
+	}
+
+	results, err := h.usecase.DeleteTasks(ctx, (&req).ToDomain(), atomic)
+	if err != nil {
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	writeTaskBatchResponse(w, r, results, atomic, http.StatusOK, func(task *domain.Task) any {
+		return response.ToTaskIdRes(task)
+	})
+}
+
+// Batches are all-or-nothing unless the caller opts out with ?atomic=false
+func parseAtomic(w http.ResponseWriter, r *http.Request) (bool, bool) {
+	return parseBoolParam(w, r, "atomic", true)
+}
+
+func decodeBatchReq(w http.ResponseWriter, r *http.Request, req any) bool {
+	ctx := r.Context()
+
+	if err := helper.DecodeJSON(w, r, req); err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
+		return false
+	}
+
+	if err := helper.Validate(req, customError.TaskBadRequest); err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
+		return false
+	}
+
+	return true
+}
+
+// A failed atomic batch answers with the status of the item that failed it.
+// Otherwise the batch itself succeeded and each item carries its own status
+func writeTaskBatchResponse(w http.ResponseWriter, r *http.Request, results []*domain.TaskBatchResult, atomic bool, okStatus int, toRes func(*domain.Task) any) {
+	res := response.TaskBatchRes{
+		Atomic:  atomic,
+		Results: make([]*response.TaskBatchItemRes, len(results)),
+	}
+
+	status := http.StatusOK
+	for i, result := range results {
+		item := &response.TaskBatchItemRes{Index: i}
+		if result.Err != nil {
+			problem := helper.NewProblem(r, result.Err)
+			problem.Instance = ""
+			item.Status = problem.Status
+			item.Error = problem
+			res.Failed++
+
+			if atomic && !errors.Is(result.Err, customError.ErrBatchAborted) {
+				status = item.Status
+			}
+		} else {
+			item.Status = okStatus
+			item.Task = toRes(result.Task)
+			res.Succeeded++
+		}
+		res.Results[i] = item
+	}
+
+	helper.WriteResponse(r.Context(), w, status, res)
+}
@@ -0,0 +1,227 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/takumi616/go-restapi/domain"
+	"github.com/takumi616/go-restapi/interface/handler/test/helper"
+	"github.com/takumi616/go-restapi/interface/handler/test/mock"
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+func TestAddTasks(t *testing.T) {
+	type expected struct {
+		status  int
+		resFile string
+	}
+
+	type mockData struct {
+		inputTasks []*domain.Task
+		atomic     bool
+		results    []*domain.TaskBatchResult
+		err        error
+	}
+
+	inputTasks := []*domain.Task{
+		{Title: "first test title", Description: "test description"},
+		{Title: "second test title", Description: "test description"},
+	}
+	firstTask := &domain.Task{
+		Id: "6a30b9b0-18bf-47b4-bd23-d72726864def", Title: "first test title", Description: "test description",
+		State: domain.TaskStateTodo, Priority: domain.TaskPriorityP2, Version: 1, CreatedAt: testCreatedAt, UpdatedAt: testCreatedAt,
+	}
+	secondTask := &domain.Task{
+		Id: "3e440171-0921-4c88-a7ec-13f4cdab0d69", Title: "second test title", Description: "test description",
+		State: domain.TaskStateTodo, Priority: domain.TaskPriorityP2, Version: 1, CreatedAt: testCreatedAt, UpdatedAt: testCreatedAt,
+	}
+
+	testTable := map[string]struct {
+		query    string
+		reqFile  string
+		expected expected
+		mockData mockData
+		mockUse  bool
+	}{
+		"AtomicOk": {
+			reqFile: "test/data/batch_create/ok_req.json.golden",
+			expected: expected{
+				status:  http.StatusOK,
+				resFile: "test/data/batch_create/ok_res.json.golden",
+			},
+			mockData: mockData{
+				inputTasks: inputTasks,
+				atomic:     true,
+				results:    []*domain.TaskBatchResult{{Task: firstTask}, {Task: secondTask}},
+			},
+			mockUse: true,
+		},
+		"AtomicItemFails": {
+			reqFile: "test/data/batch_create/ok_req.json.golden",
+			expected: expected{
+				status:  http.StatusConflict,
+				resFile: "test/data/batch_create/atomic_fail_res.json.golden",
+			},
+			mockData: mockData{
+				inputTasks: inputTasks,
+				atomic:     true,
+				results: []*domain.TaskBatchResult{
+					{Err: customError.ErrBatchAborted},
+					{Err: &customError.FieldError{Field: "title", Err: customError.ErrTaskConflict}},
+				},
+			},
+			mockUse: true,
+		},
+		"BestEffortItemFails": {
+			query:   "?atomic=false",
+			reqFile: "test/data/batch_create/ok_req.json.golden",
+			expected: expected{
+				status:  http.StatusOK,
+				resFile: "test/data/batch_create/best_effort_res.json.golden",
+			},
+			mockData: mockData{
+				inputTasks: inputTasks,
+				atomic:     false,
+				results: []*domain.TaskBatchResult{
+					{Task: firstTask},
+					{Err: &customError.FieldError{Field: "title", Err: customError.ErrTaskConflict}},
+				},
+			},
+			mockUse: true,
+		},
+		"InternalServerErr": {
+			reqFile: "test/data/batch_create/ok_req.json.golden",
+			expected: expected{
+				status:  http.StatusInternalServerError,
+				resFile: "test/data/batch_create/internal_server_err_res.json.golden",
+			},
+			mockData: mockData{
+				inputTasks: inputTasks,
+				atomic:     true,
+				err:        customError.ErrAddTasks,
+			},
+			mockUse: true,
+		},
+		"EmptyBatch": {
+			reqFile: "test/data/batch_create/empty_req.json.golden",
+			expected: expected{
+				status:  http.StatusBadRequest,
+				resFile: "test/data/batch_create/empty_res.json.golden",
+			},
+			mockUse: false,
+		},
+		"InvalidAtomic": {
+			query:   "?atomic=maybe",
+			reqFile: "test/data/batch_create/ok_req.json.golden",
+			expected: expected{
+				status:  http.StatusBadRequest,
+				resFile: "test/data/batch_create/invalid_atomic_res.json.golden",
+			},
+			mockUse: false,
+		},
+	}
+
+	for n, tt := range testTable {
+		tt := tt
+
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodPost,
+				"/tasks:batchCreate"+tt.query,
+				bytes.NewReader(helper.LoadFile(t, tt.reqFile)),
+			)
+			r.Header.Set("Content-Type", "application/json")
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockTaskUsecase := mock.NewMockTaskUsecase(mockCtrl)
+
+			if tt.mockUse {
+				mockTaskUsecase.EXPECT().AddTasks(r.Context(), tt.mockData.inputTasks, tt.mockData.atomic).
+					Return(tt.mockData.results, tt.mockData.err)
+			}
+
+			sut := NewTaskHandler(mockTaskUsecase, testCursor)
+			sut.AddTasks(w, r)
+
+			actualRes := w.Result()
+			helper.AssertResponse(t,
+				actualRes, tt.expected.status, helper.LoadFile(t, tt.expected.resFile),
+			)
+		})
+	}
+}
+
+func TestUpdateTasks(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(
+		http.MethodPatch,
+		"/tasks:batchUpdate?atomic=false",
+		bytes.NewReader(helper.LoadFile(t, "test/data/batch_update/ok_req.json.golden")),
+	)
+	r.Header.Set("Content-Type", "application/json")
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockTaskUsecase := mock.NewMockTaskUsecase(mockCtrl)
+	mockTaskUsecase.EXPECT().UpdateTasks(r.Context(), []*domain.TaskBatchUpdate{
+		{
+			Id:    "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			Patch: &domain.TaskPatch{State: ptr(domain.TaskStateDone), Version: 1},
+		},
+		{
+			Id:    "3e440171-0921-4c88-a7ec-13f4cdab0d69",
+			Patch: &domain.TaskPatch{Title: ptr("renamed test title")},
+		},
+	}, false).Return([]*domain.TaskBatchResult{
+		{Task: &domain.Task{
+			Id: "6a30b9b0-18bf-47b4-bd23-d72726864def", Title: "test title", Description: "test description",
+			State: domain.TaskStateDone, Priority: domain.TaskPriorityP2, Version: 2, CreatedAt: testCreatedAt, UpdatedAt: testUpdatedAt, CompletedAt: &testUpdatedAt,
+		}},
+		{Err: customError.ErrTaskNotFound},
+	}, nil)
+
+	sut := NewTaskHandler(mockTaskUsecase, testCursor)
+	sut.UpdateTasks(w, r)
+
+	helper.AssertResponse(t,
+		w.Result(), http.StatusOK, helper.LoadFile(t, "test/data/batch_update/ok_res.json.golden"),
+	)
+}
+
+func TestDeleteTasks(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(
+		http.MethodPost,
+		"/tasks:batchDelete",
+		bytes.NewReader(helper.LoadFile(t, "test/data/batch_delete/ok_req.json.golden")),
+	)
+	r.Header.Set("Content-Type", "application/json")
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockTaskUsecase := mock.NewMockTaskUsecase(mockCtrl)
+	mockTaskUsecase.EXPECT().DeleteTasks(r.Context(), []*domain.TaskBatchDelete{
+		{Id: "6a30b9b0-18bf-47b4-bd23-d72726864def", Version: 2},
+		{Id: "3e440171-0921-4c88-a7ec-13f4cdab0d69"},
+	}, true).Return([]*domain.TaskBatchResult{
+		{Task: &domain.Task{Id: "6a30b9b0-18bf-47b4-bd23-d72726864def"}},
+		{Task: &domain.Task{Id: "3e440171-0921-4c88-a7ec-13f4cdab0d69"}},
+	}, nil)
+
+	sut := NewTaskHandler(mockTaskUsecase, testCursor)
+	sut.DeleteTasks(w, r)
+
+	helper.AssertResponse(t,
+		w.Result(), http.StatusOK, helper.LoadFile(t, "test/data/batch_delete/ok_res.json.golden"),
+	)
+}
@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/takumi616/go-restapi/domain"
+	"github.com/takumi616/go-restapi/interface/handler/helper"
+	"github.com/takumi616/go-restapi/interface/handler/request"
+	"github.com/takumi616/go-restapi/interface/handler/response"
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+// GetChecklist shows the checklist of a task. Its ETag is the task's, as
+// every change to the checklist is a change to the task
+func (h *TaskHandler) GetChecklist(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := domain.ParseTaskID(r.PathValue("id"))
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	checklist, err := h.usecase.GetChecklist(ctx, id)
+	if err != nil {
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	helper.SetETag(w, checklist.Version)
+	helper.WriteResponse(ctx, w, http.StatusOK, response.ToChecklistRes(checklist))
+}
+
+// AddChecklistItem adds an item to the checklist of a task
+func (h *TaskHandler) AddChecklistItem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := domain.ParseTaskID(r.PathValue("id"))
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	version, err := helper.ParseIfMatch(r)
+	if err != nil {
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	var req request.AddChecklistItemReq
+	if err := helper.DecodeJSON(w, r, &req); err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	if err := helper.Validate(req, customError.ChecklistBadRequest); err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	checklist, err := h.usecase.AddChecklistItem(ctx, id, req.Text, req.Position, version)
+	if err != nil {
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	helper.SetETag(w, checklist.Version)
+	helper.WriteResponse(ctx, w, http.StatusCreated, response.ToChecklistRes(checklist))
+}
+
+// UpdateChecklistItem edits the text of an item or ticks it on or off
+func (h *TaskHandler) UpdateChecklistItem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, itemId, err := parseChecklistItemPath(r)
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	version, err := helper.ParseIfMatch(r)
+	if err != nil {
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	var req request.UpdateChecklistItemReq
+	if err := helper.DecodeJSON(w, r, &req); err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	if err := helper.Validate(req, customError.ChecklistBadRequest); err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	checklist, err := h.usecase.UpdateChecklistItem(ctx, id, itemId, req.ToDomain(), version)
+	if err != nil {
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	helper.SetETag(w, checklist.Version)
+	helper.WriteResponse(ctx, w, http.StatusOK, response.ToChecklistRes(checklist))
+}
+
+// RemoveChecklistItem takes an item off the checklist of a task
+func (h *TaskHandler) RemoveChecklistItem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, itemId, err := parseChecklistItemPath(r)
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	version, err := helper.ParseIfMatch(r)
+	if err != nil {
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	checklist, err := h.usecase.RemoveChecklistItem(ctx, id, itemId, version)
+	if err != nil {
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	helper.SetETag(w, checklist.Version)
+	helper.WriteResponse(ctx, w, http.StatusOK, response.ToChecklistRes(checklist))
+}
+
+// ReorderChecklist puts the items of a checklist in the order given,
+// which has to list each of them once
+func (h *TaskHandler) ReorderChecklist(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := domain.ParseTaskID(r.PathValue("id"))
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	version, err := helper.ParseIfMatch(r)
+	if err != nil {
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	var req request.ReorderChecklistReq
+	if err := helper.DecodeJSON(w, r, &req); err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	if err := helper.Validate(req, customError.ChecklistBadRequest); err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	checklist, err := h.usecase.ReorderChecklist(ctx, id, req.ToDomain(), version)
+	if err != nil {
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	helper.SetETag(w, checklist.Version)
+	helper.WriteResponse(ctx, w, http.StatusOK, response.ToChecklistRes(checklist))
+}
+
+func parseChecklistItemPath(r *http.Request) (domain.TaskID, domain.ChecklistItemID, error) {
+	id, err := domain.ParseTaskID(r.PathValue("id"))
+	if err != nil {
+		return "", "", err
+	}
+
+	itemId, err := domain.ParseChecklistItemID(r.PathValue("item_id"))
+	if err != nil {
+		return "", "", err
+	}
+
+	return id, itemId, nil
+}
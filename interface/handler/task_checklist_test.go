@@ -0,0 +1,526 @@
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/takumi616/go-restapi/domain"
+	"github.com/takumi616/go-restapi/interface/handler/test/helper"
+	"github.com/takumi616/go-restapi/interface/handler/test/mock"
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+var testChecklist = &domain.Checklist{
+	TaskId: "6a30b9b0-18bf-47b4-bd23-d72726864def",
+	Items: []*domain.ChecklistItem{
+		{
+			Id: "d1e2f3a4-b5c6-4d7e-8f90-a1b2c3d4e5f6", Text: "write tests", Done: true, Position: 0,
+			CreatedAt: testCreatedAt, UpdatedAt: testUpdatedAt,
+		},
+		{
+			Id: "0f1e2d3c-4b5a-4968-8776-a5b4c3d2e1f0", Text: "update docs", Done: false, Position: 1,
+			CreatedAt: testCreatedAt, UpdatedAt: testUpdatedAt,
+		},
+	},
+	Version: 5,
+}
+
+func TestGetChecklist(t *testing.T) {
+	type expected struct {
+		status  int
+		resFile string
+		etag    string
+	}
+
+	type mockData struct {
+		checklist *domain.Checklist
+		err       error
+	}
+
+	testTable := map[string]struct {
+		id       string
+		expected expected
+		mockData mockData
+		mockUse  bool
+	}{
+		"Ok": {
+			id: "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			expected: expected{
+				status:  http.StatusOK,
+				resFile: "test/data/get_checklist/ok_res.json.golden",
+				etag:    `"5"`,
+			},
+			mockData: mockData{
+				checklist: testChecklist,
+			},
+			mockUse: true,
+		},
+		"Empty": {
+			id: "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			expected: expected{
+				status:  http.StatusOK,
+				resFile: "test/data/get_checklist/empty_res.json.golden",
+				etag:    `"1"`,
+			},
+			mockData: mockData{
+				checklist: &domain.Checklist{TaskId: "6a30b9b0-18bf-47b4-bd23-d72726864def", Version: 1},
+			},
+			mockUse: true,
+		},
+		"NotFound": {
+			id: "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			expected: expected{
+				status:  http.StatusNotFound,
+				resFile: "test/data/get_checklist/not_found_res.json.golden",
+			},
+			mockData: mockData{
+				err: customError.ErrTaskNotFound,
+			},
+			mockUse: true,
+		},
+		"InvalidId": {
+			id: "abc123",
+			expected: expected{
+				status:  http.StatusBadRequest,
+				resFile: "test/data/get_checklist/invalid_id_res.json.golden",
+			},
+			mockUse: false,
+		},
+	}
+
+	for n, tt := range testTable {
+		tt := tt
+
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/tasks/%s/checklist", tt.id), nil)
+			r.SetPathValue("id", tt.id)
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockTaskUsecase := mock.NewMockTaskUsecase(mockCtrl)
+			if tt.mockUse {
+				mockTaskUsecase.EXPECT().GetChecklist(r.Context(), domain.TaskID(tt.id)).
+					Return(tt.mockData.checklist, tt.mockData.err)
+			}
+
+			sut := NewTaskHandler(mockTaskUsecase, testCursor)
+			sut.GetChecklist(w, r)
+
+			actualRes := w.Result()
+			if tt.expected.etag != "" && actualRes.Header.Get("ETag") != tt.expected.etag {
+				t.Errorf("expected ETag %s, but actual %s", tt.expected.etag, actualRes.Header.Get("ETag"))
+			}
+			helper.AssertResponse(t,
+				actualRes, tt.expected.status, helper.LoadFile(t, tt.expected.resFile),
+			)
+		})
+	}
+}
+
+func TestAddChecklistItem(t *testing.T) {
+	type expected struct {
+		status  int
+		resFile string
+	}
+
+	type mockData struct {
+		text      string
+		position  *int
+		version   int
+		checklist *domain.Checklist
+		err       error
+	}
+
+	testTable := map[string]struct {
+		id       string
+		ifMatch  string
+		reqFile  string
+		expected expected
+		mockData mockData
+		mockUse  bool
+	}{
+		"Ok": {
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			reqFile: "test/data/add_checklist_item/ok_req.json.golden",
+			expected: expected{
+				status:  http.StatusCreated,
+				resFile: "test/data/add_checklist_item/ok_res.json.golden",
+			},
+			mockData: mockData{
+				text:      "update docs",
+				checklist: testChecklist,
+			},
+			mockUse: true,
+		},
+		"AtPosition": {
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			ifMatch: `"4"`,
+			reqFile: "test/data/add_checklist_item/at_position_req.json.golden",
+			expected: expected{
+				status:  http.StatusCreated,
+				resFile: "test/data/add_checklist_item/ok_res.json.golden",
+			},
+			mockData: mockData{
+				text:      "write tests",
+				position:  ptr(0),
+				version:   4,
+				checklist: testChecklist,
+			},
+			mockUse: true,
+		},
+		"Full": {
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			reqFile: "test/data/add_checklist_item/ok_req.json.golden",
+			expected: expected{
+				status:  http.StatusConflict,
+				resFile: "test/data/add_checklist_item/full_res.json.golden",
+			},
+			mockData: mockData{
+				text: "update docs",
+				err:  customError.ErrChecklistFull,
+			},
+			mockUse: true,
+		},
+		"VersionMismatch": {
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			ifMatch: `"3"`,
+			reqFile: "test/data/add_checklist_item/ok_req.json.golden",
+			expected: expected{
+				status:  http.StatusPreconditionFailed,
+				resFile: "test/data/add_checklist_item/version_mismatch_res.json.golden",
+			},
+			mockData: mockData{
+				text:    "update docs",
+				version: 3,
+				err:     customError.ErrTaskVersionMismatch,
+			},
+			mockUse: true,
+		},
+		"BadRequest": {
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			reqFile: "test/data/add_checklist_item/bad_req.json.golden",
+			expected: expected{
+				status:  http.StatusBadRequest,
+				resFile: "test/data/add_checklist_item/bad_req_res.json.golden",
+			},
+			mockUse: false,
+		},
+	}
+
+	for n, tt := range testTable {
+		tt := tt
+
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodPost,
+				fmt.Sprintf("/tasks/%s/checklist", tt.id),
+				bytes.NewReader(helper.LoadFile(t, tt.reqFile)),
+			)
+			r.SetPathValue("id", tt.id)
+			r.Header.Set("Content-Type", "application/json")
+			if tt.ifMatch != "" {
+				r.Header.Set("If-Match", tt.ifMatch)
+			}
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockTaskUsecase := mock.NewMockTaskUsecase(mockCtrl)
+			if tt.mockUse {
+				mockTaskUsecase.EXPECT().
+					AddChecklistItem(r.Context(), domain.TaskID(tt.id), tt.mockData.text, tt.mockData.position, tt.mockData.version).
+					Return(tt.mockData.checklist, tt.mockData.err)
+			}
+
+			sut := NewTaskHandler(mockTaskUsecase, testCursor)
+			sut.AddChecklistItem(w, r)
+
+			helper.AssertResponse(t,
+				w.Result(), tt.expected.status, helper.LoadFile(t, tt.expected.resFile),
+			)
+		})
+	}
+}
+
+func TestUpdateChecklistItem(t *testing.T) {
+	type expected struct {
+		status  int
+		resFile string
+	}
+
+	type mockData struct {
+		patch     *domain.ChecklistItemPatch
+		checklist *domain.Checklist
+		err       error
+	}
+
+	done := true
+
+	testTable := map[string]struct {
+		id       string
+		itemId   string
+		reqFile  string
+		expected expected
+		mockData mockData
+		mockUse  bool
+	}{
+		"Ok": {
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			itemId:  "d1e2f3a4-b5c6-4d7e-8f90-a1b2c3d4e5f6",
+			reqFile: "test/data/update_checklist_item/ok_req.json.golden",
+			expected: expected{
+				status:  http.StatusOK,
+				resFile: "test/data/update_checklist_item/ok_res.json.golden",
+			},
+			mockData: mockData{
+				patch:     &domain.ChecklistItemPatch{Done: &done},
+				checklist: testChecklist,
+			},
+			mockUse: true,
+		},
+		"ItemNotFound": {
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			itemId:  "d1e2f3a4-b5c6-4d7e-8f90-a1b2c3d4e5f6",
+			reqFile: "test/data/update_checklist_item/ok_req.json.golden",
+			expected: expected{
+				status:  http.StatusNotFound,
+				resFile: "test/data/update_checklist_item/item_not_found_res.json.golden",
+			},
+			mockData: mockData{
+				patch: &domain.ChecklistItemPatch{Done: &done},
+				err:   customError.ErrChecklistItemNotFound,
+			},
+			mockUse: true,
+		},
+		"EmptyBody": {
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			itemId:  "d1e2f3a4-b5c6-4d7e-8f90-a1b2c3d4e5f6",
+			reqFile: "test/data/update_checklist_item/empty_req.json.golden",
+			expected: expected{
+				status:  http.StatusBadRequest,
+				resFile: "test/data/update_checklist_item/empty_res.json.golden",
+			},
+			mockUse: false,
+		},
+		"InvalidItemId": {
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			itemId:  "first",
+			reqFile: "test/data/update_checklist_item/ok_req.json.golden",
+			expected: expected{
+				status:  http.StatusBadRequest,
+				resFile: "test/data/update_checklist_item/invalid_item_id_res.json.golden",
+			},
+			mockUse: false,
+		},
+	}
+
+	for n, tt := range testTable {
+		tt := tt
+
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodPatch,
+				fmt.Sprintf("/tasks/%s/checklist/%s", tt.id, tt.itemId),
+				bytes.NewReader(helper.LoadFile(t, tt.reqFile)),
+			)
+			r.SetPathValue("id", tt.id)
+			r.SetPathValue("item_id", tt.itemId)
+			r.Header.Set("Content-Type", "application/json")
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockTaskUsecase := mock.NewMockTaskUsecase(mockCtrl)
+			if tt.mockUse {
+				mockTaskUsecase.EXPECT().
+					UpdateChecklistItem(r.Context(), domain.TaskID(tt.id), domain.ChecklistItemID(tt.itemId), tt.mockData.patch, 0).
+					Return(tt.mockData.checklist, tt.mockData.err)
+			}
+
+			sut := NewTaskHandler(mockTaskUsecase, testCursor)
+			sut.UpdateChecklistItem(w, r)
+
+			helper.AssertResponse(t,
+				w.Result(), tt.expected.status, helper.LoadFile(t, tt.expected.resFile),
+			)
+		})
+	}
+}
+
+func TestRemoveChecklistItem(t *testing.T) {
+	type expected struct {
+		status  int
+		resFile string
+	}
+
+	type mockData struct {
+		checklist *domain.Checklist
+		err       error
+	}
+
+	testTable := map[string]struct {
+		id       string
+		itemId   string
+		expected expected
+		mockData mockData
+	}{
+		"Ok": {
+			id:     "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			itemId: "a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d",
+			expected: expected{
+				status:  http.StatusOK,
+				resFile: "test/data/remove_checklist_item/ok_res.json.golden",
+			},
+			mockData: mockData{
+				checklist: testChecklist,
+			},
+		},
+		"ItemNotFound": {
+			id:     "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			itemId: "a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d",
+			expected: expected{
+				status:  http.StatusNotFound,
+				resFile: "test/data/remove_checklist_item/item_not_found_res.json.golden",
+			},
+			mockData: mockData{
+				err: customError.ErrChecklistItemNotFound,
+			},
+		},
+	}
+
+	for n, tt := range testTable {
+		tt := tt
+
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodDelete,
+				fmt.Sprintf("/tasks/%s/checklist/%s", tt.id, tt.itemId),
+				nil,
+			)
+			r.SetPathValue("id", tt.id)
+			r.SetPathValue("item_id", tt.itemId)
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockTaskUsecase := mock.NewMockTaskUsecase(mockCtrl)
+			mockTaskUsecase.EXPECT().
+				RemoveChecklistItem(r.Context(), domain.TaskID(tt.id), domain.ChecklistItemID(tt.itemId), 0).
+				Return(tt.mockData.checklist, tt.mockData.err)
+
+			sut := NewTaskHandler(mockTaskUsecase, testCursor)
+			sut.RemoveChecklistItem(w, r)
+
+			helper.AssertResponse(t,
+				w.Result(), tt.expected.status, helper.LoadFile(t, tt.expected.resFile),
+			)
+		})
+	}
+}
+
+func TestReorderChecklist(t *testing.T) {
+	type expected struct {
+		status  int
+		resFile string
+	}
+
+	type mockData struct {
+		itemIds   []domain.ChecklistItemID
+		checklist *domain.Checklist
+		err       error
+	}
+
+	testTable := map[string]struct {
+		id       string
+		reqFile  string
+		expected expected
+		mockData mockData
+		mockUse  bool
+	}{
+		"Ok": {
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			reqFile: "test/data/reorder_checklist/ok_req.json.golden",
+			expected: expected{
+				status:  http.StatusOK,
+				resFile: "test/data/reorder_checklist/ok_res.json.golden",
+			},
+			mockData: mockData{
+				itemIds:   []domain.ChecklistItemID{"d1e2f3a4-b5c6-4d7e-8f90-a1b2c3d4e5f6", "0f1e2d3c-4b5a-4968-8776-a5b4c3d2e1f0"},
+				checklist: testChecklist,
+			},
+			mockUse: true,
+		},
+		"OrderMismatch": {
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			reqFile: "test/data/reorder_checklist/ok_req.json.golden",
+			expected: expected{
+				status:  http.StatusUnprocessableEntity,
+				resFile: "test/data/reorder_checklist/order_mismatch_res.json.golden",
+			},
+			mockData: mockData{
+				itemIds: []domain.ChecklistItemID{"d1e2f3a4-b5c6-4d7e-8f90-a1b2c3d4e5f6", "0f1e2d3c-4b5a-4968-8776-a5b4c3d2e1f0"},
+				err:     &customError.FieldError{Field: "items", Err: customError.ErrChecklistOrderMismatch},
+			},
+			mockUse: true,
+		},
+		"DuplicateItems": {
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			reqFile: "test/data/reorder_checklist/duplicate_req.json.golden",
+			expected: expected{
+				status:  http.StatusBadRequest,
+				resFile: "test/data/reorder_checklist/duplicate_res.json.golden",
+			},
+			mockUse: false,
+		},
+	}
+
+	for n, tt := range testTable {
+		tt := tt
+
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodPut,
+				fmt.Sprintf("/tasks/%s/checklist/order", tt.id),
+				bytes.NewReader(helper.LoadFile(t, tt.reqFile)),
+			)
+			r.SetPathValue("id", tt.id)
+			r.Header.Set("Content-Type", "application/json")
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockTaskUsecase := mock.NewMockTaskUsecase(mockCtrl)
+			if tt.mockUse {
+				mockTaskUsecase.EXPECT().ReorderChecklist(r.Context(), domain.TaskID(tt.id), tt.mockData.itemIds, 0).
+					Return(tt.mockData.checklist, tt.mockData.err)
+			}
+
+			sut := NewTaskHandler(mockTaskUsecase, testCursor)
+			sut.ReorderChecklist(w, r)
+
+			helper.AssertResponse(t,
+				w.Result(), tt.expected.status, helper.LoadFile(t, tt.expected.resFile),
+			)
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/takumi616/go-restapi/domain"
+	"github.com/takumi616/go-restapi/interface/handler/helper"
+	"github.com/takumi616/go-restapi/interface/handler/request"
+	"github.com/takumi616/go-restapi/interface/handler/response"
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+// AddTaskDependency makes a task wait on the task given as its blocker
+func (h *TaskHandler) AddTaskDependency(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := domain.ParseTaskID(r.PathValue("id"))
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	var req request.AddTaskDependencyReq
+	if err := helper.DecodeJSON(w, r, &req); err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	if err := helper.Validate(req, customError.TaskBadRequest); err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	updated, err := h.usecase.AddTaskDependency(ctx, id, req.ToDomain())
+	if err != nil {
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	helper.SetETag(w, updated.Version)
+	helper.WriteResponse(ctx, w, http.StatusOK, response.ToTaskRes(updated))
+}
+
+// RemoveTaskDependency stops a task from waiting on one of its blockers
+func (h *TaskHandler) RemoveTaskDependency(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := domain.ParseTaskID(r.PathValue("id"))
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	blockerId, err := domain.ParseTaskID(r.PathValue("blocker_id"))
+	if err != nil {
+		slog.ErrorContext(ctx, err.Error())
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	updated, err := h.usecase.RemoveTaskDependency(ctx, id, blockerId)
+	if err != nil {
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	helper.SetETag(w, updated.Version)
+	helper.WriteResponse(ctx, w, http.StatusOK, response.ToTaskRes(updated))
+}
+
+// GetTaskSchedule plans the open tasks in an order that respects their
+// dependencies, with when each can start and finish and the critical path
+func (h *TaskHandler) GetTaskSchedule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	schedule, err := h.usecase.GetTaskSchedule(ctx)
+	if err != nil {
+		helper.WriteProblem(ctx, w, r, err)
+		return
+	}
+
+	helper.WriteResponse(ctx, w, http.StatusOK, response.ToTaskScheduleRes(schedule))
+}
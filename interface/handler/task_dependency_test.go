@@ -0,0 +1,312 @@
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/takumi616/go-restapi/domain"
+	"github.com/takumi616/go-restapi/interface/handler/test/helper"
+	"github.com/takumi616/go-restapi/interface/handler/test/mock"
+	customError "github.com/takumi616/go-restapi/shared/error"
+)
+
+func TestAddTaskDependency(t *testing.T) {
+	type expected struct {
+		status  int
+		resFile string
+	}
+
+	type mockData struct {
+		blockerId    domain.TaskID
+		returnedTask *domain.Task
+		err          error
+	}
+
+	testTable := map[string]struct {
+		id       string
+		reqFile  string
+		expected expected
+		mockData mockData
+		mockUse  bool
+	}{
+		"Ok": {
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			reqFile: "test/data/add_task_dependency/ok_req.json.golden",
+			expected: expected{
+				status:  http.StatusOK,
+				resFile: "test/data/add_task_dependency/ok_res.json.golden",
+			},
+			mockData: mockData{
+				blockerId: "f299e7ed-a22a-4494-b59e-21bb91fdae3b",
+				returnedTask: &domain.Task{
+					Id:    "6a30b9b0-18bf-47b4-bd23-d72726864def",
+					Title: "test title", Description: "test description",
+					State: domain.TaskStateTodo, Priority: domain.TaskPriorityP2,
+					BlockedBy: []*domain.TaskBlocker{
+						{Id: "f299e7ed-a22a-4494-b59e-21bb91fdae3b", Title: "test blocker", State: domain.TaskStateInProgress},
+					},
+					Version: 2, CreatedAt: testCreatedAt, UpdatedAt: testUpdatedAt,
+				},
+			},
+			mockUse: true,
+		},
+		"Cycle": {
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			reqFile: "test/data/add_task_dependency/ok_req.json.golden",
+			expected: expected{
+				status:  http.StatusUnprocessableEntity,
+				resFile: "test/data/add_task_dependency/cycle_res.json.golden",
+			},
+			mockData: mockData{
+				blockerId: "f299e7ed-a22a-4494-b59e-21bb91fdae3b",
+				err:       &customError.FieldError{Field: "blocker_id", Err: customError.ErrTaskDependencyCycle},
+			},
+			mockUse: true,
+		},
+		"BlockerNotFound": {
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			reqFile: "test/data/add_task_dependency/ok_req.json.golden",
+			expected: expected{
+				status:  http.StatusUnprocessableEntity,
+				resFile: "test/data/add_task_dependency/blocker_not_found_res.json.golden",
+			},
+			mockData: mockData{
+				blockerId: "f299e7ed-a22a-4494-b59e-21bb91fdae3b",
+				err:       &customError.FieldError{Field: "blocker_id", Err: customError.ErrTaskBlockerNotFound},
+			},
+			mockUse: true,
+		},
+		"NotFound": {
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			reqFile: "test/data/add_task_dependency/ok_req.json.golden",
+			expected: expected{
+				status:  http.StatusNotFound,
+				resFile: "test/data/add_task_dependency/not_found_res.json.golden",
+			},
+			mockData: mockData{
+				blockerId: "f299e7ed-a22a-4494-b59e-21bb91fdae3b",
+				err:       customError.ErrTaskNotFound,
+			},
+			mockUse: true,
+		},
+		"InvalidBlockerId": {
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			reqFile: "test/data/add_task_dependency/invalid_blocker_id_req.json.golden",
+			expected: expected{
+				status:  http.StatusBadRequest,
+				resFile: "test/data/add_task_dependency/invalid_blocker_id_res.json.golden",
+			},
+			mockUse: false,
+		},
+	}
+
+	for n, tt := range testTable {
+		tt := tt
+
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodPost,
+				fmt.Sprintf("/tasks/%s/dependencies", tt.id),
+				bytes.NewReader(helper.LoadFile(t, tt.reqFile)),
+			)
+			r.SetPathValue("id", tt.id)
+			r.Header.Set("Content-Type", "application/json")
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockTaskUsecase := mock.NewMockTaskUsecase(mockCtrl)
+			if tt.mockUse {
+				mockTaskUsecase.EXPECT().AddTaskDependency(r.Context(), domain.TaskID(tt.id), tt.mockData.blockerId).
+					Return(tt.mockData.returnedTask, tt.mockData.err)
+			}
+
+			sut := NewTaskHandler(mockTaskUsecase, testCursor)
+			sut.AddTaskDependency(w, r)
+
+			helper.AssertResponse(t,
+				w.Result(), tt.expected.status, helper.LoadFile(t, tt.expected.resFile),
+			)
+		})
+	}
+}
+
+func TestRemoveTaskDependency(t *testing.T) {
+	type expected struct {
+		status  int
+		resFile string
+	}
+
+	type mockData struct {
+		returnedTask *domain.Task
+		err          error
+	}
+
+	testTable := map[string]struct {
+		id        string
+		blockerId string
+		expected  expected
+		mockData  mockData
+		mockUse   bool
+	}{
+		"Ok": {
+			id:        "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			blockerId: "f299e7ed-a22a-4494-b59e-21bb91fdae3b",
+			expected: expected{
+				status:  http.StatusOK,
+				resFile: "test/data/remove_task_dependency/ok_res.json.golden",
+			},
+			mockData: mockData{
+				returnedTask: &domain.Task{
+					Id:    "6a30b9b0-18bf-47b4-bd23-d72726864def",
+					Title: "test title", Description: "test description",
+					State: domain.TaskStateTodo, Priority: domain.TaskPriorityP2,
+					Version: 3, CreatedAt: testCreatedAt, UpdatedAt: testUpdatedAt,
+				},
+			},
+			mockUse: true,
+		},
+		"NotFound": {
+			id:        "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			blockerId: "f299e7ed-a22a-4494-b59e-21bb91fdae3b",
+			expected: expected{
+				status:  http.StatusNotFound,
+				resFile: "test/data/remove_task_dependency/not_found_res.json.golden",
+			},
+			mockData: mockData{
+				err: customError.ErrTaskDependencyNotFound,
+			},
+			mockUse: true,
+		},
+		"InvalidBlockerId": {
+			id:        "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			blockerId: "123",
+			expected: expected{
+				status:  http.StatusBadRequest,
+				resFile: "test/data/remove_task_dependency/invalid_blocker_id_res.json.golden",
+			},
+			mockUse: false,
+		},
+	}
+
+	for n, tt := range testTable {
+		tt := tt
+
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodDelete,
+				fmt.Sprintf("/tasks/%s/dependencies/%s", tt.id, tt.blockerId),
+				nil,
+			)
+			r.SetPathValue("id", tt.id)
+			r.SetPathValue("blocker_id", tt.blockerId)
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockTaskUsecase := mock.NewMockTaskUsecase(mockCtrl)
+			if tt.mockUse {
+				mockTaskUsecase.EXPECT().RemoveTaskDependency(r.Context(), domain.TaskID(tt.id), domain.TaskID(tt.blockerId)).
+					Return(tt.mockData.returnedTask, tt.mockData.err)
+			}
+
+			sut := NewTaskHandler(mockTaskUsecase, testCursor)
+			sut.RemoveTaskDependency(w, r)
+
+			helper.AssertResponse(t,
+				w.Result(), tt.expected.status, helper.LoadFile(t, tt.expected.resFile),
+			)
+		})
+	}
+}
+
+func TestGetTaskSchedule(t *testing.T) {
+	type expected struct {
+		status  int
+		resFile string
+	}
+
+	type mockData struct {
+		schedule *domain.TaskSchedule
+		err      error
+	}
+
+	design := &domain.Task{
+		Id: "6a30b9b0-18bf-47b4-bd23-d72726864def", Title: "design",
+		State: domain.TaskStateInProgress, EstimateHours: ptr(3),
+	}
+	build := &domain.Task{
+		Id: "f299e7ed-a22a-4494-b59e-21bb91fdae3b", Title: "build",
+		State: domain.TaskStateTodo, EstimateHours: ptr(5),
+	}
+	docs := &domain.Task{
+		Id: "3e440171-0921-4c88-a7ec-13f4cdab0d69", Title: "docs",
+		State: domain.TaskStateTodo,
+	}
+
+	testTable := map[string]struct {
+		expected expected
+		mockData mockData
+	}{
+		"Ok": {
+			expected: expected{
+				status:  http.StatusOK,
+				resFile: "test/data/get_task_schedule/ok_res.json.golden",
+			},
+			mockData: mockData{
+				schedule: &domain.TaskSchedule{
+					Items: []*domain.TaskScheduleItem{
+						{Task: design, EarliestStart: 0, EarliestFinish: 3, LatestStart: 0, LatestFinish: 3},
+						{Task: build, DependsOn: []domain.TaskID{design.Id}, EarliestStart: 3, EarliestFinish: 8, LatestStart: 3, LatestFinish: 8},
+						{Task: docs, EarliestStart: 0, EarliestFinish: 0, LatestStart: 8, LatestFinish: 8},
+					},
+					CriticalPath: []domain.TaskID{design.Id, build.Id},
+					Duration:     8,
+				},
+			},
+		},
+		"InternalServerErr": {
+			expected: expected{
+				status:  http.StatusInternalServerError,
+				resFile: "test/data/get_task_schedule/internal_server_err_res.json.golden",
+			},
+			mockData: mockData{
+				err: customError.ErrGetTaskSchedule,
+			},
+		},
+	}
+
+	for n, tt := range testTable {
+		tt := tt
+
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/tasks/schedule", nil)
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockTaskUsecase := mock.NewMockTaskUsecase(mockCtrl)
+			mockTaskUsecase.EXPECT().GetTaskSchedule(r.Context()).Return(tt.mockData.schedule, tt.mockData.err)
+
+			sut := NewTaskHandler(mockTaskUsecase, testCursor)
+			sut.GetTaskSchedule(w, r)
+
+			helper.AssertResponse(t,
+				w.Result(), tt.expected.status, helper.LoadFile(t, tt.expected.resFile),
+			)
+		})
+	}
+}
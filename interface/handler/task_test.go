@@ -5,15 +5,25 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/takumi616/go-restapi/domain"
+	"github.com/takumi616/go-restapi/interface/handler/cursor"
 	"github.com/takumi616/go-restapi/interface/handler/test/helper"
 	"github.com/takumi616/go-restapi/interface/handler/test/mock"
 	customError "github.com/takumi616/go-restapi/shared/error"
 )
 
+var testCursor = cursor.NewCodec("test-secret")
+
+var (
+	testCreatedAt = time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	testUpdatedAt = time.Date(2025, 1, 3, 4, 5, 6, 0, time.UTC)
+)
+
 func TestAddTask(t *testing.T) {
 	type expected struct {
 		status  int
@@ -26,62 +36,1722 @@ func TestAddTask(t *testing.T) {
 	}
 
 	testTable := map[string]struct {
-		reqFile  string
+		reqFile     string
+		contentType string
+		expected    expected
+		mockData    mockData
+		mockUse     bool
+	}{
+		"Ok": {
+			reqFile: "test/data/add_task/ok_req.json.golden",
+			expected: expected{
+				status:  http.StatusCreated,
+				resFile: "test/data/add_task/ok_res.json.golden",
+			},
+			mockData: mockData{
+				param: &domain.Task{Title: "test title", Description: "test description"},
+				returned: &domain.Task{
+					Id:    "6a30b9b0-18bf-47b4-bd23-d72726864def",
+					Title: "test title", Description: "test description",
+					State:     domain.TaskStateTodo,
+					Priority:  domain.TaskPriorityP2,
+					CreatedAt: testCreatedAt, UpdatedAt: testCreatedAt,
+				},
+				err: nil,
+			},
+			mockUse: true,
+		},
+		"DuplicateErr": {
+			reqFile: "test/data/add_task/duplicate_err_req.json.golden",
+			expected: expected{
+				status:  http.StatusConflict,
+				resFile: "test/data/add_task/duplicate_err_res.json.golden",
+			},
+			mockData: mockData{
+				param:    &domain.Task{Title: "duplicate test title", Description: "test description"},
+				returned: nil,
+				err:      &customError.FieldError{Field: "title", Err: customError.ErrTaskConflict},
+			},
+			mockUse: true,
+		},
+		"TooLongErr": {
+			reqFile: "test/data/add_task/too_long_req.json.golden",
+			expected: expected{
+				status:  http.StatusUnprocessableEntity,
+				resFile: "test/data/add_task/too_long_res.json.golden",
+			},
+			mockData: mockData{
+				param:    &domain.Task{Title: "test title longer than thirty characters", Description: "test description"},
+				returned: nil,
+				err:      &customError.FieldError{Field: "title", Err: customError.ErrTaskValueTooLong},
+			},
+			mockUse: true,
+		},
+		"Subtask": {
+			reqFile: "test/data/add_task/subtask_req.json.golden",
+			expected: expected{
+				status:  http.StatusCreated,
+				resFile: "test/data/add_task/subtask_res.json.golden",
+			},
+			mockData: mockData{
+				param: &domain.Task{Title: "test subtask", ParentId: ptr(domain.TaskID("f299e7ed-a22a-4494-b59e-21bb91fdae3b"))},
+				returned: &domain.Task{
+					Id:        "6a30b9b0-18bf-47b4-bd23-d72726864def",
+					Title:     "test subtask",
+					State:     domain.TaskStateTodo,
+					Priority:  domain.TaskPriorityP2,
+					ParentId:  ptr(domain.TaskID("f299e7ed-a22a-4494-b59e-21bb91fdae3b")),
+					CreatedAt: testCreatedAt, UpdatedAt: testCreatedAt,
+				},
+				err: nil,
+			},
+			mockUse: true,
+		},
+		"EstimateAndDependencies": {
+			reqFile: "test/data/add_task/estimate_and_dependencies_req.json.golden",
+			expected: expected{
+				status:  http.StatusCreated,
+				resFile: "test/data/add_task/estimate_and_dependencies_res.json.golden",
+			},
+			mockData: mockData{
+				param: &domain.Task{
+					Title: "test title", EstimateHours: ptr(8),
+					BlockedBy: []*domain.TaskBlocker{{Id: "f299e7ed-a22a-4494-b59e-21bb91fdae3b"}},
+				},
+				returned: &domain.Task{
+					Id:            "6a30b9b0-18bf-47b4-bd23-d72726864def",
+					Title:         "test title",
+					State:         domain.TaskStateTodo,
+					Priority:      domain.TaskPriorityP2,
+					EstimateHours: ptr(8),
+					BlockedBy: []*domain.TaskBlocker{
+						{Id: "f299e7ed-a22a-4494-b59e-21bb91fdae3b", Title: "test blocker", State: domain.TaskStateTodo},
+					},
+					CreatedAt: testCreatedAt, UpdatedAt: testCreatedAt,
+				},
+				err: nil,
+			},
+			mockUse: true,
+		},
+		"DependencyNotFound": {
+			reqFile: "test/data/add_task/estimate_and_dependencies_req.json.golden",
+			expected: expected{
+				status:  http.StatusUnprocessableEntity,
+				resFile: "test/data/add_task/dependency_not_found_res.json.golden",
+			},
+			mockData: mockData{
+				param: &domain.Task{
+					Title: "test title", EstimateHours: ptr(8),
+					BlockedBy: []*domain.TaskBlocker{{Id: "f299e7ed-a22a-4494-b59e-21bb91fdae3b"}},
+				},
+				err: &customError.FieldError{Field: "depends_on", Err: customError.ErrTaskBlockerNotFound},
+			},
+			mockUse: true,
+		},
+		"InvalidEstimate": {
+			reqFile: "test/data/add_task/invalid_estimate_req.json.golden",
+			expected: expected{
+				status:  http.StatusBadRequest,
+				resFile: "test/data/add_task/invalid_estimate_res.json.golden",
+			},
+			mockUse: false,
+		},
+		"ParentNotFound": {
+			reqFile: "test/data/add_task/subtask_req.json.golden",
+			expected: expected{
+				status:  http.StatusUnprocessableEntity,
+				resFile: "test/data/add_task/parent_not_found_res.json.golden",
+			},
+			mockData: mockData{
+				param:    &domain.Task{Title: "test subtask", ParentId: ptr(domain.TaskID("f299e7ed-a22a-4494-b59e-21bb91fdae3b"))},
+				returned: nil,
+				err:      &customError.FieldError{Field: "parent_id", Err: customError.ErrTaskParentNotFound},
+			},
+			mockUse: true,
+		},
+		"InvalidParentId": {
+			reqFile: "test/data/add_task/invalid_parent_id_req.json.golden",
+			expected: expected{
+				status:  http.StatusBadRequest,
+				resFile: "test/data/add_task/invalid_parent_id_res.json.golden",
+			},
+			mockUse: false,
+		},
+		"UnmarshalFail": {
+			reqFile: "test/data/add_task/unmarshal_fail_req.json.golden",
+			expected: expected{
+				status:  http.StatusBadRequest,
+				resFile: "test/data/add_task/unmarshal_fail_res.json.golden",
+			},
+			mockData: mockData{
+				param:    nil,
+				returned: nil,
+			},
+			mockUse: false,
+		},
+		"SyntaxError": {
+			reqFile: "test/data/add_task/syntax_error_req.json.golden",
+			expected: expected{
+				status:  http.StatusBadRequest,
+				resFile: "test/data/add_task/syntax_error_res.json.golden",
+			},
+			mockUse: false,
+		},
+		"UnknownField": {
+			reqFile: "test/data/add_task/unknown_field_req.json.golden",
+			expected: expected{
+				status:  http.StatusBadRequest,
+				resFile: "test/data/add_task/unknown_field_res.json.golden",
+			},
+			mockUse: false,
+		},
+		"TrailingData": {
+			reqFile: "test/data/add_task/trailing_data_req.json.golden",
+			expected: expected{
+				status:  http.StatusBadRequest,
+				resFile: "test/data/add_task/trailing_data_res.json.golden",
+			},
+			mockUse: false,
+		},
+		"UnsupportedMediaType": {
+			reqFile:     "test/data/add_task/ok_req.json.golden",
+			contentType: "text/plain",
+			expected: expected{
+				status:  http.StatusUnsupportedMediaType,
+				resFile: "test/data/add_task/unsupported_media_type_res.json.golden",
+			},
+			mockUse: false,
+		},
+		"BadRequest": {
+			reqFile: "test/data/add_task/bad_req_req.json.golden",
+			expected: expected{
+				status:  http.StatusBadRequest,
+				resFile: "test/data/add_task/bad_req_res.json.golden",
+			},
+			mockData: mockData{
+				param:    nil,
+				returned: nil,
+			},
+			mockUse: false,
+		},
+		"PriorityAndDueDate": {
+			reqFile: "test/data/add_task/priority_and_due_date_req.json.golden",
+			expected: expected{
+				status:  http.StatusCreated,
+				resFile: "test/data/add_task/priority_and_due_date_res.json.golden",
+			},
+			mockData: mockData{
+				param: &domain.Task{
+					Title: "test title", Description: "test description",
+					Priority: domain.TaskPriorityP0, DueAt: ptr(time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)),
+				},
+				returned: &domain.Task{
+					Id:    "6a30b9b0-18bf-47b4-bd23-d72726864def",
+					Title: "test title", Description: "test description",
+					State:     domain.TaskStateTodo,
+					Priority:  domain.TaskPriorityP0,
+					DueAt:     ptr(time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)),
+					CreatedAt: testCreatedAt, UpdatedAt: testCreatedAt,
+				},
+			},
+			mockUse: true,
+		},
+		"InvalidPriorityAndDueDate": {
+			reqFile: "test/data/add_task/invalid_priority_and_due_date_req.json.golden",
+			expected: expected{
+				status:  http.StatusBadRequest,
+				resFile: "test/data/add_task/invalid_priority_and_due_date_res.json.golden",
+			},
+			mockUse: false,
+		},
+	}
+
+	for n, tt := range testTable {
+		tt := tt
+
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodPost,
+				"/tasks",
+				bytes.NewReader(helper.LoadFile(t, tt.reqFile)),
+			)
+			if tt.contentType == "" {
+				tt.contentType = "application/json"
+			}
+			r.Header.Set("Content-Type", tt.contentType)
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockTaskUsecase := mock.NewMockTaskUsecase(mockCtrl)
+			if tt.mockUse {
+				mockTaskUsecase.EXPECT().AddTask(r.Context(), tt.mockData.param).
+					Return(tt.mockData.returned, tt.mockData.err)
+			}
+
+			sut := NewTaskHandler(mockTaskUsecase, testCursor)
+			sut.AddTask(w, r)
+
+			actualRes := w.Result()
+			helper.AssertResponse(t,
+				actualRes, tt.expected.status, helper.LoadFile(t, tt.expected.resFile),
+			)
+		})
+	}
+}
+
+func TestAddTaskBodyTooLarge(t *testing.T) {
+	// One more byte than the 1 MiB a request body may hold at most
+	body := fmt.Sprintf(`{"title": "test title", "description": "%s"}`, strings.Repeat("a", 1<<20))
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/tasks", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	sut := NewTaskHandler(mock.NewMockTaskUsecase(mockCtrl), testCursor)
+	sut.AddTask(w, r)
+
+	helper.AssertResponse(t,
+		w.Result(), http.StatusRequestEntityTooLarge, helper.LoadFile(t, "test/data/add_task/too_large_res.json.golden"),
+	)
+}
+
+func TestGetTaskList(t *testing.T) {
+	type expected struct {
+		status  int
+		resFile string
+	}
+
+	type mockData struct {
+		query    *domain.TaskListQuery
+		taskList *domain.TaskList
+		err      error
+	}
+
+	status := false
+
+	testTable := map[string]struct {
+		target   string
+		header   http.Header
+		expected expected
+		mockData mockData
+		mockUse  bool
+	}{
+		"Ok": {
+			target: "/tasks?limit=2&offset=2",
+			mockData: mockData{
+				query: &domain.TaskListQuery{
+					Limit: 2, Offset: 2, SortField: domain.TaskSortByTitle,
+				},
+				taskList: &domain.TaskList{
+					Tasks: []*domain.Task{
+						{
+							Id:          "f299e7ed-a22a-4494-b59e-21bb91fdae3b",
+							Title:       "test title",
+							Description: "test description",
+							State:       domain.TaskStateTodo,
+							Priority:    domain.TaskPriorityP2,
+							CreatedAt:   testCreatedAt,
+							UpdatedAt:   testUpdatedAt,
+						},
+						{
+							Id:          "4d758d63-5c4f-4bef-9a80-d5837c324a07",
+							Title:       "test title2",
+							Description: "test description2",
+							State:       domain.TaskStateTodo,
+							Priority:    domain.TaskPriorityP2,
+							CreatedAt:   testCreatedAt,
+							UpdatedAt:   testUpdatedAt,
+						},
+					},
+					Total: 5,
+				},
+				err: nil,
+			},
+			expected: expected{
+				status:  http.StatusOK,
+				resFile: "test/data/get_task_list/ok_res.json.golden",
+			},
+			mockUse: true,
+		},
+		"FilterAndSort": {
+			target: "/tasks?status=false&title=test&sort=-title",
+			mockData: mockData{
+				query: &domain.TaskListQuery{
+					Limit: 20, Status: &status, Title: "test",
+					SortField: domain.TaskSortByTitle, SortDesc: true,
+				},
+				taskList: &domain.TaskList{
+					Tasks: []*domain.Task{
+						{
+							Id:          "4d758d63-5c4f-4bef-9a80-d5837c324a07",
+							Title:       "test title2",
+							Description: "test description2",
+							State:       domain.TaskStateTodo,
+							Priority:    domain.TaskPriorityP2,
+							CreatedAt:   testCreatedAt,
+							UpdatedAt:   testUpdatedAt,
+						},
+					},
+					Total: 1,
+				},
+				err: nil,
+			},
+			expected: expected{
+				status:  http.StatusOK,
+				resFile: "test/data/get_task_list/filter_and_sort_res.json.golden",
+			},
+			mockUse: true,
+		},
+		"FilterByDueDate": {
+			target: "/tasks?due_before=2025-02-01T09:00:00%2B09:00&overdue=false&sort=-priority",
+			mockData: mockData{
+				query: &domain.TaskListQuery{
+					Limit: 20, DueBefore: ptr(time.Date(2025, 2, 1, 9, 0, 0, 0, time.FixedZone("", 9*60*60))),
+					Overdue: ptr(false), SortField: domain.TaskSortByPriority, SortDesc: true,
+				},
+				taskList: &domain.TaskList{Tasks: []*domain.Task{}, Total: 0},
+				err:      nil,
+			},
+			expected: expected{
+				status:  http.StatusOK,
+				resFile: "test/data/get_task_list/empty_res.json.golden",
+			},
+			mockUse: true,
+		},
+		"InvalidDueBefore": {
+			target: "/tasks?due_before=2025-02-01",
+			expected: expected{
+				status:  http.StatusBadRequest,
+				resFile: "test/data/get_task_list/invalid_due_before_res.json.golden",
+			},
+			mockUse: false,
+		},
+		"Cursor": {
+			target: "/tasks?limit=1&cursor=" + testCursor.Encode(&domain.TaskCursor{
+				SortField: domain.TaskSortByTitle, SortValue: "test title",
+				Id: "f299e7ed-a22a-4494-b59e-21bb91fdae3b",
+			}),
+			mockData: mockData{
+				query: &domain.TaskListQuery{
+					Limit: 1, SortField: domain.TaskSortByTitle,
+					After: &domain.TaskCursor{
+						SortField: domain.TaskSortByTitle, SortValue: "test title",
+						Id: "f299e7ed-a22a-4494-b59e-21bb91fdae3b",
+					},
+				},
+				taskList: &domain.TaskList{
+					Tasks: []*domain.Task{
+						{
+							Id:          "4d758d63-5c4f-4bef-9a80-d5837c324a07",
+							Title:       "test title2",
+							Description: "test description2",
+							State:       domain.TaskStateTodo,
+							Priority:    domain.TaskPriorityP2,
+							CreatedAt:   testCreatedAt,
+							UpdatedAt:   testUpdatedAt,
+						},
+					},
+					NextCursor: &domain.TaskCursor{
+						SortField: domain.TaskSortByTitle, SortValue: "test title2",
+						Id: "4d758d63-5c4f-4bef-9a80-d5837c324a07",
+					},
+				},
+				err: nil,
+			},
+			expected: expected{
+				status:  http.StatusOK,
+				resFile: "test/data/get_task_list/cursor_res.json.golden",
+			},
+			mockUse: true,
+		},
+		"TamperedCursor": {
+			target: "/tasks?cursor=eyJmIjoidGl0bGUifQ.c2lnbmF0dXJl",
+			expected: expected{
+				status:  http.StatusBadRequest,
+				resFile: "test/data/get_task_list/tampered_cursor_res.json.golden",
+			},
+			mockUse: false,
+		},
+		"CursorSortMismatch": {
+			target: "/tasks?sort=-title&cursor=" + testCursor.Encode(&domain.TaskCursor{
+				SortField: domain.TaskSortByTitle, SortValue: "test title",
+				Id: "f299e7ed-a22a-4494-b59e-21bb91fdae3b",
+			}),
+			expected: expected{
+				status:  http.StatusBadRequest,
+				resFile: "test/data/get_task_list/cursor_sort_mismatch_res.json.golden",
+			},
+			mockUse: false,
+		},
+		"NotModified": {
+			target: "/tasks",
+			header: http.Header{"If-None-Match": {`W/"2-1735787045000000000"`}},
+			mockData: mockData{
+				query: &domain.TaskListQuery{Limit: 20, SortField: domain.TaskSortByTitle},
+				taskList: &domain.TaskList{
+					Tasks: []*domain.Task{
+						{Id: "f299e7ed-a22a-4494-b59e-21bb91fdae3b", Title: "test title"},
+						{Id: "4d758d63-5c4f-4bef-9a80-d5837c324a07", Title: "test title2"},
+					},
+					Total:        2,
+					LastModified: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+				},
+				err: nil,
+			},
+			expected: expected{
+				status:  http.StatusNotModified,
+				resFile: "test/data/not_modified_res.json.golden",
+			},
+			mockUse: true,
+		},
+		"Empty": {
+			target: "/tasks",
+			mockData: mockData{
+				query:    &domain.TaskListQuery{Limit: 20, SortField: domain.TaskSortByTitle},
+				taskList: &domain.TaskList{Tasks: []*domain.Task{}, Total: 0},
+				err:      nil,
+			},
+			expected: expected{
+				status:  http.StatusOK,
+				resFile: "test/data/get_task_list/empty_res.json.golden",
+			},
+			mockUse: true,
+		},
+		"InvalidLimit": {
+			target: "/tasks?limit=abc",
+			expected: expected{
+				status:  http.StatusBadRequest,
+				resFile: "test/data/get_task_list/invalid_limit_res.json.golden",
+			},
+			mockUse: false,
+		},
+		"FilterByLabels": {
+			target: "/tasks?label=backend,bug&label=bug&label_mode=all",
+			mockData: mockData{
+				query: &domain.TaskListQuery{
+					Limit: 20, Labels: []string{"backend", "bug"}, LabelMode: domain.TaskLabelModeAll,
+					SortField: domain.TaskSortByTitle,
+				},
+				taskList: &domain.TaskList{
+					Tasks: []*domain.Task{
+						{
+							Id:          "f299e7ed-a22a-4494-b59e-21bb91fdae3b",
+							Title:       "test title",
+							Description: "test description",
+							State:       domain.TaskStateTodo,
+							Priority:    domain.TaskPriorityP2,
+							Labels: []*domain.Label{
+								{Id: "0b9b4d6e-5c1f-4b0e-9a43-2f6f0c3d8e71", Name: "backend", Color: "#0000ff"},
+								{Id: "c6a1f3e2-7d4b-4e8a-b1f9-3a2d5e6c7b80", Name: "bug", Color: "#ff0000"},
+							},
+							CreatedAt: testCreatedAt,
+							UpdatedAt: testUpdatedAt,
+						},
+					},
+					Total: 1,
+				},
+			},
+			expected: expected{
+				status:  http.StatusOK,
+				resFile: "test/data/get_task_list/filter_by_labels_res.json.golden",
+			},
+			mockUse: true,
+		},
+		"InvalidLabelMode": {
+			target: "/tasks?label=bug&label_mode=none",
+			expected: expected{
+				status:  http.StatusBadRequest,
+				resFile: "test/data/get_task_list/invalid_label_mode_res.json.golden",
+			},
+			mockUse: false,
+		},
+		"InvalidSort": {
+			target: "/tasks?sort=description",
+			expected: expected{
+				status:  http.StatusBadRequest,
+				resFile: "test/data/get_task_list/invalid_sort_res.json.golden",
+			},
+			mockUse: false,
+		},
+		"InternalServerErr": {
+			target: "/tasks",
+			mockData: mockData{
+				query:    &domain.TaskListQuery{Limit: 20, SortField: domain.TaskSortByTitle},
+				taskList: nil,
+				err:      customError.ErrGetTaskList,
+			},
+			expected: expected{
+				status:  http.StatusInternalServerError,
+				resFile: "test/data/get_task_list/internal_server_err_res.json.golden",
+			},
+			mockUse: true,
+		},
+	}
+
+	for n, tt := range testTable {
+		tt := tt
+
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			for key, values := range tt.header {
+				r.Header[key] = values
+			}
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockTaskUsecase := mock.NewMockTaskUsecase(mockCtrl)
+			if tt.mockUse {
+				mockTaskUsecase.EXPECT().GetTaskList(r.Context(), tt.mockData.query).
+					Return(tt.mockData.taskList, tt.mockData.err)
+			}
+
+			sut := NewTaskHandler(mockTaskUsecase, testCursor)
+			sut.GetTaskList(w, r)
+
+			actualRes := w.Result()
+			helper.AssertResponse(t,
+				actualRes, tt.expected.status, helper.LoadFile(t, tt.expected.resFile),
+			)
+		})
+	}
+}
+
+func TestGetOverdueTasks(t *testing.T) {
+	overdue := true
+	dueAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testTable := map[string]struct {
+		target  string
+		query   *domain.TaskListQuery
+		resFile string
+	}{
+		"SortedByDueDate": {
+			target:  "/tasks/overdue",
+			query:   &domain.TaskListQuery{Limit: 20, Overdue: &overdue, SortField: domain.TaskSortByDueAt},
+			resFile: "test/data/get_overdue_tasks/ok_res.json.golden",
+		},
+		"SortedByPriority": {
+			target:  "/tasks/overdue?overdue=false&sort=priority",
+			query:   &domain.TaskListQuery{Limit: 20, Overdue: &overdue, SortField: domain.TaskSortByPriority},
+			resFile: "test/data/get_overdue_tasks/ok_res.json.golden",
+		},
+	}
+
+	for n, tt := range testTable {
+		tt := tt
+
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, tt.target, nil)
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockTaskUsecase := mock.NewMockTaskUsecase(mockCtrl)
+			mockTaskUsecase.EXPECT().GetTaskList(r.Context(), tt.query).
+				Return(&domain.TaskList{
+					Tasks: []*domain.Task{
+						{
+							Id:          "f299e7ed-a22a-4494-b59e-21bb91fdae3b",
+							Title:       "test title",
+							Description: "test description",
+							State:       domain.TaskStateInProgress,
+							Priority:    domain.TaskPriorityP1,
+							DueAt:       &dueAt,
+							CreatedAt:   testCreatedAt,
+							UpdatedAt:   testUpdatedAt,
+						},
+					},
+					Total:        1,
+					LastModified: testUpdatedAt,
+				}, nil)
+
+			sut := NewTaskHandler(mockTaskUsecase, testCursor)
+			sut.GetOverdueTasks(w, r)
+
+			helper.AssertResponse(t,
+				w.Result(), http.StatusOK, helper.LoadFile(t, tt.resFile),
+			)
+		})
+	}
+}
+
+func TestGetSubtasks(t *testing.T) {
+	parentId := domain.TaskID("f299e7ed-a22a-4494-b59e-21bb91fdae3b")
+
+	type expected struct {
+		status  int
+		resFile string
+	}
+
+	testTable := map[string]struct {
+		id       string
+		target   string
+		query    *domain.TaskListQuery
+		err      error
+		expected expected
+	}{
+		"Ok": {
+			id:     parentId.String(),
+			target: "/tasks/f299e7ed-a22a-4494-b59e-21bb91fdae3b/subtasks?sort=-priority",
+			query: &domain.TaskListQuery{
+				Limit: 20, ParentId: &parentId, SortField: domain.TaskSortByPriority, SortDesc: true,
+			},
+			expected: expected{
+				status:  http.StatusOK,
+				resFile: "test/data/get_subtasks/ok_res.json.golden",
+			},
+		},
+		"ParentNotFound": {
+			id:     parentId.String(),
+			target: "/tasks/f299e7ed-a22a-4494-b59e-21bb91fdae3b/subtasks",
+			query:  &domain.TaskListQuery{Limit: 20, ParentId: &parentId, SortField: domain.TaskSortByTitle},
+			err:    customError.ErrTaskNotFound,
+			expected: expected{
+				status:  http.StatusNotFound,
+				resFile: "test/data/get_subtasks/not_found_res.json.golden",
+			},
+		},
+		"InvalidId": {
+			id:     "abc123",
+			target: "/tasks/abc123/subtasks",
+			expected: expected{
+				status:  http.StatusBadRequest,
+				resFile: "test/data/get_subtasks/invalid_id_res.json.golden",
+			},
+		},
+	}
+
+	for n, tt := range testTable {
+		tt := tt
+
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			r.SetPathValue("id", tt.id)
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockTaskUsecase := mock.NewMockTaskUsecase(mockCtrl)
+			if tt.query != nil {
+				var taskList *domain.TaskList
+				if tt.err == nil {
+					taskList = &domain.TaskList{
+						Tasks: []*domain.Task{
+							{
+								Id:        "6a30b9b0-18bf-47b4-bd23-d72726864def",
+								Title:     "test subtask",
+								State:     domain.TaskStateDone,
+								Priority:  domain.TaskPriorityP1,
+								ParentId:  &parentId,
+								Progress:  domain.TaskProgress{Completed: 1, Total: 2},
+								CreatedAt: testCreatedAt, UpdatedAt: testUpdatedAt, CompletedAt: &testUpdatedAt,
+							},
+						},
+						Total:        1,
+						LastModified: testUpdatedAt,
+					}
+				}
+				mockTaskUsecase.EXPECT().GetTaskList(r.Context(), tt.query).Return(taskList, tt.err)
+			}
+
+			sut := NewTaskHandler(mockTaskUsecase, testCursor)
+			sut.GetSubtasks(w, r)
+
+			helper.AssertResponse(t,
+				w.Result(), tt.expected.status, helper.LoadFile(t, tt.expected.resFile),
+			)
+		})
+	}
+}
+
+func TestGetTaskTree(t *testing.T) {
+	rootId := domain.TaskID("f299e7ed-a22a-4494-b59e-21bb91fdae3b")
+	childId := domain.TaskID("3e440171-0921-4c88-a7ec-13f4cdab0d69")
+
+	type expected struct {
+		status  int
+		resFile string
+	}
+
+	testTable := map[string]struct {
+		target   string
+		tree     *domain.Task
+		err      error
+		mockUse  bool
+		expected expected
+	}{
+		"Ok": {
+			target: "/tasks/f299e7ed-a22a-4494-b59e-21bb91fdae3b?expand=subtasks",
+			tree: &domain.Task{
+				Id: rootId, Title: "test title", State: domain.TaskStateInProgress, Priority: domain.TaskPriorityP2,
+				Progress:  domain.TaskProgress{Completed: 0, Total: 1},
+				CreatedAt: testCreatedAt, UpdatedAt: testUpdatedAt, Version: 3,
+				Subtasks: []*domain.Task{
+					{
+						Id: childId, Title: "test subtask", State: domain.TaskStateTodo, Priority: domain.TaskPriorityP2,
+						ParentId: &rootId, Progress: domain.TaskProgress{Completed: 1, Total: 1},
+						CreatedAt: testCreatedAt, UpdatedAt: testUpdatedAt, Version: 2,
+						Subtasks: []*domain.Task{
+							{
+								Id: "6a30b9b0-18bf-47b4-bd23-d72726864def", Title: "test subsubtask",
+								State: domain.TaskStateDone, Priority: domain.TaskPriorityP3, ParentId: &childId,
+								CreatedAt: testCreatedAt, UpdatedAt: testUpdatedAt, CompletedAt: &testUpdatedAt, Version: 2,
+							},
+						},
+					},
+				},
+			},
+			mockUse: true,
+			expected: expected{
+				status:  http.StatusOK,
+				resFile: "test/data/get_task_tree/ok_res.json.golden",
+			},
+		},
+		"NotFound": {
+			target:  "/tasks/f299e7ed-a22a-4494-b59e-21bb91fdae3b?expand=subtasks",
+			err:     customError.ErrTaskNotFound,
+			mockUse: true,
+			expected: expected{
+				status:  http.StatusNotFound,
+				resFile: "test/data/get_task_by_id/not_found_res.json.golden",
+			},
+		},
+		"UnknownExpand": {
+			target: "/tasks/f299e7ed-a22a-4494-b59e-21bb91fdae3b?expand=labels",
+			expected: expected{
+				status:  http.StatusBadRequest,
+				resFile: "test/data/get_task_tree/unknown_expand_res.json.golden",
+			},
+		},
+	}
+
+	for n, tt := range testTable {
+		tt := tt
+
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			r.SetPathValue("id", rootId.String())
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockTaskUsecase := mock.NewMockTaskUsecase(mockCtrl)
+			if tt.mockUse {
+				mockTaskUsecase.EXPECT().GetTaskTree(r.Context(), rootId).Return(tt.tree, tt.err)
+			}
+
+			sut := NewTaskHandler(mockTaskUsecase, testCursor)
+			sut.GetTaskById(w, r)
+
+			actualRes := w.Result()
+			if etag := actualRes.Header.Get("ETag"); etag != "" {
+				t.Errorf("expected no ETag, but actual %s", etag)
+			}
+			helper.AssertResponse(t,
+				actualRes, tt.expected.status, helper.LoadFile(t, tt.expected.resFile),
+			)
+		})
+	}
+}
+
+func TestGetTaskById(t *testing.T) {
+	type expected struct {
+		status  int
+		resFile string
+		etag    string
+	}
+
+	testTable := map[string]struct {
+		id       string
+		header   http.Header
+		task     *domain.Task
+		err      error
+		expected expected
+	}{
+		"Ok": {
+			id: "f299e7ed-a22a-4494-b59e-21bb91fdae3b",
+			task: &domain.Task{
+				Id:          "f299e7ed-a22a-4494-b59e-21bb91fdae3b",
+				Title:       "test title",
+				Description: "test description",
+				State:       domain.TaskStateTodo,
+				Priority:    domain.TaskPriorityP2,
+				CreatedAt:   testCreatedAt,
+				UpdatedAt:   testUpdatedAt,
+				Version:     4,
+			},
+			err: nil,
+			expected: expected{
+				status:  http.StatusOK,
+				resFile: "test/data/get_task_by_id/ok_res.json.golden",
+				etag:    `"4"`,
+			},
+		},
+		"WithChecklist": {
+			id: "f299e7ed-a22a-4494-b59e-21bb91fdae3b",
+			task: &domain.Task{
+				Id:                "f299e7ed-a22a-4494-b59e-21bb91fdae3b",
+				Title:             "test title",
+				Description:       "test description",
+				State:             domain.TaskStateTodo,
+				Priority:          domain.TaskPriorityP2,
+				ChecklistProgress: domain.ChecklistProgress{Completed: 1, Total: 3},
+				CreatedAt:         testCreatedAt,
+				UpdatedAt:         testUpdatedAt,
+				Version:           4,
+			},
+			err: nil,
+			expected: expected{
+				status:  http.StatusOK,
+				resFile: "test/data/get_task_by_id/with_checklist_res.json.golden",
+				etag:    `"4"`,
+			},
+		},
+		"NotModifiedByETag": {
+			id:     "f299e7ed-a22a-4494-b59e-21bb91fdae3b",
+			header: http.Header{"If-None-Match": {`"3", "4"`}},
+			task: &domain.Task{
+				Id:          "f299e7ed-a22a-4494-b59e-21bb91fdae3b",
+				Title:       "test title",
+				Description: "test description",
+				State:       domain.TaskStateTodo,
+				Priority:    domain.TaskPriorityP2,
+				Version:     4,
+			},
+			err: nil,
+			expected: expected{
+				status:  http.StatusNotModified,
+				resFile: "test/data/not_modified_res.json.golden",
+				etag:    `"4"`,
+			},
+		},
+		"NotModifiedByDate": {
+			id:     "f299e7ed-a22a-4494-b59e-21bb91fdae3b",
+			header: http.Header{"If-Modified-Since": {"Thu, 02 Jan 2025 03:04:05 GMT"}},
+			task: &domain.Task{
+				Id:          "f299e7ed-a22a-4494-b59e-21bb91fdae3b",
+				Title:       "test title",
+				Description: "test description",
+				State:       domain.TaskStateTodo,
+				Priority:    domain.TaskPriorityP2,
+				Version:     4,
+				UpdatedAt:   time.Date(2025, 1, 2, 3, 4, 5, 600, time.UTC),
+			},
+			err: nil,
+			expected: expected{
+				status:  http.StatusNotModified,
+				resFile: "test/data/not_modified_res.json.golden",
+				etag:    `"4"`,
+			},
+		},
+		"ModifiedSince": {
+			id:     "f299e7ed-a22a-4494-b59e-21bb91fdae3b",
+			header: http.Header{"If-Modified-Since": {"Fri, 03 Jan 2025 04:05:05 GMT"}},
+			task: &domain.Task{
+				Id:          "f299e7ed-a22a-4494-b59e-21bb91fdae3b",
+				Title:       "test title",
+				Description: "test description",
+				State:       domain.TaskStateTodo,
+				Priority:    domain.TaskPriorityP2,
+				Version:     4,
+				CreatedAt:   testCreatedAt,
+				UpdatedAt:   testUpdatedAt,
+			},
+			err: nil,
+			expected: expected{
+				status:  http.StatusOK,
+				resFile: "test/data/get_task_by_id/ok_res.json.golden",
+				etag:    `"4"`,
+			},
+		},
+		"NotFound": {
+			id:   "f299e7ed-a22a-4494-b59e-21bb91fdae3b",
+			task: nil,
+			err:  customError.ErrTaskNotFound,
+			expected: expected{
+				status:  http.StatusNotFound,
+				resFile: "test/data/get_task_by_id/not_found_res.json.golden",
+			},
+		},
+		"InvalidId": {
+			id:   "abc123",
+			task: nil,
+			err:  nil,
+			expected: expected{
+				status:  http.StatusBadRequest,
+				resFile: "test/data/get_task_by_id/invalid_id_res.json.golden",
+			},
+		},
+	}
+
+	for n, tt := range testTable {
+		tt := tt
+
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/tasks/%s", tt.id), nil)
+			r.SetPathValue("id", tt.id)
+			for key, values := range tt.header {
+				r.Header[key] = values
+			}
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockTaskUsecase := mock.NewMockTaskUsecase(mockCtrl)
+			if tt.task != nil || tt.err != nil {
+				mockTaskUsecase.EXPECT().GetTaskById(r.Context(), domain.TaskID(tt.id)).
+					Return(tt.task, tt.err)
+			}
+
+			sut := NewTaskHandler(mockTaskUsecase, testCursor)
+			sut.GetTaskById(w, r)
+
+			actualRes := w.Result()
+			if tt.expected.etag != "" && actualRes.Header.Get("ETag") != tt.expected.etag {
+				t.Errorf("expected ETag %s, but actual %s", tt.expected.etag, actualRes.Header.Get("ETag"))
+			}
+			helper.AssertResponse(t,
+				actualRes, tt.expected.status, helper.LoadFile(t, tt.expected.resFile),
+			)
+		})
+	}
+}
+
+func TestUpdateTask(t *testing.T) {
+	type expected struct {
+		status  int
+		resFile string
+	}
+
+	type mockData struct {
+		inputPatch   *domain.TaskPatch
+		returnedTask *domain.Task
+		err          error
+	}
+
+	testTable := map[string]struct {
+		id       string
+		ifMatch  string
+		reqFile  string
+		expected expected
+		mockData mockData
+		mockUse  bool
+	}{
+		"Ok": {
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			reqFile: "test/data/update_task/ok_req.json.golden",
+			expected: expected{
+				status:  http.StatusOK,
+				resFile: "test/data/update_task/ok_res.json.golden",
+			},
+			mockData: mockData{
+				inputPatch: &domain.TaskPatch{Description: ptr("update test description"), State: ptr(domain.TaskStateDone)},
+				returnedTask: &domain.Task{
+					Id:    "6a30b9b0-18bf-47b4-bd23-d72726864def",
+					Title: "test title", Description: "update test description",
+					State: domain.TaskStateDone, Priority: domain.TaskPriorityP2, Version: 2,
+					CreatedAt: testCreatedAt, UpdatedAt: testUpdatedAt, CompletedAt: &testUpdatedAt,
+				},
+				err: nil,
+			},
+			mockUse: true,
+		},
+		"Rename": {
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			reqFile: "test/data/update_task/rename_req.json.golden",
+			expected: expected{
+				status:  http.StatusOK,
+				resFile: "test/data/update_task/rename_res.json.golden",
+			},
+			mockData: mockData{
+				inputPatch: &domain.TaskPatch{Title: ptr("renamed test title")},
+				returnedTask: &domain.Task{
+					Id:    "6a30b9b0-18bf-47b4-bd23-d72726864def",
+					Title: "renamed test title", Description: "test description",
+					State: domain.TaskStateTodo, Priority: domain.TaskPriorityP2, Version: 2,
+					CreatedAt: testCreatedAt, UpdatedAt: testUpdatedAt,
+				},
+				err: nil,
+			},
+			mockUse: true,
+		},
+		"ClearDueDate": {
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			reqFile: "test/data/update_task/clear_due_date_req.json.golden",
+			expected: expected{
+				status:  http.StatusOK,
+				resFile: "test/data/update_task/clear_due_date_res.json.golden",
+			},
+			mockData: mockData{
+				inputPatch: &domain.TaskPatch{ClearDueAt: true},
+				returnedTask: &domain.Task{
+					Id:    "6a30b9b0-18bf-47b4-bd23-d72726864def",
+					Title: "test title", Description: "test description",
+					State: domain.TaskStateTodo, Priority: domain.TaskPriorityP2, Version: 2,
+					CreatedAt: testCreatedAt, UpdatedAt: testUpdatedAt,
+				},
+				err: nil,
+			},
+			mockUse: true,
+		},
+		"ClearParent": {
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			reqFile: "test/data/update_task/clear_parent_req.json.golden",
+			expected: expected{
+				status:  http.StatusOK,
+				resFile: "test/data/update_task/clear_parent_res.json.golden",
+			},
+			mockData: mockData{
+				inputPatch: &domain.TaskPatch{ClearParent: true},
+				returnedTask: &domain.Task{
+					Id:    "6a30b9b0-18bf-47b4-bd23-d72726864def",
+					Title: "test title", Description: "test description",
+					State: domain.TaskStateTodo, Priority: domain.TaskPriorityP2, Version: 2,
+					CreatedAt: testCreatedAt, UpdatedAt: testUpdatedAt,
+				},
+				err: nil,
+			},
+			mockUse: true,
+		},
+		"ClearEstimate": {
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			reqFile: "test/data/update_task/clear_estimate_req.json.golden",
+			expected: expected{
+				status:  http.StatusOK,
+				resFile: "test/data/update_task/clear_estimate_res.json.golden",
+			},
+			mockData: mockData{
+				inputPatch: &domain.TaskPatch{ClearEstimate: true},
+				returnedTask: &domain.Task{
+					Id:    "6a30b9b0-18bf-47b4-bd23-d72726864def",
+					Title: "test title", Description: "test description",
+					State: domain.TaskStateTodo, Priority: domain.TaskPriorityP2, Version: 2,
+					CreatedAt: testCreatedAt, UpdatedAt: testUpdatedAt,
+				},
+				err: nil,
+			},
+			mockUse: true,
+		},
+		"ParentCycle": {
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			reqFile: "test/data/update_task/move_req.json.golden",
+			expected: expected{
+				status:  http.StatusUnprocessableEntity,
+				resFile: "test/data/update_task/parent_cycle_res.json.golden",
+			},
+			mockData: mockData{
+				inputPatch: &domain.TaskPatch{ParentId: ptr(domain.TaskID("f299e7ed-a22a-4494-b59e-21bb91fdae3b"))},
+				err:        &customError.FieldError{Field: "parent_id", Err: customError.ErrTaskParentCycle},
+			},
+			mockUse: true,
+		},
+		"TooDeep": {
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			reqFile: "test/data/update_task/move_req.json.golden",
+			expected: expected{
+				status:  http.StatusUnprocessableEntity,
+				resFile: "test/data/update_task/too_deep_res.json.golden",
+			},
+			mockData: mockData{
+				inputPatch: &domain.TaskPatch{ParentId: ptr(domain.TaskID("f299e7ed-a22a-4494-b59e-21bb91fdae3b"))},
+				err:        &customError.FieldError{Field: "parent_id", Err: customError.ErrTaskTooDeep},
+			},
+			mockUse: true,
+		},
+		"InvalidDueDate": {
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			reqFile: "test/data/update_task/invalid_due_date_req.json.golden",
+			expected: expected{
+				status:  http.StatusBadRequest,
+				resFile: "test/data/update_task/invalid_due_date_res.json.golden",
+			},
+			mockUse: false,
+		},
+		"EmptyTitle": {
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			reqFile: "test/data/update_task/empty_title_req.json.golden",
+			expected: expected{
+				status:  http.StatusBadRequest,
+				resFile: "test/data/update_task/empty_title_res.json.golden",
+			},
+			mockUse: false,
+		},
+		"TitleConflict": {
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			reqFile: "test/data/update_task/rename_req.json.golden",
+			expected: expected{
+				status:  http.StatusConflict,
+				resFile: "test/data/update_task/title_conflict_res.json.golden",
+			},
+			mockData: mockData{
+				inputPatch:   &domain.TaskPatch{Title: ptr("renamed test title")},
+				returnedTask: nil,
+				err:          &customError.FieldError{Field: "title", Err: customError.ErrTaskConflict},
+			},
+			mockUse: true,
+		},
+		"UnmarshalFail": {
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			reqFile: "test/data/update_task/unmarshal_fail_req.json.golden",
+			expected: expected{
+				status:  http.StatusBadRequest,
+				resFile: "test/data/update_task/unmarshal_fail_res.json.golden",
+			},
+			mockData: mockData{
+				inputPatch:   nil,
+				returnedTask: nil,
+				err:          nil,
+			},
+			mockUse: false,
+		},
+		"BadRequest": {
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			reqFile: "test/data/update_task/bad_req_req.json.golden",
+			expected: expected{
+				status:  http.StatusBadRequest,
+				resFile: "test/data/update_task/bad_req_res.json.golden",
+			},
+			mockData: mockData{
+				inputPatch:   nil,
+				returnedTask: nil,
+				err:          nil,
+			},
+			mockUse: false,
+		},
+		"NotFound": {
+			id:      "7a30b9b0-18bf-47b4-bd23-d72726864def",
+			reqFile: "test/data/update_task/not_found_req.json.golden",
+			expected: expected{
+				status:  http.StatusNotFound,
+				resFile: "test/data/update_task/not_found_res.json.golden",
+			},
+			mockData: mockData{
+				inputPatch:   &domain.TaskPatch{Description: ptr("update test description"), State: ptr(domain.TaskStateDone)},
+				returnedTask: nil,
+				err:          customError.ErrTaskNotFound,
+			},
+			mockUse: true,
+		},
+		"PreconditionFailed": {
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			ifMatch: `"3"`,
+			reqFile: "test/data/update_task/ok_req.json.golden",
+			expected: expected{
+				status:  http.StatusPreconditionFailed,
+				resFile: "test/data/update_task/precondition_failed_res.json.golden",
+			},
+			mockData: mockData{
+				inputPatch:   &domain.TaskPatch{Description: ptr("update test description"), State: ptr(domain.TaskStateDone), Version: 3},
+				returnedTask: nil,
+				err:          customError.ErrTaskVersionMismatch,
+			},
+			mockUse: true,
+		},
+		"ChangeState": {
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			reqFile: "test/data/update_task/change_state_req.json.golden",
+			expected: expected{
+				status:  http.StatusOK,
+				resFile: "test/data/update_task/change_state_res.json.golden",
+			},
+			mockData: mockData{
+				inputPatch: &domain.TaskPatch{State: ptr(domain.TaskStateInProgress)},
+				returnedTask: &domain.Task{
+					Id:    "6a30b9b0-18bf-47b4-bd23-d72726864def",
+					Title: "test title", Description: "test description",
+					State: domain.TaskStateInProgress, Priority: domain.TaskPriorityP2, Version: 2,
+					CreatedAt: testCreatedAt, UpdatedAt: testUpdatedAt,
+				},
+				err: nil,
+			},
+			mockUse: true,
+		},
+		"StatusAndState": {
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			reqFile: "test/data/update_task/status_and_state_req.json.golden",
+			expected: expected{
+				status:  http.StatusBadRequest,
+				resFile: "test/data/update_task/status_and_state_res.json.golden",
+			},
+			mockUse: false,
+		},
+		"TransitionNotAllowed": {
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			reqFile: "test/data/update_task/ok_req.json.golden",
+			expected: expected{
+				status:  http.StatusConflict,
+				resFile: "test/data/update_task/transition_not_allowed_res.json.golden",
+			},
+			mockData: mockData{
+				inputPatch:   &domain.TaskPatch{Description: ptr("update test description"), State: ptr(domain.TaskStateDone)},
+				returnedTask: nil,
+				err: &customError.TransitionError{
+					From: "blocked", To: "done", Allowed: []string{"todo", "in_progress"},
+				},
+			},
+			mockUse: true,
+		},
+		"InvalidId": {
+			id:      "abc123",
+			reqFile: "test/data/update_task/invalid_id_req.json.golden",
+			expected: expected{
+				status:  http.StatusBadRequest,
+				resFile: "test/data/update_task/invalid_id_res.json.golden",
+			},
+			mockUse: false,
+		},
+		"InternalServerErr": {
+			id:      "7a30b9b0-18bf-47b4-bd23-d72726864def",
+			reqFile: "test/data/update_task/invalid_id_req.json.golden",
+			expected: expected{
+				status:  http.StatusInternalServerError,
+				resFile: "test/data/update_task/internal_server_err_res.json.golden",
+			},
+			mockData: mockData{
+				inputPatch:   &domain.TaskPatch{Description: ptr("update test description"), State: ptr(domain.TaskStateDone)},
+				returnedTask: nil,
+				err:          customError.ErrUpdateTask,
+			},
+			mockUse: true,
+		},
+	}
+
+	for n, tt := range testTable {
+		tt := tt
+
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodPatch,
+				fmt.Sprintf("/tasks/%s", tt.id),
+				bytes.NewReader(helper.LoadFile(t, tt.reqFile)),
+			)
+			r.SetPathValue("id", tt.id)
+			r.Header.Set("Content-Type", "application/json")
+			if tt.ifMatch != "" {
+				r.Header.Set("If-Match", tt.ifMatch)
+			}
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockTaskUsecase := mock.NewMockTaskUsecase(mockCtrl)
+
+			if tt.mockUse {
+				mockTaskUsecase.EXPECT().UpdateTask(r.Context(), domain.TaskID(tt.id), tt.mockData.inputPatch).
+					Return(tt.mockData.returnedTask, tt.mockData.err)
+			}
+
+			sut := NewTaskHandler(mockTaskUsecase, testCursor)
+			sut.UpdateTask(w, r)
+
+			actualRes := w.Result()
+			helper.AssertResponse(t,
+				actualRes, tt.expected.status, helper.LoadFile(t, tt.expected.resFile),
+			)
+		})
+	}
+}
+
+func TestUpdateTaskWithPatchDocument(t *testing.T) {
+	type expected struct {
+		status      int
+		resFile     string
+		acceptPatch string
+	}
+
+	type mockData struct {
+		currentTask  *domain.Task
+		getErr       error
+		inputPatch   *domain.TaskPatch
+		returnedTask *domain.Task
+		updateErr    error
+	}
+
+	currentTask := &domain.Task{
+		Id:    "6a30b9b0-18bf-47b4-bd23-d72726864def",
+		Title: "test title", Description: "test description",
+		State: domain.TaskStateTodo, Priority: domain.TaskPriorityP2, Version: 2,
+		CreatedAt: testCreatedAt, UpdatedAt: testCreatedAt,
+	}
+	updatedTask := &domain.Task{
+		Id:    "6a30b9b0-18bf-47b4-bd23-d72726864def",
+		Title: "test title", Description: "update test description",
+		State: domain.TaskStateDone, Priority: domain.TaskPriorityP2, Version: 3,
+		CreatedAt: testCreatedAt, UpdatedAt: testUpdatedAt, CompletedAt: &testUpdatedAt,
+	}
+	updatePatch := &domain.TaskPatch{
+		Title: ptr("test title"), Description: ptr("update test description"),
+		State: ptr(domain.TaskStateDone), Priority: ptr(domain.TaskPriorityP2),
+		ClearDueAt: true, ClearEstimate: true, ClearParent: true, Version: 2,
+	}
+
+	testTable := map[string]struct {
+		contentType string
+		ifMatch     string
+		reqFile     string
+		expected    expected
+		mockData    mockData
+	}{
+		"MergePatch": {
+			contentType: "application/merge-patch+json",
+			reqFile:     "test/data/patch_task/merge_patch_req.json.golden",
+			expected: expected{
+				status:  http.StatusOK,
+				resFile: "test/data/update_task/ok_res.json.golden",
+			},
+			mockData: mockData{
+				currentTask:  currentTask,
+				inputPatch:   updatePatch,
+				returnedTask: updatedTask,
+			},
+		},
+		"JSONPatch": {
+			contentType: "application/json-patch+json; charset=utf-8",
+			ifMatch:     `"2"`,
+			reqFile:     "test/data/patch_task/json_patch_req.json.golden",
+			expected: expected{
+				status:  http.StatusOK,
+				resFile: "test/data/update_task/ok_res.json.golden",
+			},
+			mockData: mockData{
+				currentTask:  currentTask,
+				inputPatch:   updatePatch,
+				returnedTask: updatedTask,
+			},
+		},
+		"MergePatchRemovesTitle": {
+			contentType: "application/merge-patch+json",
+			reqFile:     "test/data/patch_task/remove_title_req.json.golden",
+			expected: expected{
+				status:  http.StatusUnprocessableEntity,
+				resFile: "test/data/patch_task/remove_title_res.json.golden",
+			},
+			mockData: mockData{
+				currentTask: currentTask,
+			},
+		},
+		"MergePatchUnknownMember": {
+			contentType: "application/merge-patch+json",
+			reqFile:     "test/data/patch_task/unknown_member_req.json.golden",
+			expected: expected{
+				status:  http.StatusUnprocessableEntity,
+				resFile: "test/data/patch_task/unknown_member_res.json.golden",
+			},
+			mockData: mockData{
+				currentTask: currentTask,
+			},
+		},
+		"JSONPatchTestFails": {
+			contentType: "application/json-patch+json",
+			reqFile:     "test/data/patch_task/test_fails_req.json.golden",
+			expected: expected{
+				status:  http.StatusConflict,
+				resFile: "test/data/patch_task/test_fails_res.json.golden",
+			},
+			mockData: mockData{
+				currentTask: currentTask,
+			},
+		},
+		"InvalidJSONPatch": {
+			contentType: "application/json-patch+json",
+			reqFile:     "test/data/patch_task/merge_patch_req.json.golden",
+			expected: expected{
+				status:  http.StatusBadRequest,
+				resFile: "test/data/patch_task/invalid_patch_res.json.golden",
+			},
+			mockData: mockData{
+				currentTask: currentTask,
+			},
+		},
+		"NotFound": {
+			contentType: "application/merge-patch+json",
+			reqFile:     "test/data/patch_task/merge_patch_req.json.golden",
+			expected: expected{
+				status:  http.StatusNotFound,
+				resFile: "test/data/patch_task/not_found_res.json.golden",
+			},
+			mockData: mockData{
+				getErr: customError.ErrTaskNotFound,
+			},
+		},
+		"StaleIfMatch": {
+			contentType: "application/merge-patch+json",
+			ifMatch:     `"1"`,
+			reqFile:     "test/data/patch_task/merge_patch_req.json.golden",
+			expected: expected{
+				status:  http.StatusPreconditionFailed,
+				resFile: "test/data/update_task/precondition_failed_res.json.golden",
+			},
+			mockData: mockData{
+				currentTask: currentTask,
+			},
+		},
+		"ConcurrentChange": {
+			contentType: "application/merge-patch+json",
+			reqFile:     "test/data/patch_task/merge_patch_req.json.golden",
+			expected: expected{
+				status:  http.StatusConflict,
+				resFile: "test/data/patch_task/concurrent_change_res.json.golden",
+			},
+			mockData: mockData{
+				currentTask: currentTask,
+				inputPatch:  updatePatch,
+				updateErr:   customError.ErrTaskVersionMismatch,
+			},
+		},
+		"UnsupportedMediaType": {
+			contentType: "text/plain",
+			reqFile:     "test/data/patch_task/merge_patch_req.json.golden",
+			expected: expected{
+				status:      http.StatusUnsupportedMediaType,
+				resFile:     "test/data/patch_task/unsupported_media_type_res.json.golden",
+				acceptPatch: "application/json, application/merge-patch+json, application/json-patch+json",
+			},
+		},
+	}
+
+	for n, tt := range testTable {
+		tt := tt
+
+		t.Run(n, func(t *testing.T) {
+			t.Parallel()
+
+			id := "6a30b9b0-18bf-47b4-bd23-d72726864def"
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodPatch,
+				fmt.Sprintf("/tasks/%s", id),
+				bytes.NewReader(helper.LoadFile(t, tt.reqFile)),
+			)
+			r.SetPathValue("id", id)
+			r.Header.Set("Content-Type", tt.contentType)
+			if tt.ifMatch != "" {
+				r.Header.Set("If-Match", tt.ifMatch)
+			}
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockTaskUsecase := mock.NewMockTaskUsecase(mockCtrl)
+
+			if tt.mockData.currentTask != nil || tt.mockData.getErr != nil {
+				mockTaskUsecase.EXPECT().GetTaskById(r.Context(), domain.TaskID(id)).
+					Return(tt.mockData.currentTask, tt.mockData.getErr)
+			}
+			if tt.mockData.inputPatch != nil {
+				mockTaskUsecase.EXPECT().UpdateTask(r.Context(), domain.TaskID(id), tt.mockData.inputPatch).
+					Return(tt.mockData.returnedTask, tt.mockData.updateErr)
+			}
+
+			sut := NewTaskHandler(mockTaskUsecase, testCursor)
+			sut.UpdateTask(w, r)
+
+			actualRes := w.Result()
+			helper.AssertResponse(t,
+				actualRes, tt.expected.status, helper.LoadFile(t, tt.expected.resFile),
+			)
+			if actual := actualRes.Header.Get("Accept-Patch"); actual != tt.expected.acceptPatch {
+				t.Errorf("expected Accept-Patch %q, but actual %q", tt.expected.acceptPatch, actual)
+			}
+		})
+	}
+}
+
+func TestDeleteTask(t *testing.T) {
+	type expected struct {
+		status  int
+		resFile string
+	}
+
+	type mockData struct {
+		returnedTask *domain.Task
+		err          error
+	}
+
+	testTable := map[string]struct {
+		id       string
+		query    string
+		ifMatch  string
+		version  int
+		purge    bool
+		mode     domain.SubtaskMode
 		expected expected
 		mockData mockData
 		mockUse  bool
 	}{
 		"Ok": {
-			reqFile: "test/data/add_task/ok_req.json.golden",
+			id: "f299e7ed-a22a-4494-b59e-21bb91fdae3b",
 			expected: expected{
-				status:  http.StatusCreated,
-				resFile: "test/data/add_task/ok_res.json.golden",
+				status:  http.StatusOK,
+				resFile: "test/data/delete_task/ok_res.json.golden",
 			},
 			mockData: mockData{
-				param: &domain.Task{Title: "test title", Description: "test description"},
-				returned: &domain.Task{
-					Id:    "6a30b9b0-18bf-47b4-bd23-d72726864def",
-					Title: "test title", Description: "test description",
-					Status: false,
+				returnedTask: &domain.Task{
+					Id: "f299e7ed-a22a-4494-b59e-21bb91fdae3b",
 				},
 				err: nil,
 			},
 			mockUse: true,
 		},
-		"DuplicateErr": {
-			reqFile: "test/data/add_task/duplicate_err_req.json.golden",
+		"NotFound": {
+			id: "f299e7ed-a22a-4494-b59e-21bb91fdae3b",
 			expected: expected{
-				status:  http.StatusInternalServerError,
-				resFile: "test/data/add_task/duplicate_err_res.json.golden",
+				status:  http.StatusNotFound,
+				resFile: "test/data/delete_task/not_found_res.json.golden",
 			},
 			mockData: mockData{
-				param:    &domain.Task{Title: "duplicate test title", Description: "test description"},
-				returned: nil,
-				err:      customError.ErrAddTask,
+				returnedTask: nil,
+				err:          customError.ErrTaskNotFound,
 			},
 			mockUse: true,
 		},
-		"UnmarshalFail": {
-			reqFile: "test/data/add_task/unmarshal_fail_req.json.golden",
+		"PreconditionFailed": {
+			id:      "f299e7ed-a22a-4494-b59e-21bb91fdae3b",
+			ifMatch: `"2"`,
+			version: 2,
 			expected: expected{
-				status:  http.StatusInternalServerError,
-				resFile: "test/data/add_task/unmarshal_fail_res.json.golden",
+				status:  http.StatusPreconditionFailed,
+				resFile: "test/data/delete_task/precondition_failed_res.json.golden",
 			},
 			mockData: mockData{
-				param:    nil,
-				returned: nil,
+				returnedTask: nil,
+				err:          customError.ErrTaskVersionMismatch,
+			},
+			mockUse: true,
+		},
+		"Purge": {
+			id:    "f299e7ed-a22a-4494-b59e-21bb91fdae3b",
+			query: "?purge=true",
+			purge: true,
+			expected: expected{
+				status:  http.StatusOK,
+				resFile: "test/data/delete_task/ok_res.json.golden",
+			},
+			mockData: mockData{
+				returnedTask: &domain.Task{
+					Id: "f299e7ed-a22a-4494-b59e-21bb91fdae3b",
+				},
+				err: nil,
+			},
+			mockUse: true,
+		},
+		"PurgeForbidden": {
+			id:    "f299e7ed-a22a-4494-b59e-21bb91fdae3b",
+			query: "?purge=true",
+			purge: true,
+			expected: expected{
+				status:  http.StatusForbidden,
+				resFile: "test/data/delete_task/forbidden_res.json.golden",
+			},
+			mockData: mockData{
+				returnedTask: nil,
+				err:          fmt.Errorf("%w: role 'editor' may not purge_task", customError.ErrForbidden),
+			},
+			mockUse: true,
+		},
+		"Cascade": {
+			id:    "f299e7ed-a22a-4494-b59e-21bb91fdae3b",
+			query: "?subtasks=cascade",
+			mode:  domain.SubtaskModeCascade,
+			expected: expected{
+				status:  http.StatusOK,
+				resFile: "test/data/delete_task/ok_res.json.golden",
+			},
+			mockData: mockData{
+				returnedTask: &domain.Task{
+					Id: "f299e7ed-a22a-4494-b59e-21bb91fdae3b",
+				},
+				err: nil,
+			},
+			mockUse: true,
+		},
+		"PurgeReparent": {
+			id:    "f299e7ed-a22a-4494-b59e-21bb91fdae3b",
+			query: "?purge=true&subtasks=reparent",
+			purge: true,
+			mode:  domain.SubtaskModeReparent,
+			expected: expected{
+				status:  http.StatusOK,
+				resFile: "test/data/delete_task/ok_res.json.golden",
+			},
+			mockData: mockData{
+				returnedTask: &domain.Task{
+					Id: "f299e7ed-a22a-4494-b59e-21bb91fdae3b",
+				},
+				err: nil,
+			},
+			mockUse: true,
+		},
+		"HasSubtasks": {
+			id: "f299e7ed-a22a-4494-b59e-21bb91fdae3b",
+			expected: expected{
+				status:  http.StatusConflict,
+				resFile: "test/data/delete_task/has_subtasks_res.json.golden",
+			},
+			mockData: mockData{
+				returnedTask: nil,
+				err:          customError.ErrTaskHasSubtasks,
+			},
+			mockUse: true,
+		},
+		"InvalidSubtasks": {
+			id:    "f299e7ed-a22a-4494-b59e-21bb91fdae3b",
+			query: "?subtasks=orphan",
+			expected: expected{
+				status:  http.StatusBadRequest,
+				resFile: "test/data/delete_task/invalid_subtasks_res.json.golden",
 			},
 			mockUse: false,
 		},
-		"BadRequest": {
-			reqFile: "test/data/add_task/bad_req_req.json.golden",
+		"InvalidPurge": {
+			id:    "f299e7ed-a22a-4494-b59e-21bb91fdae3b",
+			query: "?purge=yes",
 			expected: expected{
 				status:  http.StatusBadRequest,
-				resFile: "test/data/add_task/bad_req_res.json.golden",
+				resFile: "test/data/delete_task/invalid_purge_res.json.golden",
 			},
-			mockData: mockData{
-				param:    nil,
-				returned: nil,
+			mockUse: false,
+		},
+		"WeakIfMatch": {
+			id:      "f299e7ed-a22a-4494-b59e-21bb91fdae3b",
+			ifMatch: `W/"2"`,
+			expected: expected{
+				status:  http.StatusPreconditionFailed,
+				resFile: "test/data/delete_task/precondition_failed_res.json.golden",
+			},
+			mockUse: false,
+		},
+		"InvalidId": {
+			id: "abc123",
+			expected: expected{
+				status:  http.StatusBadRequest,
+				resFile: "test/data/delete_task/invalid_id_res.json.golden",
 			},
 			mockUse: false,
 		},
@@ -94,23 +1764,26 @@ func TestAddTask(t *testing.T) {
 			t.Parallel()
 
 			w := httptest.NewRecorder()
-			r := httptest.NewRequest(
-				http.MethodPost,
-				"/tasks",
-				bytes.NewReader(helper.LoadFile(t, tt.reqFile)),
-			)
+			r := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/tasks/%s%s", tt.id, tt.query), nil)
+			r.SetPathValue("id", tt.id)
+			if tt.ifMatch != "" {
+				r.Header.Set("If-Match", tt.ifMatch)
+			}
 
 			mockCtrl := gomock.NewController(t)
 			defer mockCtrl.Finish()
 
 			mockTaskUsecase := mock.NewMockTaskUsecase(mockCtrl)
-			if tt.mockUse {
-				mockTaskUsecase.EXPECT().AddTask(r.Context(), tt.mockData.param).
-					Return(tt.mockData.returned, tt.mockData.err)
+			if tt.mockUse && tt.purge {
+				mockTaskUsecase.EXPECT().PurgeTask(r.Context(), domain.TaskID(tt.id), tt.version, tt.mode).
+					Return(tt.mockData.returnedTask, tt.mockData.err)
+			} else if tt.mockUse {
+				mockTaskUsecase.EXPECT().DeleteTask(r.Context(), domain.TaskID(tt.id), tt.version, tt.mode).
+					Return(tt.mockData.returnedTask, tt.mockData.err)
 			}
 
-			sut := NewTaskHandler(mockTaskUsecase)
-			sut.AddTask(w, r)
+			sut := NewTaskHandler(mockTaskUsecase, testCursor)
+			sut.DeleteTask(w, r)
 
 			actualRes := w.Result()
 			helper.AssertResponse(t,
@@ -120,7 +1793,7 @@ func TestAddTask(t *testing.T) {
 	}
 }
 
-func TestGetTaskList(t *testing.T) {
+func TestGetTrash(t *testing.T) {
 	type expected struct {
 		status  int
 		resFile string
@@ -137,35 +1810,25 @@ func TestGetTaskList(t *testing.T) {
 					Id:          "f299e7ed-a22a-4494-b59e-21bb91fdae3b",
 					Title:       "test title",
 					Description: "test description",
-					Status:      false,
-				},
-				{
-					Id:          "4d758d63-5c4f-4bef-9a80-d5837c324a07",
-					Title:       "test title2",
-					Description: "test description2",
-					Status:      false,
+					State:       domain.TaskStateTodo,
+					Priority:    domain.TaskPriorityP2,
+					CreatedAt:   testCreatedAt,
+					UpdatedAt:   testUpdatedAt,
+					DeletedAt:   &testUpdatedAt,
 				},
 			},
 			err: nil,
 			expected: expected{
 				status:  http.StatusOK,
-				resFile: "test/data/get_task_list/ok_res.json.golden",
-			},
-		},
-		"Empty": {
-			taskList: []*domain.Task{},
-			err:      nil,
-			expected: expected{
-				status:  http.StatusOK,
-				resFile: "test/data/get_task_list/empty_res.json.golden",
+				resFile: "test/data/get_trash/ok_res.json.golden",
 			},
 		},
 		"InternalServerErr": {
 			taskList: nil,
-			err:      customError.ErrGetTaskList,
+			err:      customError.ErrGetTrash,
 			expected: expected{
 				status:  http.StatusInternalServerError,
-				resFile: "test/data/get_task_list/internal_server_err_res.json.golden",
+				resFile: "test/data/get_trash/internal_server_err_res.json.golden",
 			},
 		},
 	}
@@ -177,17 +1840,17 @@ func TestGetTaskList(t *testing.T) {
 			t.Parallel()
 
 			w := httptest.NewRecorder()
-			r := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+			r := httptest.NewRequest(http.MethodGet, "/tasks/trash", nil)
 
 			mockCtrl := gomock.NewController(t)
 			defer mockCtrl.Finish()
 
 			mockTaskUsecase := mock.NewMockTaskUsecase(mockCtrl)
-			mockTaskUsecase.EXPECT().GetTaskList(r.Context()).
+			mockTaskUsecase.EXPECT().GetTrash(r.Context()).
 				Return(tt.taskList, tt.err)
 
-			sut := NewTaskHandler(mockTaskUsecase)
-			sut.GetTaskList(w, r)
+			sut := NewTaskHandler(mockTaskUsecase, testCursor)
+			sut.GetTrash(w, r)
 
 			actualRes := w.Result()
 			helper.AssertResponse(t,
@@ -197,7 +1860,7 @@ func TestGetTaskList(t *testing.T) {
 	}
 }
 
-func TestGetTaskById(t *testing.T) {
+func TestRestoreTask(t *testing.T) {
 	type expected struct {
 		status  int
 		resFile string
@@ -215,30 +1878,34 @@ func TestGetTaskById(t *testing.T) {
 				Id:          "f299e7ed-a22a-4494-b59e-21bb91fdae3b",
 				Title:       "test title",
 				Description: "test description",
-				Status:      false,
+				State:       domain.TaskStateTodo,
+				Priority:    domain.TaskPriorityP2,
+				Version:     2,
+				CreatedAt:   testCreatedAt,
+				UpdatedAt:   testUpdatedAt,
 			},
 			err: nil,
 			expected: expected{
 				status:  http.StatusOK,
-				resFile: "test/data/get_task_by_id/ok_res.json.golden",
+				resFile: "test/data/restore_task/ok_res.json.golden",
 			},
 		},
-		"NotFound": {
+		"NotInTrash": {
 			id:   "f299e7ed-a22a-4494-b59e-21bb91fdae3b",
 			task: nil,
-			err:  customError.ErrTaskNotFound,
+			err:  customError.ErrTaskNotInTrash,
 			expected: expected{
 				status:  http.StatusNotFound,
-				resFile: "test/data/get_task_by_id/not_found_res.json.golden",
+				resFile: "test/data/restore_task/not_in_trash_res.json.golden",
 			},
 		},
 		"InvalidId": {
 			id:   "abc123",
 			task: nil,
-			err:  customError.ErrGetTaskById,
+			err:  nil,
 			expected: expected{
-				status:  http.StatusInternalServerError,
-				resFile: "test/data/get_task_by_id/invalid_id_res.json.golden",
+				status:  http.StatusBadRequest,
+				resFile: "test/data/restore_task/invalid_id_res.json.golden",
 			},
 		},
 	}
@@ -250,40 +1917,53 @@ func TestGetTaskById(t *testing.T) {
 			t.Parallel()
 
 			w := httptest.NewRecorder()
-			r := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/tasks/%s", tt.id), nil)
+			r := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/tasks/%s/restore", tt.id), nil)
 			r.SetPathValue("id", tt.id)
 
 			mockCtrl := gomock.NewController(t)
 			defer mockCtrl.Finish()
 
 			mockTaskUsecase := mock.NewMockTaskUsecase(mockCtrl)
-			mockTaskUsecase.EXPECT().GetTaskById(r.Context(), tt.id).
-				Return(tt.task, tt.err)
+			if tt.task != nil || tt.err != nil {
+				mockTaskUsecase.EXPECT().RestoreTask(r.Context(), domain.TaskID(tt.id)).
+					Return(tt.task, tt.err)
+			}
 
-			sut := NewTaskHandler(mockTaskUsecase)
-			sut.GetTaskById(w, r)
+			sut := NewTaskHandler(mockTaskUsecase, testCursor)
+			sut.RestoreTask(w, r)
 
 			actualRes := w.Result()
 			helper.AssertResponse(t,
 				actualRes, tt.expected.status, helper.LoadFile(t, tt.expected.resFile),
 			)
+
+			contentType := "application/json; charset=utf-8"
+			if tt.expected.status >= http.StatusBadRequest {
+				contentType = "application/problem+json; charset=utf-8"
+			}
+			if actual := actualRes.Header.Get("Content-Type"); actual != contentType {
+				t.Errorf("expected Content-Type %q, but actual %q", contentType, actual)
+			}
 		})
 	}
 }
 
-func TestUpdateTask(t *testing.T) {
+func TestTransitionTask(t *testing.T) {
 	type expected struct {
 		status  int
 		resFile string
 	}
 
 	type mockData struct {
-		inputTask, returnedTask *domain.Task
-		err                     error
+		inputPatch   *domain.TaskPatch
+		returnedTask *domain.Task
+		err          error
 	}
 
 	testTable := map[string]struct {
 		id       string
+		query    string
+		ifMatch  string
 		reqFile  string
 		expected expected
 		mockData mockData
@@ -291,76 +1971,105 @@ func TestUpdateTask(t *testing.T) {
 	}{
 		"Ok": {
 			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
-			reqFile: "test/data/update_task/ok_req.json.golden",
+			ifMatch: `"1"`,
+			reqFile: "test/data/transition_task/ok_req.json.golden",
 			expected: expected{
 				status:  http.StatusOK,
-				resFile: "test/data/update_task/ok_res.json.golden",
+				resFile: "test/data/transition_task/ok_res.json.golden",
 			},
 			mockData: mockData{
-				inputTask: &domain.Task{Description: "update test description", Status: true},
+				inputPatch: &domain.TaskPatch{State: ptr(domain.TaskStateInReview), Version: 1},
 				returnedTask: &domain.Task{
 					Id:    "6a30b9b0-18bf-47b4-bd23-d72726864def",
-					Title: "test title", Description: "update test description",
-					Status: true,
+					Title: "test title", Description: "test description",
+					State: domain.TaskStateInReview, Priority: domain.TaskPriorityP2, Version: 2,
+					CreatedAt: testCreatedAt, UpdatedAt: testUpdatedAt,
 				},
-				err: nil,
 			},
 			mockUse: true,
 		},
-		"UnmarshalFail": {
-			reqFile: "test/data/update_task/unmarshal_fail_req.json.golden",
+		"NotAllowed": {
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			reqFile: "test/data/transition_task/ok_req.json.golden",
 			expected: expected{
-				status:  http.StatusInternalServerError,
-				resFile: "test/data/update_task/unmarshal_fail_res.json.golden",
+				status:  http.StatusConflict,
+				resFile: "test/data/transition_task/not_allowed_res.json.golden",
 			},
 			mockData: mockData{
-				inputTask:    nil,
-				returnedTask: nil,
-				err:          nil,
+				inputPatch: &domain.TaskPatch{State: ptr(domain.TaskStateInReview)},
+				err: &customError.TransitionError{
+					From: "todo", To: "in_review", Allowed: []string{"in_progress", "blocked", "done", "archived"},
+				},
 			},
-			mockUse: false,
+			mockUse: true,
 		},
-		"BadRequest": {
-			reqFile: "test/data/update_task/bad_req_req.json.golden",
+		"ConcurrentChange": {
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			reqFile: "test/data/transition_task/ok_req.json.golden",
 			expected: expected{
-				status:  http.StatusBadRequest,
-				resFile: "test/data/update_task/bad_req_res.json.golden",
+				status:  http.StatusConflict,
+				resFile: "test/data/transition_task/concurrent_change_res.json.golden",
 			},
 			mockData: mockData{
-				inputTask:    nil,
-				returnedTask: nil,
-				err:          nil,
+				inputPatch: &domain.TaskPatch{State: ptr(domain.TaskStateInReview)},
+				err:        customError.ErrTaskVersionMismatch,
 			},
-			mockUse: false,
+			mockUse: true,
 		},
-		"NotFound": {
-			id:      "7a30b9b0-18bf-47b4-bd23-d72726864def",
-			reqFile: "test/data/update_task/not_found_req.json.golden",
+		"Blocked": {
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			reqFile: "test/data/transition_task/done_req.json.golden",
 			expected: expected{
-				status:  http.StatusNotFound,
-				resFile: "test/data/update_task/not_found_res.json.golden",
+				status:  http.StatusConflict,
+				resFile: "test/data/transition_task/blocked_res.json.golden",
 			},
 			mockData: mockData{
-				inputTask:    &domain.Task{Description: "update test description", Status: true},
-				returnedTask: nil,
-				err:          customError.ErrTaskNotFound,
+				inputPatch: &domain.TaskPatch{State: ptr(domain.TaskStateDone)},
+				err:        &customError.BlockedError{Blockers: []string{"f299e7ed-a22a-4494-b59e-21bb91fdae3b"}},
 			},
 			mockUse: true,
 		},
-		"InvalidId": {
-			id:      "7a30b9b0-18bf-47b4-bd23-d72726864def",
-			reqFile: "test/data/update_task/invalid_id_req.json.golden",
+		"Forced": {
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			query:   "?force=true",
+			reqFile: "test/data/transition_task/done_req.json.golden",
 			expected: expected{
-				status:  http.StatusInternalServerError,
-				resFile: "test/data/update_task/invalid_id_res.json.golden",
+				status:  http.StatusOK,
+				resFile: "test/data/transition_task/forced_res.json.golden",
 			},
 			mockData: mockData{
-				inputTask:    &domain.Task{Description: "update test description", Status: true},
-				returnedTask: nil,
-				err:          customError.ErrUpdateTask,
+				inputPatch: &domain.TaskPatch{State: ptr(domain.TaskStateDone), Force: true},
+				returnedTask: &domain.Task{
+					Id:    "6a30b9b0-18bf-47b4-bd23-d72726864def",
+					Title: "test title", Description: "test description",
+					State: domain.TaskStateDone, Priority: domain.TaskPriorityP2,
+					BlockedBy: []*domain.TaskBlocker{
+						{Id: "f299e7ed-a22a-4494-b59e-21bb91fdae3b", Title: "test blocker", State: domain.TaskStateInProgress},
+					},
+					Version: 2, CreatedAt: testCreatedAt, UpdatedAt: testUpdatedAt, CompletedAt: &testUpdatedAt,
+				},
 			},
 			mockUse: true,
 		},
+		"InvalidForce": {
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			query:   "?force=maybe",
+			reqFile: "test/data/transition_task/done_req.json.golden",
+			expected: expected{
+				status:  http.StatusBadRequest,
+				resFile: "test/data/transition_task/invalid_force_res.json.golden",
+			},
+			mockUse: false,
+		},
+		"UnknownState": {
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			reqFile: "test/data/transition_task/unknown_state_req.json.golden",
+			expected: expected{
+				status:  http.StatusBadRequest,
+				resFile: "test/data/transition_task/unknown_state_res.json.golden",
+			},
+			mockUse: false,
+		},
 	}
 
 	for n, tt := range testTable {
@@ -371,83 +2080,102 @@ func TestUpdateTask(t *testing.T) {
 
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest(
-				http.MethodPatch,
-				fmt.Sprintf("/tasks/%s", tt.id),
+				http.MethodPost,
+				fmt.Sprintf("/tasks/%s/transitions%s", tt.id, tt.query),
 				bytes.NewReader(helper.LoadFile(t, tt.reqFile)),
 			)
 			r.SetPathValue("id", tt.id)
+			r.Header.Set("Content-Type", "application/json")
+			if tt.ifMatch != "" {
+				r.Header.Set("If-Match", tt.ifMatch)
+			}
 
 			mockCtrl := gomock.NewController(t)
 			defer mockCtrl.Finish()
 
 			mockTaskUsecase := mock.NewMockTaskUsecase(mockCtrl)
-
 			if tt.mockUse {
-				mockTaskUsecase.EXPECT().UpdateTask(r.Context(), tt.id, tt.mockData.inputTask).
+				mockTaskUsecase.EXPECT().UpdateTask(r.Context(), domain.TaskID(tt.id), tt.mockData.inputPatch).
 					Return(tt.mockData.returnedTask, tt.mockData.err)
 			}
 
-			sut := NewTaskHandler(mockTaskUsecase)
-			sut.UpdateTask(w, r)
+			sut := NewTaskHandler(mockTaskUsecase, testCursor)
+			sut.TransitionTask(w, r)
 
-			actualRes := w.Result()
 			helper.AssertResponse(t,
-				actualRes, tt.expected.status, helper.LoadFile(t, tt.expected.resFile),
+				w.Result(), tt.expected.status, helper.LoadFile(t, tt.expected.resFile),
 			)
 		})
 	}
 }
 
-func TestDeleteTask(t *testing.T) {
+func TestSetTaskLabels(t *testing.T) {
 	type expected struct {
 		status  int
 		resFile string
 	}
 
 	type mockData struct {
+		labelIds     []domain.LabelID
+		version      int
 		returnedTask *domain.Task
 		err          error
 	}
 
 	testTable := map[string]struct {
 		id       string
+		ifMatch  string
+		reqFile  string
 		expected expected
 		mockData mockData
+		mockUse  bool
 	}{
 		"Ok": {
-			id: "f299e7ed-a22a-4494-b59e-21bb91fdae3b",
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			ifMatch: `"1"`,
+			reqFile: "test/data/set_task_labels/ok_req.json.golden",
 			expected: expected{
 				status:  http.StatusOK,
-				resFile: "test/data/delete_task/ok_res.json.golden",
+				resFile: "test/data/set_task_labels/ok_res.json.golden",
 			},
 			mockData: mockData{
+				labelIds: []domain.LabelID{"0b9b4d6e-5c1f-4b0e-9a43-2f6f0c3d8e71", "c6a1f3e2-7d4b-4e8a-b1f9-3a2d5e6c7b80"},
+				version:  1,
 				returnedTask: &domain.Task{
-					Id: "f299e7ed-a22a-4494-b59e-21bb91fdae3b",
+					Id:    "6a30b9b0-18bf-47b4-bd23-d72726864def",
+					Title: "test title", Description: "test description",
+					State: domain.TaskStateTodo, Priority: domain.TaskPriorityP2,
+					Labels: []*domain.Label{
+						{Id: "0b9b4d6e-5c1f-4b0e-9a43-2f6f0c3d8e71", Name: "backend", Color: "#0000ff"},
+						{Id: "c6a1f3e2-7d4b-4e8a-b1f9-3a2d5e6c7b80", Name: "bug", Color: "#ff0000"},
+					},
+					Version:   2,
+					CreatedAt: testCreatedAt, UpdatedAt: testUpdatedAt,
 				},
-				err: nil,
 			},
+			mockUse: true,
 		},
-		"NotFound": {
-			id: "f299e7ed-a22a-4494-b59e-21bb91fdae3b",
+		"UnknownLabel": {
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			reqFile: "test/data/set_task_labels/ok_req.json.golden",
 			expected: expected{
-				status:  http.StatusNotFound,
-				resFile: "test/data/delete_task/not_found_res.json.golden",
+				status:  http.StatusUnprocessableEntity,
+				resFile: "test/data/set_task_labels/unknown_label_res.json.golden",
 			},
 			mockData: mockData{
-				returnedTask: nil,
-				err:          customError.ErrTaskNotFound,
+				labelIds: []domain.LabelID{"0b9b4d6e-5c1f-4b0e-9a43-2f6f0c3d8e71", "c6a1f3e2-7d4b-4e8a-b1f9-3a2d5e6c7b80"},
+				err:      &customError.FieldError{Field: "labels", Err: customError.ErrTaskLabelNotFound},
 			},
+			mockUse: true,
 		},
-		"InvalidId": {
-			id: "abc123",
+		"InvalidLabelId": {
+			id:      "6a30b9b0-18bf-47b4-bd23-d72726864def",
+			reqFile: "test/data/set_task_labels/invalid_label_id_req.json.golden",
 			expected: expected{
-				status:  http.StatusInternalServerError,
-				resFile: "test/data/delete_task/invalid_id_res.json.golden",
-			},
-			mockData: mockData{
-				returnedTask: nil,
-				err:          customError.ErrDeleteTask,
+				status:  http.StatusBadRequest,
+				resFile: "test/data/set_task_labels/invalid_label_id_res.json.golden",
 			},
+			mockUse: false,
 		},
 	}
 
@@ -458,23 +2186,36 @@ func TestDeleteTask(t *testing.T) {
 			t.Parallel()
 
 			w := httptest.NewRecorder()
-			r := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/tasks/%s", tt.id), nil)
+			r := httptest.NewRequest(
+				http.MethodPut,
+				fmt.Sprintf("/tasks/%s/labels", tt.id),
+				bytes.NewReader(helper.LoadFile(t, tt.reqFile)),
+			)
 			r.SetPathValue("id", tt.id)
+			r.Header.Set("Content-Type", "application/json")
+			if tt.ifMatch != "" {
+				r.Header.Set("If-Match", tt.ifMatch)
+			}
 
 			mockCtrl := gomock.NewController(t)
 			defer mockCtrl.Finish()
 
 			mockTaskUsecase := mock.NewMockTaskUsecase(mockCtrl)
-			mockTaskUsecase.EXPECT().DeleteTask(r.Context(), tt.id).
-				Return(tt.mockData.returnedTask, tt.mockData.err)
+			if tt.mockUse {
+				mockTaskUsecase.EXPECT().SetTaskLabels(r.Context(), domain.TaskID(tt.id), tt.mockData.labelIds, tt.mockData.version).
+					Return(tt.mockData.returnedTask, tt.mockData.err)
+			}
 
-			sut := NewTaskHandler(mockTaskUsecase)
-			sut.DeleteTask(w, r)
+			sut := NewTaskHandler(mockTaskUsecase, testCursor)
+			sut.SetTaskLabels(w, r)
 
-			actualRes := w.Result()
 			helper.AssertResponse(t,
-				actualRes, tt.expected.status, helper.LoadFile(t, tt.expected.resFile),
+				w.Result(), tt.expected.status, helper.LoadFile(t, tt.expected.resFile),
 			)
 		})
 	}
 }
+
+func ptr[T any](v T) *T {
+	return &v
+}
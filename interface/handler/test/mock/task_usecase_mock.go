@@ -35,6 +35,21 @@ func (m *MockTaskUsecase) EXPECT() *MockTaskUsecaseMockRecorder {
 	return m.recorder
 }
 
+// AddChecklistItem mocks base method.
+func (m *MockTaskUsecase) AddChecklistItem(ctx context.Context, taskId domain.TaskID, text string, position *int, version int) (*domain.Checklist, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddChecklistItem", ctx, taskId, text, position, version)
+	ret0, _ := ret[0].(*domain.Checklist)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddChecklistItem indicates an expected call of AddChecklistItem.
+func (mr *MockTaskUsecaseMockRecorder) AddChecklistItem(ctx, taskId, text, position, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddChecklistItem", reflect.TypeOf((*MockTaskUsecase)(nil).AddChecklistItem), ctx, taskId, text, position, version)
+}
+
 // AddTask mocks base method.
 func (m *MockTaskUsecase) AddTask(ctx context.Context, task *domain.Task) (*domain.Task, error) {
 	m.ctrl.T.Helper()
@@ -50,23 +65,83 @@ func (mr *MockTaskUsecaseMockRecorder) AddTask(ctx, task interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTask", reflect.TypeOf((*MockTaskUsecase)(nil).AddTask), ctx, task)
 }
 
+// AddTaskDependency mocks base method.
+func (m *MockTaskUsecase) AddTaskDependency(ctx context.Context, id domain.TaskID, blockerId domain.TaskID) (*domain.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddTaskDependency", ctx, id, blockerId)
+	ret0, _ := ret[0].(*domain.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddTaskDependency indicates an expected call of AddTaskDependency.
+func (mr *MockTaskUsecaseMockRecorder) AddTaskDependency(ctx, id, blockerId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTaskDependency", reflect.TypeOf((*MockTaskUsecase)(nil).AddTaskDependency), ctx, id, blockerId)
+}
+
+// AddTasks mocks base method.
+func (m *MockTaskUsecase) AddTasks(ctx context.Context, tasks []*domain.Task, atomic bool) ([]*domain.TaskBatchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddTasks", ctx, tasks, atomic)
+	ret0, _ := ret[0].([]*domain.TaskBatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddTasks indicates an expected call of AddTasks.
+func (mr *MockTaskUsecaseMockRecorder) AddTasks(ctx, tasks, atomic interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTasks", reflect.TypeOf((*MockTaskUsecase)(nil).AddTasks), ctx, tasks, atomic)
+}
+
 // DeleteTask mocks base method.
-func (m *MockTaskUsecase) DeleteTask(ctx context.Context, id string) (*domain.Task, error) {
+func (m *MockTaskUsecase) DeleteTask(ctx context.Context, id domain.TaskID, version int, mode domain.SubtaskMode) (*domain.Task, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteTask", ctx, id)
+	ret := m.ctrl.Call(m, "DeleteTask", ctx, id, version, mode)
 	ret0, _ := ret[0].(*domain.Task)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // DeleteTask indicates an expected call of DeleteTask.
-func (mr *MockTaskUsecaseMockRecorder) DeleteTask(ctx, id interface{}) *gomock.Call {
+func (mr *MockTaskUsecaseMockRecorder) DeleteTask(ctx, id, version, mode interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTask", reflect.TypeOf((*MockTaskUsecase)(nil).DeleteTask), ctx, id, version, mode)
+}
+
+// DeleteTasks mocks base method.
+func (m *MockTaskUsecase) DeleteTasks(ctx context.Context, deletes []*domain.TaskBatchDelete, atomic bool) ([]*domain.TaskBatchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTasks", ctx, deletes, atomic)
+	ret0, _ := ret[0].([]*domain.TaskBatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTasks indicates an expected call of DeleteTasks.
+func (mr *MockTaskUsecaseMockRecorder) DeleteTasks(ctx, deletes, atomic interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTasks", reflect.TypeOf((*MockTaskUsecase)(nil).DeleteTasks), ctx, deletes, atomic)
+}
+
+// GetChecklist mocks base method.
+func (m *MockTaskUsecase) GetChecklist(ctx context.Context, taskId domain.TaskID) (*domain.Checklist, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChecklist", ctx, taskId)
+	ret0, _ := ret[0].(*domain.Checklist)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChecklist indicates an expected call of GetChecklist.
+func (mr *MockTaskUsecaseMockRecorder) GetChecklist(ctx, taskId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTask", reflect.TypeOf((*MockTaskUsecase)(nil).DeleteTask), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChecklist", reflect.TypeOf((*MockTaskUsecase)(nil).GetChecklist), ctx, taskId)
 }
 
 // GetTaskById mocks base method.
-func (m *MockTaskUsecase) GetTaskById(ctx context.Context, id string) (*domain.Task, error) {
+func (m *MockTaskUsecase) GetTaskById(ctx context.Context, id domain.TaskID) (*domain.Task, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetTaskById", ctx, id)
 	ret0, _ := ret[0].(*domain.Task)
@@ -81,31 +156,196 @@ func (mr *MockTaskUsecaseMockRecorder) GetTaskById(ctx, id interface{}) *gomock.
 }
 
 // GetTaskList mocks base method.
-func (m *MockTaskUsecase) GetTaskList(ctx context.Context) ([]*domain.Task, error) {
+func (m *MockTaskUsecase) GetTaskList(ctx context.Context, query *domain.TaskListQuery) (*domain.TaskList, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTaskList", ctx)
-	ret0, _ := ret[0].([]*domain.Task)
+	ret := m.ctrl.Call(m, "GetTaskList", ctx, query)
+	ret0, _ := ret[0].(*domain.TaskList)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetTaskList indicates an expected call of GetTaskList.
-func (mr *MockTaskUsecaseMockRecorder) GetTaskList(ctx interface{}) *gomock.Call {
+func (mr *MockTaskUsecaseMockRecorder) GetTaskList(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskList", reflect.TypeOf((*MockTaskUsecase)(nil).GetTaskList), ctx, query)
+}
+
+// GetTaskSchedule mocks base method.
+func (m *MockTaskUsecase) GetTaskSchedule(ctx context.Context) (*domain.TaskSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaskSchedule", ctx)
+	ret0, _ := ret[0].(*domain.TaskSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaskSchedule indicates an expected call of GetTaskSchedule.
+func (mr *MockTaskUsecaseMockRecorder) GetTaskSchedule(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskSchedule", reflect.TypeOf((*MockTaskUsecase)(nil).GetTaskSchedule), ctx)
+}
+
+// GetTaskTree mocks base method.
+func (m *MockTaskUsecase) GetTaskTree(ctx context.Context, id domain.TaskID) (*domain.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaskTree", ctx, id)
+	ret0, _ := ret[0].(*domain.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaskTree indicates an expected call of GetTaskTree.
+func (mr *MockTaskUsecaseMockRecorder) GetTaskTree(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskTree", reflect.TypeOf((*MockTaskUsecase)(nil).GetTaskTree), ctx, id)
+}
+
+// GetTrash mocks base method.
+func (m *MockTaskUsecase) GetTrash(ctx context.Context) ([]*domain.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrash", ctx)
+	ret0, _ := ret[0].([]*domain.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrash indicates an expected call of GetTrash.
+func (mr *MockTaskUsecaseMockRecorder) GetTrash(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskList", reflect.TypeOf((*MockTaskUsecase)(nil).GetTaskList), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrash", reflect.TypeOf((*MockTaskUsecase)(nil).GetTrash), ctx)
+}
+
+// PurgeTask mocks base method.
+func (m *MockTaskUsecase) PurgeTask(ctx context.Context, id domain.TaskID, version int, mode domain.SubtaskMode) (*domain.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeTask", ctx, id, version, mode)
+	ret0, _ := ret[0].(*domain.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeTask indicates an expected call of PurgeTask.
+func (mr *MockTaskUsecaseMockRecorder) PurgeTask(ctx, id, version, mode interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeTask", reflect.TypeOf((*MockTaskUsecase)(nil).PurgeTask), ctx, id, version, mode)
+}
+
+// RemoveChecklistItem mocks base method.
+func (m *MockTaskUsecase) RemoveChecklistItem(ctx context.Context, taskId domain.TaskID, itemId domain.ChecklistItemID, version int) (*domain.Checklist, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveChecklistItem", ctx, taskId, itemId, version)
+	ret0, _ := ret[0].(*domain.Checklist)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveChecklistItem indicates an expected call of RemoveChecklistItem.
+func (mr *MockTaskUsecaseMockRecorder) RemoveChecklistItem(ctx, taskId, itemId, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveChecklistItem", reflect.TypeOf((*MockTaskUsecase)(nil).RemoveChecklistItem), ctx, taskId, itemId, version)
+}
+
+// RemoveTaskDependency mocks base method.
+func (m *MockTaskUsecase) RemoveTaskDependency(ctx context.Context, id domain.TaskID, blockerId domain.TaskID) (*domain.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveTaskDependency", ctx, id, blockerId)
+	ret0, _ := ret[0].(*domain.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveTaskDependency indicates an expected call of RemoveTaskDependency.
+func (mr *MockTaskUsecaseMockRecorder) RemoveTaskDependency(ctx, id, blockerId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveTaskDependency", reflect.TypeOf((*MockTaskUsecase)(nil).RemoveTaskDependency), ctx, id, blockerId)
+}
+
+// ReorderChecklist mocks base method.
+func (m *MockTaskUsecase) ReorderChecklist(ctx context.Context, taskId domain.TaskID, itemIds []domain.ChecklistItemID, version int) (*domain.Checklist, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReorderChecklist", ctx, taskId, itemIds, version)
+	ret0, _ := ret[0].(*domain.Checklist)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReorderChecklist indicates an expected call of ReorderChecklist.
+func (mr *MockTaskUsecaseMockRecorder) ReorderChecklist(ctx, taskId, itemIds, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReorderChecklist", reflect.TypeOf((*MockTaskUsecase)(nil).ReorderChecklist), ctx, taskId, itemIds, version)
+}
+
+// RestoreTask mocks base method.
+func (m *MockTaskUsecase) RestoreTask(ctx context.Context, id domain.TaskID) (*domain.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreTask", ctx, id)
+	ret0, _ := ret[0].(*domain.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreTask indicates an expected call of RestoreTask.
+func (mr *MockTaskUsecaseMockRecorder) RestoreTask(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreTask", reflect.TypeOf((*MockTaskUsecase)(nil).RestoreTask), ctx, id)
+}
+
+// SetTaskLabels mocks base method.
+func (m *MockTaskUsecase) SetTaskLabels(ctx context.Context, id domain.TaskID, labelIds []domain.LabelID, version int) (*domain.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTaskLabels", ctx, id, labelIds, version)
+	ret0, _ := ret[0].(*domain.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetTaskLabels indicates an expected call of SetTaskLabels.
+func (mr *MockTaskUsecaseMockRecorder) SetTaskLabels(ctx, id, labelIds, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTaskLabels", reflect.TypeOf((*MockTaskUsecase)(nil).SetTaskLabels), ctx, id, labelIds, version)
+}
+
+// UpdateChecklistItem mocks base method.
+func (m *MockTaskUsecase) UpdateChecklistItem(ctx context.Context, taskId domain.TaskID, itemId domain.ChecklistItemID, patch *domain.ChecklistItemPatch, version int) (*domain.Checklist, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateChecklistItem", ctx, taskId, itemId, patch, version)
+	ret0, _ := ret[0].(*domain.Checklist)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateChecklistItem indicates an expected call of UpdateChecklistItem.
+func (mr *MockTaskUsecaseMockRecorder) UpdateChecklistItem(ctx, taskId, itemId, patch, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateChecklistItem", reflect.TypeOf((*MockTaskUsecase)(nil).UpdateChecklistItem), ctx, taskId, itemId, patch, version)
 }
 
 // UpdateTask mocks base method.
-func (m *MockTaskUsecase) UpdateTask(ctx context.Context, id string, task *domain.Task) (*domain.Task, error) {
+func (m *MockTaskUsecase) UpdateTask(ctx context.Context, id domain.TaskID, patch *domain.TaskPatch) (*domain.Task, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateTask", ctx, id, task)
+	ret := m.ctrl.Call(m, "UpdateTask", ctx, id, patch)
 	ret0, _ := ret[0].(*domain.Task)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateTask indicates an expected call of UpdateTask.
-func (mr *MockTaskUsecaseMockRecorder) UpdateTask(ctx, id, task interface{}) *gomock.Call {
+func (mr *MockTaskUsecaseMockRecorder) UpdateTask(ctx, id, patch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTask", reflect.TypeOf((*MockTaskUsecase)(nil).UpdateTask), ctx, id, patch)
+}
+
+// UpdateTasks mocks base method.
+func (m *MockTaskUsecase) UpdateTasks(ctx context.Context, updates []*domain.TaskBatchUpdate, atomic bool) ([]*domain.TaskBatchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTasks", ctx, updates, atomic)
+	ret0, _ := ret[0].([]*domain.TaskBatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTasks indicates an expected call of UpdateTasks.
+func (mr *MockTaskUsecaseMockRecorder) UpdateTasks(ctx, updates, atomic interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTask", reflect.TypeOf((*MockTaskUsecase)(nil).UpdateTask), ctx, id, task)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTasks", reflect.TypeOf((*MockTaskUsecase)(nil).UpdateTasks), ctx, updates, atomic)
 }
@@ -5,11 +5,15 @@ import (
 	"log/slog"
 
 	"github.com/takumi616/go-restapi/application/usecase"
+	"github.com/takumi616/go-restapi/domain"
+	"github.com/takumi616/go-restapi/infrastructure/auth"
 	"github.com/takumi616/go-restapi/infrastructure/db"
 	"github.com/takumi616/go-restapi/infrastructure/db/repository"
 	"github.com/takumi616/go-restapi/infrastructure/web"
+	"github.com/takumi616/go-restapi/infrastructure/worker"
 	"github.com/takumi616/go-restapi/interface/gateway"
 	"github.com/takumi616/go-restapi/interface/handler"
+	"github.com/takumi616/go-restapi/interface/handler/cursor"
 	"github.com/takumi616/go-restapi/shared/config"
 	"github.com/takumi616/go-restapi/shared/logger"
 )
@@ -32,17 +36,78 @@ func run(ctx context.Context) error {
 		return err
 	}
 
-	repository := repository.NewTaskRepository(db)
-	gateway := gateway.NewTaskGateway(repository)
-	usecase := usecase.NewTaskUsecase(gateway)
-	handler := handler.NewTaskHandler(usecase)
+	trashCfg, err := config.NewTrashConfig()
+	if err != nil {
+		return err
+	}
+
+	workflowCfg, err := config.NewWorkflowConfig()
+	if err != nil {
+		return err
+	}
+
+	authCfg, err := config.NewAuthConfig()
+	if err != nil {
+		return err
+	}
+
+	workflow, err := newTaskWorkflow(workflowCfg)
+	if err != nil {
+		return err
+	}
+
+	policy := usecase.NewRolePolicy()
+	taskUsecase := usecase.NewTaskUsecase(gateway.NewTaskGateway(repository.NewTaskRepository(db)), workflow, policy)
+	taskHandler := handler.NewTaskHandler(taskUsecase, cursor.NewCodec(appCfg.CursorSecret))
+
+	labelUsecase := usecase.NewLabelUsecase(gateway.NewLabelGateway(repository.NewLabelRepository(db)), policy)
+	labelHandler := handler.NewLabelHandler(labelUsecase)
+
+	userGateway := gateway.NewUserGateway(repository.NewUserRepository(db))
+	var authUsecase *usecase.AuthUsecase
+	if authCfg.Mode == config.AuthModeOIDC {
+		authUsecase = usecase.NewOIDCAuthUsecase(
+			userGateway,
+			auth.NewOIDCVerifier(authCfg.OIDC, auth.NewJWKSet(authCfg.OIDC)),
+		)
+	} else {
+		authUsecase = usecase.NewAuthUsecase(
+			userGateway,
+			auth.NewBcryptHasher(),
+			auth.NewJWTCodec(authCfg),
+			authCfg.RefreshTokenTTL,
+		)
+	}
+	authHandler := handler.NewAuthHandler(authUsecase, authCfg.TrustUserIdHeader)
 
-	serveMux := web.NewServeMux(handler)
+	serveMux := web.NewServeMux(taskHandler, labelHandler, authHandler)
+
+	// Purge the trash in the background for as long as the server runs
+	purgeCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go worker.NewTrashPurger(trashCfg, taskUsecase).Run(purgeCtx)
 
 	server := web.NewServer(appCfg, serveMux.RegisterHandler())
 	return server.Run(ctx)
 }
 
+// Use the configured workflow, falling back to the built-in one
+func newTaskWorkflow(cfg *config.WorkflowConfig) (*domain.TaskWorkflow, error) {
+	if cfg.Transitions == nil {
+		return domain.DefaultTaskWorkflow(), nil
+	}
+
+	transitions := map[domain.TaskState][]domain.TaskState{}
+	for from, nexts := range cfg.Transitions {
+		transitions[domain.TaskState(from)] = []domain.TaskState{}
+		for _, next := range nexts {
+			transitions[domain.TaskState(from)] = append(transitions[domain.TaskState(from)], domain.TaskState(next))
+		}
+	}
+
+	return domain.NewTaskWorkflow(transitions)
+}
+
 func main() {
 	ctx := context.Background()
 	if err := run(ctx); err != nil {
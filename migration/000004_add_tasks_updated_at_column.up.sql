@@ -0,0 +1,2 @@
+ALTER TABLE tasks
+ADD COLUMN updated_at TIMESTAMPTZ NOT NULL DEFAULT now();
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE tasks
+DROP COLUMN IF EXISTS created_at,
+DROP COLUMN IF EXISTS completed_at;
\ No newline at end of file
@@ -0,0 +1,5 @@
+ALTER TABLE tasks
+ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+ADD COLUMN completed_at TIMESTAMPTZ;
+
+UPDATE tasks SET completed_at = updated_at WHERE status;
\ No newline at end of file
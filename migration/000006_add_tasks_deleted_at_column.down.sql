@@ -0,0 +1,11 @@
+DELETE FROM tasks WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS tasks_deleted_at_idx;
+
+DROP INDEX IF EXISTS tasks_title_key;
+
+ALTER TABLE tasks
+ADD CONSTRAINT tasks_title_key UNIQUE (title);
+
+ALTER TABLE tasks
+DROP COLUMN IF EXISTS deleted_at;
\ No newline at end of file
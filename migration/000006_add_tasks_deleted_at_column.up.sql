@@ -0,0 +1,10 @@
+ALTER TABLE tasks
+ADD COLUMN deleted_at TIMESTAMPTZ;
+
+-- Titles only need to be unique among tasks that are not in the trash
+ALTER TABLE tasks
+DROP CONSTRAINT IF EXISTS tasks_title_key;
+
+CREATE UNIQUE INDEX tasks_title_key ON tasks (title) WHERE deleted_at IS NULL;
+
+CREATE INDEX tasks_deleted_at_idx ON tasks (deleted_at) WHERE deleted_at IS NOT NULL;
\ No newline at end of file
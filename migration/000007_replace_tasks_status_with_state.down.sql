@@ -0,0 +1,11 @@
+-- Every state other than done was open as far as the boolean can tell
+ALTER TABLE tasks
+ADD COLUMN status BOOLEAN NOT NULL DEFAULT false;
+
+UPDATE tasks SET status = (state = 'done');
+
+ALTER TABLE tasks
+ALTER COLUMN status DROP DEFAULT;
+
+ALTER TABLE tasks
+DROP COLUMN IF EXISTS state;
\ No newline at end of file
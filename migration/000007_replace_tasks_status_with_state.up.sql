@@ -0,0 +1,8 @@
+ALTER TABLE tasks
+ADD COLUMN state VARCHAR(20) NOT NULL DEFAULT 'todo'
+CONSTRAINT tasks_state_check CHECK (state IN ('todo', 'in_progress', 'blocked', 'in_review', 'done', 'archived'));
+
+UPDATE tasks SET state = 'done' WHERE status;
+
+ALTER TABLE tasks
+DROP COLUMN status;
\ No newline at end of file
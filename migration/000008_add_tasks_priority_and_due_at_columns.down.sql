@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS tasks_due_at_idx;
+
+ALTER TABLE tasks
+DROP COLUMN IF EXISTS priority,
+DROP COLUMN IF EXISTS due_at;
\ No newline at end of file
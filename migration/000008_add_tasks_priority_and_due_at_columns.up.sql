@@ -0,0 +1,6 @@
+ALTER TABLE tasks
+ADD COLUMN priority VARCHAR(2) NOT NULL DEFAULT 'P2'
+CONSTRAINT tasks_priority_check CHECK (priority IN ('P0', 'P1', 'P2', 'P3', 'P4')),
+ADD COLUMN due_at TIMESTAMPTZ;
+
+CREATE INDEX tasks_due_at_idx ON tasks (due_at) WHERE due_at IS NOT NULL AND deleted_at IS NULL;
\ No newline at end of file
@@ -0,0 +1,8 @@
+DROP TRIGGER IF EXISTS tasks_touch_parent_on_update ON tasks;
+DROP TRIGGER IF EXISTS tasks_touch_parent_on_insert_or_delete ON tasks;
+DROP FUNCTION IF EXISTS touch_parent_task();
+
+DROP INDEX IF EXISTS tasks_parent_id_idx;
+
+ALTER TABLE tasks
+DROP COLUMN IF EXISTS parent_id;
\ No newline at end of file
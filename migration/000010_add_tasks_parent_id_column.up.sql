@@ -0,0 +1,34 @@
+-- Purging a task takes its subtree along. Subtasks that should be kept
+-- are moved up before their parent is purged
+ALTER TABLE tasks
+ADD COLUMN parent_id UUID REFERENCES tasks (id) ON DELETE CASCADE;
+
+CREATE INDEX tasks_parent_id_idx ON tasks (parent_id) WHERE parent_id IS NOT NULL;
+
+-- A task shows the progress of its subtasks, so it counts as modified
+-- whenever one of them is added, removed, moved or changes state.
+-- Only version and updated_at are written, which does not fire the trigger again
+CREATE FUNCTION touch_parent_task() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        UPDATE tasks SET version = version + 1, updated_at = now() WHERE id = NEW.parent_id;
+    ELSIF TG_OP = 'DELETE' THEN
+        UPDATE tasks SET version = version + 1, updated_at = now() WHERE id = OLD.parent_id;
+    ELSE
+        UPDATE tasks SET version = version + 1, updated_at = now() WHERE id IN (OLD.parent_id, NEW.parent_id);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER tasks_touch_parent_on_insert_or_delete
+AFTER INSERT OR DELETE ON tasks
+FOR EACH ROW EXECUTE FUNCTION touch_parent_task();
+
+CREATE TRIGGER tasks_touch_parent_on_update
+AFTER UPDATE OF state, parent_id, deleted_at ON tasks
+FOR EACH ROW
+WHEN (OLD.state IS DISTINCT FROM NEW.state
+    OR OLD.parent_id IS DISTINCT FROM NEW.parent_id
+    OR OLD.deleted_at IS DISTINCT FROM NEW.deleted_at)
+EXECUTE FUNCTION touch_parent_task();
\ No newline at end of file
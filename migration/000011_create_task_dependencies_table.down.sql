@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS tasks_touch_blocked_on_update ON tasks;
+DROP TRIGGER IF EXISTS task_dependencies_touch_blocked ON task_dependencies;
+DROP FUNCTION IF EXISTS touch_blocked_tasks();
+
+DROP TABLE IF EXISTS task_dependencies;
\ No newline at end of file
@@ -0,0 +1,39 @@
+-- The task blocker_id has to be finished before the task blocked_id can be
+CREATE TABLE IF NOT EXISTS task_dependencies (
+    blocker_id UUID NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    blocked_id UUID NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (blocked_id, blocker_id),
+    CHECK (blocker_id <> blocked_id)
+);
+
+CREATE INDEX task_dependencies_blocker_id_idx ON task_dependencies (blocker_id);
+
+-- A task lists its blockers with their titles and states, so it counts as
+-- modified whenever a dependency is added or removed or one of its blockers
+-- changes. Only version and updated_at are written, which fires no trigger again
+CREATE FUNCTION touch_blocked_tasks() RETURNS trigger AS $$
+BEGIN
+    IF TG_TABLE_NAME = 'tasks' THEN
+        UPDATE tasks SET version = version + 1, updated_at = now()
+        WHERE id IN (SELECT blocked_id FROM task_dependencies WHERE blocker_id = NEW.id);
+    ELSIF TG_OP = 'INSERT' THEN
+        UPDATE tasks SET version = version + 1, updated_at = now() WHERE id = NEW.blocked_id;
+    ELSE
+        UPDATE tasks SET version = version + 1, updated_at = now() WHERE id = OLD.blocked_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER task_dependencies_touch_blocked
+AFTER INSERT OR DELETE ON task_dependencies
+FOR EACH ROW EXECUTE FUNCTION touch_blocked_tasks();
+
+CREATE TRIGGER tasks_touch_blocked_on_update
+AFTER UPDATE OF title, state, deleted_at ON tasks
+FOR EACH ROW
+WHEN (OLD.title IS DISTINCT FROM NEW.title
+    OR OLD.state IS DISTINCT FROM NEW.state
+    OR OLD.deleted_at IS DISTINCT FROM NEW.deleted_at)
+EXECUTE FUNCTION touch_blocked_tasks();
\ No newline at end of file
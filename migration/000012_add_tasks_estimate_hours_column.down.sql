@@ -0,0 +1,2 @@
+ALTER TABLE tasks
+DROP COLUMN IF EXISTS estimate_hours;
\ No newline at end of file
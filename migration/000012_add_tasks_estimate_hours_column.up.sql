@@ -0,0 +1,3 @@
+ALTER TABLE tasks
+ADD COLUMN estimate_hours INTEGER
+CONSTRAINT tasks_estimate_hours_check CHECK (estimate_hours BETWEEN 0 AND 1000);
\ No newline at end of file
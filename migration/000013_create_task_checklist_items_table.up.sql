@@ -0,0 +1,13 @@
+-- Items of a task's checklist, numbered from 0 in the order they are shown.
+-- Positions stay unique within a task, and reorders move many items at once,
+-- so uniqueness is only checked when the transaction commits
+CREATE TABLE IF NOT EXISTS task_checklist_items (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    task_id UUID NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    text VARCHAR(200) NOT NULL,
+    done BOOLEAN NOT NULL DEFAULT false,
+    position INTEGER NOT NULL CHECK (position >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT task_checklist_items_position_key UNIQUE (task_id, position) DEFERRABLE INITIALLY DEFERRED
+);
\ No newline at end of file
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS tasks_title_key;
+
+CREATE UNIQUE INDEX tasks_title_key ON tasks (title) WHERE deleted_at IS NULL;
+
+ALTER TABLE tasks
+DROP COLUMN IF EXISTS owner_id;
\ No newline at end of file
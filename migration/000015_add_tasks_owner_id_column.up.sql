@@ -0,0 +1,10 @@
+-- The user a task belongs to. There is no foreign key, since callers the
+-- gateway vouches for need not have signed up here. Tasks created before
+-- owners existed keep a NULL owner and are visible to no one
+ALTER TABLE tasks
+ADD COLUMN owner_id UUID;
+
+-- Titles only need to be unique among the live tasks of the same owner
+DROP INDEX IF EXISTS tasks_title_key;
+
+CREATE UNIQUE INDEX tasks_title_key ON tasks (owner_id, title) WHERE deleted_at IS NULL;
\ No newline at end of file
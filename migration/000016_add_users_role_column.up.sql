@@ -0,0 +1,5 @@
+-- What each user may do with tasks. Everyone who signed up before roles
+-- existed keeps the writing access they had
+ALTER TABLE users
+ADD COLUMN role VARCHAR(16) NOT NULL DEFAULT 'editor'
+CONSTRAINT users_role_check CHECK (role IN ('viewer', 'editor', 'admin'));
\ No newline at end of file
@@ -0,0 +1,15 @@
+-- Long-lived tokens users mint for scripts and bots. As with refresh tokens,
+-- only a hash of each is kept. Revoked tokens stay until their user is removed
+CREATE TABLE IF NOT EXISTS personal_access_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL CONSTRAINT personal_access_tokens_token_hash_key UNIQUE,
+    scopes TEXT[] NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    last_used_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX personal_access_tokens_user_id_idx ON personal_access_tokens (user_id) WHERE revoked_at IS NULL;
\ No newline at end of file
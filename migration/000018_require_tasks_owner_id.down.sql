@@ -0,0 +1,2 @@
+ALTER TABLE tasks
+ALTER COLUMN owner_id DROP NOT NULL;
\ No newline at end of file
@@ -0,0 +1,20 @@
+-- Tasks created before owners existed go to the user whose id is in the
+-- app.legacy_task_owner setting, which can be passed along with the
+-- migration URL as options=-c app.legacy_task_owner=<user id>. Without it
+-- the migration stops instead of leaving those tasks out of everyone's reach
+DO $$
+DECLARE
+    legacy_owner TEXT := current_setting('app.legacy_task_owner', true);
+BEGIN
+    IF EXISTS (SELECT 1 FROM tasks WHERE owner_id IS NULL) THEN
+        IF COALESCE(legacy_owner, '') = '' THEN
+            RAISE EXCEPTION 'tasks without an owner exist: set app.legacy_task_owner to the id of the user to give them to';
+        END IF;
+
+        UPDATE tasks SET owner_id = legacy_owner::uuid WHERE owner_id IS NULL;
+    END IF;
+END
+$$;
+
+ALTER TABLE tasks
+ALTER COLUMN owner_id SET NOT NULL;
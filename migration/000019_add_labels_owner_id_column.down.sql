@@ -0,0 +1,8 @@
+ALTER TABLE labels
+DROP CONSTRAINT IF EXISTS labels_name_key;
+
+ALTER TABLE labels
+DROP COLUMN IF EXISTS owner_id;
+
+ALTER TABLE labels
+ADD CONSTRAINT labels_name_key UNIQUE (name);
\ No newline at end of file
@@ -0,0 +1,46 @@
+-- Labels belong to a user as tasks do, so that a change to one only ever
+-- reaches the tasks of its owner. Names need only be unique per owner
+ALTER TABLE labels
+ADD COLUMN owner_id UUID;
+
+ALTER TABLE labels
+DROP CONSTRAINT IF EXISTS labels_name_key;
+
+-- Every owner of tasks carrying a label so far gets a copy of the label,
+-- which their tasks carry from then on
+INSERT INTO labels(owner_id, name, color, created_at, updated_at)
+SELECT DISTINCT t.owner_id, l.name, l.color, l.created_at, l.updated_at
+FROM labels l
+JOIN task_labels tl ON tl.label_id = l.id
+JOIN tasks t ON t.id = tl.task_id
+WHERE l.owner_id IS NULL;
+
+UPDATE task_labels tl SET label_id = c.id
+FROM tasks t, labels l, labels c
+WHERE t.id = tl.task_id AND l.id = tl.label_id AND l.owner_id IS NULL
+AND c.owner_id = t.owner_id AND c.name = l.name;
+
+DELETE FROM labels l
+WHERE l.owner_id IS NULL AND EXISTS (SELECT 1 FROM labels c WHERE c.owner_id IS NOT NULL AND c.name = l.name);
+
+-- Labels on no task go to the same user as the tasks without an owner did.
+-- Without one the migration stops instead of dropping them
+DO $$
+DECLARE
+    legacy_owner TEXT := current_setting('app.legacy_task_owner', true);
+BEGIN
+    IF EXISTS (SELECT 1 FROM labels WHERE owner_id IS NULL) THEN
+        IF COALESCE(legacy_owner, '') = '' THEN
+            RAISE EXCEPTION 'labels on no task exist: set app.legacy_task_owner to the id of the user to give them to';
+        END IF;
+
+        UPDATE labels SET owner_id = legacy_owner::uuid WHERE owner_id IS NULL;
+    END IF;
+END
+$$;
+
+ALTER TABLE labels
+ALTER COLUMN owner_id SET NOT NULL;
+
+ALTER TABLE labels
+ADD CONSTRAINT labels_name_key UNIQUE (owner_id, name);
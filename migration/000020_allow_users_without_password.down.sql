@@ -0,0 +1,5 @@
+-- Fails while users of an identity provider are recorded
+ALTER TABLE users
+DROP CONSTRAINT IF EXISTS users_credentials_check,
+ALTER COLUMN email SET NOT NULL,
+ALTER COLUMN password_hash SET NOT NULL;
\ No newline at end of file
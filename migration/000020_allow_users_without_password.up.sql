@@ -0,0 +1,7 @@
+-- Users of an identity provider are recorded on their first request, with
+-- the id derived from their identity and neither email nor password here.
+-- Everyone else keeps having both
+ALTER TABLE users
+ALTER COLUMN email DROP NOT NULL,
+ALTER COLUMN password_hash DROP NOT NULL,
+ADD CONSTRAINT users_credentials_check CHECK ((email IS NULL) = (password_hash IS NULL));
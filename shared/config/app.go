@@ -5,8 +5,9 @@ import (
 )
 
 type AppConfig struct {
-	Port    string
-	Timeout TimeoutConfig
+	Port         string
+	Timeout      TimeoutConfig
+	CursorSecret string
 }
 
 type TimeoutConfig struct {
@@ -39,6 +40,11 @@ func NewAppConfig() (*AppConfig, error) {
 		return nil, err
 	}
 
+	cursorSecret, err := getEnvValue("CURSOR_SECRET")
+	if err != nil {
+		return nil, err
+	}
+
 	return &AppConfig{
 		Port: port,
 		Timeout: TimeoutConfig{
@@ -47,5 +53,6 @@ func NewAppConfig() (*AppConfig, error) {
 			WriteTimeout:      writeTimeout,
 			IdleTimeout:       idleTimeout,
 		},
+		CursorSecret: cursorSecret,
 	}, nil
 }
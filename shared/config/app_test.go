@@ -10,7 +10,7 @@ import (
 
 var appEnvKeyList = []string{
 	"APP_PORT", "SERVER_READ_TIMEOUT", "SERVER_READ_HEADER_TIMEOUT",
-	"SERVER_WRITE_TIMEOUT", "SERVER_IDLE_TIMEOUT",
+	"SERVER_WRITE_TIMEOUT", "SERVER_IDLE_TIMEOUT", "CURSOR_SECRET",
 }
 
 type expectedAppConfig struct {
@@ -19,13 +19,14 @@ type expectedAppConfig struct {
 	readHeaderTimeout time.Duration
 	writeTimeout      time.Duration
 	idleTimeout       time.Duration
+	cursorSecret      string
 }
 
 func TestNewAppConfigNormal(t *testing.T) {
-	inputList := []string{"8080", "2s", "3s", "4s", "5s"}
+	inputList := []string{"8080", "2s", "3s", "4s", "5s", "secret"}
 	expected := expectedAppConfig{
 		port: "8080", readTimeout: 2 * time.Second, readHeaderTimeout: 3 * time.Second,
-		writeTimeout: 4 * time.Second, idleTimeout: 5 * time.Second,
+		writeTimeout: 4 * time.Second, idleTimeout: 5 * time.Second, cursorSecret: "secret",
 	}
 
 	for i, key := range appEnvKeyList {
@@ -41,11 +42,12 @@ func TestNewAppConfigNormal(t *testing.T) {
 	assert.Equal(t, expected.readHeaderTimeout, appCfg.Timeout.ReadHeaderTimeout)
 	assert.Equal(t, expected.writeTimeout, appCfg.Timeout.WriteTimeout)
 	assert.Equal(t, expected.idleTimeout, appCfg.Timeout.IdleTimeout)
+	assert.Equal(t, expected.cursorSecret, appCfg.CursorSecret)
 }
 
 func TestNewAppConfigEmptyPort(t *testing.T) {
 	portKey := "APP_PORT"
-	inputList := []string{"", "2s", "3s", "4s", "5s"}
+	inputList := []string{"", "2s", "3s", "4s", "5s", "secret"}
 
 	for i, key := range appEnvKeyList {
 		t.Setenv(key, inputList[i])
@@ -60,7 +62,7 @@ func TestNewAppConfigEmptyPort(t *testing.T) {
 
 func TestNewAppConfigInvalidDuration(t *testing.T) {
 	invalidDuration := "s2"
-	inputList := []string{"8080", invalidDuration, "3s", "4s", "5s"}
+	inputList := []string{"8080", invalidDuration, "3s", "4s", "5s", "secret"}
 
 	for i, key := range appEnvKeyList {
 		t.Setenv(key, inputList[i])
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Shortest secret accepted for signing access tokens, as long as the
+// SHA-256 output they are used with
+const minSigningKeyLength = 32
+
+// How users authenticate
+type AuthMode string
+
+const (
+	// Users sign up with a password, and the API issues access tokens of its own
+	AuthModePassword AuthMode = "password"
+	// Users sign in with an identity provider, whose tokens the API verifies
+	AuthModeOIDC AuthMode = "oidc"
+)
+
+type AuthConfig struct {
+	Mode AuthMode
+	// Keys access tokens are checked against. The first one signs new
+	// tokens, and the rest keep tokens signed before a rotation valid.
+	// This and the other token settings are only read in password mode
+	SigningKeys     []SigningKey
+	Issuer          string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	// The identity provider trusted in OIDC mode
+	OIDC *OIDCConfig
+	// Whether the caller named in the X-User-ID header set by the gateway
+	// is taken as authenticated
+	TrustUserIdHeader bool
+}
+
+type SigningKey struct {
+	Id     string
+	Secret string
+}
+
+// NewAuthConfig reads AUTH_MODE, which is password unless set to oidc, and
+// the settings of that mode. The optional TRUST_USER_ID_HEADER is off
+// unless set to a true value
+func NewAuthConfig() (*AuthConfig, error) {
+	mode := AuthModePassword
+	if val := os.Getenv("AUTH_MODE"); val != "" {
+		mode = AuthMode(val)
+	}
+
+	var authCfg *AuthConfig
+	var err error
+	switch mode {
+	case AuthModePassword:
+		authCfg, err = newPasswordAuthConfig()
+	case AuthModeOIDC:
+		authCfg = &AuthConfig{}
+		authCfg.OIDC, err = newOIDCConfig()
+	default:
+		return nil, fmt.Errorf("invalid auth mode: '%s'", mode)
+	}
+	if err != nil {
+		return nil, err
+	}
+	authCfg.Mode = mode
+
+	if val := os.Getenv("TRUST_USER_ID_HEADER"); val != "" {
+		authCfg.TrustUserIdHeader, err = strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bool format: '%s': %w", val, err)
+		}
+	}
+
+	return authCfg, nil
+}
+
+// Read JWT_SIGNING_KEYS as a comma separated list of "id:secret" pairs,
+// newest first, along with the rest of what password mode needs
+func newPasswordAuthConfig() (*AuthConfig, error) {
+	keysVal, err := getEnvValue("JWT_SIGNING_KEYS")
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []SigningKey
+	for _, entry := range strings.Split(keysVal, ",") {
+		id, secret, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok || id == "" {
+			return nil, fmt.Errorf("invalid signing key format: '%s'", entry)
+		}
+		if len(secret) < minSigningKeyLength {
+			return nil, fmt.Errorf("signing key '%s' must be at least %d bytes long", id, minSigningKeyLength)
+		}
+		keys = append(keys, SigningKey{Id: id, Secret: secret})
+	}
+
+	issuer, err := getEnvValue("JWT_ISSUER")
+	if err != nil {
+		return nil, err
+	}
+
+	accessTokenTTL, err := getDurationEnvValue("ACCESS_TOKEN_TTL")
+	if err != nil {
+		return nil, err
+	}
+
+	refreshTokenTTL, err := getDurationEnvValue("REFRESH_TOKEN_TTL")
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuthConfig{
+		SigningKeys:     keys,
+		Issuer:          issuer,
+		AccessTokenTTL:  accessTokenTTL,
+		RefreshTokenTTL: refreshTokenTTL,
+	}, nil
+}
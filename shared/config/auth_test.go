@@ -0,0 +1,127 @@
+package config
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var authEnvKeyList = []string{"JWT_SIGNING_KEYS", "JWT_ISSUER", "ACCESS_TOKEN_TTL", "REFRESH_TOKEN_TTL"}
+
+const (
+	testSigningSecret    = "0123456789abcdef0123456789abcdef"
+	testOldSigningSecret = "fedcba9876543210fedcba9876543210"
+)
+
+func TestNewAuthConfigNormal(t *testing.T) {
+	inputList := []string{
+		fmt.Sprintf("2025-02:%s, 2025-01:%s", testSigningSecret, testOldSigningSecret), "go-restapi", "15m", "720h",
+	}
+
+	for i, key := range authEnvKeyList {
+		t.Setenv(key, inputList[i])
+	}
+
+	authCfg, err := NewAuthConfig()
+
+	assert.NoError(t, err)
+	assert.NotNil(t, authCfg)
+	assert.Equal(t, AuthModePassword, authCfg.Mode)
+	assert.Nil(t, authCfg.OIDC)
+	assert.Equal(t, []SigningKey{
+		{Id: "2025-02", Secret: testSigningSecret},
+		{Id: "2025-01", Secret: testOldSigningSecret},
+	}, authCfg.SigningKeys)
+	assert.Equal(t, "go-restapi", authCfg.Issuer)
+	assert.Equal(t, 15*time.Minute, authCfg.AccessTokenTTL)
+	assert.Equal(t, 720*time.Hour, authCfg.RefreshTokenTTL)
+	assert.False(t, authCfg.TrustUserIdHeader)
+}
+
+func TestNewAuthConfigTrustUserIdHeader(t *testing.T) {
+	testTable := map[string]struct {
+		value    string
+		expected bool
+		err      string
+	}{
+		"True":    {value: "true", expected: true},
+		"False":   {value: "0", expected: false},
+		"Invalid": {value: "yes", err: "invalid bool format: 'yes': strconv.ParseBool: parsing \"yes\": invalid syntax"},
+	}
+
+	for n, tt := range testTable {
+		t.Run(n, func(t *testing.T) {
+			inputList := []string{"2025-02:" + testSigningSecret, "go-restapi", "15m", "720h"}
+
+			for i, key := range authEnvKeyList {
+				t.Setenv(key, inputList[i])
+			}
+			t.Setenv("TRUST_USER_ID_HEADER", tt.value)
+
+			authCfg, err := NewAuthConfig()
+
+			if tt.err != "" {
+				assert.Nil(t, authCfg)
+				assert.EqualError(t, err, tt.err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, authCfg.TrustUserIdHeader)
+			}
+		})
+	}
+}
+
+func TestNewAuthConfigEmptySigningKeys(t *testing.T) {
+	inputList := []string{"", "go-restapi", "15m", "720h"}
+
+	for i, key := range authEnvKeyList {
+		t.Setenv(key, inputList[i])
+	}
+
+	authCfg, err := NewAuthConfig()
+
+	assert.Nil(t, authCfg)
+	assert.EqualError(t, err, "environment variable JWT_SIGNING_KEYS must be set")
+}
+
+func TestNewAuthConfigInvalidSigningKey(t *testing.T) {
+	testTable := map[string]struct {
+		keys     string
+		expected string
+	}{
+		"MissingId": {
+			keys:     testSigningSecret,
+			expected: fmt.Sprintf("invalid signing key format: '%s'", testSigningSecret),
+		},
+		"ShortSecret": {
+			keys:     "2025-02:secret",
+			expected: "signing key '2025-02' must be at least 32 bytes long",
+		},
+	}
+
+	for n, tt := range testTable {
+		t.Run(n, func(t *testing.T) {
+			inputList := []string{tt.keys, "go-restapi", "15m", "720h"}
+
+			for i, key := range authEnvKeyList {
+				t.Setenv(key, inputList[i])
+			}
+
+			authCfg, err := NewAuthConfig()
+
+			assert.Nil(t, authCfg)
+			assert.EqualError(t, err, tt.expected)
+		})
+	}
+}
+
+func TestNewAuthConfigInvalidMode(t *testing.T) {
+	t.Setenv("AUTH_MODE", "ldap")
+
+	authCfg, err := NewAuthConfig()
+
+	assert.Nil(t, authCfg)
+	assert.EqualError(t, err, "invalid auth mode: 'ldap'")
+}
@@ -28,6 +28,19 @@ func getDurationEnvValue(key string) (time.Duration, error) {
 	return val, nil
 }
 
+// Like getDurationEnvValue, for durations that have to be longer than zero
+func getPositiveDurationEnvValue(key string) (time.Duration, error) {
+	val, err := getDurationEnvValue(key)
+	if err != nil {
+		return 0, err
+	}
+
+	if val <= 0 {
+		return 0, fmt.Errorf("environment variable %s must be a positive duration: '%s'", key, val)
+	}
+	return val, nil
+}
+
 func getIntEnvValue(key string) (int, error) {
 	strVal, err := getEnvValue(key)
 	if err != nil {
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+const (
+	// How long the key set is used before it is loaded again, unless set
+	defaultJWKSCacheTTL = time.Hour
+	// Claim read for the roles of a user, unless set
+	defaultRolesClaim = "roles"
+)
+
+type OIDCConfig struct {
+	// Tokens are only accepted from this issuer and for this audience
+	Issuer   string
+	Audience string
+	// Where the keys tokens are signed with are published. One of the
+	// two is set
+	JWKSURL  string
+	JWKSFile string
+	// How long loaded keys are used before they are loaded again. Tokens
+	// signed with a key not seen yet have the keys loaded sooner
+	JWKSCacheTTL time.Duration
+	// Claim holding the role, or list of roles, of a user
+	RolesClaim string
+}
+
+// Read OIDC_ISSUER, OIDC_AUDIENCE and either OIDC_JWKS_URL or OIDC_JWKS_FILE.
+// OIDC_JWKS_CACHE_TTL and OIDC_ROLES_CLAIM are optional
+func newOIDCConfig() (*OIDCConfig, error) {
+	issuer, err := getEnvValue("OIDC_ISSUER")
+	if err != nil {
+		return nil, err
+	}
+
+	audience, err := getEnvValue("OIDC_AUDIENCE")
+	if err != nil {
+		return nil, err
+	}
+
+	jwksURL := os.Getenv("OIDC_JWKS_URL")
+	jwksFile := os.Getenv("OIDC_JWKS_FILE")
+	if (jwksURL == "") == (jwksFile == "") {
+		return nil, fmt.Errorf("exactly one of OIDC_JWKS_URL and OIDC_JWKS_FILE must be set")
+	}
+
+	cacheTTL := defaultJWKSCacheTTL
+	if os.Getenv("OIDC_JWKS_CACHE_TTL") != "" {
+		cacheTTL, err = getDurationEnvValue("OIDC_JWKS_CACHE_TTL")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rolesClaim := defaultRolesClaim
+	if val := os.Getenv("OIDC_ROLES_CLAIM"); val != "" {
+		rolesClaim = val
+	}
+
+	return &OIDCConfig{
+		Issuer:       issuer,
+		Audience:     audience,
+		JWKSURL:      jwksURL,
+		JWKSFile:     jwksFile,
+		JWKSCacheTTL: cacheTTL,
+		RolesClaim:   rolesClaim,
+	}, nil
+}
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAuthConfigOIDC(t *testing.T) {
+	testTable := map[string]struct {
+		env      map[string]string
+		expected *OIDCConfig
+		err      string
+	}{
+		"File": {
+			env: map[string]string{
+				"OIDC_ISSUER":    "https://sso.example.com",
+				"OIDC_AUDIENCE":  "go-restapi",
+				"OIDC_JWKS_FILE": "/etc/go-restapi/jwks.json",
+			},
+			expected: &OIDCConfig{
+				Issuer:       "https://sso.example.com",
+				Audience:     "go-restapi",
+				JWKSFile:     "/etc/go-restapi/jwks.json",
+				JWKSCacheTTL: time.Hour,
+				RolesClaim:   "roles",
+			},
+		},
+		"URL": {
+			env: map[string]string{
+				"OIDC_ISSUER":         "https://sso.example.com",
+				"OIDC_AUDIENCE":       "go-restapi",
+				"OIDC_JWKS_URL":       "https://sso.example.com/.well-known/jwks.json",
+				"OIDC_JWKS_CACHE_TTL": "10m",
+				"OIDC_ROLES_CLAIM":    "groups",
+			},
+			expected: &OIDCConfig{
+				Issuer:       "https://sso.example.com",
+				Audience:     "go-restapi",
+				JWKSURL:      "https://sso.example.com/.well-known/jwks.json",
+				JWKSCacheTTL: 10 * time.Minute,
+				RolesClaim:   "groups",
+			},
+		},
+		"BothSources": {
+			env: map[string]string{
+				"OIDC_ISSUER":    "https://sso.example.com",
+				"OIDC_AUDIENCE":  "go-restapi",
+				"OIDC_JWKS_URL":  "https://sso.example.com/.well-known/jwks.json",
+				"OIDC_JWKS_FILE": "/etc/go-restapi/jwks.json",
+			},
+			err: "exactly one of OIDC_JWKS_URL and OIDC_JWKS_FILE must be set",
+		},
+		"NoSource": {
+			env: map[string]string{
+				"OIDC_ISSUER":   "https://sso.example.com",
+				"OIDC_AUDIENCE": "go-restapi",
+			},
+			err: "exactly one of OIDC_JWKS_URL and OIDC_JWKS_FILE must be set",
+		},
+		"NoAudience": {
+			env: map[string]string{
+				"OIDC_ISSUER":    "https://sso.example.com",
+				"OIDC_JWKS_FILE": "/etc/go-restapi/jwks.json",
+			},
+			err: "environment variable OIDC_AUDIENCE must be set",
+		},
+		"InvalidCacheTTL": {
+			env: map[string]string{
+				"OIDC_ISSUER":         "https://sso.example.com",
+				"OIDC_AUDIENCE":       "go-restapi",
+				"OIDC_JWKS_FILE":      "/etc/go-restapi/jwks.json",
+				"OIDC_JWKS_CACHE_TTL": "hourly",
+			},
+			err: "invalid duration format: 'hourly': time: invalid duration \"hourly\"",
+		},
+	}
+
+	for n, tt := range testTable {
+		t.Run(n, func(t *testing.T) {
+			t.Setenv("AUTH_MODE", "oidc")
+			for _, key := range []string{"OIDC_ISSUER", "OIDC_AUDIENCE", "OIDC_JWKS_URL", "OIDC_JWKS_FILE", "OIDC_JWKS_CACHE_TTL", "OIDC_ROLES_CLAIM"} {
+				t.Setenv(key, tt.env[key])
+			}
+			// Password mode settings are not needed
+			for _, key := range authEnvKeyList {
+				t.Setenv(key, "")
+			}
+
+			authCfg, err := NewAuthConfig()
+
+			if tt.err != "" {
+				assert.Nil(t, authCfg)
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, AuthModeOIDC, authCfg.Mode)
+			assert.Equal(t, tt.expected, authCfg.OIDC)
+			assert.Nil(t, authCfg.SigningKeys)
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package config
+
+import (
+	"time"
+)
+
+type TrashConfig struct {
+	Retention     time.Duration
+	PurgeInterval time.Duration
+}
+
+// NewTrashConfig reads TRASH_RETENTION and TRASH_PURGE_INTERVAL, which both
+// have to be positive. A ticker cannot run at a zero interval, and a
+// retention below zero would purge the trash as soon as anything lands in it
+func NewTrashConfig() (*TrashConfig, error) {
+	retention, err := getPositiveDurationEnvValue("TRASH_RETENTION")
+	if err != nil {
+		return nil, err
+	}
+
+	purgeInterval, err := getPositiveDurationEnvValue("TRASH_PURGE_INTERVAL")
+	if err != nil {
+		return nil, err
+	}
+
+	return &TrashConfig{
+		Retention:     retention,
+		PurgeInterval: purgeInterval,
+	}, nil
+}
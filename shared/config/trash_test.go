@@ -0,0 +1,92 @@
+package config
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var trashEnvKeyList = []string{"TRASH_RETENTION", "TRASH_PURGE_INTERVAL"}
+
+func TestNewTrashConfigNormal(t *testing.T) {
+	inputList := []string{"720h", "1h"}
+
+	for i, key := range trashEnvKeyList {
+		t.Setenv(key, inputList[i])
+	}
+
+	trashCfg, err := NewTrashConfig()
+
+	assert.NoError(t, err)
+	assert.NotNil(t, trashCfg)
+	assert.Equal(t, 720*time.Hour, trashCfg.Retention)
+	assert.Equal(t, time.Hour, trashCfg.PurgeInterval)
+}
+
+func TestNewTrashConfigEmptyRetention(t *testing.T) {
+	inputList := []string{"", "1h"}
+
+	for i, key := range trashEnvKeyList {
+		t.Setenv(key, inputList[i])
+	}
+
+	trashCfg, err := NewTrashConfig()
+
+	assert.Nil(t, trashCfg)
+	assert.Error(t, err)
+	assert.EqualError(t, err, fmt.Sprintf("environment variable %s must be set", "TRASH_RETENTION"))
+}
+
+func TestNewTrashConfigInvalidDuration(t *testing.T) {
+	invalidDuration := "1hour"
+	inputList := []string{"720h", invalidDuration}
+
+	for i, key := range trashEnvKeyList {
+		t.Setenv(key, inputList[i])
+	}
+
+	trashCfg, err := NewTrashConfig()
+
+	assert.Nil(t, trashCfg)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), fmt.Sprintf("invalid duration format: '%s'", invalidDuration))
+}
+
+func TestNewTrashConfigNonPositiveDuration(t *testing.T) {
+	testTable := map[string]struct {
+		inputList []string
+		expected  string
+	}{
+		"ZeroPurgeInterval": {
+			inputList: []string{"720h", "0s"},
+			expected:  "environment variable TRASH_PURGE_INTERVAL must be a positive duration: '0s'",
+		},
+		"NegativePurgeInterval": {
+			inputList: []string{"720h", "-1h"},
+			expected:  "environment variable TRASH_PURGE_INTERVAL must be a positive duration: '-1h0m0s'",
+		},
+		"ZeroRetention": {
+			inputList: []string{"0s", "1h"},
+			expected:  "environment variable TRASH_RETENTION must be a positive duration: '0s'",
+		},
+		"NegativeRetention": {
+			inputList: []string{"-1h", "1h"},
+			expected:  "environment variable TRASH_RETENTION must be a positive duration: '-1h0m0s'",
+		},
+	}
+
+	for n, tt := range testTable {
+		t.Run(n, func(t *testing.T) {
+			for i, key := range trashEnvKeyList {
+				t.Setenv(key, tt.inputList[i])
+			}
+
+			trashCfg, err := NewTrashConfig()
+
+			assert.Nil(t, trashCfg)
+			assert.EqualError(t, err, tt.expected)
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+type WorkflowConfig struct {
+	// States each state may move to, or nil for the built-in workflow
+	Transitions map[string][]string
+}
+
+// NewWorkflowConfig reads the optional TASK_WORKFLOW, which lists the moves
+// allowed from each state as in "todo:in_progress|done;in_progress:todo|done"
+func NewWorkflowConfig() (*WorkflowConfig, error) {
+	val := os.Getenv("TASK_WORKFLOW")
+	if val == "" {
+		return &WorkflowConfig{}, nil
+	}
+
+	transitions := map[string][]string{}
+	for _, entry := range strings.Split(val, ";") {
+		from, nexts, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok || from == "" {
+			return nil, fmt.Errorf("invalid workflow format: '%s'", entry)
+		}
+
+		transitions[from] = []string{}
+		for _, next := range strings.Split(nexts, "|") {
+			if next = strings.TrimSpace(next); next != "" {
+				transitions[from] = append(transitions[from], next)
+			}
+		}
+	}
+
+	return &WorkflowConfig{Transitions: transitions}, nil
+}
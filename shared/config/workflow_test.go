@@ -0,0 +1,41 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewWorkflowConfigNormal(t *testing.T) {
+	t.Setenv("TASK_WORKFLOW", "todo:in_progress|done; in_progress:todo|done;done:")
+
+	workflowCfg, err := NewWorkflowConfig()
+
+	assert.NoError(t, err)
+	assert.NotNil(t, workflowCfg)
+	assert.Equal(t, map[string][]string{
+		"todo":        {"in_progress", "done"},
+		"in_progress": {"todo", "done"},
+		"done":        {},
+	}, workflowCfg.Transitions)
+}
+
+func TestNewWorkflowConfigEmpty(t *testing.T) {
+	t.Setenv("TASK_WORKFLOW", "")
+
+	workflowCfg, err := NewWorkflowConfig()
+
+	assert.NoError(t, err)
+	assert.NotNil(t, workflowCfg)
+	assert.Nil(t, workflowCfg.Transitions)
+}
+
+func TestNewWorkflowConfigInvalidFormat(t *testing.T) {
+	t.Setenv("TASK_WORKFLOW", "todo:done;in_progress")
+
+	workflowCfg, err := NewWorkflowConfig()
+
+	assert.Nil(t, workflowCfg)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid workflow format: 'in_progress'")
+}
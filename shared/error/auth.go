@@ -0,0 +1,35 @@
+package error
+
+import "errors"
+
+var (
+	ErrRevoked = errors.New("revoked")
+	ErrExpired = errors.New("expired")
+)
+
+var (
+	ErrRegisterUser       = errors.New("failed to register a user")
+	ErrLogin              = errors.New("failed to log in")
+	ErrRefreshSession     = errors.New("failed to refresh a session")
+	ErrLogout             = errors.New("failed to log out")
+	ErrAuthenticate       = errors.New("failed to authenticate a request")
+	ErrEmailTaken         = errors.New("another user already has the same email")
+	ErrInvalidCredentials = errors.New("email or password is incorrect")
+	ErrInvalidRefresh     = errors.New("refresh token is invalid, expired or revoked")
+	ErrUnauthenticated    = errors.New("valid access token is required")
+	ErrForbidden          = errors.New("role does not permit the action")
+	ErrInsufficientScope  = errors.New("token does not have the scope required")
+	ErrTokenNotFound      = errors.New("personal access token not found")
+	ErrCreateToken        = errors.New("failed to create a personal access token")
+	ErrGetTokens          = errors.New("failed to get personal access tokens")
+	ErrRevokeToken        = errors.New("failed to revoke a personal access token")
+	ErrPasswordAuthOff    = errors.New("users sign in with the identity provider instead")
+)
+
+var (
+	AuthBadRequest  = errors.New("requested auth info is incorrect")
+	TokenBadRequest = errors.New("requested token info is incorrect")
+	InvalidUserId   = errors.New("user id is invalid")
+	InvalidRole     = errors.New("role is invalid")
+	InvalidTokenId  = errors.New("token id is invalid")
+)
@@ -0,0 +1,20 @@
+package error
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BlockedError is a task that may not be completed yet, along with
+// the open tasks it waits on
+type BlockedError struct {
+	Blockers []string
+}
+
+func (e *BlockedError) Error() string {
+	return fmt.Sprintf("%s: waiting on %s", ErrTaskBlocked, strings.Join(e.Blockers, ", "))
+}
+
+func (e *BlockedError) Unwrap() error {
+	return ErrTaskBlocked
+}
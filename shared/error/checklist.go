@@ -0,0 +1,19 @@
+package error
+
+import "errors"
+
+var (
+	ErrGetChecklist           = errors.New("failed to get the checklist of a task")
+	ErrAddChecklistItem       = errors.New("failed to add a checklist item")
+	ErrUpdateChecklistItem    = errors.New("failed to update a checklist item")
+	ErrRemoveChecklistItem    = errors.New("failed to remove a checklist item")
+	ErrReorderChecklist       = errors.New("failed to reorder a checklist")
+	ErrChecklistItemNotFound  = errors.New("checklist item specified by requested id not found")
+	ErrChecklistFull          = errors.New("checklist cannot hold any more items")
+	ErrChecklistOrderMismatch = errors.New("new order must list every item of the checklist exactly once")
+)
+
+var (
+	ChecklistBadRequest    = errors.New("requested checklist info is incorrect")
+	InvalidChecklistItemId = errors.New("checklist item id is invalid")
+)
@@ -0,0 +1,17 @@
+package error
+
+import "fmt"
+
+// FieldError ties an error to the request field that caused it
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,22 @@
+package error
+
+import "errors"
+
+var (
+	ErrAddLabel          = errors.New("failed to add a new label")
+	ErrGetLabels         = errors.New("failed to get labels")
+	ErrGetLabelById      = errors.New("failed to get a label by id")
+	ErrUpdateLabel       = errors.New("failed to update a label")
+	ErrDeleteLabel       = errors.New("failed to delete a label")
+	ErrLabelNotFound     = errors.New("label specified by requested id not found")
+	ErrLabelConflict     = errors.New("another label already has the same value")
+	ErrLabelValueTooLong = errors.New("label value is too long")
+
+	ErrSetTaskLabels     = errors.New("failed to set the labels of a task")
+	ErrTaskLabelNotFound = errors.New("label given for the task not found")
+)
+
+var (
+	LabelBadRequest = errors.New("requested label info is incorrect")
+	InvalidLabelId  = errors.New("label id is invalid")
+)
@@ -5,6 +5,17 @@ import "errors"
 var (
 	ErrInternalServerError = errors.New("internal server error")
 	ErrNotFound            = errors.New("not found")
+	ErrVersionMismatch     = errors.New("version mismatch")
+	ErrDuplicate           = errors.New("duplicate")
+	ErrNotNull             = errors.New("not null")
+	ErrValueTooLong        = errors.New("value too long")
+	ErrBatchAborted        = errors.New("not written because another item of the atomic batch failed")
+	ErrCycle               = errors.New("cycle")
+	ErrTooDeep             = errors.New("too deep")
+	ErrHasChildren         = errors.New("has children")
+	ErrItemNotFound        = errors.New("item not found")
+	ErrTooMany             = errors.New("too many")
+	ErrOrderMismatch       = errors.New("order mismatch")
 )
 
 var (
@@ -14,9 +25,51 @@ var (
 	ErrUpdateTask   = errors.New("failed to update a task")
 	ErrDeleteTask   = errors.New("failed to delete a task")
 	ErrTaskNotFound = errors.New("task specified by requested id not found")
+
+	ErrGetTrash       = errors.New("failed to get trashed tasks")
+	ErrRestoreTask    = errors.New("failed to restore a task")
+	ErrPurgeTask      = errors.New("failed to purge a task")
+	ErrPurgeTrash     = errors.New("failed to purge expired tasks from trash")
+	ErrTaskNotInTrash = errors.New("task specified by requested id not found in trash")
+
+	ErrTaskVersionMismatch  = errors.New("task has been modified since it was last retrieved")
+	ErrTaskConcurrentUpdate = errors.New("task was modified while the change was being applied")
+	ErrTaskConflict         = errors.New("another task already has the same value")
+	ErrTaskValueMissing     = errors.New("task value is required")
+	ErrTaskValueTooLong     = errors.New("task value is too long")
+
+	ErrUnknownTaskState         = errors.New("task state is unknown")
+	ErrUnknownTaskPriority      = errors.New("task priority is unknown")
+	ErrTaskTransitionNotAllowed = errors.New("task cannot move to the requested state")
+
+	ErrGetSubtasks        = errors.New("failed to get the subtasks of a task")
+	ErrTaskParentNotFound = errors.New("parent task not found")
+	ErrTaskParentCycle    = errors.New("task cannot be moved under itself or one of its subtasks")
+	ErrTaskTooDeep        = errors.New("task hierarchy would be too deep")
+	ErrTaskHasSubtasks    = errors.New("task has subtasks, so whether to delete or reparent them must be chosen")
+	ErrUnknownSubtaskMode = errors.New("subtask mode is unknown")
+
+	ErrAddTaskDependency      = errors.New("failed to add a task dependency")
+	ErrRemoveTaskDependency   = errors.New("failed to remove a task dependency")
+	ErrTaskBlockerNotFound    = errors.New("blocking task not found")
+	ErrTaskDependencyCycle    = errors.New("task cannot wait on itself or on a task that waits on it")
+	ErrTaskDependencyNotFound = errors.New("task dependency not found")
+	ErrTaskBlocked            = errors.New("task cannot be completed while tasks blocking it are open")
+	ErrGetTaskSchedule        = errors.New("failed to plan the open tasks")
+
+	ErrAddTasks    = errors.New("failed to add tasks")
+	ErrUpdateTasks = errors.New("failed to update tasks")
+	ErrDeleteTasks = errors.New("failed to delete tasks")
 )
 
 var (
-	TaskBadRequest       = errors.New("requested task info is incorrect")
-	InvalidRequestFormat = errors.New("request format is invalid")
+	TaskBadRequest        = errors.New("requested task info is incorrect")
+	InvalidTaskId         = errors.New("task id is invalid")
+	InvalidRequestFormat  = errors.New("request format is invalid")
+	InvalidQueryParameter = errors.New("query parameter is invalid")
+	UnsupportedMediaType  = errors.New("content type is not supported")
+	RequestBodyTooLarge   = errors.New("request body is too large")
+	InvalidPatchDocument  = errors.New("patch document is invalid")
+	PatchConflict         = errors.New("patch cannot be applied to the current task")
+	UnprocessablePatch    = errors.New("patched task is invalid")
 )
@@ -0,0 +1,19 @@
+package error
+
+import "fmt"
+
+// TransitionError is a workflow move that is not allowed, along with
+// the states that could have been moved to instead
+type TransitionError struct {
+	From    string
+	To      string
+	Allowed []string
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("%s: from '%s' to '%s'", ErrTaskTransitionNotAllowed, e.From, e.To)
+}
+
+func (e *TransitionError) Unwrap() error {
+	return ErrTaskTransitionNotAllowed
+}